@@ -155,6 +155,44 @@ type (
 		Close() error
 	}
 
+	// An EventCursor is a position in the stream of adjudicator events of a
+	// channel. It is opaque to the framework and only meaningful to the
+	// backend that created it.
+	EventCursor []byte
+
+	// A CursorSubscription is an AdjudicatorSubscription that keeps track of
+	// its position in the event stream.
+	CursorSubscription interface {
+		AdjudicatorSubscription
+
+		// Cursor returns the position right after the last event returned by
+		// Next. It should be persisted after the event has been processed.
+		Cursor() EventCursor
+	}
+
+	// A CursorSubscriber is an EventSubscriber that can resume subscriptions
+	// at a persisted position.
+	//
+	// SubscribeFrom returns a subscription whose first event is the first
+	// event after the cursor. Unlike Subscribe, it returns all events,
+	// including past Registered and Progressed events, in the order in which
+	// they occurred. A nil cursor starts at the first event of the channel.
+	CursorSubscriber interface {
+		EventSubscriber
+		SubscribeFrom(context.Context, ID, EventCursor) (CursorSubscription, error)
+	}
+
+	// A RegistrationSubscriber can subscribe to the registrations of all
+	// channels, e.g., for a watchtower that does not know the IDs of the
+	// channels it watches.
+	//
+	// SubscribeRegistrations returns a subscription whose events are the
+	// RegisteredEvents of all channels registered after the subscription was
+	// created.
+	RegistrationSubscriber interface {
+		SubscribeRegistrations(context.Context) (AdjudicatorSubscription, error)
+	}
+
 	// An AdjudicatorEvent is any event that an on-chain adjudicator call might
 	// cause, currently either a Registered or Progressed event.
 	// The type of the event should be checked with a type switch.
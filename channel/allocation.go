@@ -741,6 +741,9 @@ func (s *SubAlloc) Decode(r io.Reader) error {
 	if err := perunio.Decode(r, &l); err != nil {
 		return errors.WithMessage(err, "decoding index map length")
 	}
+	if err := perunio.CheckSize(r, "index map", int(l)); err != nil {
+		return err
+	}
 	s.IndexMap = make([]Index, l)
 	for i := range s.IndexMap {
 		if err := perunio.Decode(r, &s.IndexMap[i]); err != nil {
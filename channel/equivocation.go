@@ -0,0 +1,115 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire/perunio"
+)
+
+// Equivocation is evidence that a channel participant signed two different
+// states of the same channel with the same version. It can be verified by
+// anyone who knows the channel parameters.
+type Equivocation struct {
+	Params *Params
+	Idx    Index // Idx is the index of the equivocating participant.
+
+	// Txs are the conflicting transactions. Only the signatures of the
+	// equivocating participant are needed, the others may be missing.
+	Txs [2]Transaction
+}
+
+var _ perunio.Serializer = (*Equivocation)(nil)
+
+// NewEquivocation returns the evidence that participant idx signed both
+// transactions, or an error if the transactions do not prove an equivocation.
+// Only the signatures of the participant are kept.
+func NewEquivocation(params *Params, idx Index, a, b Transaction) (*Equivocation, error) {
+	e := &Equivocation{Params: params, Idx: idx}
+	for i, tx := range []Transaction{a, b} {
+		if tx.State == nil || int(idx) >= len(tx.Sigs) {
+			return nil, errors.Errorf("missing signature in transaction %d", i)
+		}
+		sigs := make([]wallet.Sig, len(params.Parts))
+		sigs[idx] = tx.Sigs[idx]
+		e.Txs[i] = Transaction{State: tx.State.Clone(), Sigs: sigs}
+	}
+	if err := e.Verify(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// ID returns the ID of the channel.
+func (e *Equivocation) ID() ID {
+	return e.Params.ID()
+}
+
+// Version returns the version of the conflicting states.
+func (e *Equivocation) Version() uint64 {
+	return e.Txs[0].Version
+}
+
+// Verify checks that both states belong to the channel, have the same version
+// but differ, and are signed by the equivocating participant.
+func (e *Equivocation) Verify() error {
+	if int(e.Idx) >= len(e.Params.Parts) {
+		return errors.Errorf("participant index %d out of bounds", e.Idx)
+	}
+	for i, tx := range e.Txs {
+		if tx.State == nil {
+			return errors.Errorf("missing state %d", i)
+		}
+		if tx.ID != e.Params.ID() {
+			return errors.Errorf("state %d of other channel", i)
+		}
+	}
+	if e.Txs[0].Version != e.Txs[1].Version {
+		return errors.New("versions differ")
+	}
+	if e.Txs[0].Equal(e.Txs[1].State) == nil {
+		return errors.New("states are equal")
+	}
+	for i, tx := range e.Txs {
+		if len(tx.Sigs) != len(e.Params.Parts) {
+			return errors.Errorf("sigs length mismatch in transaction %d", i)
+		}
+		for _, p := range e.Params.Parts[e.Idx] {
+			ok, err := Verify(p, tx.State, tx.Sigs[e.Idx])
+			if err != nil {
+				return errors.WithMessagef(err, "verifying signature on state %d", i)
+			}
+			if !ok {
+				return errors.Errorf("invalid signature on state %d", i)
+			}
+		}
+	}
+	return nil
+}
+
+// Encode encodes the equivocation into an io.Writer.
+func (e *Equivocation) Encode(w io.Writer) error {
+	return perunio.Encode(w, e.Params, e.Idx, e.Txs[0], e.Txs[1])
+}
+
+// Decode decodes an equivocation from an io.Reader.
+func (e *Equivocation) Decode(r io.Reader) error {
+	e.Params = new(Params)
+	return perunio.Decode(r, e.Params, &e.Idx, &e.Txs[0], &e.Txs[1])
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/test"
+	"perun.network/go-perun/wallet"
+	wallettest "perun.network/go-perun/wallet/test"
+	peruniotest "perun.network/go-perun/wire/perunio/test"
+	pkgtest "polycry.pt/poly-go/test"
+)
+
+func TestEquivocation(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	accs, addrs := wallettest.NewRandomAccounts(rng, 2, channel.TestBackendID)
+	params, state := test.NewRandomParamsAndState(rng, test.WithParts(addrs), test.WithNumLocked(0), test.WithIsFinal(false))
+	other := state.Clone()
+	other.Balances[0][0] = new(big.Int).Add(other.Balances[0][0], big.NewInt(1))
+
+	sign := func(s *channel.State) channel.Transaction {
+		sigs := make([]wallet.Sig, len(accs))
+		for i, acc := range accs {
+			sig, err := channel.Sign(acc[channel.TestBackendID], s, channel.TestBackendID)
+			require.NoError(t, err)
+			sigs[i] = sig
+		}
+		return channel.Transaction{State: s, Sigs: sigs}
+	}
+
+	e, err := channel.NewEquivocation(params, 1, sign(state), sign(other))
+	require.NoError(t, err)
+	require.Equal(t, params.ID(), e.ID())
+	require.Equal(t, state.Version, e.Version())
+	require.Nil(t, e.Txs[0].Sigs[0], "only the signatures of the participant are kept")
+	peruniotest.GenericSerializerTest(t, e)
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := channel.NewEquivocation(params, 1, sign(state), sign(state))
+		require.Error(t, err, "equal states")
+
+		newer := other.Clone()
+		newer.Version++
+		_, err = channel.NewEquivocation(params, 1, sign(state), sign(newer))
+		require.Error(t, err, "different versions")
+
+		unsigned := sign(other)
+		unsigned.Sigs[1] = unsigned.Sigs[0]
+		_, err = channel.NewEquivocation(params, 1, sign(state), unsigned)
+		require.Error(t, err, "invalid signature")
+
+		_, err = channel.NewEquivocation(params, 2, sign(state), sign(other))
+		require.Error(t, err, "index out of bounds")
+	})
+}
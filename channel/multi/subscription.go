@@ -15,22 +15,76 @@
 package multi
 
 import (
+	"bytes"
+	"cmp"
 	"context"
+	"fmt"
+	"slices"
+	"sync"
 
 	"perun.network/go-perun/channel"
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/wire/perunio"
 )
 
 // Subscribe creates a new multi-ledger AdjudicatorSubscription.
 func (a *Adjudicator) Subscribe(ctx context.Context, chID channel.ID) (channel.AdjudicatorSubscription, error) {
+	return a.subscribe(nil, func(_ LedgerBackendKey, la channel.Adjudicator) (channel.AdjudicatorSubscription, error) {
+		return la.Subscribe(ctx, chID)
+	})
+}
+
+// SubscribeFrom creates a new multi-ledger AdjudicatorSubscription that
+// resumes the subscriptions of all ledgers after the given cursor. The cursor
+// must have been returned by the Cursor method of a subscription of this
+// adjudicator, or be nil. It fails if the adjudicator of any ledger is not a
+// channel.CursorSubscriber.
+func (a *Adjudicator) SubscribeFrom(ctx context.Context, chID channel.ID, cursor channel.EventCursor) (channel.CursorSubscription, error) {
+	cursors, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("decoding cursor: %w", err)
+	}
+	return a.subscribe(cursors, func(key LedgerBackendKey, la channel.Adjudicator) (channel.AdjudicatorSubscription, error) {
+		cs, ok := la.(channel.CursorSubscriber)
+		if !ok {
+			return nil, fmt.Errorf("adjudicator for ledger %v does not support cursors", key.LedgerID)
+		}
+		return cs.SubscribeFrom(ctx, chID, cursors[key])
+	})
+}
+
+// SubscribeRegistrations creates a new multi-ledger AdjudicatorSubscription for
+// the registrations of all channels on all ledgers. It fails if the adjudicator
+// of any ledger is not a channel.RegistrationSubscriber.
+func (a *Adjudicator) SubscribeRegistrations(ctx context.Context) (channel.AdjudicatorSubscription, error) {
+	return a.subscribe(nil, func(key LedgerBackendKey, la channel.Adjudicator) (channel.AdjudicatorSubscription, error) {
+		rs, ok := la.(channel.RegistrationSubscriber)
+		if !ok {
+			return nil, fmt.Errorf("adjudicator for ledger %v does not support subscribing to registrations", key.LedgerID)
+		}
+		return rs.SubscribeRegistrations(ctx)
+	})
+}
+
+// subscribe subscribes to the events of all ledgers and fans them in. The
+// cursors are the initial positions of the subscriptions of the ledgers.
+func (a *Adjudicator) subscribe(
+	cursors map[LedgerBackendKey]channel.EventCursor,
+	subscribe func(LedgerBackendKey, channel.Adjudicator) (channel.AdjudicatorSubscription, error),
+) (*AdjudicatorSubscription, error) {
 	asub := &AdjudicatorSubscription{
-		events: make(chan channel.AdjudicatorEvent),
-		errors: make(chan error),
-		subs:   []channel.AdjudicatorSubscription{},
-		done:   make(chan struct{}),
+		events:  make(chan ledgerEvent),
+		errors:  make(chan error),
+		subs:    []channel.AdjudicatorSubscription{},
+		done:    make(chan struct{}),
+		cursors: make(map[LedgerBackendKey]channel.EventCursor, len(cursors)),
+	}
+	for key, c := range cursors {
+		asub.cursors[key] = c
 	}
 
-	for _, la := range a.adjudicators {
-		sub, err := la.Subscribe(ctx, chID)
+	for key, la := range a.adjudicators {
+		sub, err := subscribe(key, la)
 		if err != nil {
 			asub.Close()
 			return nil, err
@@ -38,9 +92,15 @@ func (a *Adjudicator) Subscribe(ctx context.Context, chID channel.ID) (channel.A
 		asub.subs = append(asub.subs, sub)
 
 		go func() {
+			cs, _ := sub.(channel.CursorSubscription)
 			for {
+				e := ledgerEvent{key: key, event: sub.Next()}
+				// The cursor must be read before the next call to Next.
+				if cs != nil && e.event != nil {
+					e.cursor = cs.Cursor()
+				}
 				select {
-				case asub.events <- sub.Next():
+				case asub.events <- e:
 				case <-asub.done:
 					return
 				}
@@ -55,24 +115,60 @@ func (a *Adjudicator) Subscribe(ctx context.Context, chID channel.ID) (channel.A
 	return asub, nil
 }
 
-// AdjudicatorSubscription is a multi-ledger adjudicator subscription.
-type AdjudicatorSubscription struct {
-	subs   []channel.AdjudicatorSubscription
-	events chan channel.AdjudicatorEvent
-	errors chan error
-	done   chan struct{}
-}
+type (
+	// AdjudicatorSubscription is a multi-ledger adjudicator subscription.
+	AdjudicatorSubscription struct {
+		subs   []channel.AdjudicatorSubscription
+		events chan ledgerEvent
+		errors chan error
+		done   chan struct{}
+
+		mu      sync.Mutex
+		cursors map[LedgerBackendKey]channel.EventCursor
+	}
+
+	// ledgerEvent is an event of the subscription of a single ledger together
+	// with the position of that subscription after the event.
+	ledgerEvent struct {
+		key    LedgerBackendKey
+		event  channel.AdjudicatorEvent
+		cursor channel.EventCursor
+	}
+)
+
+var _ channel.CursorSubscription = (*AdjudicatorSubscription)(nil)
 
 // Next returns the next event.
 func (s *AdjudicatorSubscription) Next() channel.AdjudicatorEvent {
 	select {
 	case e := <-s.events:
-		return e
+		if e.cursor != nil {
+			s.mu.Lock()
+			s.cursors[e.key] = e.cursor
+			s.mu.Unlock()
+		}
+		return e.event
 	case <-s.done:
 		return nil
 	}
 }
 
+// Cursor returns the position after the last event returned by Next. It
+// combines the cursors of the subscriptions of all ledgers. If the
+// subscription was not created by SubscribeFrom, the cursor only holds the
+// positions of the ledgers that support cursors.
+func (s *AdjudicatorSubscription) Cursor() channel.EventCursor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cursor, err := encodeCursor(s.cursors)
+	if err != nil {
+		// A nil cursor replays all events, which is safe.
+		log.Errorf("Encoding multi-ledger cursor: %v", err)
+		return nil
+	}
+	return cursor
+}
+
 // Err blocks until an error occurred and returns it.
 func (s *AdjudicatorSubscription) Err() error {
 	for range len(s.subs) {
@@ -93,3 +189,56 @@ func (s *AdjudicatorSubscription) Close() error {
 	close(s.done)
 	return nil
 }
+
+// encodeCursor encodes the cursors of the ledgers, ordered by their keys. A
+// cursor without any ledger positions is encoded as nil.
+func encodeCursor(cursors map[LedgerBackendKey]channel.EventCursor) (channel.EventCursor, error) {
+	if len(cursors) == 0 {
+		return nil, nil
+	}
+	keys := make([]LedgerBackendKey, 0, len(cursors))
+	for key := range cursors {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b LedgerBackendKey) int {
+		return cmp.Or(cmp.Compare(a.BackendID, b.BackendID), cmp.Compare(a.LedgerID, b.LedgerID))
+	})
+
+	var buf bytes.Buffer
+	if err := perunio.Encode(&buf, uint16(len(keys))); err != nil { //nolint:gosec // Bounded by the number of ledgers.
+		return nil, err
+	}
+	for _, key := range keys {
+		if err := perunio.Encode(&buf, key.BackendID, key.LedgerID, string(cursors[key])); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeCursor decodes a cursor that was encoded by encodeCursor.
+func decodeCursor(cursor channel.EventCursor) (map[LedgerBackendKey]channel.EventCursor, error) {
+	cursors := make(map[LedgerBackendKey]channel.EventCursor)
+	if len(cursor) == 0 {
+		return cursors, nil
+	}
+	r := bytes.NewReader(cursor)
+	var n uint16
+	if err := perunio.Decode(r, &n); err != nil {
+		return nil, err
+	}
+	for range n {
+		var (
+			key LedgerBackendKey
+			c   string
+		)
+		if err := perunio.Decode(r, &key.BackendID, &key.LedgerID, &c); err != nil {
+			return nil, err
+		}
+		cursors[key] = channel.EventCursor(c)
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes", r.Len())
+	}
+	return cursors, nil
+}
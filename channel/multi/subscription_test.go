@@ -0,0 +1,116 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	_ "perun.network/go-perun/backend/sim" // backend init
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/multi"
+	channeltest "perun.network/go-perun/channel/test"
+	clienttest "perun.network/go-perun/client/test"
+	wallettest "perun.network/go-perun/wallet/test"
+	"polycry.pt/poly-go/test"
+)
+
+func TestAdjudicator_SubscribeFrom(t *testing.T) {
+	rng := test.Prng(t)
+	ctx := context.Background()
+	backends := []*clienttest.MockBackend{
+		clienttest.NewMockBackend(rng, "1"),
+		clienttest.NewMockBackend(rng, "2"),
+	}
+	adj := multi.NewAdjudicator()
+	for _, b := range backends {
+		adj.RegisterAdjudicator(b.ID(), b.NewAdjudicator(wallettest.NewRandomAddress(rng, channel.TestBackendID)))
+	}
+
+	params, state := channeltest.NewRandomParamsAndState(rng, channeltest.WithVersion(0), channeltest.WithIsFinal(false), channeltest.WithChallengeDuration(60))
+	register := func(b *clienttest.MockBackend, version uint64) {
+		s := state.Clone()
+		s.Version = version
+		req := channel.AdjudicatorReq{Params: params, Tx: channel.Transaction{State: s}}
+		require.NoError(t, b.Register(ctx, req, nil))
+	}
+	next := func(sub channel.AdjudicatorSubscription) uint64 {
+		e := sub.Next()
+		require.IsType(t, &channel.RegisteredEvent{}, e)
+		return e.Version()
+	}
+
+	register(backends[0], 0)
+	register(backends[1], 1)
+
+	sub, err := adj.SubscribeFrom(ctx, params.ID(), nil)
+	require.NoError(t, err)
+	versions := []uint64{next(sub), next(sub)}
+	require.ElementsMatch(t, []uint64{0, 1}, versions)
+	cursor := sub.Cursor()
+	require.NoError(t, sub.Close())
+
+	// Only the events after the cursor are returned.
+	register(backends[0], 2)
+	sub, err = adj.SubscribeFrom(ctx, params.ID(), cursor)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, next(sub))
+	register(backends[1], 3)
+	require.EqualValues(t, 3, next(sub))
+
+	// The cursor of the resumed subscription starts at the given cursor.
+	cursor = sub.Cursor()
+	require.NoError(t, sub.Close())
+	sub, err = adj.SubscribeFrom(ctx, params.ID(), cursor)
+	require.NoError(t, err)
+	register(backends[0], 4)
+	require.EqualValues(t, 4, next(sub))
+	require.NoError(t, sub.Close())
+
+	_, err = adj.SubscribeFrom(ctx, params.ID(), channel.EventCursor{0xff})
+	require.Error(t, err)
+}
+
+func TestAdjudicator_SubscribeRegistrations(t *testing.T) {
+	rng := test.Prng(t)
+	ctx := context.Background()
+	backends := []*clienttest.MockBackend{
+		clienttest.NewMockBackend(rng, "1"),
+		clienttest.NewMockBackend(rng, "2"),
+	}
+	adj := multi.NewAdjudicator()
+	for _, b := range backends {
+		adj.RegisterAdjudicator(b.ID(), b.NewAdjudicator(wallettest.NewRandomAddress(rng, channel.TestBackendID)))
+	}
+
+	register := func(b *clienttest.MockBackend) channel.ID {
+		params, state := channeltest.NewRandomParamsAndState(rng, channeltest.WithIsFinal(false), channeltest.WithChallengeDuration(60))
+		req := channel.AdjudicatorReq{Params: params, Tx: channel.Transaction{State: state}}
+		require.NoError(t, b.Register(ctx, req, nil))
+		return params.ID()
+	}
+
+	// Registrations before subscribing are not returned.
+	register(backends[0])
+	sub, err := adj.SubscribeRegistrations(ctx)
+	require.NoError(t, err)
+	ids := []channel.ID{register(backends[0]), register(backends[1])}
+	got := []channel.ID{sub.Next().ID(), sub.Next().ID()}
+	require.ElementsMatch(t, ids, got)
+	require.NoError(t, sub.Close())
+	require.Nil(t, sub.Next())
+}
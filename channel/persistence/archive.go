@@ -0,0 +1,126 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+)
+
+type (
+	// An Archiver is used by the client to keep the data of a channel after it
+	// has been settled and before it is removed from the Persister. Archived
+	// channels are never restored as active channels.
+	Archiver interface {
+		// ChannelArchived is called by the client right before a withdrawn
+		// channel is removed from the Persister. If it returns an error, the
+		// channel is not removed. If removing the channel fails afterwards, it
+		// is archived again on the next attempt, so archiving the same channel
+		// twice must not change the archive.
+		ChannelArchived(context.Context, *ArchivedChannel) error
+	}
+
+	// An Archive is an Archiver that can also be queried for the archived
+	// channels.
+	Archive interface {
+		Archiver
+
+		// ArchivedChannel returns the archived channel with the given ID.
+		ArchivedChannel(context.Context, channel.ID) (*ArchivedChannel, error)
+
+		// ArchivedChannels returns all archived channels that match the given
+		// filter, ordered by their settlement time.
+		ArchivedChannels(context.Context, ArchiveFilter) ([]*ArchivedChannel, error)
+	}
+
+	// ArchivedChannel holds the data of a settled channel that is kept for
+	// later reference.
+	ArchivedChannel struct {
+		Idx    channel.Index       // Idx is the own index in the channel.
+		Params *channel.Params     // Params are the channel parameters.
+		State  channel.Transaction // State is the final, fully signed transaction.
+		Peers  []map[wallet.BackendID]wire.Address
+		Parent *channel.ID // Parent is the parent's ID, or nil for ledger channels.
+
+		// Settled is the time at which the channel's funds were withdrawn.
+		Settled time.Time
+		// Archived is the time at which the channel was stored in the archive.
+		// It is set by the Archiver.
+		Archived time.Time
+	}
+
+	// An ArchiveFilter restricts the archived channels returned by an Archive.
+	// Unset fields match all channels.
+	ArchiveFilter struct {
+		Peer  map[wallet.BackendID]wire.Address // Peer must be a channel peer.
+		Asset channel.Asset                     // Asset must be held in the channel.
+		From  time.Time                         // From is the inclusive lower bound of Settled.
+		Until time.Time                         // Until is the exclusive upper bound of Settled.
+	}
+)
+
+// NewArchivedChannel creates an ArchivedChannel from the given Source, which
+// should be in its final phase, the channel peers and the parent channel ID.
+// The Settled timestamp is set to the current time.
+func NewArchivedChannel(s channel.Source, peers []map[wallet.BackendID]wire.Address, parent *channel.ID) *ArchivedChannel {
+	return &ArchivedChannel{
+		Idx:     s.Idx(),
+		Params:  s.Params().Clone(),
+		State:   s.CurrentTX().Clone(),
+		Peers:   peers,
+		Parent:  parent,
+		Settled: time.Now(),
+	}
+}
+
+// ID returns the channel ID of the archived channel.
+func (a *ArchivedChannel) ID() channel.ID {
+	return a.Params.ID()
+}
+
+// Outcome returns the final balances of all participants with which the
+// channel was settled. The outer dimension are the assets, the inner dimension
+// the participants.
+func (a *ArchivedChannel) Outcome() channel.Balances {
+	return a.State.Balances.Clone()
+}
+
+// Matches returns whether the archived channel matches the filter.
+func (f ArchiveFilter) Matches(a *ArchivedChannel) bool {
+	if !f.From.IsZero() && a.Settled.Before(f.From) {
+		return false
+	}
+	if !f.Until.IsZero() && !a.Settled.Before(f.Until) {
+		return false
+	}
+	if f.Asset != nil {
+		if _, ok := a.State.AssetIndex(f.Asset); !ok {
+			return false
+		}
+	}
+	if f.Peer != nil {
+		for _, p := range a.Peers {
+			if channel.EqualWireMaps(p, f.Peer) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,23 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package batch implements a PersistRestorer that coalesces the persistence
+// calls of many channels into group commits on a batching backend.
+//
+// Calls after which the client sends its own signature to a peer only return
+// after their group has been committed. All other update calls are queued and
+// committed asynchronously, but always before any later call that needs to be
+// durable. This takes the persistence latency off the hot path of most channel
+// updates without ever sending a signature on a state that is not persisted.
+package batch // import "perun.network/go-perun/channel/persistence/batch"
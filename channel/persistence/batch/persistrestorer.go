@@ -0,0 +1,316 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	stdsync "sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	"polycry.pt/poly-go/sync"
+)
+
+type (
+	// A Backend is a PersistRestorer that can collect the update calls of many
+	// channels in a Batch and write them at once.
+	Backend interface {
+		persistence.PersistRestorer
+
+		// NewBatch returns a new, empty Batch.
+		NewBatch() Batch
+	}
+
+	// A Batch collects persistence calls, which have the same semantics as the
+	// corresponding Persister methods, and writes them atomically on Apply.
+	// A Batch must see the effects of its earlier calls, so the same channel
+	// may be updated several times in one Batch.
+	Batch interface {
+		Staged(context.Context, channel.Source) error
+		SigAdded(context.Context, channel.Source, channel.Index) error
+		Enabled(context.Context, channel.Source) error
+		PhaseChanged(context.Context, channel.Source) error
+
+		// Apply writes all collected calls to the backend.
+		Apply() error
+	}
+
+	// PersistRestorer is a persistence.PersistRestorer that groups the update
+	// calls of many channels into commits of a single Batch on a Backend.
+	//
+	// SigAdded for the own signature, ChannelCreated and ChannelRemoved only
+	// return after all earlier calls and the call itself are committed. Staged,
+	// Enabled, PhaseChanged and SigAdded for peer signatures return right after
+	// they have been queued. If a commit fails, the error is returned by all
+	// later calls, because durability cannot be guaranteed anymore.
+	PersistRestorer struct {
+		backend Backend
+		reqs    chan *request
+		closer  sync.Closer
+		stopped chan struct{}
+		// closeMu is held for reading while a request is queued, so that no
+		// request is queued after Close.
+		closeMu stdsync.RWMutex
+
+		mu  stdsync.Mutex
+		err error
+	}
+
+	// request is a queued call. A request without op is a flush barrier.
+	request struct {
+		op   func(Batch) error
+		done chan error // done is nil for asynchronous requests.
+	}
+)
+
+var _ persistence.PersistRestorer = (*PersistRestorer)(nil)
+
+// restorePeerTimeout bounds the flush before RestorePeer, which is not passed
+// a context.
+var restorePeerTimeout = 10 * time.Second
+
+// NewPersistRestorer creates a PersistRestorer on the backend that commits at
+// most maxSize calls per batch. Calls that arrive while a batch is being
+// committed are grouped into the next batch.
+func NewPersistRestorer(backend Backend, maxSize int) *PersistRestorer {
+	if maxSize < 1 {
+		panic("maxSize must be positive")
+	}
+	pr := &PersistRestorer{
+		backend: backend,
+		reqs:    make(chan *request, maxSize),
+		stopped: make(chan struct{}),
+	}
+	go pr.run()
+	return pr
+}
+
+// run commits the queued requests in groups until the PersistRestorer is
+// closed. The requests that are still queued on close are committed before
+// run returns.
+func (pr *PersistRestorer) run() {
+	defer close(pr.stopped)
+	for {
+		select {
+		case r := <-pr.reqs:
+			pr.commit(pr.collect([]*request{r}))
+		case <-pr.closer.Closed():
+			for group := pr.collect(nil); len(group) > 0; group = pr.collect(nil) {
+				pr.commit(group)
+			}
+			return
+		}
+	}
+}
+
+// collect appends queued requests to group until the group is full or the
+// queue is empty.
+func (pr *PersistRestorer) collect(group []*request) []*request {
+	for len(group) < cap(pr.reqs) {
+		select {
+		case r := <-pr.reqs:
+			group = append(group, r)
+		default:
+			return group
+		}
+	}
+	return group
+}
+
+// commit applies the group of requests in one batch and notifies all waiting
+// callers.
+func (pr *PersistRestorer) commit(group []*request) {
+	err := pr.Err()
+	if err == nil {
+		b := pr.backend.NewBatch()
+		for _, r := range group {
+			if r.op == nil {
+				continue
+			}
+			if err = r.op(b); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = errors.WithMessage(b.Apply(), "applying batch")
+		}
+		pr.setErr(err)
+	}
+
+	for _, r := range group {
+		if r.done != nil {
+			r.done <- err
+		}
+	}
+}
+
+// Err returns the error of the first failed commit, if any.
+func (pr *PersistRestorer) Err() error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	return pr.err
+}
+
+func (pr *PersistRestorer) setErr(err error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if pr.err == nil {
+		pr.err = err
+	}
+}
+
+// enqueue queues the operation. If sync is set, it waits until the operation
+// is committed. The operation is called with a context that is not canceled
+// together with ctx, because asynchronous operations outlive the call.
+func (pr *PersistRestorer) enqueue(ctx context.Context, op func(context.Context, Batch) error, sync bool) error {
+	if err := pr.Err(); err != nil {
+		return err
+	}
+	r := &request{}
+	if op != nil {
+		opCtx := context.WithoutCancel(ctx)
+		r.op = func(b Batch) error { return op(opCtx, b) }
+	}
+	if sync {
+		r.done = make(chan error, 1)
+	}
+
+	pr.closeMu.RLock()
+	if pr.closer.IsClosed() {
+		pr.closeMu.RUnlock()
+		return errors.New("persister closed")
+	}
+	select {
+	case pr.reqs <- r:
+	case <-ctx.Done():
+		pr.closeMu.RUnlock()
+		return ctx.Err()
+	}
+	pr.closeMu.RUnlock()
+	if !sync {
+		return nil
+	}
+
+	select {
+	case err := <-r.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Flush waits until all queued calls are committed.
+func (pr *PersistRestorer) Flush(ctx context.Context) error {
+	return pr.enqueue(ctx, nil, true)
+}
+
+// ChannelCreated flushes the queue and then persists the channel directly on
+// the backend.
+func (pr *PersistRestorer) ChannelCreated(ctx context.Context, s channel.Source, peers []map[wallet.BackendID]wire.Address, parent *channel.ID) error {
+	if err := pr.Flush(ctx); err != nil {
+		return err
+	}
+	return pr.backend.ChannelCreated(ctx, s, peers, parent)
+}
+
+// ChannelRemoved flushes the queue and then removes the channel directly on
+// the backend.
+func (pr *PersistRestorer) ChannelRemoved(ctx context.Context, id channel.ID) error {
+	if err := pr.Flush(ctx); err != nil {
+		return err
+	}
+	return pr.backend.ChannelRemoved(ctx, id)
+}
+
+// Staged queues persisting the staging state.
+func (pr *PersistRestorer) Staged(ctx context.Context, s channel.Source) error {
+	s = persistence.CloneSource(s)
+	return pr.enqueue(ctx, func(ctx context.Context, b Batch) error { return b.Staged(ctx, s) }, false)
+}
+
+// SigAdded queues persisting the signature. If it is the own signature, it
+// waits until the signature is committed, because it is sent to the peers
+// next.
+func (pr *PersistRestorer) SigAdded(ctx context.Context, s channel.Source, idx channel.Index) error {
+	own := idx == s.Idx()
+	s = persistence.CloneSource(s)
+	return pr.enqueue(ctx, func(ctx context.Context, b Batch) error { return b.SigAdded(ctx, s, idx) }, own)
+}
+
+// Enabled queues persisting the current state.
+func (pr *PersistRestorer) Enabled(ctx context.Context, s channel.Source) error {
+	s = persistence.CloneSource(s)
+	return pr.enqueue(ctx, func(ctx context.Context, b Batch) error { return b.Enabled(ctx, s) }, false)
+}
+
+// PhaseChanged queues persisting the phase.
+func (pr *PersistRestorer) PhaseChanged(ctx context.Context, s channel.Source) error {
+	s = persistence.CloneSource(s)
+	return pr.enqueue(ctx, func(ctx context.Context, b Batch) error { return b.PhaseChanged(ctx, s) }, false)
+}
+
+// ActivePeers flushes the queue and then returns the active peers of the
+// backend.
+func (pr *PersistRestorer) ActivePeers(ctx context.Context) ([]map[wallet.BackendID]wire.Address, error) {
+	if err := pr.Flush(ctx); err != nil {
+		return nil, err
+	}
+	return pr.backend.ActivePeers(ctx)
+}
+
+// RestorePeer flushes the queue and then restores the peer's channels from the
+// backend. If the flush does not complete within a timeout, its error is
+// returned.
+func (pr *PersistRestorer) RestorePeer(peer map[wallet.BackendID]wire.Address) (persistence.ChannelIterator, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), restorePeerTimeout)
+	defer cancel()
+	if err := pr.Flush(ctx); err != nil {
+		return nil, errors.WithMessage(err, "flushing before restore")
+	}
+	return pr.backend.RestorePeer(peer)
+}
+
+// RestoreChannel flushes the queue and then restores the channel from the
+// backend.
+func (pr *PersistRestorer) RestoreChannel(ctx context.Context, id channel.ID) (*persistence.Channel, error) {
+	if err := pr.Flush(ctx); err != nil {
+		return nil, err
+	}
+	return pr.backend.RestoreChannel(ctx, id)
+}
+
+// Close commits all queued calls, stops the committing routine and closes the
+// backend. Calls that are queued concurrently with Close are either committed
+// or rejected with an error. Close returns the error of the first failed
+// commit, if any.
+func (pr *PersistRestorer) Close() error {
+	pr.closeMu.Lock()
+	cerr := pr.closer.Close()
+	pr.closeMu.Unlock()
+	if cerr != nil {
+		return cerr
+	}
+	<-pr.stopped
+	err := pr.Err()
+	if cerr := pr.backend.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
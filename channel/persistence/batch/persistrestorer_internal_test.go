@@ -0,0 +1,62 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel/persistence"
+	wiretest "perun.network/go-perun/wire/test"
+	ctxtest "polycry.pt/poly-go/context/test"
+	"polycry.pt/poly-go/test"
+)
+
+// stuckBackend is a Backend whose batches never finish applying.
+type stuckBackend struct {
+	persistence.PersistRestorer
+	unblock chan struct{}
+}
+
+func (b *stuckBackend) NewBatch() Batch { return &stuckBatch{unblock: b.unblock} }
+
+type stuckBatch struct {
+	Batch
+	unblock chan struct{}
+}
+
+func (b *stuckBatch) Apply() error {
+	<-b.unblock
+	return nil
+}
+
+func TestPersistRestorer_RestorePeerTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { restorePeerTimeout = timeout }(restorePeerTimeout)
+	restorePeerTimeout = 10 * time.Millisecond
+
+	backend := &stuckBackend{unblock: make(chan struct{})}
+	pr := NewPersistRestorer(backend, 1)
+	defer close(backend.unblock)
+	require.NoError(t, pr.enqueue(context.Background(), nil, false))
+
+	ctxtest.AssertTerminates(t, time.Second, func() {
+		_, err := pr.RestorePeer(wiretest.NewRandomAddress(test.Prng(t)))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,310 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch_test
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	stdsync "sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "perun.network/go-perun/backend/sim" // backend init
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	"perun.network/go-perun/channel/persistence/batch"
+	"perun.network/go-perun/channel/persistence/keyvalue"
+	"perun.network/go-perun/channel/persistence/test"
+	ctest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/wallet"
+	wtest "perun.network/go-perun/wallet/test"
+	"perun.network/go-perun/wire"
+	wiretest "perun.network/go-perun/wire/test"
+	"polycry.pt/poly-go/sortedkv"
+	"polycry.pt/poly-go/sortedkv/leveldb"
+	"polycry.pt/poly-go/sortedkv/memorydb"
+	pkgtest "polycry.pt/poly-go/test"
+)
+
+func TestPersistRestorer_Generic(t *testing.T) {
+	lvldb, err := leveldb.LoadDatabase(t.TempDir())
+	require.NoError(t, err)
+
+	for i, backend := range []*keyvalue.PersistRestorer{
+		keyvalue.NewPersistRestorer(lvldb),
+		keyvalue.NewPersistRestorer(memorydb.NewDatabase()),
+	} {
+		pr := batch.NewPersistRestorer(backend, 16)
+		test.GenericPersistRestorerTest(
+			context.Background(),
+			t,
+			pkgtest.Prng(t, i),
+			pr,
+			4,
+			16)
+		require.NoError(t, pr.Close())
+	}
+}
+
+func TestPersistRestorer_Durability(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	ctx := context.Background()
+	backend := &failingBackend{Backend: keyvalue.NewPersistRestorer(memorydb.NewDatabase())}
+	pr := batch.NewPersistRestorer(backend, 8)
+	defer pr.Close()
+
+	ch := newUpdatingChannel(rng)
+	require.NoError(t, pr.ChannelCreated(ctx, ch, ch.PeersV, nil))
+
+	// Asynchronous calls are visible after a flush.
+	ch.PhaseV = channel.Acting
+	require.NoError(t, pr.PhaseChanged(ctx, ch))
+	rch, err := pr.RestoreChannel(ctx, ch.ID())
+	require.NoError(t, err)
+	assert.Equal(t, channel.Acting, rch.PhaseV)
+
+	// The own signature is committed before SigAdded returns.
+	backend.fail.Store(true)
+	assert.NoError(t, pr.Staged(ctx, ch))
+	assert.Error(t, pr.SigAdded(ctx, ch, ch.IdxV))
+	// The error is sticky.
+	assert.Error(t, pr.Staged(ctx, ch))
+	assert.Error(t, pr.Err())
+}
+
+func TestPersistRestorer_Close(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	ctx := context.Background()
+	backend := &countingBackend{Backend: keyvalue.NewPersistRestorer(memorydb.NewDatabase())}
+	pr := batch.NewPersistRestorer(backend, 4)
+
+	ch := newUpdatingChannel(rng)
+	require.NoError(t, pr.ChannelCreated(ctx, ch, ch.PeersV, nil))
+
+	// Every asynchronous call that is accepted around Close is committed.
+	const n = 64
+	var accepted atomic.Int64
+	var wg stdsync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if pr.Enabled(ctx, ch) == nil {
+				accepted.Add(1)
+			}
+		}()
+	}
+	require.NoError(t, pr.Close())
+	wg.Wait()
+
+	assert.Equal(t, accepted.Load(), backend.enabled.Load())
+	assert.Error(t, pr.Enabled(ctx, ch))
+}
+
+// countingBackend is a batch.Backend that counts the committed Enabled calls.
+type countingBackend struct {
+	batch.Backend
+	enabled atomic.Int64
+}
+
+func (b *countingBackend) NewBatch() batch.Batch {
+	return &countingBatch{Batch: b.Backend.NewBatch(), enabled: &b.enabled}
+}
+
+type countingBatch struct {
+	batch.Batch
+	enabled *atomic.Int64
+	n       int64
+}
+
+func (b *countingBatch) Enabled(ctx context.Context, s channel.Source) error {
+	b.n++
+	return b.Batch.Enabled(ctx, s)
+}
+
+func (b *countingBatch) Apply() error {
+	time.Sleep(time.Millisecond) // Let calls queue up during the commit.
+	if err := b.Batch.Apply(); err != nil {
+		return err
+	}
+	b.enabled.Add(b.n)
+	return nil
+}
+
+// failingBackend is a batch.Backend whose batches fail to apply if fail is
+// set.
+type failingBackend struct {
+	batch.Backend
+	fail atomic.Bool
+}
+
+func (b *failingBackend) NewBatch() batch.Batch {
+	return &failingBatch{b.Backend.NewBatch(), &b.fail}
+}
+
+type failingBatch struct {
+	batch.Batch
+	fail *atomic.Bool
+}
+
+func (b *failingBatch) Apply() error {
+	if b.fail.Load() {
+		return errors.New("apply failed")
+	}
+	return b.Batch.Apply()
+}
+
+// BenchmarkPersister measures the number of channel updates per second on the
+// plain keyvalue persister and on the batching persister around it. Every
+// update consists of the calls that the client makes on a two-party update.
+// The database syncs every write to disk, like a durable database must.
+func BenchmarkPersister(b *testing.B) {
+	for _, par := range []int{1, 16, 256} {
+		b.Run("keyvalue/parallel="+strconv.Itoa(par), func(b *testing.B) {
+			pr := keyvalue.NewPersistRestorer(newSyncedDB(b))
+			defer pr.Close()
+			benchmarkUpdates(b, pr, par)
+		})
+		b.Run("batch/parallel="+strconv.Itoa(par), func(b *testing.B) {
+			pr := batch.NewPersistRestorer(keyvalue.NewPersistRestorer(newSyncedDB(b)), 1024)
+			defer pr.Close()
+			benchmarkUpdates(b, pr, par)
+		})
+	}
+}
+
+func benchmarkUpdates(b *testing.B, pr persistence.PersistRestorer, parallelism int) {
+	b.Helper()
+	ctx := context.Background()
+	var seed atomic.Int64
+	b.SetParallelism(parallelism)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		rng := rand.New(rand.NewSource(seed.Add(1))) //nolint:gosec
+		ch := newUpdatingChannel(rng)
+		if err := pr.ChannelCreated(ctx, ch, ch.PeersV, nil); err != nil {
+			b.Error(err)
+			return
+		}
+		for pb.Next() {
+			ch.StagingTXV.Version++
+			ch.PhaseV = channel.Signing
+			for _, err := range []error{
+				pr.Staged(ctx, ch),
+				pr.SigAdded(ctx, ch, 0),
+				pr.SigAdded(ctx, ch, 1),
+			} {
+				if err != nil {
+					b.Error(err)
+					return
+				}
+			}
+			ch.CurrentTXV = ch.StagingTXV
+			ch.PhaseV = channel.Acting
+			if err := pr.Enabled(ctx, ch); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "updates/s")
+}
+
+// newUpdatingChannel creates a two-party channel with a signed current and
+// staging state.
+func newUpdatingChannel(rng *rand.Rand) *persistence.Channel {
+	accs, parts := wtest.NewRandomAccounts(rng, 2, channel.TestBackendID)
+	params, state := ctest.NewRandomParamsAndState(rng,
+		ctest.WithParts(parts), ctest.WithNumLocked(0), ctest.WithVersion(0), ctest.WithIsFinal(false))
+
+	sigs := make([]wallet.Sig, len(accs))
+	for i, acc := range accs {
+		sig, err := channel.Sign(acc[channel.TestBackendID], state, channel.TestBackendID)
+		if err != nil {
+			panic(err)
+		}
+		sigs[i] = sig
+	}
+
+	ch := persistence.NewChannel()
+	ch.ParamsV = params
+	ch.CurrentTXV = channel.Transaction{State: state, Sigs: sigs}
+	ch.StagingTXV = channel.Transaction{State: state.Clone(), Sigs: sigs}
+	ch.PhaseV = channel.Acting
+	ch.PeersV = []map[wallet.BackendID]wire.Address{wiretest.NewRandomAddress(rng), wiretest.NewRandomAddress(rng)}
+	return ch
+}
+
+// syncedDB is a LevelDB database that syncs a file to disk after every write.
+type syncedDB struct {
+	sortedkv.Database
+	f *os.File
+}
+
+type syncedBatch struct {
+	sortedkv.Batch
+	f *os.File
+}
+
+func newSyncedDB(b *testing.B) *syncedDB {
+	b.Helper()
+	dir := b.TempDir()
+	db, err := leveldb.LoadDatabase(dir)
+	require.NoError(b, err)
+	f, err := os.Create(filepath.Join(dir, "sync"))
+	require.NoError(b, err)
+	b.Cleanup(func() { f.Close() })
+	return &syncedDB{Database: db, f: f}
+}
+
+func (db *syncedDB) Put(key, value string) error {
+	if err := db.Database.Put(key, value); err != nil {
+		return err
+	}
+	return db.f.Sync()
+}
+
+func (db *syncedDB) PutBytes(key string, value []byte) error {
+	if err := db.Database.PutBytes(key, value); err != nil {
+		return err
+	}
+	return db.f.Sync()
+}
+
+func (db *syncedDB) Delete(key string) error {
+	if err := db.Database.Delete(key); err != nil {
+		return err
+	}
+	return db.f.Sync()
+}
+
+func (db *syncedDB) NewBatch() sortedkv.Batch {
+	return &syncedBatch{Batch: db.Database.NewBatch(), f: db.f}
+}
+
+func (b *syncedBatch) Apply() error {
+	if err := b.Batch.Apply(); err != nil {
+		return err
+	}
+	return b.f.Sync()
+}
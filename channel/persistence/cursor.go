@@ -0,0 +1,40 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persistence
+
+import (
+	"context"
+
+	"perun.network/go-perun/channel"
+)
+
+// A CursorPersister persists the positions of the adjudicator event
+// subscriptions of a client's channels. It is used if the adjudicator is a
+// channel.CursorSubscriber, so that subscriptions resume after the last
+// processed event when the client is restarted.
+type CursorPersister interface {
+	// CursorUpdated is called after the client processed an adjudicator event
+	// of the channel. The cursor is the position of the subscription after
+	// the event.
+	CursorUpdated(_ context.Context, id channel.ID, cursor channel.EventCursor) error
+
+	// Cursor returns the persisted cursor of the channel, or nil if no cursor
+	// is persisted.
+	Cursor(_ context.Context, id channel.ID) (channel.EventCursor, error)
+
+	// CursorRemoved is called when the channel is withdrawn and its cursor is
+	// no longer needed.
+	CursorRemoved(_ context.Context, id channel.ID) error
+}
@@ -0,0 +1,33 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persistence
+
+import (
+	"context"
+
+	"perun.network/go-perun/channel"
+)
+
+// An EquivocationStore keeps the evidence of equivocations by channel peers,
+// so that it is still available after a restart, e.g., for a dispute.
+type EquivocationStore interface {
+	// EquivocationDetected is called by the client when it detected an
+	// equivocation. Storing the same evidence twice must not fail.
+	EquivocationDetected(context.Context, *channel.Equivocation) error
+
+	// Equivocations returns the evidence of all equivocations in the given
+	// channel, ordered by version.
+	Equivocations(context.Context, channel.ID) ([]*channel.Equivocation, error)
+}
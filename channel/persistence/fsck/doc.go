@@ -0,0 +1,24 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fsck checks the consistency of persisted channel data and repairs
+// inconsistencies where this is safe.
+//
+// Check works on any persistence.Restorer. It verifies the signatures of the
+// persisted transactions, the links between sub-channels and their parents,
+// the peer index and whether the persisted phase is plausible for the
+// persisted transactions. Repair fixes those issues that do not require a
+// decision by the user, like removing withdrawn channels or invalid staging
+// signatures.
+package fsck // import "perun.network/go-perun/channel/persistence/fsck"
@@ -0,0 +1,429 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fsck
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+)
+
+// IssueKind classifies an inconsistency found by Check.
+type IssueKind int
+
+// Issue kinds reported by Check.
+const (
+	// RestoreFailed means that a peer's channels could not be restored.
+	RestoreFailed IssueKind = iota
+	// DanglingPeer means that a peer is active but has no restorable channels.
+	DanglingPeer
+	// PeerIndexMismatch means that a channel was restored for a peer that is
+	// not one of its peers.
+	PeerIndexMismatch
+	// MissingPeerIndex means that a channel cannot be restored for one of its
+	// peers.
+	MissingPeerIndex
+	// InvalidParams means that the index or the state does not match the
+	// channel parameters.
+	InvalidParams
+	// ImplausiblePhase means that the phase does not fit the persisted
+	// transactions.
+	ImplausiblePhase
+	// WithdrawnChannel means that a channel has been withdrawn but was not
+	// removed.
+	WithdrawnChannel
+	// InvalidSignature means that a signature on the current state is missing
+	// or invalid.
+	InvalidSignature
+	// InvalidStagingSignature means that a signature on the staging state is
+	// invalid.
+	InvalidStagingSignature
+	// MissingParent means that the parent of a sub-channel is not persisted.
+	MissingParent
+	// MissingSubAlloc means that the parent of an active sub-channel does not
+	// lock any funds for it.
+	MissingSubAlloc
+	// InvalidSignatureCount means that the number of signatures on a state
+	// does not match the number of participants.
+	InvalidSignatureCount
+)
+
+func (k IssueKind) String() string {
+	return [...]string{
+		"RestoreFailed",
+		"DanglingPeer",
+		"PeerIndexMismatch",
+		"MissingPeerIndex",
+		"InvalidParams",
+		"ImplausiblePhase",
+		"WithdrawnChannel",
+		"InvalidSignature",
+		"InvalidStagingSignature",
+		"MissingParent",
+		"MissingSubAlloc",
+		"InvalidSignatureCount",
+	}[k]
+}
+
+type (
+	// Issue is a single inconsistency found by Check.
+	Issue struct {
+		Kind    IssueKind
+		Channel *channel.ID                       // Channel is the affected channel, if any.
+		Peer    map[wallet.BackendID]wire.Address // Peer is the affected peer, if any.
+		Idx     channel.Index                     // Idx is the signer for signature issues.
+		Msg     string
+
+		// prunable is set for DanglingPeer issues found in a Pruner.
+		prunable bool
+	}
+
+	// Report is the result of a consistency check.
+	Report struct {
+		Peers    int // Peers is the number of checked active peers.
+		Channels int // Channels is the number of checked channels.
+		Issues   []Issue
+
+		// chans holds the checked channels, needed for repairs.
+		chans map[channel.ID]*persistence.Channel
+	}
+
+	// A Pruner is a persistence backend that can remove dangling index entries
+	// and incomplete channel data that cannot be restored anymore. Repair uses
+	// it for DanglingPeer issues.
+	Pruner interface {
+		// Prune removes all dangling data and returns the number of removed
+		// entries.
+		Prune(context.Context) (int, error)
+	}
+)
+
+// Repairable returns whether Repair can safely fix the issue. DanglingPeer
+// issues are only repaired if the checked persistence backend is a Pruner.
+func (i Issue) Repairable() bool {
+	switch i.Kind {
+	case WithdrawnChannel, InvalidStagingSignature:
+		return true
+	case DanglingPeer:
+		return i.prunable
+	default:
+		return false
+	}
+}
+
+func (i Issue) String() string {
+	s := i.Kind.String()
+	if i.Channel != nil {
+		s += fmt.Sprintf(" channel=%x", *i.Channel)
+	}
+	if i.Peer != nil {
+		s += fmt.Sprintf(" peer=%v", i.Peer)
+	}
+	if i.Kind == InvalidSignature || i.Kind == InvalidStagingSignature {
+		s += fmt.Sprintf(" idx=%d", i.Idx)
+	}
+	return s + ": " + i.Msg
+}
+
+// OK returns whether no issues were found.
+func (r *Report) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// WriteTo writes a human-readable version of the report to w.
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	write := func(format string, args ...interface{}) error {
+		m, err := fmt.Fprintf(w, format, args...)
+		n += int64(m)
+		return err
+	}
+
+	if err := write("checked %d peers and %d channels, found %d issues\n",
+		r.Peers, r.Channels, len(r.Issues)); err != nil {
+		return n, err
+	}
+	for _, i := range r.Issues {
+		repair := "manual"
+		if i.Repairable() {
+			repair = "repairable"
+		}
+		if err := write("[%s] %v\n", repair, i); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+func (r *Report) addf(kind IssueKind, ch *channel.ID, peer map[wallet.BackendID]wire.Address, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, Issue{
+		Kind:    kind,
+		Channel: ch,
+		Peer:    peer,
+		Msg:     fmt.Sprintf(format, args...),
+	})
+}
+
+func (r *Report) addSigf(kind IssueKind, ch channel.ID, idx channel.Index, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, Issue{
+		Kind:    kind,
+		Channel: &ch,
+		Idx:     idx,
+		Msg:     fmt.Sprintf(format, args...),
+	})
+}
+
+// Check restores all channels of all active peers from r and checks them for
+// inconsistencies. An error is only returned if the list of active peers
+// cannot be restored or the context is done; all other problems are reported
+// as issues in the Report.
+func Check(ctx context.Context, r persistence.Restorer) (*Report, error) {
+	peers, err := r.ActivePeers(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "restoring active peers")
+	}
+
+	rep := &Report{
+		Peers: len(peers),
+		chans: make(map[channel.ID]*persistence.Channel),
+	}
+	_, prunable := r.(Pruner)
+	// foundFor records for which peers a channel was restored.
+	foundFor := make(map[channel.ID]map[wire.AddrKey]struct{})
+	for _, peer := range peers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if n := rep.checkPeer(ctx, r, peer, foundFor); n == 0 {
+			rep.addf(DanglingPeer, nil, peer, "peer has no restorable channels")
+			rep.Issues[len(rep.Issues)-1].prunable = prunable
+		}
+	}
+	rep.Channels = len(rep.chans)
+
+	for id, ch := range rep.chans {
+		for _, p := range ch.PeersV {
+			if _, ok := foundFor[id][wire.Keys(p)]; !ok {
+				rep.addf(MissingPeerIndex, &id, p, "channel is not restorable for its peer")
+			}
+		}
+		rep.checkChannel(ch)
+		rep.checkParent(ctx, r, ch)
+	}
+	return rep, nil
+}
+
+// checkPeer restores all channels of the peer and returns their number.
+func (r *Report) checkPeer(
+	ctx context.Context,
+	rs persistence.Restorer,
+	peer map[wallet.BackendID]wire.Address,
+	foundFor map[channel.ID]map[wire.AddrKey]struct{},
+) (n int) {
+	it, err := rs.RestorePeer(peer)
+	if err != nil {
+		r.addf(RestoreFailed, nil, peer, "restoring peer: %v", err)
+		return 0
+	}
+	for it.Next(ctx) {
+		n++
+		ch := it.Channel()
+		id := ch.ID()
+		r.chans[id] = ch
+		if foundFor[id] == nil {
+			foundFor[id] = make(map[wire.AddrKey]struct{})
+		}
+		foundFor[id][wire.Keys(peer)] = struct{}{}
+
+		if !hasPeer(ch, peer) {
+			r.addf(PeerIndexMismatch, &id, peer, "channel restored for a peer that is not a channel peer")
+		}
+	}
+	if err := it.Close(); err != nil {
+		r.addf(RestoreFailed, nil, peer, "restoring channels: %v", err)
+	}
+	return n
+}
+
+func hasPeer(ch *persistence.Channel, peer map[wallet.BackendID]wire.Address) bool {
+	for _, p := range ch.PeersV {
+		if channel.EqualWireMaps(p, peer) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkChannel checks the parameters, phase and signatures of the channel.
+func (r *Report) checkChannel(ch *persistence.Channel) {
+	id := ch.ID()
+	numParts := len(ch.ParamsV.Parts)
+	if int(ch.IdxV) >= numParts {
+		r.addf(InvalidParams, &id, nil, "own index %d out of range for %d participants", ch.IdxV, numParts)
+		return
+	}
+	for _, tx := range []channel.Transaction{ch.CurrentTXV, ch.StagingTXV} {
+		if tx.State != nil && tx.ID != id {
+			r.addf(InvalidParams, &id, nil, "state belongs to channel %x", tx.ID)
+			return
+		}
+	}
+
+	if !r.checkPhase(ch) {
+		return
+	}
+
+	// Transactions that were forced by a progression are unsigned, so missing
+	// signatures are only an issue before the channel is disputed.
+	requireSigs := ch.PhaseV >= channel.Funding && ch.PhaseV <= channel.Final
+	r.checkSigs(id, ch.ParamsV, ch.CurrentTXV, requireSigs, InvalidSignature)
+	r.checkSigs(id, ch.ParamsV, ch.StagingTXV, false, InvalidStagingSignature)
+}
+
+// checkPhase checks whether the phase is plausible for the transactions of the
+// channel and returns whether the remaining checks are meaningful.
+func (r *Report) checkPhase(ch *persistence.Channel) bool {
+	id := ch.ID()
+	phase := ch.PhaseV
+	switch {
+	case int(phase) > channel.LastPhase:
+		r.addf(ImplausiblePhase, &id, nil, "unknown phase %d", phase)
+		return false
+	case phase == channel.Withdrawn:
+		r.addf(WithdrawnChannel, &id, nil, "withdrawn channel was not removed")
+	case phase == channel.InitSigning && ch.StagingTXV.State == nil:
+		r.addf(ImplausiblePhase, &id, nil, "phase %v without staging state", phase)
+	case phase >= channel.Funding && ch.CurrentTXV.State == nil:
+		r.addf(ImplausiblePhase, &id, nil, "phase %v without current state", phase)
+		return false
+	case phase == channel.Signing && ch.StagingTXV.State == nil:
+		r.addf(ImplausiblePhase, &id, nil, "phase %v without staging state", phase)
+	case phase == channel.Signing && ch.StagingTXV.Version != ch.CurrentTXV.Version+1:
+		r.addf(ImplausiblePhase, &id, nil, "staging version %d does not follow current version %d",
+			ch.StagingTXV.Version, ch.CurrentTXV.Version)
+	case phase == channel.Final && !ch.CurrentTXV.IsFinal:
+		r.addf(ImplausiblePhase, &id, nil, "phase %v with non-final current state", phase)
+	}
+	return true
+}
+
+// checkSigs verifies the signatures of tx. If require is set, missing
+// signatures are reported.
+func (r *Report) checkSigs(id channel.ID, params *channel.Params, tx channel.Transaction, require bool, kind IssueKind) {
+	if tx.State == nil {
+		return
+	}
+	if len(tx.Sigs) != len(params.Parts) {
+		// There is no single signer to blame, so this is never repairable.
+		if require || len(tx.Sigs) != 0 {
+			r.addf(InvalidSignatureCount, &id, nil, "%d signatures for %d participants on version %d", len(tx.Sigs), len(params.Parts), tx.Version)
+		}
+		return
+	}
+	for i, sig := range tx.Sigs {
+		idx := channel.Index(i) //nolint:gosec // The number of participants is bounded by channel.MaxNumParts.
+		if sig == nil {
+			if require {
+				r.addSigf(kind, id, idx, "signature missing on version %d", tx.Version)
+			}
+			continue
+		}
+		for _, addr := range params.Parts[i] {
+			if ok, err := channel.Verify(addr, tx.State, sig); err != nil {
+				r.addSigf(kind, id, idx, "verifying signature on version %d: %v", tx.Version, err)
+			} else if !ok {
+				r.addSigf(kind, id, idx, "invalid signature on version %d", tx.Version)
+			}
+		}
+	}
+}
+
+// checkParent checks that the parent of a sub-channel is persisted and locks
+// funds for the sub-channel while the sub-channel is active.
+func (r *Report) checkParent(ctx context.Context, rs persistence.Restorer, ch *persistence.Channel) {
+	if ch.Parent == nil {
+		return
+	}
+	id := ch.ID()
+	parent, ok := r.chans[*ch.Parent]
+	if !ok {
+		var err error
+		if parent, err = rs.RestoreChannel(ctx, *ch.Parent); err != nil {
+			r.addf(MissingParent, &id, nil, "parent %x: %v", *ch.Parent, err)
+			return
+		}
+	}
+
+	active := ch.PhaseV >= channel.Acting && ch.PhaseV <= channel.Final
+	parentActive := parent.PhaseV >= channel.Acting && parent.PhaseV <= channel.Final
+	if !active || !parentActive || parent.CurrentTXV.State == nil {
+		return
+	}
+	if _, ok := parent.CurrentTXV.SubAlloc(id); !ok {
+		r.addf(MissingSubAlloc, &id, nil, "parent %x does not lock funds for the channel", *ch.Parent)
+	}
+}
+
+// Repair fixes all repairable issues of the report using pr, which must be
+// the PersistRestorer that the report was created from. It returns the issues
+// that were repaired. Issues that are not repairable are left untouched, so
+// the store should be checked again afterwards.
+//
+// Withdrawn channels are removed, invalid staging signatures are discarded
+// and, if pr is a Pruner, dangling peer index entries are pruned.
+func Repair(ctx context.Context, pr persistence.PersistRestorer, rep *Report) (repaired []Issue, err error) {
+	pruned := false
+	for _, i := range rep.Issues {
+		if !i.Repairable() {
+			continue
+		}
+		switch i.Kind {
+		case WithdrawnChannel:
+			if err := pr.ChannelRemoved(ctx, *i.Channel); err != nil {
+				return repaired, errors.WithMessagef(err, "removing channel %x", *i.Channel)
+			}
+		case InvalidStagingSignature:
+			ch, ok := rep.chans[*i.Channel]
+			if !ok || int(i.Idx) >= len(ch.StagingTXV.Sigs) {
+				continue
+			}
+			ch.StagingTXV.Sigs[i.Idx] = nil
+			if err := pr.SigAdded(ctx, ch, i.Idx); err != nil {
+				return repaired, errors.WithMessagef(err, "discarding signature %d of channel %x", i.Idx, *i.Channel)
+			}
+		case DanglingPeer:
+			p, ok := pr.(Pruner)
+			if !ok {
+				continue
+			}
+			if !pruned {
+				if _, err := p.Prune(ctx); err != nil {
+					return repaired, errors.WithMessage(err, "pruning")
+				}
+				pruned = true
+			}
+		default:
+		}
+		repaired = append(repaired, i)
+	}
+	return repaired, nil
+}
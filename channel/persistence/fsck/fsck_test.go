@@ -0,0 +1,223 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fsck_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "perun.network/go-perun/backend/sim" // backend init
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	"perun.network/go-perun/channel/persistence/fsck"
+	"perun.network/go-perun/channel/persistence/keyvalue"
+	chprtest "perun.network/go-perun/channel/persistence/test"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	"perun.network/go-perun/wire/test"
+	"polycry.pt/poly-go/sortedkv/memorydb"
+	pkgtest "polycry.pt/poly-go/test"
+)
+
+var _ fsck.Pruner = (*keyvalue.PersistRestorer)(nil)
+
+func TestCheckAndRepair(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	ctx := context.Background()
+	db := memorydb.NewDatabase()
+	pr := keyvalue.NewPersistRestorer(db)
+	c := chprtest.NewClient(ctx, t, rng, pr)
+
+	newFunded := func() *chprtest.Channel {
+		ch := c.NewChannel(t, test.NewRandomAddress(rng), nil)
+		ch.Init(ctx, t, rng)
+		ch.SignAll(ctx, t)
+		ch.EnableInit(t)
+		ch.SetFunded(t)
+		return ch
+	}
+	healthy := newFunded()
+	withdrawn := newFunded()
+	badSig := newFunded()
+	dangling := newFunded()
+	staging := c.NewChannel(t, test.NewRandomAddress(rng), nil)
+	staging.Init(ctx, t, rng)
+
+	rep, err := fsck.Check(ctx, pr)
+	require.NoError(t, err)
+	require.True(t, rep.OK(), rep.Issues)
+	assert.Equal(t, 6, rep.Peers) // 5 remote peers and the client itself.
+
+	// Corrupt the database.
+	// The parent of this channel is persisted elsewhere.
+	otherParent := chprtest.NewClient(ctx, t, rng, keyvalue.NewPersistRestorer(memorydb.NewDatabase())).
+		NewChannel(t, test.NewRandomAddress(rng), nil)
+	orphan := c.NewChannel(t, test.NewRandomAddress(rng), otherParent)
+
+	restore := func(ch *chprtest.Channel) *persistence.Channel {
+		pch, err := pr.RestoreChannel(ctx, ch.ID())
+		require.NoError(t, err)
+		return pch
+	}
+	w := restore(withdrawn)
+	w.PhaseV = channel.Withdrawn
+	require.NoError(t, pr.PhaseChanged(ctx, w))
+
+	b := restore(badSig)
+	b.CurrentTXV.Sigs[0] = b.CurrentTXV.Sigs[1]
+	require.NoError(t, pr.Enabled(ctx, b))
+
+	s := restore(staging)
+	s.StagingTXV.Sigs[s.IdxV^1] = badSig.CurrentTX().Sigs[0]
+	require.NoError(t, pr.SigAdded(ctx, s, s.IdxV^1))
+
+	danglingID := dangling.ID()
+	it := db.NewIteratorWithPrefix("Chan:" + string(danglingID[:]))
+	for it.Next() {
+		require.NoError(t, db.Delete(it.Key()))
+	}
+	require.NoError(t, it.Close())
+
+	rep, err = fsck.Check(ctx, pr)
+	require.NoError(t, err)
+	requireIssues(t, rep, map[fsck.IssueKind]channel.ID{
+		fsck.WithdrawnChannel:        withdrawn.ID(),
+		fsck.InvalidSignature:        badSig.ID(),
+		fsck.InvalidStagingSignature: staging.ID(),
+		fsck.MissingParent:           orphan.ID(),
+		fsck.DanglingPeer:            {},
+	})
+	var out bytes.Buffer
+	_, err = rep.WriteTo(&out)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "found 5 issues")
+
+	// Without a Pruner, dangling peers cannot be repaired.
+	npRep, err := fsck.Check(ctx, nonPruner{pr})
+	require.NoError(t, err)
+	for _, i := range npRep.Issues {
+		if i.Kind == fsck.DanglingPeer {
+			assert.False(t, i.Repairable(), i)
+		}
+	}
+
+	repaired, err := fsck.Repair(ctx, pr, rep)
+	require.NoError(t, err)
+	assert.Len(t, repaired, 3)
+
+	rep, err = fsck.Check(ctx, pr)
+	require.NoError(t, err)
+	requireIssues(t, rep, map[fsck.IssueKind]channel.ID{
+		fsck.InvalidSignature: badSig.ID(),
+		fsck.MissingParent:    orphan.ID(),
+	})
+	healthy.AssertPersisted(ctx, t)
+}
+
+func TestRepair_SignatureCount(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	ctx := context.Background()
+	pr := keyvalue.NewPersistRestorer(memorydb.NewDatabase())
+	c := chprtest.NewClient(ctx, t, rng, pr)
+	ch := c.NewChannel(t, test.NewRandomAddress(rng), nil)
+	ch.Init(ctx, t, rng)
+	sigs := ch.StagingTX().Clone().Sigs
+
+	// Restore the staging state with too few signatures.
+	rep, err := fsck.Check(ctx, truncSigs{pr})
+	require.NoError(t, err)
+	requireIssues(t, rep, map[fsck.IssueKind]channel.ID{
+		fsck.InvalidSignatureCount: ch.ID(),
+	})
+	assert.False(t, rep.Issues[0].Repairable())
+
+	repaired, err := fsck.Repair(ctx, pr, rep)
+	require.NoError(t, err)
+	assert.Empty(t, repaired)
+	pch, err := pr.RestoreChannel(ctx, ch.ID())
+	require.NoError(t, err)
+	assert.Equal(t, sigs, pch.StagingTXV.Sigs)
+}
+
+func TestCheck_NoPruner(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	ctx := context.Background()
+	db := memorydb.NewDatabase()
+	pr := keyvalue.NewPersistRestorer(db)
+	c := chprtest.NewClient(ctx, t, rng, pr)
+	ch := c.NewChannel(t, test.NewRandomAddress(rng), nil)
+	ch.Init(ctx, t, rng)
+
+	id := ch.ID()
+	it := db.NewIteratorWithPrefix("Chan:" + string(id[:]))
+	for it.Next() {
+		require.NoError(t, db.Delete(it.Key()))
+	}
+	require.NoError(t, it.Close())
+
+	rep, err := fsck.Check(ctx, pr)
+	require.NoError(t, err)
+	require.NotEmpty(t, rep.Issues)
+	assert.True(t, rep.Issues[0].Repairable())
+
+	rep, err = fsck.Check(ctx, nonPruner{pr})
+	require.NoError(t, err)
+	require.NotEmpty(t, rep.Issues)
+	for _, i := range rep.Issues {
+		assert.Equal(t, fsck.DanglingPeer, i.Kind)
+		assert.False(t, i.Repairable(), i)
+	}
+}
+
+// nonPruner hides the Prune method of the wrapped PersistRestorer.
+type nonPruner struct {
+	persistence.PersistRestorer
+}
+
+// truncSigs restores all staging states with only the first signature.
+type truncSigs struct {
+	persistence.PersistRestorer
+}
+
+func (r truncSigs) RestorePeer(peer map[wallet.BackendID]wire.Address) (persistence.ChannelIterator, error) {
+	it, err := r.PersistRestorer.RestorePeer(peer)
+	return truncSigsIterator{it}, err
+}
+
+type truncSigsIterator struct {
+	persistence.ChannelIterator
+}
+
+func (it truncSigsIterator) Channel() *persistence.Channel {
+	ch := it.ChannelIterator.Channel()
+	ch.StagingTXV.Sigs = ch.StagingTXV.Sigs[:1]
+	return ch
+}
+
+func requireIssues(t *testing.T, rep *fsck.Report, expected map[fsck.IssueKind]channel.ID) {
+	t.Helper()
+	require.Len(t, rep.Issues, len(expected), rep.Issues)
+	for _, i := range rep.Issues {
+		id, ok := expected[i.Kind]
+		require.Truef(t, ok, "unexpected issue %v", i)
+		if i.Channel != nil {
+			assert.Equal(t, id, *i.Channel)
+		}
+	}
+}
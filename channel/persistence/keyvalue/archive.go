@@ -0,0 +1,254 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyvalue
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	"perun.network/go-perun/wire/perunio"
+	"polycry.pt/poly-go/sortedkv"
+	"polycry.pt/poly-go/sortedkv/key"
+)
+
+var _ persistence.Archive = (*Archive)(nil)
+
+// Archive implements the persistence.Archive interface on a sorted key-value
+// database. Besides the archived channels, it maintains secondary indexes by
+// peer, asset and settlement time.
+//
+// The database may be shared with a PersistRestorer because all keys of the
+// Archive use their own prefix.
+type Archive struct {
+	db sortedkv.Database
+}
+
+var archivePrefix = struct{ ChannelDB, PeerDB, AssetDB, TimeDB string }{
+	ChannelDB: "Archive:Chan:",
+	PeerDB:    "Archive:Peer:",
+	AssetDB:   "Archive:Asset:",
+	TimeDB:    "Archive:Time:",
+}
+
+// NewArchive creates a new Archive using the given database.
+func NewArchive(db sortedkv.Database) *Archive {
+	return &Archive{db: db}
+}
+
+// ChannelArchived stores the channel in the archive and indexes it. The
+// Archived timestamp of the channel is set to the current time. Archiving an
+// already archived channel again is a no-op, so that the first record and its
+// settlement time are kept.
+func (a *Archive) ChannelArchived(_ context.Context, ch *persistence.ArchivedChannel) error {
+	id := ch.ID()
+	if archived, err := a.db.Has(archivePrefix.ChannelDB + string(id[:])); err != nil {
+		return errors.WithMessage(err, "looking up archived channel")
+	} else if archived {
+		return nil
+	}
+	ch.Archived = time.Now()
+
+	var buf bytes.Buffer
+	if err := (archivedChannelEnc{ch}).Encode(&buf); err != nil {
+		return errors.WithMessage(err, "encoding archived channel")
+	}
+
+	batch := a.db.NewBatch()
+	if err := batch.PutBytes(archivePrefix.ChannelDB+string(id[:]), buf.Bytes()); err != nil {
+		return errors.WithMessage(err, "putting archived channel")
+	}
+
+	for _, peer := range ch.Peers {
+		k, err := archivePeerKey(peer)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(k+string(id[:]), ""); err != nil {
+			return errors.WithMessage(err, "putting peer index")
+		}
+	}
+	if ch.State.State != nil {
+		for _, asset := range ch.State.Assets {
+			k, err := archiveAssetKey(asset)
+			if err != nil {
+				return err
+			}
+			if err := batch.Put(k+string(id[:]), ""); err != nil {
+				return errors.WithMessage(err, "putting asset index")
+			}
+		}
+	}
+	if err := batch.Put(archiveTimeKey(ch.Settled)+string(id[:]), ""); err != nil {
+		return errors.WithMessage(err, "putting time index")
+	}
+
+	return errors.WithMessage(batch.Apply(), "applying batch")
+}
+
+// ArchivedChannel returns the archived channel with the given ID.
+func (a *Archive) ArchivedChannel(_ context.Context, id channel.ID) (*persistence.ArchivedChannel, error) {
+	b, err := a.db.GetBytes(archivePrefix.ChannelDB + string(id[:]))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "could not find archived channel %x", id)
+	}
+
+	ch := new(persistence.ArchivedChannel)
+	buf := bytes.NewBuffer(b)
+	if err := (archivedChannelDec{ch}).Decode(buf); err != nil {
+		return nil, errors.WithMessagef(err, "decoding archived channel %x", id)
+	}
+	if buf.Len() != 0 {
+		return nil, errors.Errorf("decoding archived channel %x incomplete (%d bytes left)", id, buf.Len())
+	}
+	return ch, nil
+}
+
+// ArchivedChannels returns all archived channels that match the filter,
+// ordered by their settlement time. The most selective index that is set in the
+// filter is used for the lookup, the remaining criteria are checked on the
+// decoded channels.
+func (a *Archive) ArchivedChannels(ctx context.Context, f persistence.ArchiveFilter) ([]*persistence.ArchivedChannel, error) {
+	var it sortedkv.Iterator
+	switch {
+	case f.Peer != nil:
+		k, err := archivePeerKey(f.Peer)
+		if err != nil {
+			return nil, err
+		}
+		it = a.db.NewIteratorWithPrefix(k)
+	case f.Asset != nil:
+		k, err := archiveAssetKey(f.Asset)
+		if err != nil {
+			return nil, err
+		}
+		it = a.db.NewIteratorWithPrefix(k)
+	default:
+		start, end := archivePrefix.TimeDB, key.IncPrefix(archivePrefix.TimeDB)
+		if !f.From.IsZero() {
+			start = archiveTimeKey(f.From)
+		}
+		if !f.Until.IsZero() {
+			end = archiveTimeKey(f.Until)
+		}
+		it = a.db.NewIteratorWithRange(start, end)
+	}
+	defer it.Close()
+
+	var chs []*persistence.ArchivedChannel
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, errors.WithMessage(err, "querying archive")
+		}
+
+		k := it.Key()
+		if len(k) < len(channel.ID{}) {
+			return nil, errors.Errorf("invalid index key (%x)", k)
+		}
+		var id channel.ID
+		copy(id[:], k[len(k)-len(id):])
+
+		ch, err := a.ArchivedChannel(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if f.Matches(ch) {
+			chs = append(chs, ch)
+		}
+	}
+	if err := it.Close(); err != nil {
+		return nil, errors.WithMessage(err, "closing iterator")
+	}
+
+	sort.SliceStable(chs, func(i, j int) bool { return chs[i].Settled.Before(chs[j].Settled) })
+	return chs, nil
+}
+
+// archivePeerKey returns the prefix of all peer index keys of the given peer.
+func archivePeerKey(p map[wallet.BackendID]wire.Address) (string, error) {
+	var k bytes.Buffer
+	k.WriteString(archivePrefix.PeerDB)
+	if err := perunio.Encode(&k, wire.AddressDecMap(p)); err != nil {
+		return "", errors.WithMessage(err, "encoding peer address")
+	}
+	return k.String(), nil
+}
+
+// archiveAssetKey returns the prefix of all asset index keys of the given
+// asset. The asset is length-prefixed so that no key is a prefix of another.
+func archiveAssetKey(asset channel.Asset) (string, error) {
+	b, err := asset.MarshalBinary()
+	if err != nil {
+		return "", errors.WithMessage(err, "marshaling asset")
+	}
+	var k bytes.Buffer
+	k.WriteString(archivePrefix.AssetDB)
+	if err := perunio.Encode(&k, b); err != nil {
+		return "", errors.WithMessage(err, "encoding asset")
+	}
+	return k.String(), nil
+}
+
+// archiveTimeKey returns the prefix of the time index keys at time t. The keys
+// sort in chronological order.
+func archiveTimeKey(t time.Time) string {
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(t.UnixNano())) //nolint:gosec // Negative times are not supported.
+	return archivePrefix.TimeDB + string(ts[:])
+}
+
+type (
+	archivedChannelEnc struct {
+		*persistence.ArchivedChannel
+	}
+
+	archivedChannelDec struct {
+		*persistence.ArchivedChannel
+	}
+)
+
+func (ch archivedChannelEnc) Encode(w io.Writer) error {
+	return perunio.Encode(w,
+		ch.Idx,
+		ch.Params,
+		ch.State,
+		(*wire.AddressMapArray)(&ch.Peers),
+		optChannelIDEnc{ch.Parent},
+		ch.Settled,
+		ch.Archived,
+	)
+}
+
+func (ch archivedChannelDec) Decode(r io.Reader) error {
+	ch.Params = new(channel.Params)
+	return perunio.Decode(r,
+		&ch.Idx,
+		ch.Params,
+		&ch.State,
+		(*wire.AddressMapArray)(&ch.Peers),
+		optChannelIDDec{&ch.Parent},
+		&ch.Settled,
+		&ch.Archived,
+	)
+}
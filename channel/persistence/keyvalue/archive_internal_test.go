@@ -0,0 +1,129 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyvalue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	ctest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	wiretest "perun.network/go-perun/wire/test"
+	"polycry.pt/poly-go/sortedkv/memorydb"
+	pkgtest "polycry.pt/poly-go/test"
+)
+
+func TestArchive(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	ctx := context.Background()
+	a := NewArchive(memorydb.NewDatabase())
+
+	self, peerA, peerB := wiretest.NewRandomAddress(rng), wiretest.NewRandomAddress(rng), wiretest.NewRandomAddress(rng)
+	assetX := ctest.NewRandomAsset(rng, channel.TestBackendID)
+	assetY := ctest.NewRandomAsset(rng, channel.TestBackendID)
+	start := time.Unix(0, time.Now().UnixNano())
+
+	newArchived := func(peer map[wallet.BackendID]wire.Address, asset channel.Asset, settled time.Time, parent *channel.ID) *persistence.ArchivedChannel {
+		params, state := ctest.NewRandomParamsAndState(rng, ctest.WithNumParts(2), ctest.WithAssets(asset), ctest.WithNumLocked(0), ctest.WithIsFinal(true))
+		return &persistence.ArchivedChannel{
+			Idx:     0,
+			Params:  params,
+			State:   channel.Transaction{State: state, Sigs: make([]wallet.Sig, 2)},
+			Peers:   []map[wallet.BackendID]wire.Address{self, peer},
+			Parent:  parent,
+			Settled: settled,
+		}
+	}
+
+	chA := newArchived(peerA, assetX, start, nil)
+	chAID := chA.ID()
+	chs := []*persistence.ArchivedChannel{
+		chA,
+		newArchived(peerB, assetY, start.Add(time.Minute), nil),
+		newArchived(peerA, assetY, start.Add(2*time.Minute), &chAID),
+	}
+	for _, ch := range chs {
+		require.NoError(t, a.ChannelArchived(ctx, ch))
+	}
+
+	t.Run("single", func(t *testing.T) {
+		for _, ch := range chs {
+			ach, err := a.ArchivedChannel(ctx, ch.ID())
+			require.NoError(t, err)
+			requireEqualArchived(t, ch, ach)
+			assert.Equal(t, ch.State.Balances, ach.Outcome())
+		}
+		_, err := a.ArchivedChannel(ctx, ctest.NewRandomChannelID(rng))
+		assert.Error(t, err)
+	})
+
+	query := func(t *testing.T, f persistence.ArchiveFilter, expected ...*persistence.ArchivedChannel) {
+		t.Helper()
+		res, err := a.ArchivedChannels(ctx, f)
+		require.NoError(t, err)
+		require.Len(t, res, len(expected))
+		for i := range expected {
+			requireEqualArchived(t, expected[i], res[i])
+		}
+	}
+
+	t.Run("all", func(t *testing.T) {
+		query(t, persistence.ArchiveFilter{}, chs...)
+	})
+	t.Run("peer", func(t *testing.T) {
+		query(t, persistence.ArchiveFilter{Peer: peerA}, chs[0], chs[2])
+		query(t, persistence.ArchiveFilter{Peer: peerB}, chs[1])
+		query(t, persistence.ArchiveFilter{Peer: self}, chs...)
+	})
+	t.Run("asset", func(t *testing.T) {
+		query(t, persistence.ArchiveFilter{Asset: assetX}, chs[0])
+		query(t, persistence.ArchiveFilter{Asset: assetY}, chs[1], chs[2])
+		query(t, persistence.ArchiveFilter{Peer: peerA, Asset: assetY}, chs[2])
+	})
+	t.Run("idempotent", func(t *testing.T) {
+		again := *chs[0]
+		again.Settled = start.Add(time.Hour)
+		require.NoError(t, a.ChannelArchived(ctx, &again))
+		query(t, persistence.ArchiveFilter{}, chs...)
+	})
+	t.Run("time", func(t *testing.T) {
+		query(t, persistence.ArchiveFilter{From: start.Add(time.Minute)}, chs[1], chs[2])
+		query(t, persistence.ArchiveFilter{Until: start.Add(time.Minute)}, chs[0])
+		query(t, persistence.ArchiveFilter{From: start, Until: start.Add(2 * time.Minute)}, chs[0], chs[1])
+		query(t, persistence.ArchiveFilter{Peer: peerA, From: start.Add(time.Second)}, chs[2])
+	})
+}
+
+func requireEqualArchived(t *testing.T, expected, actual *persistence.ArchivedChannel) {
+	t.Helper()
+	require.Equal(t, expected.ID(), actual.ID())
+	require.Equal(t, expected.Idx, actual.Idx)
+	require.Equal(t, expected.Params, actual.Params)
+	require.Equal(t, expected.State.State, actual.State.State)
+	require.Equal(t, expected.Parent, actual.Parent)
+	require.True(t, expected.Settled.Equal(actual.Settled))
+	require.False(t, actual.Archived.IsZero())
+	require.Len(t, actual.Peers, len(expected.Peers))
+	for i := range expected.Peers {
+		require.True(t, channel.EqualWireMaps(expected.Peers[i], actual.Peers[i]))
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyvalue
+
+import (
+	"context"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence/batch"
+	"polycry.pt/poly-go/sortedkv"
+)
+
+var (
+	_ batch.Backend = (*PersistRestorer)(nil)
+	_ batch.Batch   = (*Batch)(nil)
+)
+
+// Batch collects the update calls of several channels in a single database
+// batch. It implements batch.Batch.
+type Batch struct {
+	b sortedkv.Batch
+}
+
+// NewBatch returns a new, empty Batch on the database.
+func (pr *PersistRestorer) NewBatch() batch.Batch {
+	return &Batch{b: pr.db.NewBatch()}
+}
+
+// Staged persists the staging transaction as well as the channel's phase.
+func (b *Batch) Staged(_ context.Context, s channel.Source) error {
+	if err := putAssetIndex(b.b, s); err != nil {
+		return err
+	}
+	return dbPutSource(b.channelWriter(s.ID()), s, stagedKeys...)
+}
+
+// SigAdded persists the signature of the given index.
+func (b *Batch) SigAdded(_ context.Context, s channel.Source, idx channel.Index) error {
+	return dbPutSource(b.channelWriter(s.ID()), s, sigAddedKeys(s, idx)...)
+}
+
+// Enabled persists the channel's staging and current transaction, and phase.
+func (b *Batch) Enabled(_ context.Context, s channel.Source) error {
+	if err := putAssetIndex(b.b, s); err != nil {
+		return err
+	}
+	return dbPutSource(b.channelWriter(s.ID()), s, enabledKeys(s)...)
+}
+
+// PhaseChanged persists the channel's phase.
+func (b *Batch) PhaseChanged(_ context.Context, s channel.Source) error {
+	return dbPut(b.channelWriter(s.ID()), "phase", s.Phase())
+}
+
+// Apply writes the batch to the database.
+func (b *Batch) Apply() error {
+	return b.b.Apply()
+}
+
+// channelWriter returns a writer that writes into the batch using the same
+// keys as channelDB.
+func (b *Batch) channelWriter(id channel.ID) sortedkv.Writer {
+	return prefixWriter{b.b, prefix.ChannelDB + string(id[:]) + ":"}
+}
+
+// prefixWriter prefixes all keys before writing them to w.
+type prefixWriter struct {
+	w      sortedkv.Writer
+	prefix string
+}
+
+func (p prefixWriter) Put(key, value string) error {
+	return p.w.Put(p.prefix+key, value)
+}
+
+func (p prefixWriter) PutBytes(key string, value []byte) error {
+	return p.w.PutBytes(p.prefix+key, value)
+}
+
+func (p prefixWriter) Delete(key string) error {
+	return p.w.Delete(p.prefix + key)
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyvalue
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	"polycry.pt/poly-go/sortedkv"
+)
+
+var _ persistence.CursorPersister = (*CursorStore)(nil)
+
+// CursorStore implements the persistence.CursorPersister interface on a sorted
+// key-value database. Each cursor is stored under its channel's ID.
+//
+// The database may be shared with a PersistRestorer because all keys of the
+// CursorStore use their own prefix.
+type CursorStore struct {
+	db sortedkv.Database
+}
+
+// cursorPrefix is the prefix of the cursors in the database.
+const cursorPrefix = "Cursor:"
+
+// NewCursorStore creates a new CursorStore using the given database.
+func NewCursorStore(db sortedkv.Database) *CursorStore {
+	return &CursorStore{db: sortedkv.NewTable(db, cursorPrefix)}
+}
+
+// CursorUpdated persists the cursor of the channel.
+func (s *CursorStore) CursorUpdated(_ context.Context, id channel.ID, cursor channel.EventCursor) error {
+	return errors.WithMessage(s.db.PutBytes(string(id[:]), cursor), "putting cursor")
+}
+
+// Cursor returns the persisted cursor of the channel, or nil if no cursor is
+// persisted.
+func (s *CursorStore) Cursor(_ context.Context, id channel.ID) (channel.EventCursor, error) {
+	if ok, err := s.db.Has(string(id[:])); err != nil {
+		return nil, errors.WithMessage(err, "checking cursor")
+	} else if !ok {
+		return nil, nil
+	}
+	cursor, err := s.db.GetBytes(string(id[:]))
+	if err != nil {
+		return nil, errors.WithMessage(err, "getting cursor")
+	}
+	return cursor, nil
+}
+
+// CursorRemoved deletes the cursor of the channel, if any.
+func (s *CursorStore) CursorRemoved(_ context.Context, id channel.ID) error {
+	if ok, err := s.db.Has(string(id[:])); err != nil || !ok {
+		return errors.WithMessage(err, "checking cursor")
+	}
+	return errors.WithMessage(s.db.Delete(string(id[:])), "deleting cursor")
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyvalue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	ctest "perun.network/go-perun/channel/test"
+	"polycry.pt/poly-go/sortedkv/memorydb"
+	pkgtest "polycry.pt/poly-go/test"
+)
+
+func TestCursorStore(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	ctx := context.Background()
+	db := memorydb.NewDatabase()
+	s := NewCursorStore(db)
+	id := ctest.NewRandomChannelID(rng)
+
+	cursor, err := s.Cursor(ctx, id)
+	require.NoError(t, err)
+	require.Nil(t, cursor)
+
+	for _, c := range []channel.EventCursor{{1, 2, 3}, {4}} {
+		require.NoError(t, s.CursorUpdated(ctx, id, c))
+		cursor, err = s.Cursor(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, c, cursor)
+	}
+
+	// The cursors do not interfere with the channels of a PersistRestorer on
+	// the same database.
+	it, err := NewPersistRestorer(db).RestoreAll()
+	require.NoError(t, err)
+	require.False(t, it.Next(ctx))
+	require.NoError(t, it.Close())
+
+	require.NoError(t, s.CursorRemoved(ctx, id))
+	require.NoError(t, s.CursorRemoved(ctx, id))
+	cursor, err = s.Cursor(ctx, id)
+	require.NoError(t, err)
+	require.Nil(t, cursor)
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyvalue
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	"perun.network/go-perun/wire/perunio"
+	"polycry.pt/poly-go/sortedkv"
+)
+
+var _ persistence.EquivocationStore = (*EquivocationStore)(nil)
+
+// EquivocationStore implements the persistence.EquivocationStore interface on
+// a sorted key-value database. The evidence is stored under the channel ID, the
+// version and the index of the equivocating participant.
+//
+// The database may be shared with a PersistRestorer because all keys of the
+// EquivocationStore use their own prefix.
+type EquivocationStore struct {
+	db sortedkv.Database
+}
+
+// equivocationPrefix is the prefix of the equivocations in the database.
+const equivocationPrefix = "Equivocation:"
+
+// NewEquivocationStore creates a new EquivocationStore using the given
+// database.
+func NewEquivocationStore(db sortedkv.Database) *EquivocationStore {
+	return &EquivocationStore{db: sortedkv.NewTable(db, equivocationPrefix)}
+}
+
+// EquivocationDetected stores the evidence. Evidence of the same participant
+// for the same version replaces the stored evidence.
+func (s *EquivocationStore) EquivocationDetected(_ context.Context, e *channel.Equivocation) error {
+	var buf bytes.Buffer
+	if err := perunio.Encode(&buf, e); err != nil {
+		return errors.WithMessage(err, "encoding equivocation")
+	}
+	return errors.WithMessage(s.db.PutBytes(equivocationKey(e), buf.Bytes()), "putting equivocation")
+}
+
+// Equivocations returns the evidence of all equivocations in the channel,
+// ordered by version.
+func (s *EquivocationStore) Equivocations(ctx context.Context, id channel.ID) ([]*channel.Equivocation, error) {
+	it := s.db.NewIteratorWithPrefix(string(id[:]))
+	defer it.Close()
+
+	var es []*channel.Equivocation
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, errors.WithMessage(err, "reading equivocations")
+		}
+		e := new(channel.Equivocation)
+		if err := perunio.Decode(bytes.NewReader(it.ValueBytes()), e); err != nil {
+			return nil, errors.WithMessagef(err, "decoding equivocation %x", it.Key())
+		}
+		es = append(es, e)
+	}
+	return es, errors.WithMessage(it.Close(), "closing iterator")
+}
+
+// equivocationKey returns the key of the evidence. The version is encoded in
+// big-endian so that the evidence of a channel is ordered by version.
+func equivocationKey(e *channel.Equivocation) string {
+	id := e.ID()
+	key := binary.BigEndian.AppendUint64(id[:], e.Version())
+	return string(binary.BigEndian.AppendUint16(key, uint16(e.Idx)))
+}
@@ -65,10 +65,25 @@ func (pr *PersistRestorer) ChannelCreated(_ context.Context, s channel.Source, p
 		}
 	}
 
+	// Register the channel in the secondary indexes.
+	idxdb := pr.db.NewBatch()
+	idxKeys, err := indexKeys(s, parent)
+	if err != nil {
+		return err
+	}
+	for _, key := range idxKeys {
+		if err := idxdb.Put(key, ""); err != nil {
+			return errors.WithMessage(err, "putting index")
+		}
+	}
+
 	if err := db.Apply(); err != nil {
 		return errors.WithMessage(err, "applying channel batch")
 	}
-	return errors.WithMessage(peerdb.Apply(), "applying peer batch")
+	if err := peerdb.Apply(); err != nil {
+		return errors.WithMessage(err, "applying peer batch")
+	}
+	return errors.WithMessage(idxdb.Apply(), "applying index batch")
 }
 
 // sigKey creates a key for given idx and number of channel
@@ -80,18 +95,19 @@ func sigKey(idx, numParts int) string {
 
 // ChannelRemoved deletes a channel from the database.
 func (pr *PersistRestorer) ChannelRemoved(ctx context.Context, id channel.ID) error {
-	db := pr.channelDB(id).NewBatch()
+	chdb := pr.channelDB(id)
+	db := chdb.NewBatch()
 	peerdb := sortedkv.NewTable(pr.db, prefix.PeerDB).NewBatch()
 	// All keys a channel has.
 	params, err := pr.paramsForChan(id)
 	if err != nil {
 		return err
 	}
-	keys := append([]string{"current", "index", "params", "peers", "phase", "staging:state"},
+	keys := append([]string{"current", "index", "params", "parent", "peers", "phase", "staging:state"},
 		sigKeys(len(params.Parts))...)
 
 	for _, key := range keys {
-		if err := db.Delete(key); err != nil {
+		if err := deleteIfExists(chdb, db, key); err != nil {
 			return errors.WithMessage(err, "batch deletion of "+key)
 		}
 	}
@@ -110,10 +126,34 @@ func (pr *PersistRestorer) ChannelRemoved(ctx context.Context, id channel.ID) er
 		}
 	}
 
+	// The index keys are derived from the stored records, so that a channel
+	// with missing or corrupt states can still be removed.
+	parent, err := pr.parentForChan(id)
+	if err != nil {
+		return err
+	}
+	idxKeys, err := paramsIndexKeys(&params, parent)
+	if err != nil {
+		return err
+	}
+	assetKeys, err := pr.storedAssetIndexKeys(ctx, id)
+	if err != nil {
+		return errors.WithMessage(err, "scanning asset index")
+	}
+	idxdb := pr.db.NewBatch()
+	for _, key := range append(idxKeys, assetKeys...) {
+		if err := deleteIfExists(pr.db, idxdb, key); err != nil {
+			return errors.WithMessage(err, "deleting index")
+		}
+	}
+
 	if err := db.Apply(); err != nil {
 		return errors.WithMessage(err, "applying channel batch")
 	}
-	return errors.WithMessage(peerdb.Apply(), "applying peer batch")
+	if err := peerdb.Apply(); err != nil {
+		return errors.WithMessage(err, "applying peer batch")
+	}
+	return errors.WithMessage(idxdb.Apply(), "applying index batch")
 }
 
 // paramsForChan returns the channel parameters for a given channel id from
@@ -128,6 +168,28 @@ func (pr *PersistRestorer) paramsForChan(id channel.ID) (channel.Params, error)
 		"unable to decode channel parameters")
 }
 
+// deleteIfExists adds the deletion of the key to the batch if the key is in
+// the database, because deleting a missing key fails in some databases.
+func deleteIfExists(db sortedkv.Reader, b sortedkv.Batch, key string) error {
+	if ok, err := db.Has(key); err != nil {
+		return errors.WithMessage(err, "checking key")
+	} else if !ok {
+		return nil
+	}
+	return b.Delete(key)
+}
+
+// parentForChan returns the parent of a given channel id from the db.
+func (pr *PersistRestorer) parentForChan(id channel.ID) (*channel.ID, error) {
+	var parent *channel.ID
+	b, err := pr.channelDB(id).GetBytes("parent")
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to retrieve parent from db")
+	}
+	return parent, errors.WithMessage(perunio.Decode(bytes.NewBuffer(b), optChannelIDDec{&parent}),
+		"unable to decode channel parent")
+}
+
 // sigKeys generates all db keys for signatures and returns them as a
 // slice of strings.
 func sigKeys(numParts int) []string {
@@ -141,9 +203,14 @@ func sigKeys(numParts int) []string {
 
 // Staged persists the staging transaction as well as the channel's phase.
 func (pr *PersistRestorer) Staged(_ context.Context, s channel.Source) error {
+	// Index entries without channel data are skipped by queries, so the index
+	// is written first.
+	if err := putAssetIndex(pr.db, s); err != nil {
+		return err
+	}
 	db := pr.channelDB(s.ID()).NewBatch()
 
-	if err := dbPutSource(db, s, "staging:state", "phase"); err != nil {
+	if err := dbPutSource(db, s, stagedKeys...); err != nil {
 		return err
 	}
 
@@ -154,9 +221,7 @@ func (pr *PersistRestorer) Staged(_ context.Context, s channel.Source) error {
 func (pr *PersistRestorer) SigAdded(_ context.Context, s channel.Source, idx channel.Index) error {
 	db := pr.channelDB(s.ID()).NewBatch()
 
-	numParts := len(s.Params().Parts)
-	key := sigKey(int(idx), numParts)
-	if err := dbPutSource(db, s, key); err != nil {
+	if err := dbPutSource(db, s, sigAddedKeys(s, idx)...); err != nil {
 		return err
 	}
 
@@ -165,11 +230,12 @@ func (pr *PersistRestorer) SigAdded(_ context.Context, s channel.Source, idx cha
 
 // Enabled persists the channel's staging and current transaction, and phase.
 func (pr *PersistRestorer) Enabled(_ context.Context, s channel.Source) error {
+	if err := putAssetIndex(pr.db, s); err != nil {
+		return err
+	}
 	db := pr.channelDB(s.ID()).NewBatch()
 
-	numParts := len(s.Params().Parts)
-	keys := append([]string{"staging:state", "current", "phase"}, sigKeys(numParts)...)
-	if err := dbPutSource(db, s, keys...); err != nil {
+	if err := dbPutSource(db, s, enabledKeys(s)...); err != nil {
 		return err
 	}
 	return errors.WithMessage(db.Apply(), "applying batch")
@@ -180,6 +246,19 @@ func (pr *PersistRestorer) PhaseChanged(_ context.Context, s channel.Source) err
 	return dbPut(pr.channelDB(s.ID()), "phase", s.Phase())
 }
 
+// stagedKeys are the keys that are written by Staged.
+var stagedKeys = []string{"staging:state", "phase"}
+
+// sigAddedKeys returns the keys that are written by SigAdded.
+func sigAddedKeys(s channel.Source, idx channel.Index) []string {
+	return []string{sigKey(int(idx), len(s.Params().Parts))}
+}
+
+// enabledKeys returns the keys that are written by Enabled.
+func enabledKeys(s channel.Source) []string {
+	return append([]string{"staging:state", "current", "phase"}, sigKeys(len(s.Params().Parts))...)
+}
+
 func dbPutSource(db sortedkv.Writer, s channel.Source, keys ...string) error {
 	for _, key := range keys {
 		if err := dbPutSourceField(db, s, key); err != nil {
@@ -23,6 +23,9 @@ import (
 
 	_ "perun.network/go-perun/backend/sim" // backend init
 	"perun.network/go-perun/channel/persistence/test"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	wiretest "perun.network/go-perun/wire/test"
 	"polycry.pt/poly-go/sortedkv"
 	"polycry.pt/poly-go/sortedkv/leveldb"
 	"polycry.pt/poly-go/sortedkv/memorydb"
@@ -55,6 +58,50 @@ func TestPersistRestorer_Generic(t *testing.T) {
 	}
 }
 
+func TestPersistRestorer_ChannelRemoved(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	ctx := context.Background()
+	db := memorydb.NewDatabase()
+	pr := NewPersistRestorer(db)
+
+	peers := []map[wallet.BackendID]wire.Address{wiretest.NewRandomAddress(rng), wiretest.NewRandomAddress(rng)}
+	parent := test.NewRandomChannel(ctx, t, pr, 0, peers, nil, rng)
+	sub := test.NewRandomChannel(ctx, t, pr, 0, peers, parent, rng)
+	require.NoError(t, pr.ChannelRemoved(ctx, sub.ID()))
+	require.NoError(t, pr.ChannelRemoved(ctx, parent.ID()))
+
+	// No key of the removed channels is left behind.
+	it := db.NewIterator()
+	defer it.Close()
+	for it.Next() {
+		t.Errorf("unexpected key %q", it.Key())
+	}
+}
+
+func TestPersistRestorer_ChannelRemovedWithoutState(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	ctx := context.Background()
+	db := memorydb.NewDatabase()
+	pr := NewPersistRestorer(db)
+
+	ch := test.NewClient(ctx, t, rng, pr).NewChannel(t, wiretest.NewRandomAddress(rng), nil)
+	ch.Init(ctx, t, rng)
+	assetKeys, err := pr.storedAssetIndexKeys(ctx, ch.ID())
+	require.NoError(t, err)
+	require.NotEmpty(t, assetKeys)
+	// The channel's state records are lost, so it cannot be restored.
+	require.NoError(t, pr.channelDB(ch.ID()).Delete("current"))
+	_, err = pr.RestoreChannel(ctx, ch.ID())
+	require.Error(t, err)
+
+	require.NoError(t, pr.ChannelRemoved(ctx, ch.ID()))
+	it := db.NewIterator()
+	defer it.Close()
+	for it.Next() {
+		t.Errorf("unexpected key %q", it.Key())
+	}
+}
+
 func TestChannelIterator_Next_Empty(t *testing.T) {
 	var it ChannelIterator
 	var success bool
@@ -0,0 +1,172 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyvalue
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	"perun.network/go-perun/wire/perunio"
+	"polycry.pt/poly-go/sortedkv"
+)
+
+// Prune removes peer and secondary index entries that point to channels
+// without parameters and the remaining data of such channels. This data is left behind if the
+// node crashed while removing a channel and can never be restored.
+// It returns the number of deleted keys.
+func (pr *PersistRestorer) Prune(ctx context.Context) (int, error) {
+	n, err := pr.pruneChannels(ctx)
+	if err != nil {
+		return n, err
+	}
+	m, err := pr.prunePeers(ctx)
+	if err != nil {
+		return n + m, err
+	}
+	k, err := pr.pruneIndexes(ctx)
+	return n + m + k, err
+}
+
+// pruneChannels deletes all keys of channels that have no parameters.
+func (pr *PersistRestorer) pruneChannels(ctx context.Context) (int, error) {
+	chandb := sortedkv.NewTable(pr.db, prefix.ChannelDB)
+	it := chandb.NewIterator()
+	defer it.Close()
+
+	dangling := make(map[channel.ID][]string)
+	var id channel.ID
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		key := it.Key()
+		if len(key) <= len(id) {
+			dangling[id] = append(dangling[id], key)
+			continue
+		}
+		copy(id[:], key[:len(id)])
+		if ok, err := pr.channelDB(id).Has("params"); err != nil {
+			return 0, errors.WithMessage(err, "checking channel params")
+		} else if !ok {
+			dangling[id] = append(dangling[id], key)
+		}
+	}
+	if err := it.Close(); err != nil {
+		return 0, errors.WithMessage(err, "closing iterator")
+	}
+
+	batch := chandb.NewBatch()
+	n := 0
+	for _, keys := range dangling {
+		for _, key := range keys {
+			if err := batch.Delete(key); err != nil {
+				return 0, errors.WithMessage(err, "deleting "+key)
+			}
+			n++
+		}
+	}
+	return n, errors.WithMessage(batch.Apply(), "applying channel batch")
+}
+
+// prunePeers deletes all peer index entries whose channel has no parameters.
+func (pr *PersistRestorer) prunePeers(ctx context.Context) (int, error) {
+	peerdb := sortedkv.NewTable(pr.db, prefix.PeerDB)
+	it := peerdb.NewIterator()
+	defer it.Close()
+
+	var dangling []string
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		id, err := decodePeerChannelKey(it.Key())
+		if err != nil {
+			return 0, err
+		}
+		if ok, err := pr.channelDB(id).Has("params"); err != nil {
+			return 0, errors.WithMessage(err, "checking channel params")
+		} else if !ok {
+			dangling = append(dangling, it.Key())
+		}
+	}
+	if err := it.Close(); err != nil {
+		return 0, errors.WithMessage(err, "closing iterator")
+	}
+
+	batch := peerdb.NewBatch()
+	for _, key := range dangling {
+		if err := batch.Delete(key); err != nil {
+			return 0, errors.WithMessage(err, "deleting peer channel")
+		}
+	}
+	return len(dangling), errors.WithMessage(batch.Apply(), "applying peer batch")
+}
+
+// pruneIndexes deletes all secondary index entries whose channel has no
+// parameters.
+func (pr *PersistRestorer) pruneIndexes(ctx context.Context) (int, error) {
+	it := pr.db.NewIteratorWithPrefix(indexPrefix.IndexDB)
+	defer it.Close()
+
+	var dangling []string
+	var id channel.ID
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		key := it.Key()
+		if len(key) < len(id) {
+			return 0, errors.Errorf("invalid index key (%x)", key)
+		}
+		copy(id[:], key[len(key)-len(id):])
+		if ok, err := pr.channelDB(id).Has("params"); err != nil {
+			return 0, errors.WithMessage(err, "checking channel params")
+		} else if !ok {
+			dangling = append(dangling, key)
+		}
+	}
+	if err := it.Close(); err != nil {
+		return 0, errors.WithMessage(err, "closing iterator")
+	}
+
+	batch := pr.db.NewBatch()
+	for _, key := range dangling {
+		if err := batch.Delete(key); err != nil {
+			return 0, errors.WithMessage(err, "deleting index")
+		}
+	}
+	return len(dangling), errors.WithMessage(batch.Apply(), "applying index batch")
+}
+
+// decodePeerChannelKey returns the channel ID of a key created by
+// peerChannelKey.
+func decodePeerChannelKey(key string) (id channel.ID, err error) {
+	buf := bytes.NewBufferString(key)
+	var addr map[wallet.BackendID]wire.Address
+	if err := perunio.Decode(buf, (*wire.AddressDecMap)(&addr)); err != nil {
+		return id, errors.WithMessagef(err, "decoding peer key (%x)", key)
+	}
+	sep := []byte(":channel:")
+	if !bytes.HasPrefix(buf.Bytes(), sep) {
+		return id, errors.Errorf("invalid peer key (%x)", key)
+	}
+	buf.Next(len(sep))
+	return id, errors.WithMessage(perunio.Decode(buf, &id), "decoding channel id")
+}
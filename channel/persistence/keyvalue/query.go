@@ -0,0 +1,264 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyvalue
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	"perun.network/go-perun/wire/perunio"
+	"polycry.pt/poly-go/sortedkv"
+	"polycry.pt/poly-go/sortedkv/key"
+)
+
+var _ persistence.Querier = (*PersistRestorer)(nil)
+
+// indexPrefix are the prefixes of the secondary channel indexes. Every index
+// key is the index prefix, the indexed value and the channel ID. Only
+// attributes that never change during a channel's lifetime are indexed.
+var indexPrefix = struct{ IndexDB, Kind, Asset, App, Parent string }{
+	IndexDB: "Index:",
+	Kind:    "Index:Kind:",
+	Asset:   "Index:Asset:",
+	App:     "Index:App:",
+	Parent:  "Index:Parent:",
+}
+
+// QueryChannels returns the channels that match the query, ordered by their
+// ID. The most selective index that is set in the query is used for the
+// lookup, the remaining criteria, including the phase, are checked on the
+// restored channels. Without any indexed criterion, all channels are scanned.
+func (pr *PersistRestorer) QueryChannels(ctx context.Context, q persistence.ChannelQuery) ([]*persistence.Channel, error) {
+	var pre string
+	switch {
+	case q.Parent != nil:
+		pre = indexPrefix.Parent + string(q.Parent[:])
+	case q.App != nil:
+		k, err := indexKey(indexPrefix.App, q.App)
+		if err != nil {
+			return nil, err
+		}
+		pre = k
+	case q.Asset != nil:
+		k, err := indexKey(indexPrefix.Asset, q.Asset)
+		if err != nil {
+			return nil, err
+		}
+		pre = k
+	case q.Kind != persistence.AnyChannel:
+		pre = indexPrefix.Kind + string([]byte{byte(q.Kind)})
+	default:
+		return pr.scanChannels(ctx, q)
+	}
+
+	start := pre
+	if q.After != nil {
+		start = key.IncPrefix(pre + string(q.After[:]))
+	}
+	it := pr.db.NewIteratorWithRange(start, key.IncPrefix(pre))
+	defer it.Close()
+
+	var chs []*persistence.Channel
+	for (q.Limit <= 0 || len(chs) < q.Limit) && it.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, errors.WithMessage(err, "querying channels")
+		}
+
+		k := it.Key()
+		if len(k) != len(pre)+len(channel.ID{}) {
+			return nil, errors.Errorf("invalid index key (%x)", k)
+		}
+		var id channel.ID
+		copy(id[:], k[len(pre):])
+
+		// Index entries of removed channels are left behind if the node
+		// crashed while removing the channel. They are skipped.
+		if ok, err := pr.channelDB(id).Has("params"); err != nil {
+			return nil, errors.WithMessage(err, "checking channel params")
+		} else if !ok {
+			continue
+		}
+		ch, err := pr.RestoreChannel(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if q.Matches(ch) {
+			chs = append(chs, ch)
+		}
+	}
+	return chs, errors.WithMessage(it.Close(), "closing iterator")
+}
+
+// scanChannels restores all channels in the order of their IDs and returns the
+// ones that match the query.
+func (pr *PersistRestorer) scanChannels(ctx context.Context, q persistence.ChannelQuery) ([]*persistence.Channel, error) {
+	var start string
+	if q.After != nil {
+		start = key.IncPrefix(string(q.After[:]))
+	}
+	it := &ChannelIterator{
+		restorer: pr,
+		its:      []sortedkv.Iterator{sortedkv.NewTable(pr.db, prefix.ChannelDB).NewIteratorWithRange(start, "")},
+	}
+
+	var chs []*persistence.Channel
+	for (q.Limit <= 0 || len(chs) < q.Limit) && it.Next(ctx) {
+		if q.Matches(it.Channel()) {
+			chs = append(chs, it.Channel())
+		}
+	}
+	return chs, errors.WithMessage(it.Close(), "restoring channels")
+}
+
+// indexKeys returns all index keys of the channel. The assets are taken from
+// the current and the staging state, see assetIndexKeys.
+func indexKeys(s channel.Source, parent *channel.ID) ([]string, error) {
+	keys, err := paramsIndexKeys(s.Params(), parent)
+	if err != nil {
+		return nil, err
+	}
+	assetKeys, err := assetIndexKeys(s)
+	if err != nil {
+		return nil, err
+	}
+	return append(keys, assetKeys...), nil
+}
+
+// paramsIndexKeys returns the index keys of the channel that only depend on
+// its parameters and parent, i.e., all but the asset index keys.
+func paramsIndexKeys(params *channel.Params, parent *channel.ID) ([]string, error) {
+	id := params.ID()
+	kind := persistence.KindOf(params, parent)
+	keys := []string{indexPrefix.Kind + string([]byte{byte(kind)}) + string(id[:])}
+	if parent != nil {
+		keys = append(keys, indexPrefix.Parent+string(parent[:])+string(id[:]))
+	}
+	if app := params.App; !channel.IsNoApp(app) {
+		k, err := indexKey(indexPrefix.App, app.Def())
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k+string(id[:]))
+	}
+	return keys, nil
+}
+
+// assetIndexKeys returns the asset index keys of the channel's current and
+// staging state. A channel has no state when it is created, so the assets are
+// indexed when a state is staged or enabled.
+func assetIndexKeys(s channel.Source) ([]string, error) {
+	id := s.ID()
+	var keys []string
+	seen := make(map[string]struct{})
+	for _, state := range []*channel.State{s.CurrentTX().State, s.StagingTX().State} {
+		if state == nil {
+			continue
+		}
+		for _, asset := range state.Assets {
+			k, err := indexKey(indexPrefix.Asset, asset)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				keys = append(keys, k+string(id[:]))
+			}
+		}
+	}
+	return keys, nil
+}
+
+// storedAssetIndexKeys returns the asset index keys of the channel that are
+// in the database. The asset index is scanned for the channel ID, so that the
+// keys are found without the channel's states.
+func (pr *PersistRestorer) storedAssetIndexKeys(ctx context.Context, id channel.ID) ([]string, error) {
+	it := pr.db.NewIteratorWithPrefix(indexPrefix.Asset)
+	defer it.Close()
+
+	var keys []string
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if key := it.Key(); strings.HasSuffix(key, string(id[:])) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, errors.WithMessage(it.Close(), "closing iterator")
+}
+
+// putAssetIndex writes the asset index keys of the channel to w.
+func putAssetIndex(w sortedkv.Writer, s channel.Source) error {
+	keys, err := assetIndexKeys(s)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := w.Put(k, ""); err != nil {
+			return errors.WithMessage(err, "putting index")
+		}
+	}
+	return nil
+}
+
+// indexKey returns the prefix of all index keys of the given value. The value
+// is length-prefixed so that no key is a prefix of another.
+func indexKey(pre string, v encoding.BinaryMarshaler) (string, error) {
+	b, err := v.MarshalBinary()
+	if err != nil {
+		return "", errors.WithMessage(err, "marshaling index value")
+	}
+	var k bytes.Buffer
+	k.WriteString(pre)
+	if err := perunio.Encode(&k, b); err != nil {
+		return "", errors.WithMessage(err, "encoding index value")
+	}
+	return k.String(), nil
+}
+
+// Reindex rebuilds the secondary channel indexes of all persisted channels.
+// It needs to be called once on databases that were written before the
+// indexes were introduced.
+func (pr *PersistRestorer) Reindex(ctx context.Context) error {
+	it, err := pr.RestoreAll()
+	if err != nil {
+		return err
+	}
+	batch := pr.db.NewBatch()
+	for it.Next(ctx) {
+		ch := it.Channel()
+		keys, err := indexKeys(ch, ch.Parent)
+		if err != nil {
+			it.Close()
+			return err
+		}
+		for _, k := range keys {
+			if err := batch.Put(k, ""); err != nil {
+				it.Close()
+				return errors.WithMessage(err, "putting index")
+			}
+		}
+	}
+	if err := it.Close(); err != nil {
+		return errors.WithMessage(err, "restoring channels")
+	}
+	return errors.WithMessage(batch.Apply(), "applying index batch")
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyvalue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	"perun.network/go-perun/channel/persistence/test"
+	wiretest "perun.network/go-perun/wire/test"
+	"polycry.pt/poly-go/sortedkv/memorydb"
+	pkgtest "polycry.pt/poly-go/test"
+)
+
+func TestPersistRestorer_QueryChannels(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	ctx := context.Background()
+	db := memorydb.NewDatabase()
+	pr := NewPersistRestorer(db)
+	c := test.NewClient(ctx, t, rng, pr)
+
+	var chs []*test.Channel
+	for i := range 12 {
+		var parent *test.Channel
+		if i%3 == 2 {
+			parent = chs[i-1]
+		}
+		ch := c.NewChannel(t, wiretest.NewRandomAddress(rng), parent)
+		if i%2 == 0 {
+			ch.Init(ctx, t, rng)
+		}
+		chs = append(chs, ch)
+	}
+
+	parent := chs[1].ID()
+	after := chs[5].ID()
+	state := chs[4].StagingState()
+	queries := map[string]persistence.ChannelQuery{
+		"all":      {},
+		"phase":    {Phases: []channel.Phase{channel.InitSigning}},
+		"kind":     {Kind: persistence.LedgerChannel},
+		"parent":   {Parent: &parent},
+		"asset":    {Asset: state.Assets[0]},
+		"combined": {Kind: persistence.SubChannel, Phases: []channel.Phase{channel.InitActing}},
+		"after":    {Kind: persistence.LedgerChannel, After: &after},
+	}
+	if !channel.IsNoApp(chs[4].Params().App) {
+		queries["app"] = persistence.ChannelQuery{App: chs[4].Params().App.Def()}
+	}
+
+	for name, q := range queries {
+		t.Run(name, func(t *testing.T) {
+			// The generic implementation restores all channels and is the
+			// reference for the indexed lookup.
+			expected, err := persistence.QueryChannels(ctx, restorerOnly{pr}, q)
+			require.NoError(t, err)
+			actual, err := pr.QueryChannels(ctx, q)
+			require.NoError(t, err)
+			assert.Equal(t, channelIDs(expected), channelIDs(actual))
+
+			// Paging through the results yields the same channels.
+			var paged []*persistence.Channel
+			for {
+				page := q
+				page.Limit = 2
+				if len(paged) > 0 {
+					last := paged[len(paged)-1].ID()
+					page.After = &last
+				}
+				res, err := pr.QueryChannels(ctx, page)
+				require.NoError(t, err)
+				require.LessOrEqual(t, len(res), 2)
+				if len(res) == 0 {
+					break
+				}
+				paged = append(paged, res...)
+			}
+			assert.Equal(t, channelIDs(actual), channelIDs(paged))
+		})
+	}
+
+	t.Run("removed", func(t *testing.T) {
+		for _, ch := range chs {
+			require.NoError(t, pr.ChannelRemoved(ctx, ch.ID()))
+		}
+		res, err := pr.QueryChannels(ctx, persistence.ChannelQuery{Kind: persistence.LedgerChannel})
+		require.NoError(t, err)
+		assert.Empty(t, res)
+
+		it := db.NewIteratorWithPrefix(indexPrefix.IndexDB)
+		assert.False(t, it.Next(), "index entries left after removal")
+		require.NoError(t, it.Close())
+	})
+}
+
+// restorerOnly hides the Querier implementation of a PersistRestorer.
+type restorerOnly struct {
+	persistence.Restorer
+}
+
+func channelIDs(chs []*persistence.Channel) []channel.ID {
+	ids := make([]channel.ID, len(chs))
+	for i, ch := range chs {
+		ids[i] = ch.ID()
+	}
+	return ids
+}
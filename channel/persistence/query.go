@@ -0,0 +1,184 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persistence
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+)
+
+// Channel kinds that can be queried.
+const (
+	AnyChannel ChannelKind = iota
+	LedgerChannel
+	SubChannel
+	VirtualChannel
+)
+
+type (
+	// A Querier is a Restorer that can list the persisted channels that match
+	// a ChannelQuery.
+	Querier interface {
+		Restorer
+
+		// QueryChannels returns the persisted channels that match the query,
+		// ordered by their ID.
+		QueryChannels(context.Context, ChannelQuery) ([]*Channel, error)
+	}
+
+	// ChannelKind distinguishes ledger channels, sub-channels and virtual
+	// channels.
+	ChannelKind uint8
+
+	// A ChannelQuery restricts the channels returned by a Querier. Unset
+	// fields match all channels.
+	//
+	// Results are ordered by channel ID. A page of results is requested by
+	// setting Limit and passing the ID of the last channel of the previous page
+	// as After.
+	ChannelQuery struct {
+		Phases []channel.Phase // Phases contains the allowed phases.
+		Asset  channel.Asset   // Asset must be held in the channel.
+		App    channel.AppID   // App is the channel's app. NoApp channels never match.
+		Parent *channel.ID     // Parent is the parent channel's ID.
+		Kind   ChannelKind     // Kind is the kind of the channel.
+
+		After *channel.ID // After is the exclusive lower bound of the channel IDs.
+		Limit int         // Limit is the maximum number of results, if positive.
+	}
+)
+
+// KindOf returns the kind of the channel with the given parameters and parent.
+func KindOf(params *channel.Params, parent *channel.ID) ChannelKind {
+	switch {
+	case params.VirtualChannel:
+		return VirtualChannel
+	case parent != nil:
+		return SubChannel
+	default:
+		return LedgerChannel
+	}
+}
+
+// String returns the name of the kind.
+func (k ChannelKind) String() string {
+	return [...]string{"AnyChannel", "LedgerChannel", "SubChannel", "VirtualChannel"}[k]
+}
+
+// Matches returns whether the persisted channel matches the query.
+func (q ChannelQuery) Matches(ch *Channel) bool {
+	return q.MatchesSource(ch, KindOf(ch.ParamsV, ch.Parent), ch.Parent)
+}
+
+// MatchesSource returns whether the channel with the given source, kind and
+// parent matches the query. The asset and app are checked on the current
+// state or, if there is none yet, on the staging state.
+func (q ChannelQuery) MatchesSource(s channel.Source, kind ChannelKind, parent *channel.ID) bool {
+	if q.After != nil {
+		if id := s.ID(); bytes.Compare(id[:], q.After[:]) <= 0 {
+			return false
+		}
+	}
+	if q.Kind != AnyChannel && q.Kind != kind {
+		return false
+	}
+	if q.Parent != nil && (parent == nil || *parent != *q.Parent) {
+		return false
+	}
+	if len(q.Phases) > 0 && !containsPhase(q.Phases, s.Phase()) {
+		return false
+	}
+	if q.Asset == nil && q.App == nil {
+		return true
+	}
+
+	state := s.CurrentTX().State
+	if state == nil {
+		state = s.StagingTX().State
+	}
+	if state == nil {
+		return false
+	}
+	if q.Asset != nil {
+		if _, ok := state.AssetIndex(q.Asset); !ok {
+			return false
+		}
+	}
+	if q.App != nil && (channel.IsNoApp(state.App) || !state.App.Def().Equal(q.App)) {
+		return false
+	}
+	return true
+}
+
+func containsPhase(phases []channel.Phase, p channel.Phase) bool {
+	for _, q := range phases {
+		if p == q {
+			return true
+		}
+	}
+	return false
+}
+
+// QueryChannels returns the channels of the Restorer that match the query,
+// ordered by their ID. If the Restorer is a Querier, the query is delegated
+// to it. Otherwise, the channels of all active peers are restored and
+// filtered.
+func QueryChannels(ctx context.Context, r Restorer, q ChannelQuery) ([]*Channel, error) {
+	if qr, ok := r.(Querier); ok {
+		return qr.QueryChannels(ctx, q)
+	}
+
+	peers, err := r.ActivePeers(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "restoring active peers")
+	}
+	seen := make(map[channel.ID]struct{})
+	var chs []*Channel
+	for _, peer := range peers {
+		if err := func() error {
+			it, err := r.RestorePeer(peer)
+			if err != nil {
+				return errors.WithMessage(err, "restoring peer channels")
+			}
+			for it.Next(ctx) {
+				ch := it.Channel()
+				if _, ok := seen[ch.ID()]; ok {
+					continue
+				}
+				seen[ch.ID()] = struct{}{}
+				if q.Matches(ch) {
+					chs = append(chs, ch)
+				}
+			}
+			return it.Close()
+		}(); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(chs, func(i, j int) bool {
+		a, b := chs[i].ID(), chs[j].ID()
+		return bytes.Compare(a[:], b[:]) < 0
+	})
+	if q.Limit > 0 && len(chs) > q.Limit {
+		chs = chs[:q.Limit]
+	}
+	return chs, nil
+}
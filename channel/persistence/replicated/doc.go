@@ -0,0 +1,26 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package replicated implements a PersistRestorer that replicates all channel
+// data to several underlying PersistRestorers.
+//
+// Writes are acknowledged once a quorum of replicas persisted them. On
+// restore, all replicas are read, waiting only a short grace period for slow
+// replicas once a read quorum answered, and, per channel, the data with the
+// highest version that carries valid signatures of all participants is used.
+// A channel that is missing on a read quorum of replicas counts as removed.
+// Replicas that hold older, missing or removed data for a channel are reported
+// as divergent. A replica that falls too far behind is marked failed and not
+// used anymore.
+package replicated // import "perun.network/go-perun/channel/persistence/replicated"
@@ -0,0 +1,367 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replicated
+
+import (
+	"context"
+	stdsync "sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+)
+
+var _ persistence.PersistRestorer = (*PersistRestorer)(nil)
+
+type (
+	// PersistRestorer is a persistence.PersistRestorer that replicates all
+	// writes to several underlying PersistRestorers.
+	//
+	// Every replica processes the writes in the order in which they were made,
+	// so the per-channel ordering guarantee of the Persister interface holds
+	// for every replica. A write returns as soon as a quorum of replicas
+	// succeeded. Writes to slower replicas continue in the background.
+	PersistRestorer struct {
+		replicas []*replica
+		quorum   int
+
+		mu           stdsync.Mutex
+		onDivergence func(Divergence)
+		limits       Limits
+	}
+
+	// Limits configures how long reads wait for slow replicas and how many
+	// operations may be queued for a replica.
+	Limits struct {
+		// ReadGracePeriod is how long a read waits for the remaining replicas
+		// once a read quorum answered, so that their divergences are detected.
+		ReadGracePeriod time.Duration
+		// RestorePeerTimeout bounds RestorePeer, which is not passed a context.
+		RestorePeerTimeout time.Duration
+		// MaxQueue is the maximum number of operations that may be queued for
+		// a replica. A replica that exceeds it is marked failed, its queued
+		// operations fail and it is not used anymore. Zero means no limit.
+		MaxQueue int
+	}
+
+	// replica processes the operations for one underlying PersistRestorer in
+	// order.
+	replica struct {
+		pr persistence.PersistRestorer
+
+		mu     stdsync.Mutex
+		cond   *stdsync.Cond
+		queue  []func(error) // Operations are called with an error if they are dropped.
+		closed bool
+		failed bool
+		done   chan struct{}
+	}
+
+	// QuorumError is returned if a write or read did not succeed on enough
+	// replicas.
+	QuorumError struct {
+		Succeeded, Required int
+		Errs                []error // Errs are the errors of the failed replicas.
+	}
+
+	// result is the result of an operation on a single replica.
+	result struct {
+		replica int
+		val     interface{}
+		err     error
+	}
+)
+
+// DefaultLimits returns limits with a read grace period of 100 milliseconds,
+// a RestorePeer timeout of 30 seconds and at most 4096 queued operations per
+// replica.
+func DefaultLimits() Limits {
+	return Limits{
+		ReadGracePeriod:    100 * time.Millisecond, //nolint:mnd
+		RestorePeerTimeout: 30 * time.Second,       //nolint:mnd
+		MaxQueue:           1 << 12,                //nolint:mnd
+	}
+}
+
+// NewPersistRestorer creates a PersistRestorer on the given replicas. Writes
+// are acknowledged once quorum replicas succeeded. Reads need
+// len(replicas)-quorum+1 replicas, so that every read sees at least one
+// replica that acknowledged the latest write. The DefaultLimits are used.
+func NewPersistRestorer(quorum int, replicas ...persistence.PersistRestorer) *PersistRestorer {
+	if quorum < 1 || quorum > len(replicas) {
+		panic("quorum must be between 1 and the number of replicas")
+	}
+	pr := &PersistRestorer{
+		replicas: make([]*replica, len(replicas)),
+		quorum:   quorum,
+		limits:   DefaultLimits(),
+	}
+	for i, r := range replicas {
+		pr.replicas[i] = newReplica(r)
+	}
+	return pr
+}
+
+// SetLimits sets the limits of the PersistRestorer. It is expected to be
+// called during setup, before the PersistRestorer is used.
+func (pr *PersistRestorer) SetLimits(limits Limits) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.limits = limits
+}
+
+func (pr *PersistRestorer) getLimits() Limits {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	return pr.limits
+}
+
+func newReplica(pr persistence.PersistRestorer) *replica {
+	r := &replica{pr: pr, done: make(chan struct{})}
+	r.cond = stdsync.NewCond(&r.mu)
+	go r.run()
+	return r
+}
+
+// run executes the queued operations until the replica is closed and its
+// queue is empty.
+func (r *replica) run() {
+	defer close(r.done)
+	for {
+		r.mu.Lock()
+		for len(r.queue) == 0 && !r.closed {
+			r.cond.Wait()
+		}
+		if len(r.queue) == 0 {
+			r.mu.Unlock()
+			return
+		}
+		op := r.queue[0]
+		r.queue = r.queue[1:]
+		r.mu.Unlock()
+
+		op(nil)
+	}
+}
+
+// push queues the operation. It fails if the replica is closed or failed. If
+// the queue already holds maxQueue operations, the replica is marked failed
+// and all queued operations are called with an error.
+func (r *replica) push(op func(error), maxQueue int) error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return errors.New("replica closed")
+	}
+	if r.failed {
+		r.mu.Unlock()
+		return errReplicaFailed
+	}
+	if maxQueue > 0 && len(r.queue) >= maxQueue {
+		r.failed = true
+		dropped := r.queue
+		r.queue = nil
+		r.mu.Unlock()
+		log.Errorf("Marking replica as failed: %d operations queued", len(dropped))
+		for _, op := range dropped {
+			op(errReplicaFailed)
+		}
+		return errReplicaFailed
+	}
+	r.queue = append(r.queue, op)
+	r.cond.Signal()
+	r.mu.Unlock()
+	return nil
+}
+
+// errReplicaFailed is the error of operations on a failed replica.
+var errReplicaFailed = errors.New("replica failed: too many queued operations")
+
+// close stops the replica after its queued operations are done. The
+// operation that a failed replica may still be stuck in is not waited for.
+func (r *replica) close() {
+	r.mu.Lock()
+	r.closed = true
+	failed := r.failed
+	r.cond.Signal()
+	r.mu.Unlock()
+	if !failed {
+		<-r.done
+	}
+}
+
+func (e *QuorumError) Error() string {
+	return errors.Errorf("quorum not reached: %d of %d required replicas succeeded: %v",
+		e.Succeeded, e.Required, e.Errs).Error()
+}
+
+// write queues the write on all replicas and waits until quorum replicas
+// succeeded or a quorum cannot be reached anymore.
+func (pr *PersistRestorer) write(ctx context.Context, op func(context.Context, persistence.PersistRestorer) error) error {
+	_, err := pr.do(ctx, pr.quorum, func(ctx context.Context, r persistence.PersistRestorer) (interface{}, error) {
+		return nil, op(ctx, r)
+	})
+	return err
+}
+
+// do queues the operation on all replicas and waits until required replicas
+// succeeded or the required number cannot be reached anymore. It returns the
+// successful results. The operation is called with a context that is not
+// canceled together with ctx, because it outlives the call on slow replicas.
+func (pr *PersistRestorer) do(ctx context.Context, required int, op func(context.Context, persistence.PersistRestorer) (interface{}, error)) ([]result, error) {
+	results := pr.start(ctx, op)
+
+	var succeeded []result
+	qerr := &QuorumError{Required: required}
+	for range pr.replicas {
+		select {
+		case res := <-results:
+			if res.err != nil {
+				qerr.Errs = append(qerr.Errs, errors.WithMessagef(res.err, "replica %d", res.replica))
+			} else {
+				succeeded = append(succeeded, res)
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if len(succeeded) >= required {
+			return succeeded, nil
+		}
+		if len(qerr.Errs) > len(pr.replicas)-required {
+			break
+		}
+	}
+	qerr.Succeeded = len(succeeded)
+	return nil, qerr
+}
+
+// readAll queues the operation on all replicas and waits until all of them
+// are done, or until the read grace period passed after a read quorum of
+// replicas succeeded. It returns the successful results if at least a read
+// quorum of replicas succeeded.
+func (pr *PersistRestorer) readAll(ctx context.Context, op func(context.Context, persistence.PersistRestorer) (interface{}, error)) ([]result, error) {
+	results := pr.start(ctx, op)
+
+	var succeeded []result
+	var grace <-chan time.Time
+	qerr := &QuorumError{Required: pr.readQuorum()}
+	for range pr.replicas {
+		select {
+		case res := <-results:
+			if res.err != nil {
+				qerr.Errs = append(qerr.Errs, errors.WithMessagef(res.err, "replica %d", res.replica))
+			} else {
+				succeeded = append(succeeded, res)
+			}
+		case <-grace:
+			return succeeded, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if grace == nil && len(succeeded) >= qerr.Required {
+			grace = time.After(pr.getLimits().ReadGracePeriod)
+		}
+	}
+	if len(succeeded) < qerr.Required {
+		qerr.Succeeded = len(succeeded)
+		return nil, qerr
+	}
+	return succeeded, nil
+}
+
+// start queues the operation on all replicas and returns the channel on which
+// the results of all replicas are sent.
+func (pr *PersistRestorer) start(ctx context.Context, op func(context.Context, persistence.PersistRestorer) (interface{}, error)) <-chan result {
+	opCtx := context.WithoutCancel(ctx)
+	maxQueue := pr.getLimits().MaxQueue
+	results := make(chan result, len(pr.replicas))
+	for i, r := range pr.replicas {
+		if err := r.push(func(err error) {
+			if err != nil {
+				results <- result{replica: i, err: err}
+				return
+			}
+			val, err := op(opCtx, r.pr)
+			results <- result{replica: i, val: val, err: err}
+		}, maxQueue); err != nil {
+			results <- result{replica: i, err: err}
+		}
+	}
+	return results
+}
+
+// ChannelCreated persists the new channel on all replicas.
+func (pr *PersistRestorer) ChannelCreated(ctx context.Context, s channel.Source, peers []map[wallet.BackendID]wire.Address, parent *channel.ID) error {
+	s = persistence.CloneSource(s)
+	return pr.write(ctx, func(ctx context.Context, r persistence.PersistRestorer) error {
+		return r.ChannelCreated(ctx, s, peers, parent)
+	})
+}
+
+// ChannelRemoved removes the channel from all replicas.
+func (pr *PersistRestorer) ChannelRemoved(ctx context.Context, id channel.ID) error {
+	return pr.write(ctx, func(ctx context.Context, r persistence.PersistRestorer) error {
+		return r.ChannelRemoved(ctx, id)
+	})
+}
+
+// Staged persists the staging state on all replicas.
+func (pr *PersistRestorer) Staged(ctx context.Context, s channel.Source) error {
+	s = persistence.CloneSource(s)
+	return pr.write(ctx, func(ctx context.Context, r persistence.PersistRestorer) error {
+		return r.Staged(ctx, s)
+	})
+}
+
+// SigAdded persists the signature on all replicas.
+func (pr *PersistRestorer) SigAdded(ctx context.Context, s channel.Source, idx channel.Index) error {
+	s = persistence.CloneSource(s)
+	return pr.write(ctx, func(ctx context.Context, r persistence.PersistRestorer) error {
+		return r.SigAdded(ctx, s, idx)
+	})
+}
+
+// Enabled persists the current state on all replicas.
+func (pr *PersistRestorer) Enabled(ctx context.Context, s channel.Source) error {
+	s = persistence.CloneSource(s)
+	return pr.write(ctx, func(ctx context.Context, r persistence.PersistRestorer) error {
+		return r.Enabled(ctx, s)
+	})
+}
+
+// PhaseChanged persists the phase on all replicas.
+func (pr *PersistRestorer) PhaseChanged(ctx context.Context, s channel.Source) error {
+	s = persistence.CloneSource(s)
+	return pr.write(ctx, func(ctx context.Context, r persistence.PersistRestorer) error {
+		return r.PhaseChanged(ctx, s)
+	})
+}
+
+// Close waits until all replicas processed their queued writes and then
+// closes them. It returns the first error of the replicas' Close calls.
+func (pr *PersistRestorer) Close() (err error) {
+	for _, r := range pr.replicas {
+		r.close()
+		if cerr := r.pr.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,387 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replicated_test
+
+import (
+	"context"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "perun.network/go-perun/backend/sim" // backend init
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	"perun.network/go-perun/channel/persistence/keyvalue"
+	"perun.network/go-perun/channel/persistence/replicated"
+	"perun.network/go-perun/channel/persistence/test"
+	ctest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/wallet"
+	wtest "perun.network/go-perun/wallet/test"
+	"perun.network/go-perun/wire"
+	wiretest "perun.network/go-perun/wire/test"
+	ctxtest "polycry.pt/poly-go/context/test"
+	"polycry.pt/poly-go/sortedkv/memorydb"
+	pkgtest "polycry.pt/poly-go/test"
+)
+
+func TestPersistRestorer_Generic(t *testing.T) {
+	pr := replicated.NewPersistRestorer(2,
+		keyvalue.NewPersistRestorer(memorydb.NewDatabase()),
+		keyvalue.NewPersistRestorer(memorydb.NewDatabase()),
+		keyvalue.NewPersistRestorer(memorydb.NewDatabase()),
+	)
+	test.GenericPersistRestorerTest(
+		context.Background(),
+		t,
+		pkgtest.Prng(t),
+		pr,
+		4,
+		16)
+	require.NoError(t, pr.Close())
+}
+
+func TestPersistRestorer_Quorum(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	ctx := context.Background()
+	replicas := newFailingReplicas(3)
+	pr := replicated.NewPersistRestorer(2, replicas[0], replicas[1], replicas[2])
+	defer pr.Close()
+
+	ch, _ := newSignedChannel(rng)
+	replicas[2].fail.Store(true)
+	require.NoError(t, pr.ChannelCreated(ctx, ch, ch.PeersV, nil))
+
+	replicas[1].fail.Store(true)
+	err := pr.PhaseChanged(ctx, ch)
+	var qerr *replicated.QuorumError
+	require.ErrorAs(t, err, &qerr)
+	// The write fails as soon as two replicas failed, which may be before
+	// replica 0 succeeded.
+	assert.LessOrEqual(t, qerr.Succeeded, 1)
+	assert.Equal(t, 2, qerr.Required)
+	assert.Len(t, qerr.Errs, 2)
+}
+
+func TestPersistRestorer_Divergence(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	ctx := context.Background()
+	replicas := newFailingReplicas(3)
+	// With a write quorum of one, every read needs all replicas.
+	pr := replicated.NewPersistRestorer(1, replicas[0], replicas[1], replicas[2])
+	defer pr.Close()
+	var divs []replicated.Divergence
+	pr.OnDivergence(func(d replicated.Divergence) { divs = append(divs, d) })
+
+	ch, accs := newSignedChannel(rng)
+	require.NoError(t, pr.ChannelCreated(ctx, ch, ch.PeersV, nil))
+	rch, err := pr.RestoreChannel(ctx, ch.ID())
+	require.NoError(t, err)
+	assert.Equal(t, ch.CurrentTXV.Version, rch.CurrentTXV.Version)
+	assert.Empty(t, divs)
+
+	// Replica 1 misses the next update, replica 2 only its last signature.
+	replicas[1].fail.Store(true)
+	ch.StagingTXV = ch.CurrentTXV.Clone()
+	ch.StagingTXV.Version++
+	ch.StagingTXV.Sigs = make([]wallet.Sig, len(ch.CurrentTXV.Sigs))
+	ch.PhaseV = channel.Signing
+	require.NoError(t, pr.Staged(ctx, ch))
+	ch.StagingTXV.Sigs[0] = sign(accs[0], ch.StagingTXV.State)
+	require.NoError(t, pr.SigAdded(ctx, ch, 0))
+	awaitReplicas(ctx, t, pr)
+	replicas[2].fail.Store(true)
+	ch.StagingTXV.Sigs[1] = sign(accs[1], ch.StagingTXV.State)
+	require.NoError(t, pr.SigAdded(ctx, ch, 1))
+	awaitReplicas(ctx, t, pr)
+	replicas[1].fail.Store(false)
+	replicas[2].fail.Store(false)
+
+	// The fully signed staging state of replica 0 is restored.
+	rch, err = pr.RestoreChannel(ctx, ch.ID())
+	require.NoError(t, err)
+	assert.Equal(t, ch.StagingTXV.Version, rch.StagingTXV.Version)
+	assert.Len(t, rch.StagingTXV.Sigs, 2)
+	for _, sig := range rch.StagingTXV.Sigs {
+		assert.NotNil(t, sig)
+	}
+	require.Len(t, divs, 1)
+	assert.Equal(t, replicated.Divergence{
+		Channel:  ch.ID(),
+		Version:  ch.StagingTXV.Version,
+		Replicas: []int{1, 2},
+	}, divs[0])
+
+	// RestorePeer reconciles in the same way.
+	it, err := pr.RestorePeer(ch.PeersV[0])
+	require.NoError(t, err)
+	require.True(t, it.Next(ctx))
+	assert.Equal(t, ch.StagingTXV.Version, it.Channel().StagingTXV.Version)
+	assert.False(t, it.Next(ctx))
+	require.NoError(t, it.Close())
+	assert.Len(t, divs, 2)
+
+	// A channel that no replica holds is not found.
+	_, err = pr.RestoreChannel(ctx, channel.ID{})
+	assert.Error(t, err)
+}
+
+func TestPersistRestorer_InvalidSignatures(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	ctx := context.Background()
+	replicas := newFailingReplicas(3)
+	pr := replicated.NewPersistRestorer(1, replicas[0], replicas[1], replicas[2])
+	defer pr.Close()
+
+	ch, _ := newSignedChannel(rng)
+	require.NoError(t, pr.ChannelCreated(ctx, ch, ch.PeersV, nil))
+	awaitReplicas(ctx, t, pr)
+
+	// Replica 2 holds a newer state with signatures of the older state.
+	replicas[0].fail.Store(true)
+	replicas[1].fail.Store(true)
+	forged := *ch
+	forged.StagingTXV = ch.CurrentTXV.Clone()
+	forged.StagingTXV.Version++
+	forged.PhaseV = channel.Signing
+	require.NoError(t, pr.Staged(ctx, &forged))
+	require.NoError(t, pr.SigAdded(ctx, &forged, 0))
+	require.NoError(t, pr.SigAdded(ctx, &forged, 1))
+	awaitReplicas(ctx, t, pr)
+
+	// The forged state does not count as signed.
+	var divs []replicated.Divergence
+	pr.OnDivergence(func(d replicated.Divergence) { divs = append(divs, d) })
+	rch, err := pr.RestoreChannel(ctx, ch.ID())
+	require.NoError(t, err)
+	assert.Equal(t, ch.CurrentTXV.Version, rch.CurrentTXV.Version)
+	require.Len(t, divs, 1)
+	assert.Equal(t, ch.CurrentTXV.Version, divs[0].Version)
+}
+
+func TestPersistRestorer_Removed(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	ctx := context.Background()
+	replicas := newFailingReplicas(3)
+	pr := replicated.NewPersistRestorer(2, replicas[0], replicas[1], replicas[2])
+	defer pr.Close()
+	var divs []replicated.Divergence
+	pr.OnDivergence(func(d replicated.Divergence) { divs = append(divs, d) })
+
+	// Replica 2 misses the creation of the channel, which is still restored.
+	ch, _ := newSignedChannel(rng)
+	replicas[2].fail.Store(true)
+	require.NoError(t, pr.ChannelCreated(ctx, ch, ch.PeersV, nil))
+	awaitReplicas(ctx, t, pr)
+	replicas[2].fail.Store(false)
+	removed, _ := newSignedChannel(rng)
+	require.NoError(t, pr.ChannelCreated(ctx, removed, removed.PeersV, nil))
+	_, err := pr.RestoreChannel(ctx, ch.ID())
+	require.NoError(t, err)
+
+	// Replica 2 misses the removal of the other channel.
+	replicas[2].fail.Store(true)
+	require.NoError(t, pr.ChannelRemoved(ctx, removed.ID()))
+	awaitReplicas(ctx, t, pr)
+	replicas[2].fail.Store(false)
+	divs = nil
+
+	_, err = pr.RestoreChannel(ctx, removed.ID())
+	require.Error(t, err)
+	require.Len(t, divs, 1)
+	assert.Equal(t, replicated.Divergence{
+		Channel:  removed.ID(),
+		Replicas: []int{2},
+		Removed:  true,
+	}, divs[0])
+
+	it, err := pr.RestorePeer(removed.PeersV[0])
+	require.NoError(t, err)
+	assert.False(t, it.Next(ctx))
+	require.NoError(t, it.Close())
+
+	peers, err := pr.ActivePeers(ctx)
+	require.NoError(t, err)
+	for _, p := range peers {
+		assert.False(t, channel.EqualWireMaps(p, removed.PeersV[0]))
+	}
+	assert.Len(t, peers, 2)
+}
+
+func TestPersistRestorer_HungReplica(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	ctx := context.Background()
+	hung := &hungReplica{
+		PersistRestorer: keyvalue.NewPersistRestorer(memorydb.NewDatabase()),
+		release:         make(chan struct{}),
+	}
+	defer close(hung.release)
+	pr := replicated.NewPersistRestorer(2,
+		keyvalue.NewPersistRestorer(memorydb.NewDatabase()),
+		keyvalue.NewPersistRestorer(memorydb.NewDatabase()),
+		hung,
+	)
+	limits := replicated.DefaultLimits()
+	limits.ReadGracePeriod = 10 * time.Millisecond
+	limits.MaxQueue = 4
+	pr.SetLimits(limits)
+
+	ch, _ := newSignedChannel(rng)
+	ctxtest.AssertTerminates(t, time.Second, func() {
+		// Reads return without the hung replica.
+		assert.NoError(t, pr.ChannelCreated(ctx, ch, ch.PeersV, nil))
+		rch, err := pr.RestoreChannel(ctx, ch.ID())
+		if assert.NoError(t, err) {
+			assert.Equal(t, ch.CurrentTXV.Version, rch.CurrentTXV.Version)
+		}
+		it, err := pr.RestorePeer(ch.PeersV[0])
+		if assert.NoError(t, err) {
+			assert.True(t, it.Next(ctx))
+			assert.NoError(t, it.Close())
+		}
+
+		// The hung replica fails once its queue is full, so that closing
+		// does not wait for it.
+		for range limits.MaxQueue {
+			assert.NoError(t, pr.PhaseChanged(ctx, ch))
+		}
+		assert.NoError(t, pr.Close())
+	})
+}
+
+// awaitReplicas waits until all replicas processed the earlier writes, because
+// reads go to all replicas.
+func awaitReplicas(ctx context.Context, t *testing.T, pr *replicated.PersistRestorer) {
+	t.Helper()
+	_, err := pr.ActivePeers(ctx)
+	require.NoError(t, err)
+}
+
+// failingReplica is a PersistRestorer whose writes fail if fail is set.
+type failingReplica struct {
+	persistence.PersistRestorer
+	fail atomic.Bool
+}
+
+func newFailingReplicas(n int) []*failingReplica {
+	replicas := make([]*failingReplica, n)
+	for i := range replicas {
+		replicas[i] = &failingReplica{PersistRestorer: keyvalue.NewPersistRestorer(memorydb.NewDatabase())}
+	}
+	return replicas
+}
+
+func (r *failingReplica) err() error {
+	if r.fail.Load() {
+		return errors.New("write failed")
+	}
+	return nil
+}
+
+func (r *failingReplica) ChannelCreated(ctx context.Context, s channel.Source, peers []map[wallet.BackendID]wire.Address, parent *channel.ID) error {
+	if err := r.err(); err != nil {
+		return err
+	}
+	return r.PersistRestorer.ChannelCreated(ctx, s, peers, parent)
+}
+
+func (r *failingReplica) Staged(ctx context.Context, s channel.Source) error {
+	if err := r.err(); err != nil {
+		return err
+	}
+	return r.PersistRestorer.Staged(ctx, s)
+}
+
+func (r *failingReplica) SigAdded(ctx context.Context, s channel.Source, idx channel.Index) error {
+	if err := r.err(); err != nil {
+		return err
+	}
+	return r.PersistRestorer.SigAdded(ctx, s, idx)
+}
+
+func (r *failingReplica) ChannelRemoved(ctx context.Context, id channel.ID) error {
+	if err := r.err(); err != nil {
+		return err
+	}
+	return r.PersistRestorer.ChannelRemoved(ctx, id)
+}
+
+func (r *failingReplica) PhaseChanged(ctx context.Context, s channel.Source) error {
+	if err := r.err(); err != nil {
+		return err
+	}
+	return r.PersistRestorer.PhaseChanged(ctx, s)
+}
+
+// hungReplica is a PersistRestorer whose channel operations block until
+// release is closed and then fail.
+type hungReplica struct {
+	persistence.PersistRestorer
+	release chan struct{}
+}
+
+var errHung = errors.New("hung replica")
+
+func (r *hungReplica) ChannelCreated(context.Context, channel.Source, []map[wallet.BackendID]wire.Address, *channel.ID) error {
+	<-r.release
+	return errHung
+}
+
+func (r *hungReplica) PhaseChanged(context.Context, channel.Source) error {
+	<-r.release
+	return errHung
+}
+
+func (r *hungReplica) RestoreChannel(context.Context, channel.ID) (*persistence.Channel, error) {
+	<-r.release
+	return nil, errHung
+}
+
+func (r *hungReplica) RestorePeer(map[wallet.BackendID]wire.Address) (persistence.ChannelIterator, error) {
+	<-r.release
+	return nil, errHung
+}
+
+// newSignedChannel creates a two-party channel with a signed current state and
+// returns it together with the participants' accounts.
+func newSignedChannel(rng *rand.Rand) (*persistence.Channel, []map[wallet.BackendID]wallet.Account) {
+	accs, parts := wtest.NewRandomAccounts(rng, 2, channel.TestBackendID)
+	params, state := ctest.NewRandomParamsAndState(rng,
+		ctest.WithParts(parts), ctest.WithNumLocked(0), ctest.WithVersion(0), ctest.WithIsFinal(false))
+
+	sigs := make([]wallet.Sig, len(accs))
+	for i, acc := range accs {
+		sigs[i] = sign(acc, state)
+	}
+
+	ch := persistence.NewChannel()
+	ch.ParamsV = params
+	ch.CurrentTXV = channel.Transaction{State: state, Sigs: sigs}
+	ch.PhaseV = channel.Acting
+	ch.PeersV = []map[wallet.BackendID]wire.Address{wiretest.NewRandomAddress(rng), wiretest.NewRandomAddress(rng)}
+	return ch, accs
+}
+
+func sign(acc map[wallet.BackendID]wallet.Account, state *channel.State) wallet.Sig {
+	sig, err := channel.Sign(acc[channel.TestBackendID], state, channel.TestBackendID)
+	if err != nil {
+		panic(err)
+	}
+	return sig
+}
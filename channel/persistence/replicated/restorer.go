@@ -0,0 +1,325 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replicated
+
+import (
+	"context"
+	"sort"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+)
+
+type (
+	// Divergence describes a channel whose data differs between replicas.
+	Divergence struct {
+		Channel  channel.ID
+		Version  uint64 // Version is the restored, highest fully signed version.
+		Replicas []int  // Replicas are the indices of the replicas with older or missing data.
+		// Removed is set if the channel was restored as removed. Replicas
+		// then are the indices of the replicas that still hold the channel.
+		Removed bool
+	}
+
+	// rank orders the data of a channel on different replicas.
+	rank struct {
+		signed         bool   // whether any transaction is fully signed.
+		version        uint64 // highest fully signed version.
+		enabled        bool   // whether the current transaction has this version.
+		stagingSigs    int
+		stagingVersion uint64
+		phase          channel.Phase
+	}
+
+	// restored is the result of RestoreChannel on a single replica.
+	restored struct {
+		ch  *persistence.Channel
+		err error
+	}
+
+	// chanIterator iterates over already restored channels.
+	chanIterator struct {
+		chans []*persistence.Channel
+		ch    *persistence.Channel
+	}
+)
+
+// OnDivergence sets the handler that is called for every divergent channel that
+// is detected during a restore. Divergences are logged in any case.
+func (pr *PersistRestorer) OnDivergence(handler func(Divergence)) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.onDivergence = handler
+}
+
+// readQuorum is the number of replicas that need to be read, so that at least
+// one of them acknowledged every write.
+func (pr *PersistRestorer) readQuorum() int {
+	return len(pr.replicas) - pr.quorum + 1
+}
+
+// removed returns whether data that is missing on the given number of
+// replicas counts as removed. If it is missing on a read quorum, fewer
+// replicas than a write quorum hold it, so it was removed or its creation
+// failed. If the write quorum is a majority, every acknowledged removal is
+// detected.
+func (pr *PersistRestorer) removed(missing int) bool {
+	return missing >= pr.readQuorum()
+}
+
+// ActivePeers returns the active peers of the replicas. All replicas are read,
+// at least a read quorum of them must succeed, see readAll. A peer that is missing on so
+// many replicas that its removal was acknowledged is not returned.
+func (pr *PersistRestorer) ActivePeers(ctx context.Context) ([]map[wallet.BackendID]wire.Address, error) {
+	results, err := pr.readAll(ctx, func(ctx context.Context, r persistence.PersistRestorer) (interface{}, error) {
+		return r.ActivePeers(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var peers []map[wallet.BackendID]wire.Address
+	count := make(map[wire.AddrKey]int)
+	for _, res := range results {
+		for _, p := range res.val.([]map[wallet.BackendID]wire.Address) {
+			if count[wire.Keys(p)]++; count[wire.Keys(p)] == 1 {
+				peers = append(peers, p)
+			}
+		}
+	}
+	active := peers[:0]
+	for _, p := range peers {
+		if !pr.removed(len(results) - count[wire.Keys(p)]) {
+			active = append(active, p)
+		}
+	}
+	return active, nil
+}
+
+// RestorePeer restores the peer's channels from all replicas, of which at
+// least a read quorum must succeed within the RestorePeerTimeout, and returns
+// the best data for each channel that is not removed.
+func (pr *PersistRestorer) RestorePeer(peer map[wallet.BackendID]wire.Address) (persistence.ChannelIterator, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pr.getLimits().RestorePeerTimeout)
+	defer cancel()
+	results, err := pr.readAll(ctx, func(ctx context.Context, r persistence.PersistRestorer) (interface{}, error) {
+		it, err := r.RestorePeer(peer)
+		if err != nil {
+			return nil, err
+		}
+		var chans []*persistence.Channel
+		for it.Next(ctx) {
+			chans = append(chans, it.Channel())
+		}
+		return chans, it.Close()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make(map[channel.ID]map[int]*persistence.Channel)
+	var ids []channel.ID
+	for _, res := range results {
+		for _, ch := range res.val.([]*persistence.Channel) {
+			if candidates[ch.ID()] == nil {
+				candidates[ch.ID()] = make(map[int]*persistence.Channel)
+				ids = append(ids, ch.ID())
+			}
+			candidates[ch.ID()][res.replica] = ch
+		}
+	}
+
+	it := &chanIterator{chans: make([]*persistence.Channel, 0, len(ids))}
+	for _, id := range ids {
+		if ch := pr.reconcile(id, results, candidates[id]); ch != nil {
+			it.chans = append(it.chans, ch)
+		}
+	}
+	return it, nil
+}
+
+// RestoreChannel restores the channel from all replicas, of which at least a
+// read quorum must respond, and returns the best data. If the channel is
+// removed, the first error of the replicas that miss it is returned.
+func (pr *PersistRestorer) RestoreChannel(ctx context.Context, id channel.ID) (*persistence.Channel, error) {
+	// Missing channels are reported as errors by the replicas, so errors are
+	// results here.
+	results, err := pr.readAll(ctx, func(ctx context.Context, r persistence.PersistRestorer) (interface{}, error) {
+		ch, err := r.RestoreChannel(ctx, id)
+		return restored{ch, err}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make(map[int]*persistence.Channel)
+	var firstErr error
+	for _, res := range results {
+		r := res.val.(restored)
+		if r.err != nil {
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			continue
+		}
+		candidates[res.replica] = r.ch
+	}
+	if ch := pr.reconcile(id, results, candidates); ch != nil {
+		return ch, nil
+	}
+	return nil, firstErr
+}
+
+// reconcile picks the best candidate of a channel and reports all replicas
+// that responded with worse or no data. If the channel is removed, it returns
+// nil and reports the replicas that still hold the channel.
+func (pr *PersistRestorer) reconcile(id channel.ID, results []result, candidates map[int]*persistence.Channel) *persistence.Channel {
+	if pr.removed(len(results) - len(candidates)) {
+		div := Divergence{Channel: id, Removed: true}
+		for _, res := range results {
+			if _, ok := candidates[res.replica]; ok {
+				div.Replicas = append(div.Replicas, res.replica)
+			}
+		}
+		if len(div.Replicas) > 0 {
+			sort.Ints(div.Replicas)
+			pr.reportDivergence(div)
+		}
+		return nil
+	}
+
+	var best *persistence.Channel
+	var bestRank rank
+	ranks := make(map[int]rank, len(candidates))
+	for _, res := range results {
+		ch, ok := candidates[res.replica]
+		if !ok {
+			continue
+		}
+		ranks[res.replica] = rankOf(ch)
+		if best == nil || bestRank.less(ranks[res.replica]) {
+			best, bestRank = ch, ranks[res.replica]
+		}
+	}
+
+	div := Divergence{Channel: id, Version: bestRank.version}
+	for _, res := range results {
+		if r, ok := ranks[res.replica]; !ok || r != bestRank {
+			div.Replicas = append(div.Replicas, res.replica)
+		}
+	}
+	if len(div.Replicas) > 0 {
+		sort.Ints(div.Replicas)
+		pr.reportDivergence(div)
+	}
+	return best
+}
+
+func (pr *PersistRestorer) reportDivergence(div Divergence) {
+	if div.Removed {
+		log.WithField("channel", div.Channel).Warnf(
+			"Replicas %v still hold removed channel", div.Replicas)
+	} else {
+		log.WithField("channel", div.Channel).Warnf(
+			"Replicas %v diverge from restored version %d", div.Replicas, div.Version)
+	}
+	pr.mu.Lock()
+	handler := pr.onDivergence
+	pr.mu.Unlock()
+	if handler != nil {
+		handler(div)
+	}
+}
+
+// rankOf returns the rank of the channel data. Data with a higher fully signed
+// version ranks higher. Ties are broken by the progress of the update
+// protocol.
+func rankOf(ch *persistence.Channel) (r rank) {
+	for _, tx := range []channel.Transaction{ch.CurrentTXV, ch.StagingTXV} {
+		if fullySigned(ch.ParamsV, tx) && (!r.signed || tx.Version > r.version) {
+			r.signed, r.version = true, tx.Version
+		}
+	}
+	r.enabled = r.signed && ch.CurrentTXV.State != nil && ch.CurrentTXV.Version == r.version
+	if ch.StagingTXV.State != nil {
+		r.stagingVersion = ch.StagingTXV.Version
+		for _, sig := range ch.StagingTXV.Sigs {
+			if sig != nil {
+				r.stagingSigs++
+			}
+		}
+	}
+	r.phase = ch.PhaseV
+	return r
+}
+
+func (r rank) less(o rank) bool {
+	switch {
+	case r.signed != o.signed:
+		return o.signed
+	case r.version != o.version:
+		return r.version < o.version
+	case r.enabled != o.enabled:
+		return o.enabled
+	case r.stagingVersion != o.stagingVersion:
+		return r.stagingVersion < o.stagingVersion
+	case r.stagingSigs != o.stagingSigs:
+		return r.stagingSigs < o.stagingSigs
+	default:
+		return r.phase < o.phase
+	}
+}
+
+// fullySigned returns whether the transaction has a state of the channel and
+// valid signatures of all participants.
+func fullySigned(params *channel.Params, tx channel.Transaction) bool {
+	if params == nil || tx.State == nil || tx.ID != params.ID() || len(tx.Sigs) != len(params.Parts) {
+		return false
+	}
+	for i, sig := range tx.Sigs {
+		if sig == nil {
+			return false
+		}
+		for _, addr := range params.Parts[i] {
+			if ok, err := channel.Verify(addr, tx.State, sig); err != nil || !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Next advances the iterator to the next channel.
+func (it *chanIterator) Next(context.Context) bool {
+	if len(it.chans) == 0 {
+		return false
+	}
+	it.ch, it.chans = it.chans[0], it.chans[1:]
+	return true
+}
+
+// Channel returns the current channel.
+func (it *chanIterator) Channel() *persistence.Channel {
+	return it.ch
+}
+
+// Close releases the remaining channels.
+func (it *chanIterator) Close() error {
+	it.chans = nil
+	return nil
+}
@@ -16,12 +16,14 @@ package client
 
 import (
 	"context"
+	stdsync "sync"
 
 	"perun.network/go-perun/wallet"
 
 	"github.com/pkg/errors"
 
 	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
 	"perun.network/go-perun/log"
 	"perun.network/go-perun/watcher"
 	"perun.network/go-perun/wire"
@@ -42,32 +44,113 @@ type AdjudicatorEventHandler interface {
 // available to the watcher. In such a case, the handler may receive multiple
 // registered events in short succession.
 //
+// The parents of a sub-channel or a virtual channel are registered with the
+// watcher as well, if needed. Their events are forwarded to a handler only once
+// Watch is called for them.
+//
 // It should be started as a go-routine and returns when the channel is closed.
 func (c *Channel) Watch(h AdjudicatorEventHandler) error {
 	log := c.Log().WithField("proc", "watcher")
 	defer log.Info("Watcher returned.")
 
-	statesPub, eventsSub, err := c.startWatching()
+	w, err := c.ensureWatching()
 	if err != nil {
 		return err
 	}
-	c.machMtx.Lock()
-	c.statesPub = statesPub
-	c.machMtx.Unlock()
-	err = c.handleEvents(eventsSub, h)
-	if err != nil {
-		return errors.WithMessage(err, "handling events from watcher")
+	if !w.setHandler(h) {
+		return errors.New("channel already watched")
 	}
+	<-w.done
+	log.Debugf("Subscription closed: %v", w.err)
+	return w.err
+}
 
-	err = errors.WithMessage(eventsSub.Err(), "subscription closed")
-	log.Debugf("Subscription closed: %v", err)
-	return err
+// channelWatch is the registration of a channel with the watcher. The events
+// received from the watcher are handled by a single loop, which forwards them
+// to the handler passed to Watch, if any.
+type channelWatch struct {
+	mtx     stdsync.Mutex
+	handler AdjudicatorEventHandler
+
+	done chan struct{} // closed when the event loop returns
+	err  error         // set before done is closed
 }
 
-func (c *Channel) startWatching() (watcher.StatesPub, watcher.AdjudicatorSub, error) {
+// setHandler sets the handler that is notified about the events. It returns
+// false if a handler is already set.
+func (w *channelWatch) setHandler(h AdjudicatorEventHandler) bool {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if w.handler != nil {
+		return false
+	}
+	w.handler = h
+	return true
+}
+
+// HandleAdjudicatorEvent forwards the event to the handler. Events received
+// before a handler is set are not forwarded.
+func (w *channelWatch) HandleAdjudicatorEvent(e channel.AdjudicatorEvent) {
+	w.mtx.Lock()
+	h := w.handler
+	w.mtx.Unlock()
+	if h != nil {
+		h.HandleAdjudicatorEvent(e)
+	}
+}
+
+// ensureWatching registers the channel with the watcher, unless it is already
+// registered, and starts handling the events received from the watcher. The
+// parents of the channel are registered first, because the watcher requires
+// them to be registered before their children.
+func (c *Channel) ensureWatching() (*channelWatch, error) {
+	for _, parent := range c.ledgerParents() {
+		if _, err := parent.ensureWatching(); err != nil {
+			return nil, errors.WithMessagef(err, "watching parent %x", parent.ID())
+		}
+	}
+
 	c.machMtx.Lock()
 	defer c.machMtx.Unlock()
+	if c.watching != nil {
+		return c.watching, nil
+	}
 
+	statesPub, eventsSub, err := c.startWatching()
+	if err != nil {
+		return nil, err
+	}
+	c.statesPub = statesPub
+	w := &channelWatch{done: make(chan struct{})}
+	c.watching = w
+	go func() {
+		defer close(w.done)
+		if w.err = c.handleEvents(eventsSub, w); w.err != nil {
+			w.err = errors.WithMessage(w.err, "handling events from watcher")
+			return
+		}
+		w.err = errors.WithMessage(eventsSub.Err(), "subscription closed")
+	}()
+	return w, nil
+}
+
+// ledgerParents returns the ledger channels funding the channel: both channels
+// with the participants for a virtual channel on the hub, the parent for other
+// sub-channels and none for ledger channels.
+func (c *Channel) ledgerParents() []*Channel {
+	switch {
+	case len(c.hubParents) > 0:
+		return c.hubParents
+	case c.parent != nil:
+		return []*Channel{c.parent}
+	default:
+		return nil
+	}
+}
+
+// startWatching registers the channel with the watcher. The machine must be
+// locked.
+func (c *Channel) startWatching() (watcher.StatesPub, watcher.AdjudicatorSub, error) {
 	currentTx := c.machine.CurrentTX()
 	signedState := channel.SignedState{
 		Params: c.Params(),
@@ -76,10 +159,19 @@ func (c *Channel) startWatching() (watcher.StatesPub, watcher.AdjudicatorSub, er
 	}
 
 	statesPub, eventsSub, err := func() (watcher.StatesPub, watcher.AdjudicatorSub, error) {
-		if c.IsLedgerChannel() {
+		switch {
+		case c.IsLedgerChannel():
 			return c.client.watcher.StartWatchingLedgerChannel(c.Ctx(), signedState)
+		case c.IsVirtualChannel():
+			parents := c.ledgerParents()
+			ids := make([]channel.ID, len(parents))
+			for i, parent := range parents {
+				ids[i] = parent.ID()
+			}
+			return c.client.watcher.StartWatchingVirtualChannel(c.Ctx(), ids, signedState)
+		default:
+			return c.client.watcher.StartWatchingSubChannel(c.Ctx(), c.parent.ID(), signedState)
 		}
-		return c.client.watcher.StartWatchingSubChannel(c.Ctx(), c.parent.ID(), signedState)
 	}()
 	if err != nil {
 		return nil, nil, errors.WithMessage(err, "registering channel with the watcher")
@@ -132,6 +224,12 @@ func (c *Channel) setMachinePhase(ctx context.Context, e channel.AdjudicatorEven
 		err = c.machine.SetProgressed(ctx, e)
 	case *channel.ConcludedEvent:
 		// Do nothing as there is currently no corresponding phase in the channel machine.
+	case *watcher.WithdrawnEvent:
+		if e.Err != nil {
+			// The handler is notified about the failed withdrawal.
+			break
+		}
+		err = c.setWithdrawnByWatcher(ctx)
 	default:
 		c.Log().Panic("unsupported event type")
 	}
@@ -284,7 +382,7 @@ func (c *Channel) Settle(ctx context.Context, secondary bool) (err error) {
 		if c.machine.Phase() == channel.Withdrawn {
 			return nil
 		}
-		return c.machine.SetWithdrawn(ctx)
+		return c.setWithdrawn(ctx)
 	}); err != nil {
 		return errors.WithMessage(err, "setting phase `Withdrawn` recursive")
 	}
@@ -355,6 +453,65 @@ func (c *Channel) hasParticipant(id map[wallet.BackendID]wire.Address) bool {
 	return false
 }
 
+// setWithdrawn archives the channel, if the client has an Archiver, and then
+// sets the machine's phase to Withdrawn, which removes it from persistence.
+// The channel is archived first so that it is never removed without being
+// archived. If setting the phase fails, the channel is archived again on the
+// next attempt, which the Archiver ignores.
+func (c *Channel) setWithdrawn(ctx context.Context) error {
+	if c.client.archiver != nil {
+		var parent *channel.ID
+		if c.parent != nil {
+			parent = new(channel.ID)
+			*parent = c.parent.ID()
+		}
+		ach := persistence.NewArchivedChannel(c.machine, c.Peers(), parent)
+		if err := c.client.archiver.ChannelArchived(ctx, ach); err != nil {
+			return errors.WithMessage(err, "archiving channel")
+		}
+	}
+	if err := c.machine.SetWithdrawn(ctx); err != nil {
+		return err
+	}
+	if c.client.cursors != nil {
+		if err := c.client.cursors.CursorRemoved(ctx, c.ID()); err != nil {
+			c.Log().Warnf("removing event cursor: %v", err)
+		}
+	}
+	return nil
+}
+
+// setWithdrawnByWatcher sets the channel and all of its sub-channels to
+// `Withdrawn` after the watcher withdrew the funds of the channel. Assumes that
+// the channel machine has been locked.
+func (c *Channel) setWithdrawnByWatcher(ctx context.Context) error {
+	var l mutexList
+	defer l.Unlock()
+	err := c.applyToSubChannelsRecursive(func(c *Channel) error {
+		if !c.machMtx.TryLockCtx(ctx) {
+			return errors.Errorf("locking machine mutex in time: %v", ctx.Err())
+		}
+		l = append(l, &c.machMtx)
+		return nil
+	})
+	if err != nil {
+		return errors.WithMessage(err, "locking sub-channels")
+	}
+
+	return c.applyRecursive(func(c *Channel) error {
+		switch c.machine.Phase() {
+		case channel.Withdrawn:
+			return nil
+		case channel.Withdrawing:
+		default:
+			if err := c.machine.SetWithdrawing(ctx); err != nil {
+				return err
+			}
+		}
+		return c.setWithdrawn(ctx)
+	})
+}
+
 type mutexList []*sync.Mutex
 
 func (a mutexList) Unlock() {
@@ -46,6 +46,8 @@ type Channel struct {
 	wallet      map[wallet.BackendID]wallet.Wallet
 
 	parent                *Channel            // must be nil for ledger channel
+	hubParents            []*Channel          // both parents of a virtual channel on the hub
+	watching              *channelWatch       // registration with the watcher, guarded by machMtx
 	subChannelFundings    *updateInterceptors // awaited subchannel funding updates
 	subChannelWithdrawals *updateInterceptors // awaited subchannel settlement updates
 }
@@ -17,6 +17,8 @@ package client
 import (
 	"context"
 	"math"
+	stdsync "sync"
+	"sync/atomic"
 
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
@@ -39,6 +41,9 @@ type channelConn struct {
 	peers []map[wallet.BackendID]wire.Address
 	idx   channel.Index // our index
 
+	recvsMtx stdsync.Mutex
+	recvs    map[*channelMsgRecv]struct{} // open update response receivers
+
 	log log.Logger
 }
 
@@ -81,11 +86,43 @@ func (c *channelConn) NewUpdateResRecv(version uint64) (*channelMsgRecv, error)
 		return nil, errors.WithMessagef(err, "subscribing update response receiver")
 	}
 
-	return &channelMsgRecv{
+	r := &channelMsgRecv{
 		Receiver: recv,
 		peers:    c.peers,
 		log:      c.log.WithField("version", version),
-	}, nil
+	}
+	c.recvsMtx.Lock()
+	c.recvs[r] = struct{}{}
+	c.recvsMtx.Unlock()
+	recv.OnCloseAlways(func() {
+		c.recvsMtx.Lock()
+		delete(c.recvs, r)
+		c.recvsMtx.Unlock()
+	})
+	return r, nil
+}
+
+// peerEvicted closes the open update response receivers if the peer is part
+// of the channel, because the responses of the evicted connection may be
+// lost. It returns whether any receiver was closed.
+func (c *channelConn) peerEvicted(peer map[wallet.BackendID]wire.Address) bool {
+	if wire.IndexOfAddrs(c.peers, peer) == -1 {
+		return false
+	}
+	c.recvsMtx.Lock()
+	recvs := make([]*channelMsgRecv, 0, len(c.recvs))
+	for r := range c.recvs {
+		recvs = append(recvs, r)
+	}
+	c.recvsMtx.Unlock()
+
+	for _, r := range recvs {
+		r.evicted.Store(true)
+		if err := r.Close(); err != nil {
+			c.log.Warnf("Closing update response receiver: %v", err)
+		}
+	}
+	return len(recvs) > 0
 }
 
 // Close closes the broadcaster and update request receiver.
@@ -133,6 +170,7 @@ func newChannelConn(id channel.ID, peers []map[wallet.BackendID]wire.Address, id
 		pub:      pub,
 		peers:    peers,
 		idx:      idx,
+		recvs:    make(map[*channelMsgRecv]struct{}),
 		log:      log.WithField("channel", id),
 	}, nil
 }
@@ -155,6 +193,8 @@ type (
 
 		peers []map[wallet.BackendID]wire.Address
 		log   log.Logger
+
+		evicted atomic.Bool // set if closed because a peer was evicted
 	}
 )
 
@@ -163,6 +203,9 @@ type (
 func (r *channelMsgRecv) Next(ctx context.Context) (channel.Index, ChannelMsg, error) {
 	env, err := r.Receiver.Next(ctx)
 	if err != nil {
+		if r.evicted.Load() {
+			return 0, nil, errors.WithMessage(err, "peer connection evicted")
+		}
 		return 0, nil, err
 	}
 	idx := wire.IndexOfAddrs(r.peers, env.Sender)
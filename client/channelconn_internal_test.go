@@ -0,0 +1,56 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	channeltest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/wire"
+	wiretest "perun.network/go-perun/wire/test"
+	"polycry.pt/poly-go/test"
+)
+
+func TestChannelConn_PeerEvicted(t *testing.T) {
+	rng := test.Prng(t)
+	relay := wire.NewRelay()
+	defer relay.Close()
+	peers := wiretest.NewRandomAddressesMap(rng, 2)
+	conn, err := newChannelConn(channeltest.NewRandomChannelID(rng), peers, 0, relay, wire.NewLocalBus())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	recv, err := conn.NewUpdateResRecv(1)
+	require.NoError(t, err)
+
+	// Evicting an unrelated peer doesn't abort the pending update.
+	assert.False(t, conn.peerEvicted(wiretest.NewRandomAddress(rng)))
+	assert.False(t, recv.IsClosed())
+
+	assert.True(t, conn.peerEvicted(peers[1]))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, _, err = recv.Next(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "evicted")
+
+	// Closed receivers are no longer tracked.
+	assert.False(t, conn.peerEvicted(peers[1]))
+}
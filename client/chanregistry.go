@@ -114,3 +114,15 @@ func (r *chanRegistry) CloseAll() (err error) {
 
 	return err
 }
+
+// Channels returns all registered channels.
+func (r *chanRegistry) Channels() []*Channel {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	chs := make([]*Channel, 0, len(r.values))
+	for _, ch := range r.values {
+		chs = append(chs, ch)
+	}
+	return chs
+}
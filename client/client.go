@@ -44,7 +44,13 @@ type Client struct {
 	adjudicator       channel.Adjudicator
 	wallet            map[wallet.BackendID]wallet.Wallet
 	pr                persistence.PersistRestorer
-	log               log.Logger // structured logger for this client
+	archiver          persistence.Archiver
+	cursors           persistence.CursorPersister
+	equivocations     equivocations
+	rateLimiter       rateLimiter
+	handlers          handlerGroup // running request handlers
+	drainer           wire.Drainer // the bus, if it can be shut down gracefully
+	log               log.Logger   // structured logger for this client
 	version1Cache     version1Cache
 	fundingWatcher    *stateWatcher
 	settlementWatcher *stateWatcher
@@ -111,9 +117,30 @@ func New(
 
 	c.fundingWatcher = newStateWatcher(c.matchFundingProposal)
 	c.settlementWatcher = newStateWatcher(c.matchSettlementProposal)
+	if en, ok := bus.(wire.EvictionNotifier); ok {
+		en.OnPeerEvicted(c.handlePeerEvicted)
+	}
+	if d, ok := bus.(wire.Drainer); ok {
+		c.drainer = d
+		d.OnDrain(c.drain)
+	}
 	return c, nil
 }
 
+// handlePeerEvicted aborts the pending updates of all channels with the
+// evicted peer, because their responses may have been lost with the
+// connection. The aborted updates fail instead of waiting for their timeout.
+func (c *Client) handlePeerEvicted(peer map[wallet.BackendID]wire.Address) {
+	if c.IsClosed() {
+		return
+	}
+	for _, ch := range c.channels.Channels() {
+		if ch.conn.peerEvicted(peer) {
+			c.logChan(ch.ID()).WithField("peer", peer).Warn("Aborted pending updates of evicted peer")
+		}
+	}
+}
+
 // Close closes this state channel client.
 // It also closes the peer registry.
 func (c *Client) Close() error {
@@ -166,6 +193,23 @@ func (c *Client) EnablePersistence(pr persistence.PersistRestorer) {
 	c.pr = pr
 }
 
+// EnableArchive sets the Archiver that the client is going to use to archive
+// channels after they have been settled. Without an Archiver, the data of
+// settled channels is discarded. This method is expected to be called once
+// during the setup of the client and is hence not thread-safe.
+func (c *Client) EnableArchive(a persistence.Archiver) {
+	c.archiver = a
+}
+
+// EnableCursorPersistence sets the CursorPersister that the client is going to
+// use to persist the positions of its adjudicator event subscriptions. It only
+// has an effect if the adjudicator is a channel.CursorSubscriber. This method
+// is expected to be called once during the setup of the client and is hence
+// not thread-safe.
+func (c *Client) EnableCursorPersistence(cp persistence.CursorPersister) {
+	c.cursors = cp
+}
+
 // Channel queries a channel by its ID.
 func (c *Client) Channel(id channel.ID) (*Channel, error) {
 	if ch, ok := c.channels.Channel(id); ok {
@@ -177,7 +221,9 @@ func (c *Client) Channel(id channel.ID) (*Channel, error) {
 // Handle is the incoming request handler routine. It handles channel proposals
 // and channel update requests. It must be started exactly once by the user,
 // during the setup of the Client. Incoming requests are handled by the passed
-// respecive handlers.
+// respecive handlers. If rate limits are enabled, requests that exceed them are
+// dropped or rejected, see EnableRateLimits. Requests of a peer whose
+// connection is being shut down gracefully are rejected.
 func (c *Client) Handle(ph ProposalHandler, uh UpdateHandler) {
 	if ph == nil || uh == nil {
 		c.log.Panic("handlers must not be nil")
@@ -191,24 +237,32 @@ func (c *Client) Handle(ph ProposalHandler, uh UpdateHandler) {
 		}
 		msg := env.Msg
 
+		var handle func(ProposalHandler, UpdateHandler)
 		switch msg := msg.(type) {
 		case *LedgerChannelProposalMsg:
-			go c.handleChannelProposal(ph, env.Sender, msg)
+			handle = func(ph ProposalHandler, _ UpdateHandler) { c.handleChannelProposal(ph, env.Sender, msg) }
 		case *SubChannelProposalMsg:
-			go c.handleChannelProposal(ph, env.Sender, msg)
+			handle = func(ph ProposalHandler, _ UpdateHandler) { c.handleChannelProposal(ph, env.Sender, msg) }
 		case *VirtualChannelProposalMsg:
-			go c.handleChannelProposal(ph, env.Sender, msg)
+			handle = func(ph ProposalHandler, _ UpdateHandler) { c.handleChannelProposal(ph, env.Sender, msg) }
 		case *ChannelUpdateMsg:
-			go c.handleChannelUpdate(uh, env.Sender, msg)
+			handle = func(_ ProposalHandler, uh UpdateHandler) { c.handleChannelUpdate(uh, env.Sender, msg) }
 		case *VirtualChannelFundingProposalMsg:
-			go c.handleChannelUpdate(uh, env.Sender, msg)
+			handle = func(_ ProposalHandler, uh UpdateHandler) { c.handleChannelUpdate(uh, env.Sender, msg) }
 		case *VirtualChannelSettlementProposalMsg:
-			go c.handleChannelUpdate(uh, env.Sender, msg)
+			handle = func(_ ProposalHandler, uh UpdateHandler) { c.handleChannelUpdate(uh, env.Sender, msg) }
 		case *ChannelSyncMsg:
-			go c.handleSyncMsg(env.Sender, msg)
+			handle = func(ProposalHandler, UpdateHandler) { c.handleSyncMsg(env.Sender, msg) }
 		default:
-			c.log.Error("Unexpected %T message received in request loop")
+			c.log.Errorf("Unexpected %T message received in request loop", msg)
+			continue
+		}
+		if msg.Type().IsRequest() && c.peerShuttingDown(env.Sender) {
+			c.logPeer(env.Sender).Warnf("Rejecting %T request: %s", msg, shutdownReason)
+			c.reject(env, shutdownReason)
+			continue
 		}
+		c.spawnHandler(env, ph, uh, handle)
 	}
 }
 
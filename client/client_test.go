@@ -16,6 +16,7 @@ package client_test
 
 import (
 	"context"
+	"math/rand"
 	"testing"
 	"time"
 
@@ -26,13 +27,17 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	"perun.network/go-perun/channel/persistence/keyvalue"
 	chtest "perun.network/go-perun/channel/test"
 	"perun.network/go-perun/client"
 	ctest "perun.network/go-perun/client/test"
 	wtest "perun.network/go-perun/wallet/test"
+	"perun.network/go-perun/watcher"
 	"perun.network/go-perun/watcher/local"
 	"perun.network/go-perun/wire"
 	wiretest "perun.network/go-perun/wire/test"
+	"polycry.pt/poly-go/sortedkv/memorydb"
 	"polycry.pt/poly-go/test"
 )
 
@@ -130,3 +135,160 @@ func TestChannelRejection(t *testing.T) {
 	require.Error(err)
 	require.NoError(ctx.Err())
 }
+
+func TestChannel_Settle_Archives(t *testing.T) {
+	rng := test.Prng(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require := require.New(t)
+	_, alice, _, ch, chBob := openLedgerChannel(ctx, t, rng)
+
+	archive := keyvalue.NewArchive(memorydb.NewDatabase())
+	alice.EnableArchive(archive)
+
+	// Finalize and settle the channel.
+	require.NoError(ch.Update(ctx, func(s *channel.State) { s.IsFinal = true }))
+	require.NoError(ch.Settle(ctx, false))
+	require.NoError(chBob.Settle(ctx, false))
+
+	archived, err := archive.ArchivedChannels(ctx, persistence.ArchiveFilter{})
+	require.NoError(err)
+	require.Len(archived, 1)
+	require.Equal(ch.ID(), archived[0].Params.ID())
+	require.True(archived[0].State.IsFinal)
+}
+
+func TestChannel_WithdrawnByWatcher(t *testing.T) {
+	rng := test.Prng(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require := require.New(t)
+	roles, alice, _, ch, chBob := openLedgerChannel(ctx, t, rng)
+
+	// Alice watches the channel and lets her watcher withdraw automatically.
+	events := make(chan channel.AdjudicatorEvent, 10)
+	go func() { _ = ch.Watch(eventRecorder(events)) }()
+	acc, err := roles[0].Wallet[channel.TestBackendID].Unlock(alice.WalletAddress[channel.TestBackendID])
+	require.NoError(err)
+	w, ok := roles[0].Watcher.(*local.Watcher)
+	require.True(ok)
+	require.Eventually(func() bool {
+		return w.EnableWithdrawal(ch.ID(), roles[0].Adjudicator, map[wallet.BackendID]wallet.Account{channel.TestBackendID: acc}, ch.Idx()) == nil
+	}, time.Second, 10*time.Millisecond)
+
+	// Bob's settlement concludes the channel, upon which Alice's watcher
+	// withdraws her funds.
+	require.NoError(ch.Update(ctx, func(s *channel.State) { s.IsFinal = true }))
+	require.NoError(chBob.Settle(ctx, false))
+
+	for {
+		select {
+		case e := <-events:
+			if e, ok := e.(*watcher.WithdrawnEvent); ok {
+				require.NoError(e.Err)
+				require.Equal(channel.Withdrawn, ch.Phase())
+				return
+			}
+		case <-ctx.Done():
+			t.Fatal("no withdrawn event received")
+		}
+	}
+}
+
+type eventRecorder chan<- channel.AdjudicatorEvent
+
+func (r eventRecorder) HandleAdjudicatorEvent(e channel.AdjudicatorEvent) { r <- e }
+
+func TestChannel_DisputeOnEquivocation(t *testing.T) {
+	rng := test.Prng(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require := require.New(t)
+	roles, alice, bob, ch, chBob := openLedgerChannel(ctx, t, rng)
+
+	evidence := make(chan *channel.Equivocation, 1)
+	alice.EnableEquivocationEvidence(keyvalue.NewEquivocationStore(memorydb.NewDatabase()))
+	alice.OnEquivocation(func(e *channel.Equivocation) { evidence <- e })
+	alice.DisputeOnEquivocation(true)
+	errs := make(chan error, 1)
+	go alice.Handle(ctest.AlwaysRejectChannelHandler(ctx, errs), ctest.AlwaysRejectUpdateHandler(ctx, errs))
+
+	// Bob proposes a state that conflicts with the current state of the same
+	// version.
+	state := chBob.State().Clone()
+	state.IsFinal = true
+	acc, err := roles[1].Wallet[channel.TestBackendID].Unlock(bob.WalletAddress[channel.TestBackendID])
+	require.NoError(err)
+	sig, err := channel.Sign(acc, state, channel.TestBackendID)
+	require.NoError(err)
+	msg := &client.ChannelUpdateMsg{
+		ChannelUpdate: client.ChannelUpdate{State: state, ActorIdx: chBob.Idx()},
+		Sig:           sig,
+	}
+	require.NoError(roles[1].Bus.Publish(ctx, &wire.Envelope{
+		Sender:    wire.AddressMapfromAccountMap(bob.Identity),
+		Recipient: wire.AddressMapfromAccountMap(alice.Identity),
+		Msg:       msg,
+	}))
+
+	select {
+	case e := <-evidence:
+		require.Equal(ch.ID(), e.ID())
+		require.Equal(chBob.Idx(), e.Idx)
+		require.NoError(e.Verify())
+	case <-ctx.Done():
+		t.Fatal("no equivocation detected")
+	}
+	persisted, err := alice.Equivocations(ctx, ch.ID())
+	require.NoError(err)
+	require.Len(persisted, 1)
+
+	// Alice registers her latest state.
+	require.Eventually(func() bool { return ch.Phase() == channel.Registered }, time.Second, 10*time.Millisecond)
+}
+
+// openLedgerChannel opens a ledger channel between Alice and Bob, who accepts
+// all updates.
+func openLedgerChannel(ctx context.Context, t *testing.T, rng *rand.Rand) (
+	roles []ctest.RoleSetup, alice, bob *ctest.Client, ch, chBob *client.Channel,
+) {
+	t.Helper()
+	roles = NewSetups(rng, []string{"Alice", "Bob"}, channel.TestBackendID)
+	asset := chtest.NewRandomAsset(rng, channel.TestBackendID)
+	clients := ctest.NewClients(t, rng, roles)
+	require := require.New(t)
+	alice, bob = clients[0], clients[1]
+
+	// Bob accepts the channel and all updates.
+	chsBob := make(chan *client.Channel, 1)
+	errs := make(chan error, 1)
+	go bob.Handle(
+		ctest.AlwaysAcceptChannelHandler(ctx, bob.WalletAddress, chsBob, errs),
+		ctest.AlwaysAcceptUpdateHandler(ctx, errs),
+	)
+
+	parts := []map[wallet.BackendID]wire.Address{wire.AddressMapfromAccountMap(alice.Identity), wire.AddressMapfromAccountMap(bob.Identity)}
+	initAlloc := channel.NewAllocation(len(parts), []wallet.BackendID{channel.TestBackendID}, asset)
+	prop, err := client.NewLedgerChannelProposal(
+		challengeDuration,
+		alice.WalletAddress,
+		initAlloc,
+		parts,
+	)
+	require.NoError(err, "creating ledger channel proposal")
+
+	ch, err = alice.ProposeChannel(ctx, prop)
+	require.NoError(err)
+	select {
+	case chBob = <-chsBob:
+	case err := <-errs:
+		require.NoError(err)
+	}
+	return roles, alice, bob, ch, chBob
+}
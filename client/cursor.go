@@ -0,0 +1,50 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+)
+
+// subscribeFrom subscribes to the adjudicator events of the channel. If the
+// client persists cursors and the adjudicator supports them, the subscription
+// resumes after the last processed event.
+func (c *Channel) subscribeFrom(ctx context.Context) (channel.AdjudicatorSubscription, error) {
+	cs, ok := c.adjudicator.(channel.CursorSubscriber)
+	if !ok || c.client.cursors == nil {
+		return c.adjudicator.Subscribe(ctx, c.ID())
+	}
+	cursor, err := c.client.cursors.Cursor(ctx, c.ID())
+	if err != nil {
+		return nil, errors.WithMessage(err, "retrieving event cursor")
+	}
+	return cs.SubscribeFrom(ctx, c.ID(), cursor)
+}
+
+// eventProcessed persists the position of the subscription after the last
+// processed event, if the client persists cursors.
+func (c *Channel) eventProcessed(ctx context.Context, sub channel.AdjudicatorSubscription) {
+	cs, ok := sub.(channel.CursorSubscription)
+	if !ok || c.client.cursors == nil {
+		return
+	}
+	if err := c.client.cursors.CursorUpdated(ctx, c.ID(), cs.Cursor()); err != nil {
+		c.Log().Warnf("persisting event cursor: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	stdsync "sync"
+
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+)
+
+// handlerGroup tracks the running request handlers of a client per peer.
+type handlerGroup struct {
+	mu    stdsync.Mutex
+	peers map[wire.AddrKey]*peerHandlers
+}
+
+// peerHandlers tracks the running request handlers of a peer.
+type peerHandlers struct {
+	active int
+	idle   chan struct{} // Closed when the last active handler is done.
+}
+
+// add registers a running handler of the peer. done must be called when it
+// returns.
+func (g *handlerGroup) add(peer map[wallet.BackendID]wire.Address) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.peers == nil {
+		g.peers = make(map[wire.AddrKey]*peerHandlers)
+	}
+	key := wire.Keys(peer)
+	h, ok := g.peers[key]
+	if !ok {
+		h = &peerHandlers{idle: make(chan struct{})}
+		g.peers[key] = h
+	}
+	h.active++
+}
+
+// done unregisters a running handler of the peer.
+func (g *handlerGroup) done(peer map[wallet.BackendID]wire.Address) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	key := wire.Keys(peer)
+	h := g.peers[key]
+	h.active--
+	if h.active == 0 {
+		close(h.idle)
+		delete(g.peers, key)
+	}
+}
+
+// wait waits until no handler of the peer is running or the context is done.
+func (g *handlerGroup) wait(ctx context.Context, peer map[wallet.BackendID]wire.Address) error {
+	g.mu.Lock()
+	h, ok := g.peers[wire.Keys(peer)]
+	g.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	select {
+	case <-h.idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// shutdownReason is the reason of the rejection of a request that is received
+// while the connection to the peer is being shut down.
+const shutdownReason = "connection shutting down"
+
+// drain waits until the running request handlers of the peer have returned,
+// so that their responses are published before the connection to the peer is
+// shut down. It is added to the bus if it is a wire.Drainer.
+func (c *Client) drain(ctx context.Context, peer map[wallet.BackendID]wire.Address) {
+	if err := c.handlers.wait(ctx, peer); err != nil {
+		c.logPeer(peer).Warnf("Shutting down connection with running request handlers: %v", err)
+	}
+}
+
+// peerShuttingDown returns whether the connection to the peer is being shut
+// down gracefully, in which case no new requests are accepted from the peer.
+func (c *Client) peerShuttingDown(peer map[wallet.BackendID]wire.Address) bool {
+	return c.drainer != nil && c.drainer.IsShuttingDown(peer)
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	wiretest "perun.network/go-perun/wire/test"
+	"polycry.pt/poly-go/test"
+)
+
+func TestHandlerGroup(t *testing.T) {
+	rng := test.Prng(t)
+	alice, bob := wiretest.NewRandomAddress(rng), wiretest.NewRandomAddress(rng)
+	var g handlerGroup
+	assert.NoError(t, g.wait(context.Background(), alice))
+
+	g.add(alice)
+	g.add(alice)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, g.wait(ctx, alice), context.DeadlineExceeded)
+
+	// The handlers of other peers are not waited for.
+	g.add(bob)
+	assert.NoError(t, g.wait(context.Background(), wiretest.NewRandomAddress(rng)))
+
+	g.done(alice)
+	waited := make(chan error)
+	go func() { waited <- g.wait(context.Background(), alice) }()
+	select {
+	case <-waited:
+		t.Fatal("wait returned with a running handler")
+	case <-time.After(10 * time.Millisecond):
+	}
+	g.done(alice)
+	assert.NoError(t, <-waited)
+	g.done(bob)
+	assert.Empty(t, g.peers)
+
+	// The group can be reused.
+	g.add(alice)
+	g.done(alice)
+	assert.NoError(t, g.wait(context.Background(), alice))
+}
+
+func TestClient_HandleShuttingDown(t *testing.T) {
+	rng := test.Prng(t)
+	bus := wire.NewLocalBus()
+	addr, peer := wiretest.NewRandomAddress(rng), wiretest.NewRandomAddress(rng)
+	conn, err := makeClientConn(addr, bus)
+	require.NoError(t, err)
+	c := &Client{conn: conn, log: log.Default(), drainer: shuttingDownDrainer{peer}}
+	t.Cleanup(func() { assert.NoError(t, c.conn.Close()) })
+	recv := wire.NewReceiver()
+	require.NoError(t, bus.SubscribeClient(recv, peer))
+	go c.Handle(
+		ProposalHandlerFunc(func(ChannelProposal, *ProposalResponder) { t.Error("proposal not rejected") }),
+		UpdateHandlerFunc(func(*channel.State, ChannelUpdate, *UpdateResponder) { t.Error("update not rejected") }),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	proposal := &LedgerChannelProposalMsg{BaseChannelProposal: BaseChannelProposal{ProposalID: ProposalID{1}}}
+	require.NoError(t, bus.Publish(ctx, &wire.Envelope{Sender: peer, Recipient: addr, Msg: proposal}))
+	rej, err := recv.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &ChannelProposalRejMsg{ProposalID: proposal.ProposalID, Reason: shutdownReason}, rej.Msg)
+	assert.NoError(t, c.handlers.wait(ctx, peer))
+}
+
+// shuttingDownDrainer is a wire.Drainer whose connection to peer is shutting
+// down.
+type shuttingDownDrainer struct {
+	peer map[wallet.BackendID]wire.Address
+}
+
+func (shuttingDownDrainer) OnDrain(func(context.Context, map[wallet.BackendID]wire.Address)) {}
+
+func (d shuttingDownDrainer) IsShuttingDown(peer map[wallet.BackendID]wire.Address) bool {
+	return wire.Keys(peer) == wire.Keys(d.peer)
+}
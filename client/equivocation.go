@@ -0,0 +1,148 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	"perun.network/go-perun/wallet"
+)
+
+type (
+	// An EquivocationHandler is called with the evidence of each equivocation
+	// by a channel peer that the client detects.
+	EquivocationHandler func(*channel.Equivocation)
+
+	// equivocations holds the client's configuration for handling
+	// equivocations.
+	equivocations struct {
+		mu      sync.Mutex
+		store   persistence.EquivocationStore
+		handler EquivocationHandler
+		dispute bool
+	}
+)
+
+// EnableEquivocationEvidence sets the EquivocationStore that the client is
+// going to use to persist the evidence of equivocations by channel peers. This
+// method is expected to be called once during the setup of the client and is
+// hence not thread-safe.
+func (c *Client) EnableEquivocationEvidence(s persistence.EquivocationStore) {
+	c.equivocations.store = s
+}
+
+// OnEquivocation sets a callback to be called whenever a channel peer
+// equivocated, i.e., signed two different states with the same version. Only
+// one such handler can be set at a time. This function may be safely called at
+// any time.
+func (c *Client) OnEquivocation(handler EquivocationHandler) {
+	c.equivocations.mu.Lock()
+	defer c.equivocations.mu.Unlock()
+	c.equivocations.handler = handler
+}
+
+// DisputeOnEquivocation sets whether the client immediately registers the
+// latest state of a channel when a peer equivocated in it. This function may
+// be safely called at any time.
+func (c *Client) DisputeOnEquivocation(enabled bool) {
+	c.equivocations.mu.Lock()
+	defer c.equivocations.mu.Unlock()
+	c.equivocations.dispute = enabled
+}
+
+// Equivocations returns the persisted evidence of all equivocations in the
+// channel. It fails if no EquivocationStore is enabled.
+func (c *Client) Equivocations(ctx context.Context, id channel.ID) ([]*channel.Equivocation, error) {
+	if c.equivocations.store == nil {
+		return nil, errors.New("equivocation evidence not enabled")
+	}
+	return c.equivocations.store.Equivocations(ctx, id)
+}
+
+// handleEquivocations persists the evidence, calls the handler and, if
+// enabled, registers the latest state of the channel.
+func (c *Client) handleEquivocations(evidence []*channel.Equivocation) {
+	c.equivocations.mu.Lock()
+	handler, dispute := c.equivocations.handler, c.equivocations.dispute
+	c.equivocations.mu.Unlock()
+
+	for _, e := range evidence {
+		log := c.logChan(e.ID())
+		log.Errorf("Peer %d equivocated in version %d", e.Idx, e.Version())
+		if c.equivocations.store != nil {
+			if err := c.equivocations.store.EquivocationDetected(c.Ctx(), e); err != nil {
+				log.Errorf("Persisting equivocation: %v", err)
+			}
+		}
+		if handler != nil {
+			handler(e)
+		}
+	}
+
+	if !dispute || len(evidence) == 0 {
+		return
+	}
+	id := evidence[0].ID()
+	ch, ok := c.channels.Channel(id)
+	if !ok {
+		c.logChan(id).Warn("Cannot dispute equivocation: channel not found")
+		return
+	}
+	if err := ch.registerDispute(c.Ctx()); err != nil {
+		c.logChan(id).Errorf("Registering dispute after equivocation: %v", err)
+	}
+}
+
+// detectEquivocations returns the evidence of all peers that signed both
+// transactions although they conflict. The own index is skipped.
+func detectEquivocations(params *channel.Params, own channel.Index, a, b channel.Transaction) []*channel.Equivocation {
+	if a.State == nil || b.State == nil || a.Version != b.Version || a.Equal(b.State) == nil {
+		return nil
+	}
+	var evidence []*channel.Equivocation
+	for i := range params.Parts {
+		idx := channel.Index(i) //nolint:gosec // The number of participants is bounded.
+		if idx == own {
+			continue
+		}
+		if e, err := channel.NewEquivocation(params, idx, a, b); err == nil {
+			evidence = append(evidence, e)
+		}
+	}
+	return evidence
+}
+
+// checkUpdateEquivocation handles the evidence if the peer at index pidx signed
+// a state with the version of the current state that conflicts with it. The
+// machine must be locked.
+func (c *Channel) checkUpdateEquivocation(pidx channel.Index, state *channel.State, sig wallet.Sig) {
+	current := c.machine.CurrentTX()
+	params := c.machine.Params()
+	if state == nil || state.Version != current.Version || int(pidx) >= len(params.Parts) {
+		return
+	}
+	sigs := make([]wallet.Sig, len(params.Parts))
+	sigs[pidx] = sig
+	e, err := channel.NewEquivocation(params, pidx, current, channel.Transaction{State: state, Sigs: sigs})
+	if err != nil {
+		return
+	}
+	go c.client.handleEquivocations([]*channel.Equivocation{e})
+}
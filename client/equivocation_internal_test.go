@@ -0,0 +1,77 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence/keyvalue"
+	"perun.network/go-perun/channel/test"
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/wallet"
+	wallettest "perun.network/go-perun/wallet/test"
+	"polycry.pt/poly-go/sortedkv/memorydb"
+	pkgtest "polycry.pt/poly-go/test"
+)
+
+func TestEquivocationEvidence(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	accs, addrs := wallettest.NewRandomAccounts(rng, 2, channel.TestBackendID)
+	params, state := test.NewRandomParamsAndState(rng, test.WithParts(addrs), test.WithNumLocked(0), test.WithIsFinal(false))
+	conflicting := state.Clone()
+	conflicting.Balances[0][0] = new(big.Int).Add(conflicting.Balances[0][0], big.NewInt(1))
+	sign := func(s *channel.State) channel.Transaction {
+		sigs := make([]wallet.Sig, len(accs))
+		for i, acc := range accs {
+			sig, err := channel.Sign(acc[channel.TestBackendID], s, channel.TestBackendID)
+			require.NoError(t, err)
+			sigs[i] = sig
+		}
+		return channel.Transaction{State: s, Sigs: sigs}
+	}
+	own, remote := sign(state), sign(conflicting)
+
+	// The state of the peer conflicts with the own state.
+	evidence := detectEquivocations(params, 0, own, remote)
+	require.Len(t, evidence, 1)
+	require.EqualValues(t, 1, evidence[0].Idx)
+	require.NoError(t, evidence[0].Verify())
+
+	// Without a valid signature of the peer, there is no evidence.
+	invalid := channel.Transaction{State: remote.State, Sigs: []wallet.Sig{remote.Sigs[0], remote.Sigs[0]}}
+	require.Empty(t, detectEquivocations(params, 0, own, invalid))
+
+	c := &Client{log: log.Default(), channels: makeChanRegistry()}
+	_, err := c.Equivocations(context.Background(), params.ID())
+	require.Error(t, err)
+
+	c.EnableEquivocationEvidence(keyvalue.NewEquivocationStore(memorydb.NewDatabase()))
+	var handled []*channel.Equivocation
+	c.OnEquivocation(func(e *channel.Equivocation) { handled = append(handled, e) })
+	c.DisputeOnEquivocation(true) // The channel is unknown, so no dispute is registered.
+	c.handleEquivocations(evidence)
+	require.Equal(t, evidence, handled)
+
+	persisted, err := c.Equivocations(context.Background(), params.ID())
+	require.NoError(t, err)
+	require.Len(t, persisted, 1)
+	require.NoError(t, persisted[0].Verify())
+	require.EqualValues(t, 1, persisted[0].Idx)
+}
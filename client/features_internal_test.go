@@ -0,0 +1,87 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	wiretest "perun.network/go-perun/wire/test"
+	"polycry.pt/poly-go/test"
+)
+
+// featureBus is a wire.Bus that reports fixed features for all peers.
+type featureBus struct {
+	*wire.LocalBus
+	features wire.Features
+	err      error
+}
+
+func (b *featureBus) PeerFeatures(context.Context, map[wallet.BackendID]wire.Address) (wire.Features, error) {
+	return b.features, b.err
+}
+
+func TestClient_requireFeatures(t *testing.T) {
+	rng := test.Prng(t)
+	peer := wiretest.NewRandomAddress(rng)
+	ctx := context.Background()
+	newClient := func(bus wire.Bus) *Client {
+		return &Client{conn: clientConn{bus: bus}}
+	}
+
+	t.Run("no feature provider", func(t *testing.T) {
+		c := newClient(wire.NewLocalBus())
+		assert.NoError(t, c.requireFeatures(ctx, &VirtualChannelProposalMsg{}, peer))
+	})
+
+	t.Run("supported", func(t *testing.T) {
+		c := newClient(&featureBus{features: wire.SupportedFeatures})
+		assert.NoError(t, c.requireFeatures(ctx, &SubChannelProposalMsg{}, peer))
+		assert.NoError(t, c.requireFeatures(ctx, &VirtualChannelProposalMsg{}, peer))
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		c := newClient(&featureBus{features: wire.FeatureSubChannels})
+		assert.NoError(t, c.requireFeatures(ctx, &LedgerChannelProposalMsg{}, peer))
+		assert.NoError(t, c.requireFeatures(ctx, &SubChannelProposalMsg{}, peer))
+
+		err := c.requireFeatures(ctx, &VirtualChannelProposalMsg{}, peer)
+		require.True(t, wire.IsFeatureNotSupportedError(err))
+		var ferr *wire.FeatureNotSupportedError
+		require.ErrorAs(t, err, &ferr)
+		assert.Equal(t, wire.FeatureVirtualChannels, ferr.Missing)
+
+		// Virtual channel updates and channel synchronization are gated, too.
+		for _, msg := range []wire.Msg{
+			&VirtualChannelFundingProposalMsg{}, &VirtualChannelSettlementProposalMsg{}, &ChannelSyncMsg{},
+		} {
+			assert.True(t, wire.IsFeatureNotSupportedError(c.requireFeatures(ctx, msg, peer)), "%T", msg)
+		}
+		assert.NoError(t, c.requireFeatures(ctx, &ChannelUpdateMsg{}, peer))
+	})
+
+	t.Run("query fails", func(t *testing.T) {
+		c := newClient(&featureBus{err: errors.New("dial failed")})
+		err := c.requireFeatures(ctx, &SubChannelProposalMsg{}, peer)
+		assert.Error(t, err)
+		assert.False(t, wire.IsFeatureNotSupportedError(err))
+	})
+}
@@ -185,6 +185,9 @@ func (c *Client) ProposeChannel(ctx context.Context, prop ChannelProposal) (*Cha
 	if err := c.validTwoPartyProposal(prop, ProposerIdx, peer); err != nil {
 		return nil, errors.WithMessage(err, "invalid channel proposal")
 	}
+	if err := c.requireFeatures(ctx, prop, peer); err != nil {
+		return nil, err
+	}
 
 	// 2. send proposal, wait for response, create channel object
 	// cache version 1 updates until channel is opened
@@ -381,6 +384,24 @@ func (c *Client) proposeTwoPartyChannel(
 	return c.completeCPP(ctx, proposal, acc, ProposerIdx)
 }
 
+// requireFeatures checks that the peer supports the protocol features that
+// are needed to send it the message. The check is skipped if the bus does not
+// know the features of its peers.
+func (c *Client) requireFeatures(ctx context.Context, msg wire.Msg, peer map[wallet.BackendID]wire.Address) error {
+	var required wire.Features
+	switch msg.(type) {
+	case *SubChannelProposalMsg:
+		required = wire.FeatureSubChannels
+	case *VirtualChannelProposalMsg, *VirtualChannelFundingProposalMsg, *VirtualChannelSettlementProposalMsg:
+		required = wire.FeatureVirtualChannels
+	case *ChannelSyncMsg:
+		required = wire.FeatureChannelSync
+	default:
+		return nil
+	}
+	return wire.RequireFeatures(ctx, c.conn.bus, peer, required)
+}
+
 // validTwoPartyProposal checks that the proposal is valid in the two-party
 // setting, where the proposer is expected to have index 0 in the peer list and
 // the receiver to have index 1. The generic validity of the proposal is also
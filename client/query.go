@@ -0,0 +1,58 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bytes"
+	"sort"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+)
+
+// QueryChannels returns the channels currently loaded by the client that match
+// the query, ordered by their ID. Use persistence.QueryChannels to query
+// persisted channels that are not loaded. Can not be called from an update
+// handler.
+func (c *Client) QueryChannels(q persistence.ChannelQuery) []*Channel {
+	var chs []*Channel
+	for _, ch := range c.channels.Channels() {
+		if ch.matches(q) {
+			chs = append(chs, ch)
+		}
+	}
+
+	sort.Slice(chs, func(i, j int) bool {
+		a, b := chs[i].ID(), chs[j].ID()
+		return bytes.Compare(a[:], b[:]) < 0
+	})
+	if q.Limit > 0 && len(chs) > q.Limit {
+		chs = chs[:q.Limit]
+	}
+	return chs
+}
+
+// matches returns whether the channel matches the query.
+func (c *Channel) matches(q persistence.ChannelQuery) bool {
+	var parent *channel.ID
+	if c.parent != nil {
+		parent = new(channel.ID)
+		*parent = c.parent.ID()
+	}
+
+	c.machMtx.Lock()
+	defer c.machMtx.Unlock()
+	return q.MatchesSource(c.machine, persistence.KindOf(c.Params(), parent), parent)
+}
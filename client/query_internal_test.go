@@ -0,0 +1,72 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	ctest "perun.network/go-perun/channel/test"
+	wtest "perun.network/go-perun/wallet/test"
+	pkgtest "polycry.pt/poly-go/test"
+)
+
+func TestClient_QueryChannels(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	c := &Client{channels: makeChanRegistry()}
+
+	ledger := queryTestCh(t, rng, nil, false)
+	sub := queryTestCh(t, rng, ledger, false)
+	virtual := queryTestCh(t, rng, ledger, true)
+	for _, ch := range []*Channel{ledger, sub, virtual} {
+		require.True(t, c.channels.Put(ch.ID(), ch))
+	}
+	require.NoError(t, ledger.machine.Init(context.Background(), *ctest.NewRandomAllocation(rng, ctest.WithNumParts(2)), channel.NewMockOp(channel.OpValid)))
+	ledgerID := ledger.ID()
+
+	assert.Len(t, c.QueryChannels(persistence.ChannelQuery{}), 3)
+	assert.Equal(t, []*Channel{ledger}, c.QueryChannels(persistence.ChannelQuery{Kind: persistence.LedgerChannel}))
+	assert.Equal(t, []*Channel{sub}, c.QueryChannels(persistence.ChannelQuery{Kind: persistence.SubChannel}))
+	assert.Equal(t, []*Channel{virtual}, c.QueryChannels(persistence.ChannelQuery{Kind: persistence.VirtualChannel}))
+	assert.Len(t, c.QueryChannels(persistence.ChannelQuery{Parent: &ledgerID}), 2)
+	assert.Equal(t, []*Channel{ledger}, c.QueryChannels(persistence.ChannelQuery{Phases: []channel.Phase{channel.InitSigning}}))
+	assert.Equal(t, []*Channel{ledger}, c.QueryChannels(persistence.ChannelQuery{Asset: ledger.machine.StagingState().Assets[0]}))
+
+	first := c.QueryChannels(persistence.ChannelQuery{Limit: 2})
+	require.Len(t, first, 2)
+	lastID := first[1].ID()
+	rest := c.QueryChannels(persistence.ChannelQuery{After: &lastID})
+	require.Len(t, rest, 1)
+	assert.NotContains(t, first, rest[0])
+}
+
+func queryTestCh(t *testing.T, rng *rand.Rand, parent *Channel, virtual bool) *Channel {
+	t.Helper()
+	accs, parts := wtest.NewRandomAccounts(rng, 2, channel.TestBackendID)
+	params := ctest.NewRandomParams(rng, ctest.WithParts(parts), ctest.WithVirtualChannel(virtual))
+	machine, err := channel.NewStateMachine(accs[0], *params)
+	require.NoError(t, err)
+
+	ch := testCh()
+	ch.machine = persistence.FromStateMachine(machine, persistence.NonPersistRestorer)
+	ch.parent = parent
+	return ch
+}
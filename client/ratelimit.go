@@ -0,0 +1,309 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+)
+
+type (
+	// A Rate is the configuration of a token bucket. Tokens are refilled at
+	// PerSecond tokens per second up to Burst tokens, and each request takes
+	// one token.
+	Rate struct {
+		PerSecond float64
+		Burst     int
+	}
+
+	// RateLimits configures the flood protection of the client's request
+	// handling. Requests that exceed a limit are dropped and count as a
+	// violation. Dropped channel proposals and updates are answered with a
+	// rejection. Peers that commit too many violations are temporarily banned
+	// and all their requests are dropped without reply.
+	RateLimits struct {
+		// Default is the rate of requests per peer and message type.
+		Default Rate
+		// PerType overrides the rate for specific message types.
+		PerType map[wire.Type]Rate
+		// MaxConcurrent is the maximum number of requests per peer that are
+		// handled concurrently. A request stops counting once it is passed to
+		// the user's ProposalHandler or UpdateHandler. Zero means no limit.
+		MaxConcurrent int
+		// MaxViolations is the number of violations after which a peer is
+		// banned. Violations are forgotten after BanDuration without further
+		// violation. Zero disables banning.
+		MaxViolations int
+		// BanDuration is the duration of a ban.
+		BanDuration time.Duration
+	}
+
+	// rateLimiter enforces RateLimits on incoming requests.
+	rateLimiter struct {
+		mu        sync.Mutex
+		enabled   bool
+		limits    RateLimits
+		peers     map[wire.AddrKey]*peerLimits
+		lastSweep time.Time // Time at which idle peers were last removed.
+	}
+
+	// peerLimits holds the rate limiting state of a peer.
+	peerLimits struct {
+		buckets       map[wire.Type]*tokenBucket
+		active        int // Number of running handlers.
+		violations    int
+		lastViolation time.Time
+		bannedUntil   time.Time
+	}
+
+	tokenBucket struct {
+		tokens float64
+		last   time.Time
+	}
+)
+
+// DefaultRateLimits returns rate limits that allow 10 requests per second with
+// bursts of 20 requests per peer and message type, 16 concurrent requests per
+// peer, and ban a peer for 10 minutes after 100 violations.
+func DefaultRateLimits() RateLimits {
+	return RateLimits{
+		Default:       Rate{PerSecond: 10, Burst: 20}, //nolint:mnd
+		MaxConcurrent: 16,                             //nolint:mnd
+		MaxViolations: 100,                            //nolint:mnd
+		BanDuration:   10 * time.Minute,               //nolint:mnd
+	}
+}
+
+// EnableRateLimits enables the flood protection of the client's request
+// handling. This method is expected to be called once during the setup of the
+// client, before Handle is started.
+func (c *Client) EnableRateLimits(limits RateLimits) {
+	c.rateLimiter.mu.Lock()
+	defer c.rateLimiter.mu.Unlock()
+	c.rateLimiter.enabled = true
+	c.rateLimiter.limits = limits
+	c.rateLimiter.peers = make(map[wire.AddrKey]*peerLimits)
+}
+
+// rateLimitSweepInterval is the interval in which the rate limiting state of
+// idle peers is removed.
+const rateLimitSweepInterval = time.Minute
+
+// rateLimitReason is the reason of the rejection of a dropped request.
+const rateLimitReason = "rate limit exceeded"
+
+// spawnHandler runs the request handler in a new goroutine if the request is
+// within the rate limits, and drops it otherwise. The handler is called with
+// wrappers of ph and uh that release the request's concurrency slot before
+// calling the user's handler.
+func (c *Client) spawnHandler(
+	env *wire.Envelope,
+	ph ProposalHandler,
+	uh UpdateHandler,
+	handle func(ProposalHandler, UpdateHandler),
+) {
+	now := time.Now()
+	done, ok := c.rateLimiter.admit(env.Sender, env.Msg.Type(), now)
+	if !ok {
+		c.logPeer(env.Sender).Warnf("Dropping %T request: %s", env.Msg, rateLimitReason)
+		if !c.rateLimiter.banned(env.Sender, now) {
+			c.reject(env, rateLimitReason)
+		}
+		return
+	}
+	release := sync.OnceFunc(done)
+	c.handlers.add(env.Sender)
+	go func() {
+		defer c.handlers.done(env.Sender)
+		defer release()
+		handle(&releasingProposalHandler{ph, release}, &releasingUpdateHandler{uh, release})
+	}()
+}
+
+// reject answers a channel proposal or update request that is not handled with
+// a rejection, so that the peer does not wait for a response until it times
+// out. The rejection is tracked as a running handler of the peer.
+func (c *Client) reject(env *wire.Envelope, reason string) {
+	var rej wire.Msg
+	switch msg := env.Msg.(type) {
+	case ChannelProposal:
+		rej = &ChannelProposalRejMsg{ProposalID: msg.Base().ProposalID, Reason: reason}
+	case ChannelUpdateProposal:
+		rej = &ChannelUpdateRejMsg{
+			ChannelID: msg.Base().ID(),
+			Version:   msg.Base().State.Version,
+			Reason:    reason,
+		}
+	default:
+		return
+	}
+
+	c.handlers.add(env.Sender)
+	go func() {
+		defer c.handlers.done(env.Sender)
+		ctx, cancel := context.WithTimeout(c.Ctx(), responseTimeout)
+		defer cancel()
+		if err := c.conn.pubMsg(ctx, rej, env.Sender); err != nil {
+			c.logPeer(env.Sender).Warnf("Rejecting %T request: %v", env.Msg, err)
+		}
+	}()
+}
+
+// releasingProposalHandler releases the concurrency slot of a request before
+// calling the user's ProposalHandler, which may wait for the user's decision
+// for an arbitrary time.
+type releasingProposalHandler struct {
+	ProposalHandler
+	release func()
+}
+
+// HandleProposal releases the request and calls the wrapped handler.
+func (h *releasingProposalHandler) HandleProposal(p ChannelProposal, r *ProposalResponder) {
+	h.release()
+	h.ProposalHandler.HandleProposal(p, r)
+}
+
+// releasingUpdateHandler is the UpdateHandler equivalent of
+// releasingProposalHandler.
+type releasingUpdateHandler struct {
+	UpdateHandler
+	release func()
+}
+
+// HandleUpdate releases the request and calls the wrapped handler.
+func (h *releasingUpdateHandler) HandleUpdate(s *channel.State, u ChannelUpdate, r *UpdateResponder) {
+	h.release()
+	h.UpdateHandler.HandleUpdate(s, u, r)
+}
+
+// admit checks whether a request of the peer may be handled. If so, it returns
+// a function that must be called when the handling is done.
+func (l *rateLimiter) admit(peer map[wallet.BackendID]wire.Address, t wire.Type, now time.Time) (done func(), ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.enabled {
+		return func() {}, true
+	}
+	if now.Sub(l.lastSweep) >= rateLimitSweepInterval {
+		l.removeIdle(now)
+	}
+
+	p, exists := l.peers[wire.Keys(peer)]
+	if !exists {
+		p = &peerLimits{buckets: make(map[wire.Type]*tokenBucket)}
+		l.peers[wire.Keys(peer)] = p
+	}
+	if now.Before(p.bannedUntil) {
+		return nil, false
+	}
+
+	rate := l.rate(t)
+	b, exists := p.buckets[t]
+	if !exists {
+		b = &tokenBucket{tokens: float64(rate.Burst), last: now}
+		p.buckets[t] = b
+	}
+	if (l.limits.MaxConcurrent > 0 && p.active >= l.limits.MaxConcurrent) || !b.take(rate, now) {
+		l.violate(peer, p, now)
+		return nil, false
+	}
+
+	p.active++
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		p.active--
+	}, true
+}
+
+// rate returns the rate of the message type.
+func (l *rateLimiter) rate(t wire.Type) Rate {
+	if rate, ok := l.limits.PerType[t]; ok {
+		return rate
+	}
+	return l.limits.Default
+}
+
+// removeIdle removes the state of all peers that are idle, so that the state
+// of peers that stopped sending requests does not accumulate. Removing an idle
+// peer does not change its limits, because its state equals that of a new
+// peer. The mutex must be held.
+func (l *rateLimiter) removeIdle(now time.Time) {
+	l.lastSweep = now
+	for key, p := range l.peers {
+		if l.idle(p, now) {
+			delete(l.peers, key)
+		}
+	}
+}
+
+// idle returns whether the peer has no running handlers, is not banned, has
+// no violations that are not yet forgotten, and all its buckets are refilled.
+func (l *rateLimiter) idle(p *peerLimits, now time.Time) bool {
+	if p.active > 0 || now.Before(p.bannedUntil) ||
+		(p.violations > 0 && now.Sub(p.lastViolation) <= l.limits.BanDuration) {
+		return false
+	}
+	for t, b := range p.buckets {
+		if !b.full(l.rate(t), now) {
+			return false
+		}
+	}
+	return true
+}
+
+// banned returns whether the peer is banned at the given time.
+func (l *rateLimiter) banned(peer map[wallet.BackendID]wire.Address, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	p, ok := l.peers[wire.Keys(peer)]
+	return ok && now.Before(p.bannedUntil)
+}
+
+// violate records a violation of the peer and bans it if it committed too many.
+func (l *rateLimiter) violate(peer map[wallet.BackendID]wire.Address, p *peerLimits, now time.Time) {
+	if now.Sub(p.lastViolation) > l.limits.BanDuration {
+		p.violations = 0
+	}
+	p.violations++
+	p.lastViolation = now
+	if l.limits.MaxViolations > 0 && p.violations >= l.limits.MaxViolations {
+		p.bannedUntil = now.Add(l.limits.BanDuration)
+		p.violations = 0
+		log.WithField("peer", peer).Warnf("Banning peer until %v: too many requests", p.bannedUntil)
+	}
+}
+
+// full returns whether the bucket is refilled completely at the given time.
+func (b *tokenBucket) full(r Rate, now time.Time) bool {
+	return b.tokens+now.Sub(b.last).Seconds()*r.PerSecond >= float64(r.Burst)
+}
+
+// take refills the bucket and takes a token if one is available.
+func (b *tokenBucket) take(r Rate, now time.Time) bool {
+	b.tokens = min(float64(r.Burst), b.tokens+now.Sub(b.last).Seconds()*r.PerSecond)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,190 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	wiretest "perun.network/go-perun/wire/test"
+	"polycry.pt/poly-go/test"
+)
+
+func TestRateLimiter(t *testing.T) {
+	rng := test.Prng(t)
+	alice, bob := wiretest.NewRandomAddress(rng), wiretest.NewRandomAddress(rng)
+	now := time.Now()
+	newLimiter := func(limits RateLimits) *rateLimiter {
+		l := &rateLimiter{}
+		l.enabled, l.limits, l.peers = true, limits, make(map[wire.AddrKey]*peerLimits)
+		return l
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		var l rateLimiter
+		for range 100 {
+			_, ok := l.admit(alice, wire.ChannelUpdate, now)
+			require.True(t, ok)
+		}
+	})
+
+	t.Run("token bucket", func(t *testing.T) {
+		l := newLimiter(RateLimits{
+			Default: Rate{PerSecond: 1, Burst: 2},
+			PerType: map[wire.Type]Rate{wire.ChannelSync: {PerSecond: 1, Burst: 1}},
+		})
+		for range 2 {
+			_, ok := l.admit(alice, wire.ChannelUpdate, now)
+			require.True(t, ok)
+		}
+		_, ok := l.admit(alice, wire.ChannelUpdate, now)
+		assert.False(t, ok, "burst exceeded")
+
+		// Limits are per peer and message type.
+		_, ok = l.admit(bob, wire.ChannelUpdate, now)
+		assert.True(t, ok)
+		_, ok = l.admit(alice, wire.ChannelSync, now)
+		assert.True(t, ok)
+		_, ok = l.admit(alice, wire.ChannelSync, now)
+		assert.False(t, ok, "per-type burst exceeded")
+
+		// Tokens are refilled over time.
+		_, ok = l.admit(alice, wire.ChannelUpdate, now.Add(time.Second))
+		assert.True(t, ok)
+	})
+
+	t.Run("concurrency", func(t *testing.T) {
+		l := newLimiter(RateLimits{Default: Rate{PerSecond: 100, Burst: 100}, MaxConcurrent: 1})
+		done, ok := l.admit(alice, wire.ChannelUpdate, now)
+		require.True(t, ok)
+		_, ok = l.admit(alice, wire.LedgerChannelProposal, now)
+		assert.False(t, ok)
+		done()
+		_, ok = l.admit(alice, wire.LedgerChannelProposal, now)
+		assert.True(t, ok)
+	})
+
+	t.Run("ban", func(t *testing.T) {
+		l := newLimiter(RateLimits{Default: Rate{PerSecond: 1, Burst: 1}, MaxViolations: 2, BanDuration: time.Minute})
+		_, ok := l.admit(alice, wire.ChannelUpdate, now)
+		require.True(t, ok)
+		for range 2 {
+			_, ok = l.admit(alice, wire.ChannelUpdate, now)
+			require.False(t, ok)
+		}
+
+		// Banned peers are dropped even if they are within the limits.
+		_, ok = l.admit(alice, wire.ChannelSync, now)
+		assert.False(t, ok)
+		_, ok = l.admit(bob, wire.ChannelSync, now)
+		assert.True(t, ok)
+
+		_, ok = l.admit(alice, wire.ChannelSync, now.Add(time.Minute))
+		assert.True(t, ok, "ban expired")
+	})
+
+	t.Run("remove idle", func(t *testing.T) {
+		l := newLimiter(RateLimits{Default: Rate{PerSecond: 1, Burst: 2}, MaxViolations: 2, BanDuration: time.Hour})
+		handle := func(peer map[wallet.BackendID]wire.Address, at time.Time) {
+			done, ok := l.admit(peer, wire.ChannelUpdate, at)
+			require.True(t, ok)
+			done()
+		}
+		carol := wiretest.NewRandomAddress(rng)
+		done, ok := l.admit(alice, wire.ChannelUpdate, now)
+		require.True(t, ok)
+		handle(bob, now)
+		handle(bob, now)
+		_, ok = l.admit(bob, wire.ChannelUpdate, now)
+		require.False(t, ok)
+		handle(carol, now)
+		require.Len(t, l.peers, 3)
+
+		// Only Carol is idle once her bucket is refilled: Alice's request is
+		// still running and Bob's violation is not forgotten yet.
+		later := now.Add(rateLimitSweepInterval)
+		handle(wiretest.NewRandomAddress(rng), later)
+		assert.Len(t, l.peers, 3)
+		assert.NotContains(t, l.peers, wire.Keys(carol))
+		assert.Contains(t, l.peers, wire.Keys(alice))
+		assert.Contains(t, l.peers, wire.Keys(bob))
+
+		// Peers are removed at most once per interval.
+		done()
+		handle(carol, later.Add(time.Second))
+		assert.Contains(t, l.peers, wire.Keys(alice))
+		handle(carol, later.Add(rateLimitSweepInterval))
+		assert.NotContains(t, l.peers, wire.Keys(alice))
+	})
+}
+
+func TestClient_spawnHandler(t *testing.T) {
+	rng := test.Prng(t)
+	bus := wire.NewLocalBus()
+	addr, peer := wiretest.NewRandomAddress(rng), wiretest.NewRandomAddress(rng)
+	conn, err := makeClientConn(addr, bus)
+	require.NoError(t, err)
+	c := &Client{conn: conn, log: log.Default()}
+	t.Cleanup(func() { assert.NoError(t, c.conn.Close()) })
+	c.EnableRateLimits(RateLimits{Default: Rate{PerSecond: 100, Burst: 100}, MaxConcurrent: 1})
+	recv := wire.NewReceiver()
+	require.NoError(t, bus.SubscribeClient(recv, peer))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	proposal := &LedgerChannelProposalMsg{BaseChannelProposal: BaseChannelProposal{ProposalID: ProposalID{1}}}
+	update := &ChannelUpdateMsg{ChannelUpdate: ChannelUpdate{State: &channel.State{ID: channel.ID{2}, Version: 3}}}
+	env := func(msg wire.Msg) *wire.Envelope { return &wire.Envelope{Sender: peer, Recipient: addr, Msg: msg} }
+
+	// A request that waits for the user's handler is not counted.
+	called, unblock := make(chan struct{}), make(chan struct{})
+	defer close(unblock)
+	ph := proposalHandlerFunc(func(ChannelProposal, *ProposalResponder) {
+		called <- struct{}{}
+		<-unblock
+	})
+	c.spawnHandler(env(proposal), ph, nil, func(ph ProposalHandler, _ UpdateHandler) { ph.HandleProposal(proposal, nil) })
+	<-called
+	handled := make(chan struct{})
+	c.spawnHandler(env(update), nil, nil, func(ProposalHandler, UpdateHandler) {
+		handled <- struct{}{}
+		<-unblock
+	})
+	<-handled
+
+	// The update handler above has not passed the request to the user, so the
+	// next requests are dropped and rejected.
+	c.spawnHandler(env(proposal), nil, nil, func(ProposalHandler, UpdateHandler) { t.Error("proposal not dropped") })
+	rej, err := recv.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &ChannelProposalRejMsg{ProposalID: proposal.ProposalID, Reason: rateLimitReason}, rej.Msg)
+
+	c.spawnHandler(env(update), nil, nil, func(ProposalHandler, UpdateHandler) { t.Error("update not dropped") })
+	rej, err = recv.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &ChannelUpdateRejMsg{ChannelID: update.State.ID, Version: update.State.Version, Reason: rateLimitReason}, rej.Msg)
+}
+
+type proposalHandlerFunc func(ChannelProposal, *ProposalResponder)
+
+func (f proposalHandlerFunc) HandleProposal(p ChannelProposal, r *ProposalResponder) { f(p, r) }
@@ -50,6 +50,10 @@ func (c *Client) handleSyncMsg(peer map[wallet.BackendID]wire.Address, msg *Chan
 	}
 	defer ch.machMtx.Unlock()
 
+	if evidence := detectEquivocations(ch.machine.Params(), ch.machine.Idx(), ch.machine.CurrentTX(), msg.CurrentTX); len(evidence) > 0 {
+		go c.handleEquivocations(evidence)
+	}
+
 	syncMsg := newChannelSyncMsg(persistence.CloneSource(ch.machine))
 	if err := c.conn.pubMsg(ctx, syncMsg, peer); err != nil {
 		log.Error("Error sending sync reply: ", err)
@@ -72,6 +76,13 @@ func (c *Client) handleSyncMsg(peer map[wallet.BackendID]wire.Address, msg *Chan
 //
 //nolint:unused
 func (c *Client) syncChannel(ctx context.Context, ch *persistence.Channel, p map[wallet.BackendID]wire.Address) (err error) {
+	// syncMsg needs to be a clone so that there's no data race when updating the
+	// own channel data later.
+	syncMsg := newChannelSyncMsg(persistence.CloneSource(ch))
+	if err = c.requireFeatures(ctx, syncMsg, p); err != nil {
+		return err
+	}
+
 	recv := wire.NewReceiver()
 	defer recv.Close() // ignore error
 	id := ch.ID()
@@ -84,10 +95,6 @@ func (c *Client) syncChannel(ctx context.Context, ch *persistence.Channel, p map
 	}
 
 	sendError := make(chan error, 1)
-	// syncMsg needs to be a clone so that there's no data race when updating the
-	// own channel data later.
-	syncMsg := newChannelSyncMsg(persistence.CloneSource(ch))
-
 	go func() { sendError <- c.conn.pubMsg(ctx, syncMsg, p) }()
 
 	defer func() {
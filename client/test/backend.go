@@ -17,6 +17,7 @@ package test
 import (
 	"context"
 	"encoding"
+	"encoding/binary"
 	"fmt"
 	"math"
 	"math/big"
@@ -41,6 +42,16 @@ type (
 		eventSubs    map[channel.ID][]*MockSubscription
 		balances     map[addressMapKey]map[assetMapKey]*big.Int
 		id           multi.LedgerBackendID
+
+		// eventLogs holds all events of each channel in the order in which
+		// they occurred. eventLogged is closed and replaced whenever an
+		// event is logged.
+		eventLogs   map[channel.ID][]channel.AdjudicatorEvent
+		eventLogged chan struct{}
+
+		// registrations holds the RegisteredEvents of all channels in the
+		// order in which they occurred.
+		registrations []channel.AdjudicatorEvent
 	}
 
 	// AssetID is the unique asset identifier.
@@ -86,6 +97,8 @@ func NewMockBackend(rng *rand.Rand, id string) *MockBackend {
 		assetHolder:  newAssetHolder(newThreadSafePrng(backendRng)),
 		latestEvents: make(map[channel.ID]channel.AdjudicatorEvent),
 		eventSubs:    make(map[channel.ID][]*MockSubscription),
+		eventLogs:    make(map[channel.ID][]channel.AdjudicatorEvent),
+		eventLogged:  make(chan struct{}),
 		balances:     make(map[string]map[string]*big.Int),
 		id:           AssetID{0, LedgerID(id)},
 	}
@@ -232,6 +245,36 @@ func (b *MockBackend) Subscribe(ctx context.Context, chID channel.ID) (channel.A
 	return sub, nil
 }
 
+// SubscribeFrom creates an event subscription that starts with the first
+// event after the cursor.
+func (b *MockBackend) SubscribeFrom(_ context.Context, chID channel.ID, cursor channel.EventCursor) (channel.CursorSubscription, error) {
+	b.log.Infof("SubscribeFrom: %+v, %x", chID, cursor)
+
+	var pos uint64
+	switch len(cursor) {
+	case 0:
+	case mockCursorLen:
+		pos = binary.BigEndian.Uint64(cursor)
+	default:
+		return nil, fmt.Errorf("invalid cursor length: %d", len(cursor))
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if n := uint64(len(b.eventLogs[chID])); pos > n {
+		return nil, fmt.Errorf("cursor %d beyond the %d events of channel", pos, n)
+	}
+	return &MockCursorSubscription{b: b, ch: chID, pos: pos}, nil
+}
+
+// SubscribeRegistrations creates a subscription for the registrations of all
+// channels that are registered after the call.
+func (b *MockBackend) SubscribeRegistrations(context.Context) (channel.AdjudicatorSubscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return &MockRegistrationSubscription{b: b, pos: len(b.registrations)}, nil
+}
+
 // Progress progresses the channel state.
 func (b *MockBackend) Progress(_ context.Context, req channel.ProgressReq) error {
 	b.log.Infof("Progress: %+v", req)
@@ -343,6 +386,12 @@ func (b *MockBackend) Balance(p wallet.Address, a channel.Asset) *big.Int {
 
 func (b *MockBackend) setLatestEvent(ch channel.ID, e channel.AdjudicatorEvent) {
 	b.latestEvents[ch] = e
+	b.eventLogs[ch] = append(b.eventLogs[ch], e)
+	if _, ok := e.(*channel.RegisteredEvent); ok {
+		b.registrations = append(b.registrations, e)
+	}
+	close(b.eventLogged)
+	b.eventLogged = make(chan struct{})
 	// Update subscriptions.
 	if channelSubs, ok := b.eventSubs[ch]; ok {
 		for _, sub := range channelSubs {
@@ -619,3 +668,89 @@ func (s *MockSubscription) Close() error {
 func (s *MockSubscription) Err() error {
 	return <-s.err
 }
+
+// mockCursorLen is the length of the cursors of the MockBackend. A cursor
+// holds the number of events of the channel that have been returned.
+const mockCursorLen = 8
+
+// MockCursorSubscription is a subscription for MockBackend that returns all
+// events of a channel in order.
+type MockCursorSubscription struct {
+	sync.Closer
+	b   *MockBackend
+	ch  channel.ID
+	mu  sync.Mutex
+	pos uint64
+}
+
+// Next returns the next event.
+func (s *MockCursorSubscription) Next() channel.AdjudicatorEvent {
+	for {
+		s.b.mu.Lock()
+		events, logged := s.b.eventLogs[s.ch], s.b.eventLogged
+		s.b.mu.Unlock()
+
+		s.mu.Lock()
+		if s.pos < uint64(len(events)) {
+			e := events[s.pos]
+			s.pos++
+			s.mu.Unlock()
+			return e
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-logged:
+		case <-s.Closed():
+			return nil
+		}
+	}
+}
+
+// Cursor returns the position after the last event returned by Next.
+func (s *MockCursorSubscription) Cursor() channel.EventCursor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return binary.BigEndian.AppendUint64(nil, s.pos)
+}
+
+// Err returns the subscription's error after it has been closed.
+func (s *MockCursorSubscription) Err() error {
+	<-s.Closed()
+	return nil
+}
+
+// MockRegistrationSubscription is a subscription for MockBackend that returns
+// the registrations of all channels in order.
+type MockRegistrationSubscription struct {
+	sync.Closer
+	b   *MockBackend
+	pos int // only accessed by Next
+}
+
+// Next returns the next registration.
+func (s *MockRegistrationSubscription) Next() channel.AdjudicatorEvent {
+	for {
+		s.b.mu.Lock()
+		registrations, logged := s.b.registrations, s.b.eventLogged
+		s.b.mu.Unlock()
+
+		if s.pos < len(registrations) {
+			e := registrations[s.pos]
+			s.pos++
+			return e
+		}
+
+		select {
+		case <-logged:
+		case <-s.Closed():
+			return nil
+		}
+	}
+}
+
+// Err returns the subscription's error after it has been closed.
+func (s *MockRegistrationSubscription) Err() error {
+	<-s.Closed()
+	return nil
+}
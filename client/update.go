@@ -210,6 +210,14 @@ func (c *Channel) updateGeneric(
 		Sig:           sig,
 	}
 	msg := prepareMsg(msgUpdate)
+	for i, peer := range c.Peers() {
+		if channel.Index(i) == c.Idx() {
+			continue
+		}
+		if err = c.client.requireFeatures(ctx, msg, peer); err != nil {
+			return err
+		}
+	}
 	if err = c.conn.Send(ctx, msg); err != nil {
 		return errors.WithMessage(err, "sending update")
 	}
@@ -273,6 +281,7 @@ func (c *Channel) handleUpdateReq(
 
 	if err := c.machine.CheckUpdate(req.Base().State, req.Base().ActorIdx, req.Base().Sig, pidx); err != nil {
 		c.logPeer(pidx).Warnf("invalid update received: %v", err)
+		c.checkUpdateEquivocation(pidx, req.Base().State, req.Base().Sig)
 		return
 	}
 
@@ -104,49 +104,31 @@ func (c *Client) handleVirtualChannelFundingProposal(
 	c.acceptProposal(responder)
 }
 
-func (c *Channel) watchVirtual() error {
-	log := c.Log().WithField("proc", fmt.Sprintf("virtual channel watcher %v", c.ID()))
-	defer log.Info("Watcher returned.")
-
-	// Subscribe to state changes
-	ctx := c.Ctx()
-	sub, err := c.adjudicator.Subscribe(ctx, c.Params().ID())
-	if err != nil {
-		return errors.WithMessage(err, "subscribing to adjudicator state changes")
-	}
+// hubVirtualHandler applies the newer states of a virtual channel that are
+// registered by its participants to the hub's channel, so that the hub can
+// enforce them on both parents.
+type hubVirtualHandler struct {
+	ch *Channel
+}
 
-	defer func() {
-		if err := sub.Close(); err != nil {
-			log.Warn(err)
+func (h hubVirtualHandler) HandleAdjudicatorEvent(e channel.AdjudicatorEvent) {
+	c := h.ch
+	log := c.Log().WithField("proc", fmt.Sprintf("virtual channel watcher %v", c.ID()))
+	switch e := e.(type) {
+	case *channel.RegisteredEvent:
+		if e.Version() <= c.State().Version {
+			return
+		}
+		if err := c.pushVirtualUpdate(c.Ctx(), e.State, e.Sigs); err != nil {
+			log.Warnf("error updating virtual channel: %v", err)
 		}
-	}()
-
-	// Wait for state changed event
-	for e := sub.Next(); e != nil; e = sub.Next() {
-		// Update channel
-		switch e := e.(type) {
-		case *channel.RegisteredEvent:
-			if e.Version() > c.State().Version {
-				err := c.pushVirtualUpdate(ctx, e.State, e.Sigs)
-				if err != nil {
-					log.Warnf("error updating virtual channel: %v", err)
-				}
-			}
-
-		case *channel.ProgressedEvent:
-			log.Errorf("Virtual channel progressed: %v", e.ID())
 
-		case *channel.ConcludedEvent:
-			log.Infof("Virtual channel concluded: %v", e.ID())
+	case *channel.ProgressedEvent:
+		log.Errorf("Virtual channel progressed: %v", e.ID())
 
-		default:
-			log.Errorf("unsupported type: %T", e)
-		}
+	case *channel.ConcludedEvent:
+		log.Infof("Virtual channel concluded: %v", e.ID())
 	}
-
-	err = sub.Err()
-	log.Debugf("Subscription closed: %v", err)
-	return errors.WithMessage(err, "subscription closed")
 }
 
 // dummyAcount represents an address but cannot be used for signing.
@@ -215,6 +197,9 @@ func (c *Client) persistVirtualChannel(ctx context.Context, parent *Channel, pee
 	return ch, nil
 }
 
+// pushVirtualUpdate forces the hub's virtual channel to the given state and
+// publishes it to the watcher. The update is pushed when the state is
+// registered, so the dispute phase of the machine is kept.
 func (c *Channel) pushVirtualUpdate(ctx context.Context, state *channel.State, sigs []wallet.Sig) error {
 	if !c.machMtx.TryLockCtx(ctx) {
 		return errors.Errorf("locking machine mutex in time: %v", ctx.Err())
@@ -222,6 +207,7 @@ func (c *Channel) pushVirtualUpdate(ctx context.Context, state *channel.State, s
 	defer c.machMtx.Unlock()
 
 	m := c.machine
+	phase := m.Phase()
 	if err := m.ForceUpdate(ctx, state, hubIndex); err != nil {
 		return err
 	}
@@ -242,7 +228,21 @@ func (c *Channel) pushVirtualUpdate(ctx context.Context, state *channel.State, s
 	} else {
 		err = m.EnableUpdate(ctx)
 	}
-	return err
+	if err != nil {
+		return err
+	}
+
+	switch phase {
+	case channel.Registering:
+		err = m.SetRegistering(ctx)
+	case channel.Registered:
+		err = m.SetRegistered(ctx)
+	}
+	if err != nil {
+		return errors.WithMessage(err, "restoring dispute phase")
+	}
+
+	return errors.WithMessage(c.statesPub.Publish(ctx, m.CurrentTX()), "publishing state to watcher")
 }
 
 func (c *Client) validateVirtualChannelFundingProposal(
@@ -366,10 +366,10 @@ func (c *Client) matchFundingProposal(ctx context.Context, a, b interface{}) boo
 		return false
 	}
 
-	//nolint:contextcheck
+	virtual.hubParents = channels
+
 	go func() {
-		// The context will be derived from the channel context.
-		err := virtual.watchVirtual()
+		err := virtual.Watch(hubVirtualHandler{virtual})
 		c.log.Debugf("channel %v: watcher stopped: %v", virtual.ID(), err)
 	}()
 	return true
@@ -0,0 +1,93 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/channel/persistence"
+	"perun.network/go-perun/channel/test"
+	"perun.network/go-perun/wallet"
+	wallettest "perun.network/go-perun/wallet/test"
+	"polycry.pt/poly-go/sync"
+	pkgtest "polycry.pt/poly-go/test"
+)
+
+func TestChannel_pushVirtualUpdate(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	ctx := context.Background()
+	accs, addrs := wallettest.NewRandomAccounts(rng, 2, channel.TestBackendID)
+	params, state := test.NewRandomParamsAndState(rng,
+		test.WithParts(addrs), test.WithoutApp(), test.WithNumLocked(0), test.WithIsFinal(false), test.WithVersion(0))
+	sign := func(s *channel.State) []wallet.Sig {
+		sigs := make([]wallet.Sig, len(accs))
+		for i, acc := range accs {
+			sig, err := channel.Sign(acc[channel.TestBackendID], s, channel.TestBackendID)
+			require.NoError(t, err)
+			sigs[i] = sig
+		}
+		return sigs
+	}
+
+	machine, err := channel.NewStateMachine(accs[hubIndex], *params)
+	require.NoError(t, err)
+	m := persistence.FromStateMachine(machine, persistence.NonPersistRestorer)
+	require.NoError(t, m.Init(ctx, state.Allocation, state.Data))
+	for i, sig := range sign(state) {
+		require.NoError(t, m.AddSig(ctx, channel.Index(i), sig))
+	}
+	require.NoError(t, m.EnableInit(ctx))
+	require.NoError(t, m.SetFunded(ctx))
+	pub := &recordingStatesPub{}
+	ch := &Channel{machine: m, OnCloser: new(sync.Closer), statesPub: pub}
+
+	// The hub's channel stays registered when the newer registered state is
+	// pushed.
+	require.NoError(t, m.SetRegistered(ctx))
+	registered := state.Clone()
+	registered.Version++
+	require.NoError(t, ch.pushVirtualUpdate(ctx, registered, sign(registered)))
+	assert.Equal(t, channel.Registered, m.Phase())
+	assert.Equal(t, registered.Version, m.State().Version)
+	require.Len(t, pub.txs, 1)
+	assert.Equal(t, registered.Version, pub.txs[0].Version)
+
+	// An error of the watcher is returned.
+	pub.err = errors.New("watcher unreachable")
+	newer := registered.Clone()
+	newer.Version++
+	require.ErrorIs(t, ch.pushVirtualUpdate(ctx, newer, sign(newer)), pub.err)
+}
+
+// recordingStatesPub records the published transactions and fails with err if
+// it is set.
+type recordingStatesPub struct {
+	txs []channel.Transaction
+	err error
+}
+
+func (p *recordingStatesPub) Publish(_ context.Context, tx channel.Transaction) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.txs = append(p.txs, tx)
+	return nil
+}
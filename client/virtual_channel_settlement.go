@@ -207,7 +207,7 @@ func (c *Client) matchSettlementProposal(ctx context.Context, a, b interface{})
 		return false
 	}
 	c.channels.Delete(virtual.ID())
-	err = virtual.machine.SetWithdrawn(ctx)
+	err = virtual.setWithdrawn(ctx)
 	return true
 }
 
@@ -0,0 +1,101 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command perun-fsck checks a LevelDB channel database written by the
+// keyvalue persister for inconsistencies and optionally repairs them.
+//
+// Usage:
+//
+//	perun-fsck [-repair] <database directory>
+//
+// The exit code is 0 if no issues remain, 1 if issues remain and 2 on errors.
+//
+// Persisted channel data can only be decoded with the blockchain backend that
+// created it. This command links the simulated backend only. For other
+// backends, build a copy of this command that imports the backend's package.
+package main // import "perun.network/go-perun/cmd/perun-fsck"
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	_ "perun.network/go-perun/backend/sim" // backend init
+	"perun.network/go-perun/channel/persistence/fsck"
+	"perun.network/go-perun/channel/persistence/keyvalue"
+	"polycry.pt/poly-go/sortedkv/leveldb"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	repair := flag.Bool("repair", false, "repair all issues that can be repaired safely")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: perun-fsck [-repair] <database directory>")
+		return 2
+	}
+
+	dir := flag.Arg(0)
+	if _, err := os.Stat(dir); err != nil {
+		fmt.Fprintln(os.Stderr, "opening database:", err)
+		return 2
+	}
+	db, err := leveldb.LoadDatabase(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "opening database:", err)
+		return 2
+	}
+	pr := keyvalue.NewPersistRestorer(db)
+	defer pr.Close()
+
+	ctx := context.Background()
+	rep, err := fsck.Check(ctx, pr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "checking database:", err)
+		return 2
+	}
+	if _, err := rep.WriteTo(os.Stdout); err != nil {
+		return 2
+	}
+	if rep.OK() || !*repair {
+		return exitCode(rep)
+	}
+
+	repaired, err := fsck.Repair(ctx, pr, rep)
+	fmt.Fprintf(os.Stdout, "repaired %d issues\n", len(repaired))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "repairing database:", err)
+		return 2
+	}
+
+	if rep, err = fsck.Check(ctx, pr); err != nil {
+		fmt.Fprintln(os.Stderr, "checking database:", err)
+		return 2
+	}
+	if _, err := rep.WriteTo(os.Stdout); err != nil {
+		return 2
+	}
+	return exitCode(rep)
+}
+
+func exitCode(rep *fsck.Report) int {
+	if rep.OK() {
+		return 0
+	}
+	return 1
+}
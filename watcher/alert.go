@@ -0,0 +1,97 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watcher
+
+import (
+	"fmt"
+	"time"
+
+	"perun.network/go-perun/channel"
+)
+
+// AlertKind is the kind of an Alert.
+type AlertKind uint8
+
+// Alert kinds.
+const (
+	// AlertRegistered signals a registration that was not made by the
+	// watcher, e.g., by a counterparty.
+	AlertRegistered AlertKind = iota
+	// AlertOutdatedState signals that an outdated state was registered.
+	AlertOutdatedState
+	// AlertRefuted signals that the watcher refuted an outdated state.
+	AlertRefuted
+	// AlertRefutationFailed signals that the watcher failed to refute an
+	// outdated state.
+	AlertRefutationFailed
+	// AlertTimeoutNearing signals that the timeout of the current dispute
+	// phase is about to elapse.
+	AlertTimeoutNearing
+	// AlertConcluded signals that the channel was concluded.
+	AlertConcluded
+)
+
+type (
+	// Alert notifies about a dispute of a watched channel.
+	Alert struct {
+		Kind    AlertKind
+		Channel channel.ID
+		// Version is the version of the registered, progressed or concluded
+		// state.
+		Version uint64
+		// LatestVersion is the latest version known to the watcher.
+		LatestVersion uint64
+		// Timeout is the time at which the dispute phase times out. It is only
+		// set for AlertTimeoutNearing.
+		Timeout time.Time
+		// Err is the error of a failed refutation.
+		Err  error
+		Time time.Time
+	}
+
+	// Notifier is notified about the disputes of watched channels.
+	//
+	// Notify is called synchronously while the watcher handles the
+	// adjudicator event and thus must not block.
+	Notifier interface {
+		Notify(Alert)
+	}
+
+	// NotifierFunc is a function that implements Notifier.
+	NotifierFunc func(Alert)
+)
+
+// Notify calls f.
+func (f NotifierFunc) Notify(a Alert) { f(a) }
+
+// String returns the name of the alert kind.
+func (k AlertKind) String() string {
+	switch k {
+	case AlertRegistered:
+		return "registered"
+	case AlertOutdatedState:
+		return "outdated_state"
+	case AlertRefuted:
+		return "refuted"
+	case AlertRefutationFailed:
+		return "refutation_failed"
+	case AlertTimeoutNearing:
+		return "timeout_nearing"
+	case AlertConcluded:
+		return "concluded"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(k))
+	}
+}
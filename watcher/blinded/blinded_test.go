@@ -0,0 +1,470 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blinded_test
+
+import (
+	"context"
+	"math/big"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "perun.network/go-perun/backend/sim" // backend init
+	"perun.network/go-perun/channel"
+	ctest "perun.network/go-perun/channel/test"
+	clienttest "perun.network/go-perun/client/test"
+	"perun.network/go-perun/wallet"
+	wtest "perun.network/go-perun/wallet/test"
+	"perun.network/go-perun/watcher/blinded"
+	"perun.network/go-perun/wire"
+	perunio "perun.network/go-perun/wire/perunio/serializer"
+	peruniotest "perun.network/go-perun/wire/perunio/test"
+	"perun.network/go-perun/wire/protobuf"
+	protobuftest "perun.network/go-perun/wire/protobuf/test"
+	wiretest "perun.network/go-perun/wire/test"
+	pkgtest "polycry.pt/poly-go/test"
+)
+
+const timeout = 5 * time.Second
+
+// forEachSerializer runs test on towers whose bus encodes with each of the
+// envelope serializers.
+func forEachSerializer(t *testing.T, test func(t *testing.T, ser wire.EnvelopeSerializer)) {
+	t.Helper()
+	t.Run("perunio", func(t *testing.T) { test(t, perunio.Serializer()) })
+	t.Run("protobuf", func(t *testing.T) { test(t, protobuf.Serializer()) })
+}
+
+func TestUploadMsg(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	id := ctest.NewRandomChannelID(rng)
+	blob := make([]byte, 100)
+	rng.Read(blob)
+
+	for _, serializerTest := range []func(*testing.T, wire.Msg){
+		peruniotest.MsgSerializerTest, protobuftest.MsgSerializerTest,
+	} {
+		serializerTest(t, &blinded.UploadMsg{Hint: blinded.HintOf(id), Blob: blob})
+		serializerTest(t, &blinded.UploadMsg{Hint: blinded.HintOf(id), Blob: []byte{}})
+	}
+}
+
+func TestHintOf(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	id := ctest.NewRandomChannelID(rng)
+	assert.Equal(t, blinded.HintOf(id), blinded.HintOf(id))
+	assert.NotEqual(t, blinded.HintOf(id), blinded.HintOf(ctest.NewRandomChannelID(rng)))
+	assert.NotEqual(t, id[:blinded.HintLen], blinded.HintOf(id).Bytes())
+}
+
+func TestTower(t *testing.T) {
+	forEachSerializer(t, func(t *testing.T, ser wire.EnvelopeSerializer) {
+		rng := pkgtest.Prng(t)
+		adj, bus, towerAddr := newTower(t, rng, ser)
+		victim := blinded.NewWatcher(blinded.NewWireUploader(bus, wiretest.NewRandomAddress(rng), towerAddr), adj)
+		cheater := blinded.NewWatcher(blinded.NewWireUploader(bus, wiretest.NewRandomAddress(rng), towerAddr), adj)
+		ctx := context.Background()
+
+		params, txs := newSignedTxs(rng, 3)
+		// The cheater cannot override the uploads of the victim and cannot
+		// upload unsigned states.
+		unsigned := channel.Transaction{State: txs[0].State.Clone()}
+		unsigned.Version = 5
+		cheaterPub, _, err := cheater.StartWatchingLedgerChannel(ctx, signedState(params, txs[0]))
+		require.NoError(t, err)
+		require.NoError(t, cheaterPub.Publish(ctx, unsigned))
+
+		pub, sub, err := victim.StartWatchingLedgerChannel(ctx, signedState(params, txs[0]))
+		require.NoError(t, err)
+		require.NoError(t, pub.Publish(ctx, txs[1]))
+		require.NoError(t, pub.Publish(ctx, txs[2]))
+
+		req := adj.registerUntilRefuted(t, makeRegisteredEvent(txs[1]))
+		assert.Equal(t, txs[2].Version, req.req.Tx.Version)
+		assert.Equal(t, params, req.req.Params)
+		assert.Empty(t, req.subStates)
+
+		// Registrations of unknown channels are ignored.
+		_, unknownTxs := newSignedTxs(rng, 1)
+		adj.assertNotRefuted(t, makeRegisteredEvent(unknownTxs[0]))
+
+		// The client receives the adjudicator events from its own subscription.
+		require.NoError(t, cheater.StopWatching(ctx, txs[0].ID))
+		e := makeRegisteredEvent(txs[2])
+		adj.events <- e
+		select {
+		case got := <-sub.EventStream():
+			assert.Equal(t, e, got)
+		case <-time.After(timeout):
+			t.Fatal("expected adjudicator event")
+		}
+		require.NoError(t, victim.StopWatching(ctx, txs[0].ID))
+		require.Error(t, victim.StopWatching(ctx, txs[0].ID))
+		select {
+		case _, ok := <-sub.EventStream():
+			assert.False(t, ok)
+		case <-time.After(timeout):
+			t.Fatal("expected closed event stream")
+		}
+	})
+}
+
+func TestTower_SubChannel(t *testing.T) {
+	forEachSerializer(t, func(t *testing.T, ser wire.EnvelopeSerializer) {
+		rng := pkgtest.Prng(t)
+		adj, bus, towerAddr := newTower(t, rng, ser)
+		w := blinded.NewWatcher(blinded.NewWireUploader(bus, wiretest.NewRandomAddress(rng), towerAddr), adj)
+		ctx := context.Background()
+
+		subParams, subTxs := newSignedTxs(rng, 2)
+		parentParams, parentTxs := newSignedTxs(rng, 2, ctest.WithLocked(
+			*channel.NewSubAlloc(subTxs[0].ID, []channel.Bal{big.NewInt(1)}, nil)))
+
+		parentPub, _, err := w.StartWatchingLedgerChannel(ctx, signedState(parentParams, parentTxs[0]))
+		require.NoError(t, err)
+		require.NoError(t, parentPub.Publish(ctx, parentTxs[1]))
+		subPub, _, err := w.StartWatchingSubChannel(ctx, parentTxs[0].ID, signedState(subParams, subTxs[0]))
+		require.NoError(t, err)
+		require.NoError(t, subPub.Publish(ctx, subTxs[1]))
+
+		// An outdated sub-channel state is refuted with the channel tree of the
+		// parent.
+		req := adj.registerUntilRefuted(t, makeRegisteredEvent(subTxs[0]))
+		assert.Equal(t, parentTxs[0].ID, req.req.Tx.ID)
+		assert.Equal(t, parentTxs[1].Version, req.req.Tx.Version)
+		require.Len(t, req.subStates, 1)
+		assert.Equal(t, subTxs[1].Version, req.subStates[0].State.Version)
+	})
+}
+
+func TestTower_StoredUploads(t *testing.T) {
+	forEachSerializer(t, func(t *testing.T, ser wire.EnvelopeSerializer) {
+		rng := pkgtest.Prng(t)
+		adj, bus, towerAddr := newTower(t, rng, ser)
+		w := blinded.NewWatcher(blinded.NewWireUploader(bus, wiretest.NewRandomAddress(rng), towerAddr), adj)
+		ctx := context.Background()
+
+		// An older state that is uploaded last does not hide the newest state.
+		params, txs := newSignedTxs(rng, 3)
+		pub, _, err := w.StartWatchingLedgerChannel(ctx, signedState(params, txs[0]))
+		require.NoError(t, err)
+		require.NoError(t, pub.Publish(ctx, txs[2]))
+		require.NoError(t, pub.Publish(ctx, txs[1]))
+
+		// Only the last MaxBlobsPerClient uploads are stored.
+		cappedParams, cappedTxs := newSignedTxs(rng, blinded.MaxBlobsPerClient+2)
+		cappedPub, _, err := w.StartWatchingLedgerChannel(ctx, signedState(cappedParams, cappedTxs[0]))
+		require.NoError(t, err)
+		require.NoError(t, cappedPub.Publish(ctx, cappedTxs[len(cappedTxs)-1]))
+		for _, tx := range cappedTxs[1 : len(cappedTxs)-1] {
+			require.NoError(t, cappedPub.Publish(ctx, tx))
+		}
+
+		// The tower stores the uploads in order, so all of the above are stored
+		// once the state of the last channel is refuted.
+		lastParams, lastTxs := newSignedTxs(rng, 2)
+		lastPub, _, err := w.StartWatchingLedgerChannel(ctx, signedState(lastParams, lastTxs[0]))
+		require.NoError(t, err)
+		require.NoError(t, lastPub.Publish(ctx, lastTxs[1]))
+		adj.registerUntilRefuted(t, makeRegisteredEvent(lastTxs[0]))
+
+		req := adj.registerUntilRefuted(t, makeRegisteredEvent(txs[0]))
+		assert.Equal(t, txs[2].Version, req.req.Tx.Version)
+		req = adj.registerUntilRefuted(t, makeRegisteredEvent(cappedTxs[0]))
+		assert.Equal(t, cappedTxs[len(cappedTxs)-2].Version, req.req.Tx.Version)
+	})
+}
+
+func TestTower_MockBackend(t *testing.T) {
+	forEachSerializer(t, func(t *testing.T, ser wire.EnvelopeSerializer) {
+		rng := pkgtest.Prng(t)
+		ctx := context.Background()
+		backend := clienttest.NewMockBackend(rng, "1337")
+		adj := backend.NewAdjudicator(wtest.NewRandomAddress(rng, channel.TestBackendID))
+		bus := wiretest.NewSerializingLocalBusWith(ser)
+		towerAddr := wiretest.NewRandomAddress(rng)
+		tower, err := blinded.NewTower(ctx, bus, towerAddr, adj)
+		require.NoError(t, err)
+		defer func() { assert.NoError(t, tower.Close()) }()
+		w := blinded.NewWatcher(blinded.NewWireUploader(bus, wiretest.NewRandomAddress(rng), towerAddr), adj)
+
+		params, txs := newSignedTxs(rng, 2, ctest.WithChallengeDuration(60))
+		pub, _, err := w.StartWatchingLedgerChannel(ctx, signedState(params, txs[0]))
+		require.NoError(t, err)
+		require.NoError(t, pub.Publish(ctx, txs[1]))
+
+		// The outdated registration is refuted on the backend.
+		sub, err := adj.Subscribe(ctx, params.ID())
+		require.NoError(t, err)
+		defer sub.Close()
+		require.Eventually(t, func() bool {
+			// Registering fails once the tower refuted.
+			req := channel.AdjudicatorReq{Params: params, Tx: txs[0]}
+			_ = adj.Register(ctx, req, nil)
+			time.Sleep(10 * time.Millisecond)
+			return sub.Next().Version() == txs[1].Version
+		}, timeout, 10*time.Millisecond)
+	})
+}
+
+func TestTower_Limits(t *testing.T) {
+	forEachSerializer(t, func(t *testing.T, ser wire.EnvelopeSerializer) {
+		rng := pkgtest.Prng(t)
+		ctx := context.Background()
+		adj, bus, towerAddr := newTowerWithLimits(t, rng, ser, blinded.TowerLimits{MaxHints: 1, MaxBytes: 1 << 20})
+		newWatcher := func() *blinded.Watcher {
+			return blinded.NewWatcher(blinded.NewWireUploader(bus, wiretest.NewRandomAddress(rng), towerAddr), adj)
+		}
+		watch := func(w *blinded.Watcher) []channel.Transaction {
+			params, txs := newSignedTxs(rng, 2)
+			pub, _, err := w.StartWatchingLedgerChannel(ctx, signedState(params, txs[0]))
+			require.NoError(t, err)
+			require.NoError(t, pub.Publish(ctx, txs[1]))
+			return txs
+		}
+		// flush waits until the tower stored the earlier uploads, which it
+		// does in order, by refuting with the uploads of another client.
+		flush := func() {
+			adj.registerUntilRefuted(t, makeRegisteredEvent(watch(newWatcher())[0]))
+		}
+		w := newWatcher()
+
+		// The uploads of a second channel exceed the client's limit, but do
+		// not affect other clients.
+		first := watch(w)
+		dropped := watch(w)
+		flush()
+		adj.assertNotRefuted(t, makeRegisteredEvent(dropped[0]))
+
+		// The uploads of a refuted ledger channel are dropped and do not count
+		// towards the limit anymore.
+		adj.registerUntilRefuted(t, makeRegisteredEvent(first[0]))
+		adj.assertNotRefuted(t, makeRegisteredEvent(first[0]))
+		adj.registerUntilRefuted(t, makeRegisteredEvent(watch(w)[0]))
+
+		// Neither do those of a concluded channel. The registrations are
+		// handled in order, so the uploads are dropped before the next one.
+		other := newWatcher()
+		concluded := watch(other)
+		flush()
+		adj.registrations <- &channel.ConcludedEvent{AdjudicatorEventBase: channel.AdjudicatorEventBase{
+			IDV: concluded[0].ID, TimeoutV: &channel.ElapsedTimeout{}, VersionV: concluded[1].Version,
+		}}
+		adj.assertNotRefuted(t, makeRegisteredEvent(concluded[0]))
+		adj.registerUntilRefuted(t, makeRegisteredEvent(watch(other)[0]))
+
+		// Uploads that exceed the size limit are dropped.
+		adj, bus, towerAddr = newTowerWithLimits(t, rng, ser, blinded.TowerLimits{MaxHints: 1, MaxBytes: 1})
+		w = blinded.NewWatcher(blinded.NewWireUploader(bus, wiretest.NewRandomAddress(rng), towerAddr), adj)
+		adj.assertNotRefuted(t, makeRegisteredEvent(watch(w)[0]))
+	})
+}
+
+func TestTower_HungRegistration(t *testing.T) {
+	forEachSerializer(t, func(t *testing.T, ser wire.EnvelopeSerializer) {
+		rng := pkgtest.Prng(t)
+		ctx := context.Background()
+		adj, bus, towerAddr := newTower(t, rng, ser)
+		w := blinded.NewWatcher(blinded.NewWireUploader(bus, wiretest.NewRandomAddress(rng), towerAddr), adj)
+		var txs [3][]channel.Transaction
+		for i := range txs {
+			params, chTxs := newSignedTxs(rng, 2)
+			pub, _, err := w.StartWatchingLedgerChannel(ctx, signedState(params, chTxs[0]))
+			require.NoError(t, err)
+			require.NoError(t, pub.Publish(ctx, chTxs[1]))
+			txs[i] = chTxs
+		}
+		hung, other, last := txs[0], txs[1], txs[2]
+		adj.blocked, adj.unblock = hung[0].ID, make(chan struct{})
+		// All uploads are stored once the last channel is refuted.
+		adj.registerUntilRefuted(t, makeRegisteredEvent(last[0]))
+
+		// The registration that does not return does not delay the refutation
+		// of other channels.
+		adj.registrations <- makeRegisteredEvent(hung[0])
+		req := adj.registerUntilRefuted(t, makeRegisteredEvent(other[0]))
+		assert.Equal(t, other[1].Version, req.req.Tx.Version)
+
+		close(adj.unblock)
+		select {
+		case req := <-adj.registered:
+			assert.Equal(t, hung[0].ID, req.req.Tx.ID)
+		case <-time.After(timeout):
+			t.Fatal("expected refutation")
+		}
+	})
+}
+
+func newTower(t *testing.T, rng *rand.Rand, ser wire.EnvelopeSerializer) (*adjudicator, wire.Bus, map[wallet.BackendID]wire.Address) {
+	t.Helper()
+	return newTowerWithLimits(t, rng, ser, blinded.DefaultTowerLimits())
+}
+
+func newTowerWithLimits(t *testing.T, rng *rand.Rand, ser wire.EnvelopeSerializer, limits blinded.TowerLimits) (
+	*adjudicator, wire.Bus, map[wallet.BackendID]wire.Address,
+) {
+	t.Helper()
+	adj := &adjudicator{
+		registrations: make(chan channel.AdjudicatorEvent),
+		events:        make(chan channel.AdjudicatorEvent),
+		registered:    make(chan registerReq, 1),
+	}
+	bus := wiretest.NewSerializingLocalBusWith(ser)
+	towerAddr := wiretest.NewRandomAddress(rng)
+	tower, err := blinded.NewTower(context.Background(), bus, towerAddr, adj)
+	require.NoError(t, err)
+	tower.SetLimits(limits)
+	t.Cleanup(func() { assert.NoError(t, tower.Close()) })
+	return adj, bus, towerAddr
+}
+
+// newSignedTxs returns n fully signed transactions with increasing versions
+// of a random two-party channel.
+func newSignedTxs(rng *rand.Rand, n int, opts ...ctest.RandomOpt) (*channel.Params, []channel.Transaction) {
+	accs, parts := wtest.NewRandomAccounts(rng, 2, channel.TestBackendID)
+	opts = append([]ctest.RandomOpt{
+		ctest.WithParts(parts), ctest.WithNumLocked(0), ctest.WithVersion(0), ctest.WithIsFinal(false),
+	}, opts...)
+	params, state := ctest.NewRandomParamsAndState(rng, opts...)
+
+	txs := make([]channel.Transaction, n)
+	for i := range txs {
+		s := state.Clone()
+		s.Version = uint64(i)
+		txs[i] = channel.Transaction{State: s, Sigs: make([]wallet.Sig, len(accs))}
+		for j, acc := range accs {
+			sig, err := channel.Sign(acc[channel.TestBackendID], s, channel.TestBackendID)
+			if err != nil {
+				panic(err)
+			}
+			txs[i].Sigs[j] = sig
+		}
+	}
+	return params, txs
+}
+
+func signedState(params *channel.Params, tx channel.Transaction) channel.SignedState {
+	return channel.SignedState{Params: params, State: tx.State, Sigs: tx.Sigs}
+}
+
+func makeRegisteredEvent(tx channel.Transaction) *channel.RegisteredEvent {
+	return &channel.RegisteredEvent{
+		AdjudicatorEventBase: channel.AdjudicatorEventBase{
+			IDV:      tx.ID,
+			TimeoutV: &channel.ElapsedTimeout{},
+			VersionV: tx.Version,
+		},
+		State: tx.State,
+		Sigs:  tx.Sigs,
+	}
+}
+
+type (
+	// adjudicator emits the events sent on registrations on the registration
+	// subscriptions and those sent on events on the channel subscriptions. It
+	// reports the registered channel trees on registered. Registering the
+	// blocked channel does not return before unblock is closed.
+	adjudicator struct {
+		registrations chan channel.AdjudicatorEvent
+		events        chan channel.AdjudicatorEvent
+		registered    chan registerReq
+		blocked       channel.ID
+		unblock       chan struct{}
+	}
+
+	registerReq struct {
+		req       channel.AdjudicatorReq
+		subStates []channel.SignedState
+	}
+
+	subscription struct {
+		events <-chan channel.AdjudicatorEvent
+		once   sync.Once
+		closed chan struct{}
+	}
+)
+
+// registerUntilRefuted emits the registration until the tower refutes,
+// because the uploads are stored asynchronously.
+func (a *adjudicator) registerUntilRefuted(t *testing.T, e *channel.RegisteredEvent) registerReq {
+	t.Helper()
+	deadline := time.After(timeout)
+	for {
+		select {
+		case a.registrations <- e:
+		case <-deadline:
+			t.Fatal("registration not emitted")
+		}
+		select {
+		case req := <-a.registered:
+			return req
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("expected refutation")
+		}
+	}
+}
+
+// assertNotRefuted emits the registration and asserts that the tower does not
+// refute it.
+func (a *adjudicator) assertNotRefuted(t *testing.T, e *channel.RegisteredEvent) {
+	t.Helper()
+	a.registrations <- e
+	select {
+	case req := <-a.registered:
+		t.Errorf("unexpected registration of version %d", req.req.Tx.Version)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func (a *adjudicator) Register(ctx context.Context, req channel.AdjudicatorReq, subStates []channel.SignedState) error {
+	if a.unblock != nil && req.Tx.ID == a.blocked {
+		select {
+		case <-a.unblock:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	a.registered <- registerReq{req: req, subStates: subStates}
+	return nil
+}
+
+func (a *adjudicator) SubscribeRegistrations(context.Context) (channel.AdjudicatorSubscription, error) {
+	return &subscription{events: a.registrations, closed: make(chan struct{})}, nil
+}
+
+func (a *adjudicator) Subscribe(context.Context, channel.ID) (channel.AdjudicatorSubscription, error) {
+	return &subscription{events: a.events, closed: make(chan struct{})}, nil
+}
+
+func (s *subscription) Next() channel.AdjudicatorEvent {
+	select {
+	case e := <-s.events:
+		return e
+	case <-s.closed:
+		return nil
+	}
+}
+
+func (s *subscription) Err() error { return nil }
+
+func (s *subscription) Close() error {
+	s.once.Do(func() { close(s.closed) })
+	return nil
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blinded
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire/perunio"
+)
+
+const (
+	// HintLen is the length of a hint in bytes.
+	HintLen = 16
+
+	// MaxBlobSize is the maximum size of an encrypted state in bytes.
+	MaxBlobSize = math.MaxUint16
+
+	// MaxBlobsPerClient is the maximum number of encrypted states a tower
+	// stores per client and hint. Older uploads are dropped.
+	MaxBlobsPerClient = 8
+
+	// maxParents is the maximum number of parents of an uploaded channel.
+	maxParents = 2
+
+	hintDomain = "perun/watcher/blinded/hint"
+	keyDomain  = "perun/watcher/blinded/key"
+)
+
+type (
+	// Hint indexes the encrypted states of a channel at the tower. It does not
+	// reveal the channel ID.
+	Hint [HintLen]byte
+
+	// upload is the plaintext of an encrypted state.
+	upload struct {
+		Parents []channel.ID // Parents are the parents of a sub-channel or virtual channel.
+		Params  *channel.Params
+		Tx      channel.Transaction
+	}
+)
+
+// HintOf returns the hint of the channel with the given ID.
+func HintOf(id channel.ID) Hint {
+	var hint Hint
+	h := derive(hintDomain, id)
+	copy(hint[:], h[:HintLen])
+	return hint
+}
+
+func derive(domain string, id channel.ID) [sha256.Size]byte {
+	return sha256.Sum256(append([]byte(domain), id[:]...))
+}
+
+// seal encrypts the upload under the key derived from its channel ID. The
+// hint is authenticated as additional data.
+func seal(u *upload) (Hint, []byte, error) {
+	id := u.Tx.ID
+	hint := HintOf(id)
+	var buf bytes.Buffer
+	if err := perunio.Encode(&buf, u); err != nil {
+		return hint, nil, errors.WithMessage(err, "encoding state")
+	}
+	aead, err := newAEAD(id)
+	if err != nil {
+		return hint, nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+buf.Len()+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return hint, nil, errors.Wrap(err, "generating nonce")
+	}
+	blob := aead.Seal(nonce, nonce, buf.Bytes(), hint[:])
+	if len(blob) > MaxBlobSize {
+		return hint, nil, errors.Errorf("encrypted state too large: %d bytes", len(blob))
+	}
+	return hint, blob, nil
+}
+
+// open decrypts the blob of the channel with the given ID.
+func open(id channel.ID, blob []byte) (*upload, error) {
+	aead, err := newAEAD(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(blob) < aead.NonceSize() {
+		return nil, errors.New("blob too short")
+	}
+	hint := HintOf(id)
+	nonce, ciphertext := blob[:aead.NonceSize()], blob[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, hint[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting state")
+	}
+	u := new(upload)
+	if err := perunio.Decode(bytes.NewReader(plaintext), u); err != nil {
+		return nil, errors.WithMessage(err, "decoding state")
+	}
+	if u.Tx.ID != id {
+		return nil, errors.New("state of another channel")
+	}
+	return u, nil
+}
+
+func newAEAD(id channel.ID) (cipher.AEAD, error) {
+	key := derive(keyDomain, id)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "creating cipher")
+	}
+	aead, err := cipher.NewGCM(block)
+	return aead, errors.Wrap(err, "creating AEAD")
+}
+
+// signedState returns the uploaded state as a signed state.
+func (u *upload) signedState() channel.SignedState {
+	return channel.SignedState{Params: u.Params, State: u.Tx.State, Sigs: u.Tx.Sigs}
+}
+
+// Encode implements perunio.Encoder. Missing signatures are encoded as
+// absent.
+func (u *upload) Encode(w io.Writer) error {
+	if len(u.Parents) > maxParents {
+		return errors.Errorf("too many parents: %d", len(u.Parents))
+	}
+	if err := perunio.Encode(w, uint8(len(u.Parents))); err != nil {
+		return err
+	}
+	for _, parent := range u.Parents {
+		if err := perunio.Encode(w, parent); err != nil {
+			return err
+		}
+	}
+	tx := u.Tx
+	if len(tx.Sigs) != len(u.Params.Parts) {
+		tx.Sigs = make([]wallet.Sig, len(u.Params.Parts))
+	}
+	return perunio.Encode(w, u.Params, tx)
+}
+
+// Decode implements perunio.Decoder.
+func (u *upload) Decode(r io.Reader) error {
+	var numParents uint8
+	if err := perunio.Decode(r, &numParents); err != nil {
+		return err
+	}
+	if numParents > maxParents {
+		return errors.Errorf("too many parents: %d", numParents)
+	}
+	u.Parents = make([]channel.ID, numParents)
+	for i := range u.Parents {
+		if err := perunio.Decode(r, &u.Parents[i]); err != nil {
+			return err
+		}
+	}
+	u.Params = new(channel.Params)
+	return perunio.Decode(r, u.Params, &u.Tx)
+}
+
+// Bytes returns the hint as a byte slice.
+func (h Hint) Bytes() []byte {
+	return h[:]
+}
@@ -0,0 +1,29 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package blinded implements a privacy-preserving watcher mode, in which a
+// third-party tower does not learn anything about a channel until a dispute
+// for it is registered on-chain.
+//
+// The client encrypts each state under a key derived from the channel ID and
+// uploads it to the tower, indexed by a hint that is also derived from the
+// channel ID. The channel ID is only revealed on-chain when a state of the
+// channel is registered, assuming that the funding only reveals the funding
+// IDs of the participants. When the tower sees a registration, it derives the
+// hint and the key from the registered channel ID, decrypts the latest
+// uploaded state and refutes if it is newer than the registered one.
+//
+// The tower does not relay adjudicator events. The client receives them from
+// its own subscription to the adjudicator.
+package blinded // import "perun.network/go-perun/watcher/blinded"
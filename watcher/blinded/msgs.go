@@ -0,0 +1,97 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blinded
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	"perun.network/go-perun/wire/perunio"
+)
+
+func init() {
+	wire.RegisterDecoder(wire.WatcherBlindedUpload,
+		func(r io.Reader) (wire.Msg, error) {
+			var m UploadMsg
+			return &m, m.Decode(r)
+		})
+}
+
+var _ Uploader = (*WireUploader)(nil)
+
+type (
+	// UploadMsg uploads an encrypted state to the tower. It is not
+	// acknowledged.
+	UploadMsg struct {
+		Hint Hint
+		Blob []byte
+	}
+
+	// WireUploader uploads encrypted states to a tower over the wire.
+	WireUploader struct {
+		bus   wire.Bus
+		addr  map[wallet.BackendID]wire.Address
+		tower map[wallet.BackendID]wire.Address
+	}
+)
+
+// NewWireUploader creates an uploader that sends the encrypted states from
+// addr to the tower at address tower over the bus.
+func NewWireUploader(bus wire.Bus, addr, tower map[wallet.BackendID]wire.Address) *WireUploader {
+	return &WireUploader{bus: bus, addr: addr, tower: tower}
+}
+
+// Upload sends the encrypted state to the tower. It fails if the tower does
+// not support the watcher messages.
+func (u *WireUploader) Upload(ctx context.Context, hint Hint, blob []byte) error {
+	if err := wire.RequireFeatures(ctx, u.bus, u.tower, wire.FeatureWatcher); err != nil {
+		return err
+	}
+	env := &wire.Envelope{Sender: u.addr, Recipient: u.tower, Msg: &UploadMsg{Hint: hint, Blob: blob}}
+	return errors.WithMessage(u.bus.Publish(ctx, env), "sending to tower")
+}
+
+// Type returns wire.WatcherBlindedUpload.
+func (*UploadMsg) Type() wire.Type { return wire.WatcherBlindedUpload }
+
+// Encode implements perunio.Encode.
+func (m *UploadMsg) Encode(w io.Writer) error {
+	if len(m.Blob) > MaxBlobSize {
+		return errors.Errorf("blob too large: %d bytes", len(m.Blob))
+	}
+	if err := perunio.Encode(w, perunio.ByteSlice(m.Hint[:]), uint16(len(m.Blob))); err != nil || len(m.Blob) == 0 {
+		return err
+	}
+	return perunio.ByteSlice(m.Blob).Encode(w)
+}
+
+// Decode implements perunio.Decode.
+func (m *UploadMsg) Decode(r io.Reader) error {
+	hint := perunio.ByteSlice(m.Hint[:])
+	var n uint16
+	if err := perunio.Decode(r, &hint, &n); err != nil {
+		return err
+	}
+	blob := make(perunio.ByteSlice, n)
+	if err := perunio.Decode(r, &blob); err != nil {
+		return err
+	}
+	m.Blob = blob
+	return nil
+}
@@ -0,0 +1,389 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blinded
+
+import (
+	"context"
+	stdsync "sync"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	"polycry.pt/poly-go/sync"
+)
+
+type (
+	// Adjudicator is the adjudicator on which a tower watches and refutes.
+	//
+	// Because the tower does not know the IDs of the channels it watches, it
+	// subscribes to the registrations of all channels. The uploads of a
+	// channel are dropped on a ConcludedEvent, other events are ignored.
+	Adjudicator interface {
+		channel.Registerer
+		channel.RegistrationSubscriber
+	}
+
+	// Tower is a watchtower that refutes with the encrypted states uploaded by
+	// its clients. It cannot decrypt a state before the channel is registered
+	// on-chain.
+	//
+	// The tower keeps the last MaxBlobsPerClient uploads of each client per
+	// hint, because it cannot tell which of them is the newest before the
+	// channel is registered and uploads may arrive out of order. During a
+	// dispute, it uses the newest fully signed state among the uploads of all
+	// clients, so that a participant cannot override the uploads of its
+	// counterparty. The uploads of a ledger channel are dropped once the tower
+	// refuted with it, and those of any channel once it is concluded.
+	Tower struct {
+		sync.Closer
+		log.Embedding
+
+		adj  Adjudicator
+		recv *wire.Receiver
+
+		mu         stdsync.Mutex
+		limits     TowerLimits
+		blobs      map[Hint]map[wire.AddrKey][][]byte // oldest first
+		usage      map[wire.AddrKey]*clientUsage
+		registered map[channel.ID]uint64   // registered versions of refuted channels.
+		refuting   map[channel.ID]struct{} // channels with a refutation in progress.
+	}
+
+	// TowerLimits bounds the uploads that a tower stores for a single client.
+	// Uploads that exceed them are dropped.
+	TowerLimits struct {
+		MaxHints int // number of hints that a client can upload states for.
+		MaxBytes int // total size of the stored states of a client in bytes.
+	}
+
+	// clientUsage is the number of hints and bytes that a client's stored
+	// uploads use.
+	clientUsage struct {
+		hints, bytes int
+	}
+)
+
+// DefaultTowerLimits returns limits of 4096 hints and 64 MiB per client.
+func DefaultTowerLimits() TowerLimits {
+	return TowerLimits{
+		MaxHints: 1 << 12, //nolint:mnd
+		MaxBytes: 1 << 26, //nolint:mnd
+	}
+}
+
+// NewTower creates a tower that receives the uploads of its clients on the bus
+// at addr and refutes on adj. The context is only used to subscribe to the
+// registrations. The tower runs until it is closed.
+func NewTower(
+	ctx context.Context,
+	bus wire.Bus,
+	addr map[wallet.BackendID]wire.Address,
+	adj Adjudicator,
+) (*Tower, error) {
+	t := &Tower{
+		Embedding:  log.MakeEmbedding(log.WithField("role", "blinded tower")),
+		adj:        adj,
+		recv:       wire.NewReceiver(),
+		limits:     DefaultTowerLimits(),
+		blobs:      make(map[Hint]map[wire.AddrKey][][]byte),
+		usage:      make(map[wire.AddrKey]*clientUsage),
+		registered: make(map[channel.ID]uint64),
+		refuting:   make(map[channel.ID]struct{}),
+	}
+	sub, err := adj.SubscribeRegistrations(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "subscribing to registrations")
+	}
+	if err := bus.SubscribeClient(t.recv, addr); err != nil {
+		if cerr := sub.Close(); cerr != nil {
+			t.Log().WithError(cerr).Warn("Closing subscription")
+		}
+		return nil, errors.WithMessage(err, "subscribing tower to bus")
+	}
+	t.OnCloseAlways(func() {
+		if err := sub.Close(); err != nil {
+			t.Log().WithError(err).Warn("Closing subscription")
+		}
+		if err := t.recv.Close(); err != nil {
+			t.Log().WithError(err).Warn("Closing receiver")
+		}
+	})
+	go t.handleUploads()
+	go t.handleRegistrations(sub)
+	return t, nil
+}
+
+// SetLimits sets the limits of the uploads per client. It only applies to
+// later uploads.
+func (t *Tower) SetLimits(limits TowerLimits) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.limits = limits
+}
+
+// handleUploads stores the uploads of the clients until the tower is closed.
+func (t *Tower) handleUploads() {
+	for {
+		env, err := t.recv.Next(t.Ctx())
+		if err != nil {
+			return
+		}
+		msg, ok := env.Msg.(*UploadMsg)
+		if !ok {
+			t.Log().Warnf("Ignoring unexpected message of type %v", env.Msg.Type())
+			continue
+		}
+		t.store(wire.Keys(env.Sender), msg.Hint, msg.Blob)
+	}
+}
+
+// store stores the upload of the client, unless it exceeds the client's
+// limits.
+func (t *Tower) store(client wire.AddrKey, hint Hint, blob []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	usage, ok := t.usage[client]
+	if !ok {
+		usage = new(clientUsage)
+	}
+	stored, known := t.blobs[hint][client]
+	hints, bytes := usage.hints, usage.bytes+len(blob)
+	if !known {
+		hints++
+	}
+	stored = append(stored, blob)
+	if len(stored) > MaxBlobsPerClient {
+		for _, dropped := range stored[:len(stored)-MaxBlobsPerClient] {
+			bytes -= len(dropped)
+		}
+		stored = stored[len(stored)-MaxBlobsPerClient:]
+	}
+	if hints > t.limits.MaxHints || bytes > t.limits.MaxBytes {
+		t.Log().Warn("Dropping upload exceeding the client's limits")
+		return
+	}
+
+	blobs, ok := t.blobs[hint]
+	if !ok {
+		blobs = make(map[wire.AddrKey][][]byte)
+		t.blobs[hint] = blobs
+	}
+	blobs[client] = stored
+	usage.hints, usage.bytes = hints, bytes
+	t.usage[client] = usage
+}
+
+// drop drops the uploads of the channel. The tower must be locked.
+func (t *Tower) drop(id channel.ID) {
+	hint := HintOf(id)
+	for client, stored := range t.blobs[hint] {
+		usage := t.usage[client]
+		usage.hints--
+		for _, blob := range stored {
+			usage.bytes -= len(blob)
+		}
+		if usage.hints == 0 {
+			delete(t.usage, client)
+		}
+	}
+	delete(t.blobs, hint)
+}
+
+// handleRegistrations refutes registrations of outdated states until the
+// subscription is closed.
+func (t *Tower) handleRegistrations(sub channel.AdjudicatorSubscription) {
+	for e := sub.Next(); e != nil; e = sub.Next() {
+		switch e := e.(type) {
+		case *channel.RegisteredEvent:
+			t.handleRegistered(e)
+		case *channel.ConcludedEvent:
+			t.mu.Lock()
+			t.drop(e.ID())
+			delete(t.registered, e.ID())
+			t.mu.Unlock()
+		}
+	}
+	if err := sub.Err(); err != nil {
+		t.Log().WithError(err).Error("Subscription to registrations closed")
+	}
+}
+
+// handleRegistered refutes the registration in its own goroutine, so that a
+// slow adjudicator does not delay the refutation of other channels.
+// Registrations of a channel that is already being refuted are ignored.
+func (t *Tower) handleRegistered(e *channel.RegisteredEvent) {
+	if !t.claim(e.ID()) {
+		return
+	}
+	go func() {
+		defer t.release(e.ID())
+		t.refute(e)
+	}()
+}
+
+// claim marks the channel as being refuted. It returns false if it already
+// is.
+func (t *Tower) claim(id channel.ID) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.refuting[id]; ok {
+		return false
+	}
+	t.refuting[id] = struct{}{}
+	return true
+}
+
+// release unmarks the channel as being refuted.
+func (t *Tower) release(id channel.ID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.refuting, id)
+}
+
+func (t *Tower) refute(e *channel.RegisteredEvent) {
+	log := t.Log().WithFields(log.Fields{"ID": e.ID(), "Version": e.Version()})
+	latest, ok := t.latest(e.ID())
+	if !ok || latest.Tx.Version <= e.Version() {
+		return
+	}
+
+	root, err := t.root(latest)
+	if err != nil {
+		log.WithError(err).Error("Cannot refute")
+		return
+	}
+	// The channel tree of a sub-channel is refuted only once, even if several
+	// of its channels are registered at the same time.
+	if root.Tx.ID != e.ID() {
+		if !t.claim(root.Tx.ID) {
+			return
+		}
+		defer t.release(root.Tx.ID)
+	}
+	t.mu.Lock()
+	version, registered := t.registered[root.Tx.ID]
+	t.mu.Unlock()
+	if registered && version >= root.Tx.Version {
+		return
+	}
+
+	subStates := make([]channel.SignedState, len(root.Tx.Locked))
+	for i, sub := range root.Tx.Locked {
+		s, ok := t.latest(sub.ID)
+		if !ok {
+			log.Errorf("Cannot refute: no state of sub-channel %x", sub.ID)
+			return
+		}
+		subStates[i] = s.signedState()
+	}
+
+	log.Debugf("Registering latest version (%d)", latest.Tx.Version)
+	req := channel.AdjudicatorReq{Params: root.Params, Tx: root.Tx}
+	if err := t.adj.Register(t.Ctx(), req, subStates); err != nil {
+		log.WithError(err).Error("Registering dispute")
+		return
+	}
+	// The registered state of the ledger channel cannot be refuted anymore,
+	// so its uploads are not needed. Those of its sub-channels are kept for
+	// registrations on other parents.
+	t.mu.Lock()
+	t.registered[root.Tx.ID] = root.Tx.Version
+	if len(root.Parents) == 0 {
+		t.drop(root.Tx.ID)
+	}
+	t.mu.Unlock()
+}
+
+// root returns the latest state of the ledger channel whose channel tree is
+// registered to refute. For a sub-channel or virtual channel, it is the first
+// parent that still funds it.
+func (t *Tower) root(u *upload) (*upload, error) {
+	if len(u.Parents) == 0 {
+		return u, nil
+	}
+	var root *upload
+	for _, id := range u.Parents {
+		parent, ok := t.latest(id)
+		if !ok {
+			continue
+		}
+		if _, funds := parent.Tx.SubAlloc(u.Tx.ID); funds {
+			return parent, nil
+		}
+		if root == nil {
+			root = parent
+		}
+	}
+	if root == nil {
+		return nil, errors.New("no state of parent channel")
+	}
+	return root, nil
+}
+
+// latest decrypts the uploads of the channel and returns the newest fully
+// signed state. Invalid uploads are ignored.
+func (t *Tower) latest(id channel.ID) (*upload, bool) {
+	t.mu.Lock()
+	var blobs [][]byte
+	for _, stored := range t.blobs[HintOf(id)] {
+		blobs = append(blobs, stored...)
+	}
+	t.mu.Unlock()
+
+	var latest *upload
+	for _, blob := range blobs {
+		u, err := open(id, blob)
+		if err == nil {
+			err = checkUpload(u)
+		}
+		if err != nil {
+			t.Log().WithField("ID", id).WithError(err).Warn("Ignoring invalid upload")
+			continue
+		}
+		if latest == nil || u.Tx.Version > latest.Tx.Version {
+			latest = u
+		}
+	}
+	return latest, latest != nil
+}
+
+// checkUpload checks that the uploaded state belongs to the parameters and is
+// signed by all participants.
+func checkUpload(u *upload) error {
+	if u.Params.ID() != u.Tx.ID {
+		return errors.New("state does not belong to params")
+	}
+	if len(u.Tx.Sigs) != len(u.Params.Parts) {
+		return errors.New("sigs length mismatch")
+	}
+	for i, sig := range u.Tx.Sigs {
+		if sig == nil {
+			return errors.Errorf("missing sig %d", i)
+		}
+		for _, p := range u.Params.Parts[i] {
+			ok, err := channel.Verify(p, u.Tx.State, sig)
+			if err != nil {
+				return errors.WithMessagef(err, "validating sig %d", i)
+			}
+			if !ok {
+				return errors.Errorf("invalid sig %d", i)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blinded
+
+import (
+	"context"
+	stdsync "sync"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/watcher"
+)
+
+var _ watcher.Watcher = (*Watcher)(nil)
+
+// eventsBufferSize is the number of adjudicator events buffered for the
+// client.
+const eventsBufferSize = 10
+
+type (
+	// Uploader uploads encrypted states to a tower.
+	Uploader interface {
+		Upload(_ context.Context, _ Hint, blob []byte) error
+	}
+
+	// Watcher implements watcher.Watcher by uploading encrypted states to a
+	// tower. The adjudicator events are received from the client's own
+	// subscription, because the tower does not relay them.
+	Watcher struct {
+		uploader Uploader
+		es       channel.EventSubscriber
+
+		mu   stdsync.Mutex
+		subs map[channel.ID]*adjudicatorSub
+	}
+
+	// statesPub encrypts and uploads the published states of a channel.
+	statesPub struct {
+		uploader Uploader
+		parents  []channel.ID
+		params   *channel.Params
+	}
+
+	// adjudicatorSub relays the events of an adjudicator subscription.
+	adjudicatorSub struct {
+		sub    channel.AdjudicatorSubscription
+		events chan channel.AdjudicatorEvent
+		once   stdsync.Once
+		done   chan struct{}
+	}
+)
+
+// NewWatcher creates a watcher that uploads the states to a tower with the
+// uploader and subscribes to adjudicator events on es.
+func NewWatcher(uploader Uploader, es channel.EventSubscriber) *Watcher {
+	return &Watcher{
+		uploader: uploader,
+		es:       es,
+		subs:     make(map[channel.ID]*adjudicatorSub),
+	}
+}
+
+// StartWatchingLedgerChannel uploads the state of the ledger channel.
+func (w *Watcher) StartWatchingLedgerChannel(ctx context.Context, s channel.SignedState) (
+	watcher.StatesPub, watcher.AdjudicatorSub, error,
+) {
+	return w.startWatching(ctx, nil, s)
+}
+
+// StartWatchingSubChannel uploads the state of the sub-channel. The tower
+// needs the states of the parent to refute.
+func (w *Watcher) StartWatchingSubChannel(ctx context.Context, parent channel.ID, s channel.SignedState) (
+	watcher.StatesPub, watcher.AdjudicatorSub, error,
+) {
+	return w.startWatching(ctx, []channel.ID{parent}, s)
+}
+
+// StartWatchingVirtualChannel uploads the state of the virtual channel. The
+// tower needs the states of a parent to refute.
+func (w *Watcher) StartWatchingVirtualChannel(ctx context.Context, parents []channel.ID, s channel.SignedState) (
+	watcher.StatesPub, watcher.AdjudicatorSub, error,
+) {
+	if len(parents) == 0 || len(parents) > maxParents {
+		return nil, nil, errors.Errorf("invalid number of parents: %d", len(parents))
+	}
+	return w.startWatching(ctx, parents, s)
+}
+
+func (w *Watcher) startWatching(ctx context.Context, parents []channel.ID, s channel.SignedState) (
+	watcher.StatesPub, watcher.AdjudicatorSub, error,
+) {
+	id := s.State.ID
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.subs[id]; ok {
+		return nil, nil, errors.New("already watching channel")
+	}
+
+	pub := &statesPub{uploader: w.uploader, parents: parents, params: s.Params}
+	if err := pub.Publish(ctx, channel.Transaction{State: s.State, Sigs: s.Sigs}); err != nil {
+		return nil, nil, err
+	}
+	sub, err := w.es.Subscribe(ctx, id)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "subscribing to adjudicator events")
+	}
+	w.subs[id] = newAdjudicatorSub(sub)
+	return pub, w.subs[id], nil
+}
+
+// StopWatching closes the adjudicator subscription of the channel. The
+// uploaded states remain at the tower.
+func (w *Watcher) StopWatching(_ context.Context, id channel.ID) error {
+	w.mu.Lock()
+	sub, ok := w.subs[id]
+	delete(w.subs, id)
+	w.mu.Unlock()
+	if !ok {
+		return errors.New("channel not watched")
+	}
+	return sub.close()
+}
+
+// Publish encrypts the transaction and uploads it to the tower.
+func (p *statesPub) Publish(ctx context.Context, tx channel.Transaction) error {
+	if tx.ID != p.params.ID() {
+		return errors.New("transaction of another channel")
+	}
+	hint, blob, err := seal(&upload{Parents: p.parents, Params: p.params, Tx: tx})
+	if err != nil {
+		return err
+	}
+	return errors.WithMessage(p.uploader.Upload(ctx, hint, blob), "uploading state")
+}
+
+func newAdjudicatorSub(sub channel.AdjudicatorSubscription) *adjudicatorSub {
+	s := &adjudicatorSub{
+		sub:    sub,
+		events: make(chan channel.AdjudicatorEvent, eventsBufferSize),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// run relays the events until the subscription is closed.
+func (s *adjudicatorSub) run() {
+	defer close(s.events)
+	for e := s.sub.Next(); e != nil; e = s.sub.Next() {
+		select {
+		case s.events <- e:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// EventStream returns the channel of adjudicator events. It is closed when the
+// subscription is closed.
+func (s *adjudicatorSub) EventStream() <-chan channel.AdjudicatorEvent {
+	return s.events
+}
+
+// Err returns the error of the adjudicator subscription.
+func (s *adjudicatorSub) Err() error {
+	return s.sub.Err()
+}
+
+func (s *adjudicatorSub) close() (err error) {
+	s.once.Do(func() {
+		close(s.done)
+		err = errors.WithMessage(s.sub.Close(), "closing adjudicator subscription")
+	})
+	return err
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"context"
+	stdsync "sync"
+	"time"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/watcher"
+)
+
+// alerts notifies the notifier of the watcher about disputes.
+type alerts struct {
+	mu            stdsync.Mutex
+	notifier      watcher.Notifier
+	timeoutMargin time.Duration
+}
+
+// SetNotifier sets the notifier that is notified about the disputes of all
+// watched channels. A nil notifier disables the notifications.
+//
+// The notifier is alerted timeoutMargin before the timeout of a dispute phase
+// elapses. This requires the adjudicator events to have a
+// *channel.TimeTimeout, other timeouts are not alerted.
+func (w *Watcher) SetNotifier(notifier watcher.Notifier, timeoutMargin time.Duration) {
+	w.alerts.mu.Lock()
+	defer w.alerts.mu.Unlock()
+	w.alerts.notifier = notifier
+	w.alerts.timeoutMargin = timeoutMargin
+}
+
+func (a *alerts) get() (watcher.Notifier, time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.notifier, a.timeoutMargin
+}
+
+// notify notifies the notifier, if any, about the alert.
+func (a *alerts) notify(alert watcher.Alert) {
+	notifier, _ := a.get()
+	if notifier == nil {
+		return
+	}
+	alert.Time = time.Now()
+	notifier.Notify(alert)
+}
+
+// alertTimeout schedules the alert for the nearing timeout of the event. A
+// pending alert for an earlier event is canceled.
+func (ch *ch) alertTimeout(ctx context.Context, e channel.AdjudicatorEvent, alerts *alerts) {
+	ch.cancelTimeoutAlert()
+	notifier, margin := alerts.get()
+	timeout, ok := e.Timeout().(*channel.TimeTimeout)
+	if notifier == nil || !ok || time.Now().After(timeout.Time) {
+		return
+	}
+
+	ctx, ch.timeoutAlertCancel = context.WithCancel(ctx)
+	ch.Go(func() {
+		timer := time.NewTimer(time.Until(timeout.Time.Add(-margin)))
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			alerts.notify(watcher.Alert{
+				Kind:    watcher.AlertTimeoutNearing,
+				Channel: ch.id,
+				Version: e.Version(),
+				Timeout: timeout.Time,
+			})
+		case <-ctx.Done():
+		}
+	})
+}
+
+// cancelTimeoutAlert cancels the pending timeout alert, if any.
+func (ch *ch) cancelTimeoutAlert() {
+	if ch.timeoutAlertCancel != nil {
+		ch.timeoutAlertCancel()
+		ch.timeoutAlertCancel = nil
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/watcher"
+	"perun.network/go-perun/watcher/internal/mocks"
+	"polycry.pt/poly-go/test"
+)
+
+func Test_Watcher_Alerts(t *testing.T) {
+	rng := test.Prng(t)
+
+	t.Run("refuted", func(t *testing.T) {
+		params, txs := randomTxsForSingleCh(rng, 2)
+		adjSub := &mocks.AdjudicatorSubscription{}
+		trigger := setExpectationNextCall(adjSub, append(makeRegisteredEvents(txs[0], txs[1]),
+			makeConcludedEvents(txs[1])...)...)
+		rs := &mocks.RegisterSubscriber{}
+		setExpectationSubscribeCall(rs, adjSub, nil)
+		setExpectationRegisterCalls(t, rs, &channelTree{txs[1], []channel.Transaction{}})
+		w := newWatcher(t, rs)
+		alerts := make(chan watcher.Alert, 10)
+		w.SetNotifier(watcher.NotifierFunc(func(a watcher.Alert) { alerts <- a }), time.Minute)
+
+		statesPub, eventsForClient := startWatchingForLedgerChannel(t, w, makeSignedStateWDummySigs(params, txs[0].State))
+		require.NoError(t, statesPub.Publish(context.Background(), txs[1]))
+
+		triggerAdjEventAndExpectNotification(t, trigger, eventsForClient)
+		requireAlerts(t, alerts, watcher.AlertRegistered, watcher.AlertOutdatedState, watcher.AlertRefuted)
+		// The registration of the watcher is not alerted.
+		triggerAdjEventAndExpectNotification(t, trigger, eventsForClient)
+		triggerAdjEventAndExpectNotification(t, trigger, eventsForClient)
+		requireAlerts(t, alerts, watcher.AlertConcluded)
+	})
+
+	t.Run("refutation_failed", func(t *testing.T) {
+		params, txs := randomTxsForSingleCh(rng, 2)
+		adjSub := &mocks.AdjudicatorSubscription{}
+		trigger := setExpectationNextCall(adjSub, makeRegisteredEvents(txs[0])...)
+		rs := &mocks.RegisterSubscriber{}
+		setExpectationSubscribeCall(rs, adjSub, nil)
+		errRegister := errors.New("register failed")
+		rs.On("Register", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything).Return(errRegister)
+		w := newWatcher(t, rs)
+		alerts := make(chan watcher.Alert, 10)
+		w.SetNotifier(watcher.NotifierFunc(func(a watcher.Alert) { alerts <- a }), time.Minute)
+
+		statesPub, _ := startWatchingForLedgerChannel(t, w, makeSignedStateWDummySigs(params, txs[0].State))
+		require.NoError(t, statesPub.Publish(context.Background(), txs[1]))
+
+		trigger.trigger()
+		got := requireAlerts(t, alerts, watcher.AlertRegistered, watcher.AlertOutdatedState,
+			watcher.AlertRefutationFailed)
+		assert.ErrorIs(t, got[2].Err, errRegister)
+		assert.Equal(t, txs[0].ID, got[2].Channel)
+		assert.Equal(t, txs[0].Version, got[2].Version)
+		assert.Equal(t, txs[1].Version, got[2].LatestVersion)
+	})
+
+	t.Run("timeout_nearing", func(t *testing.T) {
+		params, txs := randomTxsForSingleCh(rng, 1)
+		events := makeRegisteredEvents(txs[0])
+		deadline := time.Now().Add(time.Hour)
+		events[0].(*channel.RegisteredEvent).TimeoutV = &channel.TimeTimeout{Time: deadline}
+		adjSub := &mocks.AdjudicatorSubscription{}
+		trigger := setExpectationNextCall(adjSub, events...)
+		rs := &mocks.RegisterSubscriber{}
+		setExpectationSubscribeCall(rs, adjSub, nil)
+		w := newWatcher(t, rs)
+		alerts := make(chan watcher.Alert, 10)
+		w.SetNotifier(watcher.NotifierFunc(func(a watcher.Alert) { alerts <- a }), 2*time.Hour)
+
+		_, eventsForClient := startWatchingForLedgerChannel(t, w, makeSignedStateWDummySigs(params, txs[0].State))
+		triggerAdjEventAndExpectNotification(t, trigger, eventsForClient)
+		got := requireAlerts(t, alerts, watcher.AlertRegistered, watcher.AlertTimeoutNearing)
+		assert.True(t, deadline.Equal(got[1].Timeout))
+	})
+}
+
+// requireAlerts requires the next alerts to be of the given kinds.
+func requireAlerts(t *testing.T, alerts <-chan watcher.Alert, kinds ...watcher.AlertKind) []watcher.Alert {
+	t.Helper()
+	got := make([]watcher.Alert, len(kinds))
+	for i, kind := range kinds {
+		select {
+		case got[i] = <-alerts:
+			require.Equal(t, kind, got[i].Kind, "alert %d", i)
+		case <-time.After(time.Second):
+			t.Fatalf("expected %v alert", kind)
+		}
+	}
+	return got
+}
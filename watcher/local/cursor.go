@@ -0,0 +1,46 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"context"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/log"
+)
+
+// subscribe subscribes to the adjudicator events of the channel. If the
+// registry is persisted and the adjudicator supports cursors, the subscription
+// resumes after the cursor, so that events emitted while the watcher was down
+// are not missed.
+func (w *Watcher) subscribe(ctx context.Context, id channel.ID, cursor channel.EventCursor) (channel.AdjudicatorSubscription, error) {
+	if cs, ok := w.rs.(channel.CursorSubscriber); ok && w.store != nil {
+		return cs.SubscribeFrom(ctx, id, cursor)
+	}
+	return w.rs.Subscribe(ctx, id)
+}
+
+// persistCursor persists the position of the channel's adjudicator
+// subscription after the last processed event. It does nothing if the store is
+// nil or the subscription does not support cursors.
+func (ch *ch) persistCursor(ctx context.Context, store Store) {
+	sub, ok := ch.eventsFromChainSub.(channel.CursorSubscription)
+	if store == nil || !ok || ctx.Err() != nil {
+		return
+	}
+	if err := store.EventProcessed(ctx, ch.id, sub.Cursor()); err != nil {
+		log.WithField("ID", ch.id).Error("Persisting event cursor: ", err)
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire/perunio"
+	"polycry.pt/poly-go/sortedkv"
+)
+
+var _ Store = (*KeyValueStore)(nil)
+
+// KeyValueStore is a Store on a sorted key-value database. Each channel is
+// stored under its ID. The sub-channels and virtual channels of each parent
+// are indexed, so that removing a channel only touches its children.
+type KeyValueStore struct {
+	mu sync.Mutex
+	db sortedkv.Database
+}
+
+const (
+	// watchedChannelPrefix is the prefix of the watcher's data in the
+	// database.
+	watchedChannelPrefix = "Watcher:"
+	// chanPrefix is the prefix of the channels, which are stored under
+	// their ID.
+	chanPrefix = "Chan:"
+	// childPrefix is the prefix of the child index. Every key is the
+	// parent's ID followed by the child's ID.
+	childPrefix = "Child:"
+)
+
+// NewKeyValueStore creates a Store on the given database.
+func NewKeyValueStore(db sortedkv.Database) *KeyValueStore {
+	return &KeyValueStore{db: sortedkv.NewTable(db, watchedChannelPrefix)}
+}
+
+// ChannelWatched persists the channel and indexes it under its parents.
+func (s *KeyValueStore) ChannelWatched(_ context.Context, c WatchedChannel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	batch := s.db.NewBatch()
+	for _, parent := range c.Parents {
+		if err := batch.Put(childKey(parent, c.Tx.ID), ""); err != nil {
+			return errors.WithMessage(err, "putting child index")
+		}
+	}
+	if err := putChannel(batch, c); err != nil {
+		return err
+	}
+	return errors.WithMessage(batch.Apply(), "applying batch")
+}
+
+// TxPublished replaces the transaction of the persisted channel, if the
+// transaction is newer.
+func (s *KeyValueStore) TxPublished(_ context.Context, tx channel.Transaction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, err := s.get(tx.ID)
+	if err != nil {
+		return err
+	}
+	if c.Tx.State != nil && tx.Version <= c.Tx.Version {
+		return nil
+	}
+	c.Tx = tx
+	return s.put(c)
+}
+
+// EventProcessed replaces the event cursor of the persisted channel.
+func (s *KeyValueStore) EventProcessed(_ context.Context, id channel.ID, cursor channel.EventCursor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, err := s.get(id)
+	if err != nil {
+		return err
+	}
+	c.Cursor = cursor
+	return s.put(c)
+}
+
+// ChannelArchived marks the persisted sub-channel as archived.
+func (s *KeyValueStore) ChannelArchived(_ context.Context, id channel.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, err := s.get(id)
+	if err != nil {
+		return err
+	}
+	c.Archived = true
+	return s.put(c)
+}
+
+// ChannelRemoved removes the persisted channel. It is also removed from the
+// parents of its children, which are removed if no parent remains.
+func (s *KeyValueStore) ChannelRemoved(ctx context.Context, id channel.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// A channel that was already removed together with its last parent only
+	// needs its children to be updated.
+	var c WatchedChannel
+	if ok, err := s.db.Has(chanKey(id)); err != nil {
+		return errors.WithMessage(err, "checking channel")
+	} else if ok {
+		if c, err = s.get(id); err != nil {
+			return err
+		}
+	}
+	children, err := s.children(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	batch := s.db.NewBatch()
+	for _, parent := range c.Parents {
+		if err := batch.Delete(childKey(parent, id)); err != nil {
+			return errors.WithMessage(err, "deleting child index")
+		}
+	}
+	if err := batch.Delete(chanKey(id)); err != nil {
+		return errors.WithMessage(err, "deleting channel")
+	}
+	for _, childID := range children {
+		if err := batch.Delete(childKey(id, childID)); err != nil {
+			return errors.WithMessage(err, "deleting child index")
+		}
+		child, err := s.get(childID)
+		if err != nil {
+			return err
+		}
+		if child.Parents = removeID(child.Parents, id); len(child.Parents) == 0 {
+			err = batch.Delete(chanKey(childID))
+		} else {
+			err = putChannel(batch, child)
+		}
+		if err != nil {
+			return errors.WithMessage(err, "updating child")
+		}
+	}
+	return errors.WithMessage(batch.Apply(), "applying batch")
+}
+
+// children returns the IDs of the indexed children of the channel.
+func (s *KeyValueStore) children(ctx context.Context, id channel.ID) ([]channel.ID, error) {
+	pre := childPrefix + string(id[:])
+	it := s.db.NewIteratorWithPrefix(pre)
+	defer it.Close()
+
+	var ids []channel.ID
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, errors.WithMessage(err, "reading children")
+		}
+		var child channel.ID
+		if n := copy(child[:], it.Key()[len(pre):]); n != len(child) {
+			return nil, errors.Errorf("invalid child index key %x", it.Key())
+		}
+		ids = append(ids, child)
+	}
+	return ids, errors.WithMessage(it.Close(), "closing iterator")
+}
+
+func removeID(ids []channel.ID, id channel.ID) []channel.ID {
+	var res []channel.ID
+	for _, i := range ids {
+		if i != id {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// WatchedChannels returns all persisted channels.
+func (s *KeyValueStore) WatchedChannels(ctx context.Context) ([]WatchedChannel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.all(ctx)
+}
+
+func (s *KeyValueStore) all(ctx context.Context) ([]WatchedChannel, error) {
+	it := s.db.NewIteratorWithPrefix(chanPrefix)
+	defer it.Close()
+
+	var chs []WatchedChannel
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, errors.WithMessage(err, "reading channels")
+		}
+		var c WatchedChannel
+		if err := perunio.Decode(bytes.NewReader(it.ValueBytes()), (*watchedChannel)(&c)); err != nil {
+			return nil, errors.WithMessagef(err, "decoding channel %x", it.Key())
+		}
+		chs = append(chs, c)
+	}
+	return chs, errors.WithMessage(it.Close(), "closing iterator")
+}
+
+func (s *KeyValueStore) get(id channel.ID) (WatchedChannel, error) {
+	var c WatchedChannel
+	b, err := s.db.GetBytes(chanKey(id))
+	if err != nil {
+		return c, errors.WithMessage(err, "getting channel")
+	}
+	return c, errors.WithMessage(perunio.Decode(bytes.NewReader(b), (*watchedChannel)(&c)), "decoding channel")
+}
+
+func (s *KeyValueStore) put(c WatchedChannel) error {
+	return putChannel(s.db, c)
+}
+
+func putChannel(w sortedkv.Writer, c WatchedChannel) error {
+	var buf bytes.Buffer
+	if err := perunio.Encode(&buf, (*watchedChannel)(&c)); err != nil {
+		return errors.WithMessage(err, "encoding channel")
+	}
+	return errors.WithMessage(w.PutBytes(chanKey(c.Tx.ID), buf.Bytes()), "putting channel")
+}
+
+func chanKey(id channel.ID) string {
+	return chanPrefix + string(id[:])
+}
+
+func childKey(parent, child channel.ID) string {
+	return childPrefix + string(parent[:]) + string(child[:])
+}
+
+// watchedChannel implements the perunio encoding of a WatchedChannel.
+type watchedChannel WatchedChannel
+
+// Encode implements perunio.Encoder. Missing signatures are encoded as
+// absent.
+func (c *watchedChannel) Encode(w io.Writer) error {
+	tx := c.Tx
+	if len(tx.Sigs) != len(c.Params.Parts) {
+		tx.Sigs = make([]wallet.Sig, len(c.Params.Parts))
+	}
+	if len(c.Parents) > maxVirtualParents {
+		return errors.Errorf("too many parents: %d", len(c.Parents))
+	}
+	if err := perunio.Encode(w, uint8(len(c.Parents))); err != nil {
+		return err
+	}
+	for _, parent := range c.Parents {
+		if err := perunio.Encode(w, parent); err != nil {
+			return err
+		}
+	}
+	return perunio.Encode(w, c.Params, tx, c.Archived, string(c.Cursor))
+}
+
+// Decode implements perunio.Decoder.
+func (c *watchedChannel) Decode(r io.Reader) error {
+	var numParents uint8
+	if err := perunio.Decode(r, &numParents); err != nil {
+		return err
+	}
+	if numParents > maxVirtualParents {
+		return errors.Errorf("too many parents: %d", numParents)
+	}
+	c.Parents = nil
+	for range numParents {
+		var parent channel.ID
+		if err := perunio.Decode(r, &parent); err != nil {
+			return err
+		}
+		c.Parents = append(c.Parents, parent)
+	}
+	c.Params = new(channel.Params)
+	var cursor string
+	if err := perunio.Decode(r, c.Params, &c.Tx, &c.Archived, &cursor); err != nil {
+		return err
+	}
+	if len(cursor) > 0 {
+		c.Cursor = channel.EventCursor(cursor)
+	}
+	return nil
+}
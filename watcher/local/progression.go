@@ -0,0 +1,144 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"context"
+	stdsync "sync"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/watcher"
+)
+
+// progression holds the progression strategy of a channel and cancels the
+// pending move when a newer adjudicator event is received.
+type progression struct {
+	mu       stdsync.Mutex
+	strategy watcher.ProgressionStrategy
+	cancel   context.CancelFunc
+}
+
+// SetProgressionStrategy sets the strategy with which the watcher progresses
+// the app channel on-chain during a dispute. A nil strategy disables the
+// automatic progression.
+//
+// The strategy should be set right after the client starts watching the
+// channel. It requires the watcher's RegisterSubscriber to implement
+// channel.Progresser.
+func (w *Watcher) SetProgressionStrategy(id channel.ID, strategy watcher.ProgressionStrategy) error {
+	if _, ok := w.rs.(channel.Progresser); !ok && strategy != nil {
+		return errors.New("adjudicator does not support progression")
+	}
+	ch, ok := w.retrieve(id)
+	if !ok {
+		return errors.New("channel not registered with the watcher")
+	}
+	if channel.IsNoApp(ch.params.App) {
+		return errors.New("channel has no app")
+	}
+
+	ch.progression.mu.Lock()
+	defer ch.progression.mu.Unlock()
+	ch.progression.strategy = strategy
+	if strategy == nil {
+		ch.progression.cancelPending()
+	}
+	return nil
+}
+
+// progress asks the progression strategy for the next move after the
+// adjudicator event. A pending move for an earlier event is canceled.
+//
+// The move for a registered event is made after its refutation phase has
+// elapsed. If refuted is set, the registered state was already refuted and no
+// move is made.
+func (ch *ch) progress(
+	ctx context.Context,
+	e channel.AdjudicatorEvent,
+	refuted bool,
+	progresser channel.Progresser,
+) {
+	p := &ch.progression
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cancelPending()
+	if p.strategy == nil {
+		return
+	}
+
+	var (
+		req     channel.AdjudicatorReq
+		timeout channel.Timeout
+	)
+	switch e := e.(type) {
+	case *channel.RegisteredEvent:
+		if refuted {
+			return
+		}
+		req = makeAdjudicatorReq(ch.params, channel.Transaction{State: e.State, Sigs: e.Sigs})
+		timeout = e.Timeout()
+	case *channel.ProgressedEvent:
+		p.strategy.Progressed(e)
+		req = makeAdjudicatorReq(ch.params, channel.Transaction{State: e.State})
+	default:
+		return
+	}
+
+	ctx, p.cancel = context.WithCancel(ctx)
+	strategy := p.strategy
+	ch.Go(func() { ch.move(ctx, strategy, progresser, req, timeout) })
+}
+
+// move waits for the timeout, if any, and progresses the channel with the
+// strategy's next move.
+func (ch *ch) move(
+	ctx context.Context,
+	strategy watcher.ProgressionStrategy,
+	progresser channel.Progresser,
+	req channel.AdjudicatorReq,
+	timeout channel.Timeout,
+) {
+	log := log.WithFields(log.Fields{"ID": ch.id, "Version": req.Tx.Version})
+	if timeout != nil {
+		if err := timeout.Wait(ctx); err != nil {
+			return
+		}
+	}
+
+	pr, err := strategy.Move(ctx, req)
+	if err != nil {
+		log.Error("Error determining next move: ", err)
+		return
+	}
+	if pr == nil {
+		return
+	}
+	if err := progresser.Progress(ctx, *pr); err != nil {
+		log.Error("Error progressing: ", err)
+		return
+	}
+	log.Debugf("Progressed to version (%d)", pr.NewState.Version)
+}
+
+// cancelPending cancels the pending move. The mutex must be held.
+func (p *progression) cancelPending() {
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local_test
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	channeltest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/watcher/internal/mocks"
+	"polycry.pt/poly-go/test"
+)
+
+func Test_Watcher_Progression(t *testing.T) {
+	rng := test.Prng(t)
+
+	t.Run("error/set_strategy", func(t *testing.T) {
+		rs := &mocks.RegisterSubscriber{}
+		setExpectationSubscribeCall(rs, newIdleAdjSub(), nil)
+		setExpectationSubscribeCall(rs, newIdleAdjSub(), nil)
+		prs := &progressingRS{RegisterSubscriber: rs}
+		w := newWatcher(t, prs)
+
+		appParams, appTxs := randomTxsForAppCh(rng, 1)
+		startWatchingForLedgerChannel(t, w, makeSignedStateWDummySigs(appParams, appTxs[0].State))
+		noAppParams, noAppTxs := randomTxsForSingleCh(rng, 1, channeltest.WithoutApp())
+		startWatchingForLedgerChannel(t, w, makeSignedStateWDummySigs(noAppParams, noAppTxs[0].State))
+
+		require.Error(t, w.SetProgressionStrategy(noAppTxs[0].ID, &turnStrategy{}), "no app")
+		require.Error(t, w.SetProgressionStrategy(channeltest.NewRandomChannelID(rng), &turnStrategy{}),
+			"unknown channel")
+		require.NoError(t, w.SetProgressionStrategy(appTxs[0].ID, &turnStrategy{}))
+		require.NoError(t, w.SetProgressionStrategy(appTxs[0].ID, nil))
+
+		// The adjudicator must support progression.
+		w = newWatcher(t, rs)
+		require.Error(t, w.SetProgressionStrategy(appTxs[0].ID, &turnStrategy{}))
+	})
+
+	// The strategy moves after the refutation phase and after each progression
+	// of the other participant.
+	t.Run("happy/progress_on_turn", func(t *testing.T) {
+		params, txs := randomTxsForAppCh(rng, 4)
+		adjSub := &mocks.AdjudicatorSubscription{}
+		trigger := setExpectationNextCall(adjSub, makeRegisteredEvents(txs[0])[0],
+			makeProgressedEventsBy(1, txs[1])[0], makeProgressedEventsBy(0, txs[2])[0])
+		rs := &mocks.RegisterSubscriber{}
+		setExpectationSubscribeCall(rs, adjSub, nil)
+		prs := &progressingRS{RegisterSubscriber: rs, progressed: make(chan channel.ProgressReq, 2)}
+		w := newWatcher(t, prs)
+
+		_, eventsForClient := startWatchingForLedgerChannel(t, w, makeSignedStateWDummySigs(params, txs[0].State))
+		strategy := &turnStrategy{}
+		require.NoError(t, w.SetProgressionStrategy(txs[0].ID, strategy))
+
+		triggerAdjEventAndExpectNotification(t, trigger, eventsForClient)
+		requireProgressed(t, prs, 1)
+		triggerAdjEventAndExpectNotification(t, trigger, eventsForClient)
+		triggerAdjEventAndExpectNotification(t, trigger, eventsForClient)
+		requireProgressed(t, prs, 3)
+		assert.Equal(t, []channel.Index{1, 0}, strategy.progressedBy())
+		select {
+		case pr := <-prs.progressed:
+			t.Fatalf("unexpected progression to version %d", pr.NewState.Version)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	// No move is made if the registered state was refuted.
+	t.Run("happy/no_progress_after_refute", func(t *testing.T) {
+		params, txs := randomTxsForAppCh(rng, 2)
+		adjSub := &mocks.AdjudicatorSubscription{}
+		trigger := setExpectationNextCall(adjSub, makeRegisteredEvents(txs[0])...)
+		rs := &mocks.RegisterSubscriber{}
+		setExpectationSubscribeCall(rs, adjSub, nil)
+		setExpectationRegisterCalls(t, rs, &channelTree{txs[1], []channel.Transaction{}})
+		prs := &progressingRS{RegisterSubscriber: rs, progressed: make(chan channel.ProgressReq, 1)}
+		w := newWatcher(t, prs)
+
+		statesPub, eventsForClient := startWatchingForLedgerChannel(t, w,
+			makeSignedStateWDummySigs(params, txs[0].State))
+		require.NoError(t, w.SetProgressionStrategy(txs[0].ID, &turnStrategy{}))
+		require.NoError(t, statesPub.Publish(context.Background(), txs[1]))
+
+		triggerAdjEventAndExpectNotification(t, trigger, eventsForClient)
+		rs.AssertExpectations(t)
+		select {
+		case pr := <-prs.progressed:
+			t.Fatalf("unexpected progression to version %d", pr.NewState.Version)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+}
+
+// progressingRS is a register subscriber that also implements
+// channel.Progresser.
+type progressingRS struct {
+	*mocks.RegisterSubscriber
+	progressed chan channel.ProgressReq
+}
+
+func (rs *progressingRS) Progress(_ context.Context, req channel.ProgressReq) error {
+	rs.progressed <- req
+	return nil
+}
+
+// turnStrategy moves on all even versions, i.e., when the other participant
+// has made the last move.
+type turnStrategy struct {
+	mu          sync.Mutex
+	progressors []channel.Index
+}
+
+func (s *turnStrategy) Move(_ context.Context, req channel.AdjudicatorReq) (*channel.ProgressReq, error) {
+	if req.Tx.Version%2 != 0 {
+		return nil, nil //nolint:nilnil
+	}
+	next := req.Tx.State.Clone()
+	next.Version++
+	return channel.NewProgressReq(req, next, nil), nil
+}
+
+func (s *turnStrategy) Progressed(e *channel.ProgressedEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.progressors = append(s.progressors, e.Idx)
+}
+
+func (s *turnStrategy) progressedBy() []channel.Index {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.progressors
+}
+
+func requireProgressed(t *testing.T, rs *progressingRS, version uint64) {
+	t.Helper()
+	select {
+	case pr := <-rs.progressed:
+		require.Equal(t, version, pr.NewState.Version)
+	case <-time.After(time.Second):
+		t.Fatalf("expected progression to version %d", version)
+	}
+}
+
+func makeProgressedEventsBy(idx channel.Index, txs ...channel.Transaction) []channel.AdjudicatorEvent {
+	events := makeProgressedEvents(txs...)
+	for _, e := range events {
+		e.(*channel.ProgressedEvent).Idx = idx
+	}
+	return events
+}
+
+// randomTxsForAppCh returns "n" transactions for a random app channel.
+func randomTxsForAppCh(rng *rand.Rand, n int) (*channel.Params, []channel.Transaction) {
+	return randomTxsForSingleCh(rng, n, channeltest.WithApp(channeltest.NewRandomApp(rng)))
+}
@@ -69,3 +69,26 @@ func (r *registry) remove(id channel.ID) {
 	delete(r.chs, id)
 	r.mtx.Unlock()
 }
+
+// setResumed sets the pub-subs that are handed over to the client when it
+// starts watching the resumed channel.
+func (r *registry) setResumed(ch *ch, pubSubs *clientPubSubs) {
+	r.mtx.Lock()
+	ch.resumed = pubSubs
+	r.mtx.Unlock()
+}
+
+// takeResumed retrieves a resumed channel from the registry and removes its
+// pub-subs, so that they are handed over to the client only once.
+func (r *registry) takeResumed(id channel.ID) (*ch, *clientPubSubs, bool) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	ch, ok := r.chs[id]
+	if !ok || ch.resumed == nil {
+		return nil, nil, false
+	}
+	pubSubs := ch.resumed
+	ch.resumed = nil
+	return ch, pubSubs, true
+}
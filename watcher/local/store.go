@@ -0,0 +1,228 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/watcher"
+)
+
+type (
+	// Store persists the registry of a local watcher, so that the watcher can
+	// resume watching after a restart.
+	Store interface {
+		// ChannelWatched is called when the watcher starts watching a channel.
+		ChannelWatched(context.Context, WatchedChannel) error
+
+		// TxPublished is called when the client publishes a newer transaction
+		// of a watched channel.
+		TxPublished(context.Context, channel.Transaction) error
+
+		// EventProcessed is called after the watcher processed an adjudicator
+		// event of the channel, if the subscription supports cursors. The
+		// cursor is the position of the subscription after the event.
+		EventProcessed(_ context.Context, id channel.ID, cursor channel.EventCursor) error
+
+		// ChannelArchived is called when the watcher stops watching a
+		// sub-channel whose latest state is still needed for disputes of the
+		// parent channel.
+		ChannelArchived(_ context.Context, id channel.ID) error
+
+		// ChannelRemoved is called when the watcher stops watching a channel.
+		// For a ledger channel, it is removed from the parents of its archived
+		// sub-channels and virtual channels. Archived channels without any
+		// remaining parent are removed, too.
+		ChannelRemoved(_ context.Context, id channel.ID) error
+
+		// WatchedChannels returns all persisted channels.
+		WatchedChannels(context.Context) ([]WatchedChannel, error)
+	}
+
+	// WatchedChannel is a channel in the registry of a watcher.
+	WatchedChannel struct {
+		Parents  []channel.ID    // Parents are the parents of a sub-channel or virtual channel.
+		Params   *channel.Params // Params are the channel parameters.
+		Tx       channel.Transaction
+		Archived bool // Archived is set for sub-channels that are no longer watched.
+
+		// Cursor is the position of the adjudicator subscription after the
+		// last processed event, or nil.
+		Cursor channel.EventCursor
+	}
+
+	// clientPubSubs are the pub-subs of a resumed channel that are handed over
+	// to the client when it starts watching the channel again.
+	clientPubSubs struct {
+		statesPub watcher.StatesPub
+		eventsSub watcher.AdjudicatorSub
+	}
+
+	// persistingStatesPub persists each transaction before publishing it to
+	// the watcher.
+	persistingStatesPub struct {
+		*statesPubSub
+		store Store
+	}
+)
+
+// NewPersistentWatcher initializes a local watcher that persists its registry
+// in the store.
+//
+// It resumes watching all channels of the store before it returns. Until the
+// client starts watching a resumed channel again, disputes are refuted with
+// the latest persisted transaction and adjudicator events are buffered for the
+// client. When the client starts watching, it receives the pub-subs of the
+// resumed channel.
+//
+// If the adjudicator is a channel.CursorSubscriber, the position of each
+// channel's event subscription is persisted, too, and the subscriptions of
+// resumed channels start right after the last processed event.
+func NewPersistentWatcher(ctx context.Context, rs channel.RegisterSubscriber, store Store) (*Watcher, error) {
+	w := &Watcher{
+		rs:       rs,
+		registry: newRegistry(),
+		store:    store,
+	}
+	if err := w.resume(ctx); err != nil {
+		return nil, errors.WithMessage(err, "resuming watched channels")
+	}
+	return w, nil
+}
+
+// resume starts watching all channels of the store. Ledger channels are
+// resumed before their sub-channels.
+func (w *Watcher) resume(ctx context.Context) error {
+	chs, err := w.store.WatchedChannels(ctx)
+	if err != nil {
+		return err
+	}
+
+	var resumed []*ch
+	abort := func(err error) error {
+		for i := len(resumed) - 1; i >= 0; i-- {
+			close(resumed[i].done)
+			closePubSubs(resumed[i])
+			w.remove(resumed[i].id)
+		}
+		return err
+	}
+
+	for _, c := range chs {
+		if len(c.Parents) > 0 {
+			continue
+		}
+		ch, err := w.resumeCh(ctx, nil, c)
+		if err != nil {
+			return abort(err)
+		}
+		resumed = append(resumed, ch)
+	}
+
+	for _, c := range chs {
+		if len(c.Parents) == 0 {
+			continue
+		}
+		parents := make([]*ch, len(c.Parents))
+		for i, id := range c.Parents {
+			parent, ok := w.retrieve(id)
+			if !ok {
+				return abort(errors.Errorf("parent of channel %x not persisted", c.Tx.ID))
+			}
+			parents[i] = parent
+		}
+		sortChs(parents)
+		if c.Archived {
+			for _, parent := range parents {
+				parent.archivedSubChStates[c.Tx.ID] = makeSignedState(c.Params, c.Tx)
+			}
+			continue
+		}
+		ch, err := w.resumeCh(ctx, parents, c)
+		if err != nil {
+			return abort(err)
+		}
+		for _, parent := range parents {
+			parent.subChs[ch.id] = struct{}{}
+		}
+		resumed = append(resumed, ch)
+	}
+	return nil
+}
+
+func (w *Watcher) resumeCh(ctx context.Context, parents []*ch, c WatchedChannel) (*ch, error) {
+	signedState := makeSignedState(c.Params, c.Tx)
+	ch, statesPub, eventsSub, err := w.watch(ctx, parents, signedState, c.Cursor, false)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "resuming channel %x", c.Tx.ID)
+	}
+	w.setResumed(ch, &clientPubSubs{statesPub: statesPub, eventsSub: eventsSub})
+	log.WithField("ID", ch.id).Info("Resumed watching")
+	return ch, nil
+}
+
+// adopt hands the pub-subs of a resumed channel over to the client. It returns
+// false if the channel was not resumed or was already adopted.
+//
+// If the client starts watching with a newer state than the persisted one, the
+// state is published.
+func (w *Watcher) adopt(
+	ctx context.Context,
+	parents []*ch,
+	signedState channel.SignedState,
+) (watcher.StatesPub, watcher.AdjudicatorSub, bool, error) {
+	ch, pubSubs, ok := w.takeResumed(signedState.State.ID)
+	if !ok {
+		return nil, nil, false, nil
+	}
+	if !equalChs(ch.parents, parents) {
+		w.setResumed(ch, pubSubs)
+		return nil, nil, true, errors.New("channel was resumed with different parents")
+	}
+
+	if latest := ch.txRetriever.retrieve(); latest.Version < signedState.State.Version {
+		tx := channel.Transaction{State: signedState.State, Sigs: signedState.Sigs}
+		if err := pubSubs.statesPub.Publish(ctx, tx); err != nil {
+			w.setResumed(ch, pubSubs)
+			return nil, nil, true, errors.WithMessage(err, "publishing state")
+		}
+	}
+	log.WithField("ID", ch.id).Debug("Client adopted resumed channel")
+	return pubSubs.statesPub, pubSubs.eventsSub, true, nil
+}
+
+// Publish persists the transaction and publishes it to the watcher.
+func (s *persistingStatesPub) Publish(ctx context.Context, tx channel.Transaction) error {
+	if err := s.store.TxPublished(ctx, tx); err != nil {
+		return errors.WithMessage(err, "persisting transaction")
+	}
+	return s.statesPubSub.Publish(ctx, tx)
+}
+
+func equalChs(a, b []*ch) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
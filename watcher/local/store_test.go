@@ -0,0 +1,220 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	channeltest "perun.network/go-perun/channel/test"
+	cltest "perun.network/go-perun/client/test"
+	wallettest "perun.network/go-perun/wallet/test"
+	"perun.network/go-perun/watcher/internal/mocks"
+	"perun.network/go-perun/watcher/local"
+	"polycry.pt/poly-go/sortedkv/memorydb"
+	"polycry.pt/poly-go/test"
+)
+
+func Test_PersistentWatcher(t *testing.T) {
+	rng := test.Prng(t)
+	ctx := context.Background()
+	store := local.NewKeyValueStore(memorydb.NewDatabase())
+
+	parentParams, parentTxs := randomTxsForSingleCh(rng, 3)
+	childParams, childTxs := randomTxsForSingleCh(rng, 2)
+	// Sub-channel funding. The sub-allocation must be valid to be persisted.
+	parentTxs[2].Locked = []channel.SubAlloc{*channel.NewSubAlloc(childTxs[0].ID, []channel.Bal{big.NewInt(1)}, nil)}
+
+	// The first watcher persists the channels and the published transactions.
+	{
+		adjSubParent := &mocks.AdjudicatorSubscription{}
+		triggerParent := setExpectationNextCall(adjSubParent)
+		setExpectationCloseCallErrCall(adjSubParent, triggerParent, nil)
+		adjSubChild := &mocks.AdjudicatorSubscription{}
+		triggerChild := setExpectationNextCall(adjSubChild)
+		setExpectationCloseCallErrCall(adjSubChild, triggerChild, nil)
+		rs := &mocks.RegisterSubscriber{}
+		setExpectationSubscribeCall(rs, adjSubParent, nil)
+		setExpectationSubscribeCall(rs, adjSubChild, nil)
+
+		w, err := local.NewPersistentWatcher(ctx, rs, store)
+		require.NoError(t, err)
+		statesPubParent, _ := startWatchingForLedgerChannel(t, w, makeSignedStateWDummySigs(parentParams, parentTxs[0].State))
+		require.NoError(t, statesPubParent.Publish(ctx, parentTxs[1]))
+		require.NoError(t, statesPubParent.Publish(ctx, parentTxs[2]))
+		statesPubChild, _ := startWatchingForSubChannel(t, w, makeSignedStateWDummySigs(childParams, childTxs[0].State), parentTxs[0].ID)
+		require.NoError(t, statesPubChild.Publish(ctx, childTxs[1]))
+
+		chs, err := store.WatchedChannels(ctx)
+		require.NoError(t, err)
+		require.Len(t, chs, 2)
+
+		// Stop the watcher without updating the store, as if the process
+		// was terminated.
+		triggerChild.close()
+		triggerParent.close()
+	}
+
+	// The second watcher resumes watching and refutes an outdated registration
+	// before the client started watching again.
+	adjSubParent := &mocks.AdjudicatorSubscription{}
+	triggerParent := setExpectationNextCall(adjSubParent, makeRegisteredEvents(parentTxs[0])...)
+	setExpectationCloseCallErrCall(adjSubParent, triggerParent, nil)
+	adjSubChild := &mocks.AdjudicatorSubscription{}
+	triggerChild := setExpectationNextCall(adjSubChild)
+	setExpectationCloseCallErrCall(adjSubChild, triggerChild, nil)
+	rs := &mocks.RegisterSubscriber{}
+	setExpectationSubscribeCall(rs, adjSubParent, nil)
+	setExpectationSubscribeCall(rs, adjSubChild, nil)
+	setExpectationRegisterCalls(t, rs, &channelTree{parentTxs[2], []channel.Transaction{childTxs[1]}})
+
+	w, err := local.NewPersistentWatcher(ctx, rs, store)
+	require.NoError(t, err)
+	wantEvent := triggerParent.trigger()
+
+	t.Run("adopt", func(t *testing.T) {
+		// The client receives the events that happened before it started
+		// watching.
+		_, eventsForClientParent := startWatchingForLedgerChannel(t, w, makeSignedStateWDummySigs(parentParams, parentTxs[0].State))
+		require.EqualValues(t, wantEvent, <-eventsForClientParent.EventStream())
+		rs.AssertExpectations(t)
+
+		// Channels are handed over only once.
+		_, _, err := w.StartWatchingLedgerChannel(ctx, makeSignedStateWDummySigs(parentParams, parentTxs[0].State))
+		require.Error(t, err)
+
+		// Sub-channels must be adopted with the same parent.
+		_, _, err = w.StartWatchingLedgerChannel(ctx, makeSignedStateWDummySigs(childParams, childTxs[0].State))
+		require.Error(t, err)
+		startWatchingForSubChannel(t, w, makeSignedStateWDummySigs(childParams, childTxs[0].State), parentTxs[0].ID)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		// The child is still funded by the parent and therefore archived.
+		require.NoError(t, w.StopWatching(ctx, childTxs[0].ID))
+		chs, err := store.WatchedChannels(ctx)
+		require.NoError(t, err)
+		require.Len(t, chs, 2)
+		for _, c := range chs {
+			assert.Equal(t, len(c.Parents) > 0, c.Archived)
+			if c.Tx.ID == childTxs[0].ID {
+				assert.NoError(t, c.Tx.Equal(childTxs[1].State))
+			} else {
+				assert.NoError(t, c.Tx.Equal(parentTxs[2].State))
+			}
+		}
+
+		require.NoError(t, w.StopWatching(ctx, parentTxs[0].ID))
+		chs, err = store.WatchedChannels(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, chs)
+	})
+}
+
+func Test_PersistentWatcher_Cursor(t *testing.T) {
+	rng := test.Prng(t)
+	ctx := context.Background()
+	store := local.NewKeyValueStore(memorydb.NewDatabase())
+	backend := cltest.NewMockBackend(rng, "1")
+	params, txs := randomTxsForSingleCh(rng, 1, channeltest.WithChallengeDuration(60))
+	signedState := makeSignedStateWDummySigs(params, txs[0].State)
+	req := channel.AdjudicatorReq{Params: params, Tx: txs[0]}
+
+	// The first watcher persists the cursor after processing the
+	// registration.
+	w, err := local.NewPersistentWatcher(ctx, backend, store)
+	require.NoError(t, err)
+	_, eventsForClient := startWatchingForLedgerChannel(t, w, signedState)
+	require.NoError(t, backend.Register(ctx, req, nil))
+	require.IsType(t, &channel.RegisteredEvent{}, <-eventsForClient.EventStream())
+	require.Eventually(t, func() bool {
+		chs, err := store.WatchedChannels(ctx)
+		return err == nil && len(chs) == 1 && chs[0].Cursor != nil
+	}, time.Second, 10*time.Millisecond)
+
+	// The second watcher resumes after the registration, so the conclusion
+	// is the first event that the client receives.
+	w, err = local.NewPersistentWatcher(ctx, backend, store)
+	require.NoError(t, err)
+	adj := backend.NewAdjudicator(wallettest.NewRandomAddress(rng, channel.TestBackendID))
+	require.NoError(t, adj.Withdraw(ctx, req, nil))
+	_, eventsForClient = startWatchingForLedgerChannel(t, w, signedState)
+	require.IsType(t, &channel.ConcludedEvent{}, <-eventsForClient.EventStream())
+}
+
+func TestKeyValueStore(t *testing.T) {
+	rng := test.Prng(t)
+	ctx := context.Background()
+	db := memorydb.NewDatabase()
+	store := local.NewKeyValueStore(db)
+
+	params := make([]*channel.Params, 4)
+	txs := make([][]channel.Transaction, 4)
+	for i := range params {
+		params[i], txs[i] = randomTxsForSingleCh(rng, 2)
+	}
+	ledgerA, ledgerB, sub, virtual := txs[0][0].ID, txs[1][0].ID, txs[2][0].ID, txs[3][0].ID
+	for i, parents := range [][]channel.ID{nil, nil, {ledgerA}, {ledgerA, ledgerB}} {
+		require.NoError(t, store.ChannelWatched(ctx, local.WatchedChannel{
+			Parents: parents, Params: params[i], Tx: txs[i][0],
+		}))
+	}
+
+	t.Run("tx_published", func(t *testing.T) {
+		require.NoError(t, store.TxPublished(ctx, txs[0][1]))
+		// Older transactions do not replace newer ones.
+		require.NoError(t, store.TxPublished(ctx, txs[0][0]))
+		chs, err := store.WatchedChannels(ctx)
+		require.NoError(t, err)
+		for _, c := range chs {
+			if c.Tx.ID == ledgerA {
+				assert.Equal(t, txs[0][1].Version, c.Tx.Version)
+			}
+		}
+	})
+
+	t.Run("channel_removed", func(t *testing.T) {
+		// Removing a parent removes its sub-channel and detaches the virtual
+		// channel.
+		require.NoError(t, store.ChannelRemoved(ctx, ledgerA))
+		chs, err := store.WatchedChannels(ctx)
+		require.NoError(t, err)
+		require.Len(t, chs, 2)
+		for _, c := range chs {
+			assert.NotEqual(t, sub, c.Tx.ID)
+			if c.Tx.ID == virtual {
+				assert.Equal(t, []channel.ID{ledgerB}, c.Parents)
+			}
+		}
+
+		require.NoError(t, store.ChannelRemoved(ctx, ledgerB))
+		chs, err = store.WatchedChannels(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, chs)
+
+		// No index entries are left behind.
+		it := db.NewIterator()
+		for it.Next() {
+			t.Errorf("unexpected key %q", it.Key())
+		}
+		require.NoError(t, it.Close())
+	})
+}
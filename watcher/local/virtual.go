@@ -0,0 +1,158 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/watcher"
+)
+
+// maxVirtualParents is the maximum number of ledger channels funding a virtual
+// channel.
+const maxVirtualParents = 2
+
+// StartWatchingVirtualChannel starts watching for a virtual channel.
+//
+// The parents are the ledger channels funding the virtual channel that are
+// registered with the watcher: the channel with the hub for a participant and
+// the channels with both participants for the hub.
+//
+// If an outdated state of the virtual channel is registered, the watcher
+// refutes by registering the channel tree of a parent that still funds the
+// virtual channel. The latest state of the virtual channel, e.g., its final
+// state, is also part of every dispute registered for any of the parents.
+func (w *Watcher) StartWatchingVirtualChannel(
+	ctx context.Context,
+	parents []channel.ID,
+	signedState channel.SignedState,
+) (watcher.StatesPub, watcher.AdjudicatorSub, error) {
+	if !signedState.Params.VirtualChannel {
+		return nil, nil, errors.New("not a virtual channel")
+	}
+	parentChs, err := w.retrieveParents(parents)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lockFamily(parentChs)
+	defer unlockFamily(parentChs)
+	statesPub, eventsSub, err := w.startWatching(ctx, parentChs, signedState)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, parent := range parentChs {
+		parent.subChs[signedState.State.ID] = struct{}{}
+	}
+	return statesPub, eventsSub, nil
+}
+
+// retrieveParents retrieves the parent ledger channels of a virtual channel
+// from the registry, ordered by their IDs.
+func (w *Watcher) retrieveParents(ids []channel.ID) ([]*ch, error) {
+	if len(ids) == 0 || len(ids) > maxVirtualParents {
+		return nil, errors.Errorf("invalid number of parents: %d", len(ids))
+	}
+	parents := make([]*ch, len(ids))
+	for i, id := range ids {
+		parent, ok := w.retrieve(id)
+		if !ok {
+			return nil, errors.Errorf("parent channel %x not registered with the watcher", id)
+		}
+		if parent.isSubChannel() {
+			return nil, errors.New("parent must be a ledger channel")
+		}
+		for _, p := range parents[:i] {
+			if p == parent {
+				return nil, errors.New("duplicate parent")
+			}
+		}
+		parents[i] = parent
+	}
+	sortChs(parents)
+	return parents, nil
+}
+
+// family returns the channels whose subChsAccess mutexes guard the channel,
+// ordered by their IDs: the channel itself for a ledger channel and its
+// parents otherwise.
+func (c *ch) family() []*ch {
+	if !c.isSubChannel() {
+		return []*ch{c}
+	}
+	return c.parents
+}
+
+// root returns the ledger channel whose channel tree is registered to dispute
+// the channel. For a sub-channel or virtual channel, it is the first parent
+// that still funds it.
+func (ch *ch) root() *ch {
+	if !ch.isSubChannel() {
+		return ch
+	}
+	for _, parent := range ch.parents {
+		if _, ok := parent.txRetriever.retrieve().SubAlloc(ch.id); ok {
+			return parent
+		}
+	}
+	return ch.parents[0]
+}
+
+// lockFamily locks the channels in the given order.
+func lockFamily(family []*ch) {
+	for _, ch := range family {
+		ch.subChsAccess.Lock()
+	}
+}
+
+// tryLockFamily locks the channels in the given order. It returns false if
+// the context is done before all channels are locked.
+func tryLockFamily(ctx context.Context, family []*ch) bool {
+	for i, ch := range family {
+		if !ch.subChsAccess.TryLockCtx(ctx) {
+			unlockFamily(family[:i])
+			return false
+		}
+	}
+	return true
+}
+
+func unlockFamily(family []*ch) {
+	for i := len(family) - 1; i >= 0; i-- {
+		family[i].subChsAccess.Unlock()
+	}
+}
+
+func sortChs(chs []*ch) {
+	sort.Slice(chs, func(i, j int) bool {
+		return bytes.Compare(chs[i].id[:], chs[j].id[:]) < 0
+	})
+}
+
+func chIDs(chs []*ch) []channel.ID {
+	if len(chs) == 0 {
+		return nil
+	}
+	ids := make([]channel.ID, len(chs))
+	for i, ch := range chs {
+		ids[i] = ch.id
+	}
+	return ids
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local_test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	channeltest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/watcher/internal/mocks"
+	"polycry.pt/poly-go/test"
+)
+
+func Test_Watcher_VirtualChannel(t *testing.T) {
+	rng := test.Prng(t)
+
+	t.Run("error/start_watching", func(t *testing.T) {
+		rs := &mocks.RegisterSubscriber{}
+		setExpectationSubscribeCall(rs, newIdleAdjSub(), nil)
+		setExpectationSubscribeCall(rs, newIdleAdjSub(), nil)
+		w := newWatcher(t, rs)
+
+		parentParams, parentTxs := randomTxsForSingleCh(rng, 1)
+		startWatchingForLedgerChannel(t, w, makeSignedStateWDummySigs(parentParams, parentTxs[0].State))
+		subParams, subTxs := randomTxsForSingleCh(rng, 1)
+		startWatchingForSubChannel(t, w, makeSignedStateWDummySigs(subParams, subTxs[0].State), parentTxs[0].ID)
+		virtualParams, virtualTxs := randomTxsForVirtualCh(rng, 1)
+		virtualState := makeSignedStateWDummySigs(virtualParams, virtualTxs[0].State)
+
+		for name, parents := range map[string][]channel.ID{
+			"no_parent":         nil,
+			"unknown_parent":    {channeltest.NewRandomChannelID(rng)},
+			"sub_channel":       {subTxs[0].ID},
+			"duplicate_parents": {parentTxs[0].ID, parentTxs[0].ID},
+		} {
+			_, _, err := w.StartWatchingVirtualChannel(context.Background(), parents, virtualState)
+			require.Error(t, err, name)
+		}
+		_, _, err := w.StartWatchingVirtualChannel(context.Background(), []channel.ID{parentTxs[0].ID},
+			makeSignedStateWDummySigs(subParams, subTxs[0].State))
+		require.Error(t, err, "not_virtual")
+	})
+
+	// A registered event with an outdated state of the virtual channel is
+	// refuted by registering the channel tree of a parent that funds it.
+	t.Run("happy/refute_virtual", func(t *testing.T) {
+		parents, virtualParams, virtualTxs := setupVirtualChannelTree(rng)
+		root := parents[0]
+
+		adjSubVirtual := &mocks.AdjudicatorSubscription{}
+		trigger := setExpectationNextCall(adjSubVirtual, makeRegisteredEvents(virtualTxs[0])...)
+		rs := &mocks.RegisterSubscriber{}
+		setExpectationSubscribeCall(rs, newIdleAdjSub(), nil)
+		setExpectationSubscribeCall(rs, newIdleAdjSub(), nil)
+		setExpectationSubscribeCall(rs, adjSubVirtual, nil)
+		setExpectationRegisterCalls(t, rs, &channelTree{root.txs[1], []channel.Transaction{virtualTxs[1]}})
+		w := newWatcher(t, rs)
+
+		for _, p := range parents {
+			statesPub, _ := startWatchingForLedgerChannel(t, w, makeSignedStateWDummySigs(p.params, p.txs[0].State))
+			require.NoError(t, statesPub.Publish(context.Background(), p.txs[1]))
+		}
+		statesPub, eventsForClient, err := w.StartWatchingVirtualChannel(context.Background(),
+			[]channel.ID{parents[1].txs[0].ID, parents[0].txs[0].ID},
+			makeSignedStateWDummySigs(virtualParams, virtualTxs[0].State))
+		require.NoError(t, err)
+		require.NoError(t, statesPub.Publish(context.Background(), virtualTxs[1]))
+
+		triggerAdjEventAndExpectNotification(t, trigger, eventsForClient)
+		rs.AssertExpectations(t)
+	})
+
+	// A dispute of any parent enforces the latest, final state of the virtual
+	// channel.
+	t.Run("happy/enforce_final_state", func(t *testing.T) {
+		parents, virtualParams, virtualTxs := setupVirtualChannelTree(rng)
+		virtualTxs[1].IsFinal = true
+		disputed := parents[1]
+
+		adjSubDisputed := &mocks.AdjudicatorSubscription{}
+		trigger := setExpectationNextCall(adjSubDisputed, makeRegisteredEvents(disputed.txs[0])...)
+		rs := &mocks.RegisterSubscriber{}
+		setExpectationSubscribeCall(rs, newIdleAdjSub(), nil)
+		setExpectationSubscribeCall(rs, adjSubDisputed, nil)
+		setExpectationSubscribeCall(rs, newIdleAdjSub(), nil)
+		setExpectationRegisterCalls(t, rs, &channelTree{disputed.txs[1], []channel.Transaction{virtualTxs[1]}})
+		w := newWatcher(t, rs)
+
+		_, _ = startWatchingForLedgerChannel(t, w, makeSignedStateWDummySigs(parents[0].params, parents[0].txs[1].State))
+		statesPub, eventsForClient := startWatchingForLedgerChannel(t, w,
+			makeSignedStateWDummySigs(disputed.params, disputed.txs[0].State))
+		require.NoError(t, statesPub.Publish(context.Background(), disputed.txs[1]))
+		statesPub, _, err := w.StartWatchingVirtualChannel(context.Background(),
+			[]channel.ID{parents[0].txs[0].ID, disputed.txs[0].ID},
+			makeSignedStateWDummySigs(virtualParams, virtualTxs[0].State))
+		require.NoError(t, err)
+		require.NoError(t, statesPub.Publish(context.Background(), virtualTxs[1]))
+
+		triggerAdjEventAndExpectNotification(t, trigger, eventsForClient)
+		rs.AssertExpectations(t)
+	})
+
+	t.Run("happy/stop_watching", func(t *testing.T) {
+		parents, virtualParams, virtualTxs := setupVirtualChannelTree(rng)
+
+		rs := &mocks.RegisterSubscriber{}
+		for range 3 {
+			setExpectationSubscribeCall(rs, newIdleAdjSub(), nil)
+		}
+		w := newWatcher(t, rs)
+		for _, p := range parents {
+			startWatchingForLedgerChannel(t, w, makeSignedStateWDummySigs(p.params, p.txs[1].State))
+		}
+		_, _, err := w.StartWatchingVirtualChannel(context.Background(),
+			[]channel.ID{parents[0].txs[0].ID, parents[1].txs[0].ID},
+			makeSignedStateWDummySigs(virtualParams, virtualTxs[0].State))
+		require.NoError(t, err)
+
+		// The parents cannot be stopped while the virtual channel is watched.
+		for _, p := range parents {
+			require.Error(t, w.StopWatching(context.Background(), p.txs[0].ID))
+		}
+	})
+}
+
+type ledgerCh struct {
+	params *channel.Params
+	txs    []channel.Transaction
+}
+
+// setupVirtualChannelTree returns two parent ledger channels, ordered by their
+// IDs, and a virtual channel that is funded by both parents in their second
+// transaction.
+func setupVirtualChannelTree(rng *rand.Rand) ([]ledgerCh, *channel.Params, []channel.Transaction) {
+	virtualParams, virtualTxs := randomTxsForVirtualCh(rng, 2)
+	parents := make([]ledgerCh, 2)
+	for i := range parents {
+		parents[i].params, parents[i].txs = randomTxsForSingleCh(rng, 2)
+		parents[i].txs[1].Locked = []channel.SubAlloc{
+			*channel.NewSubAlloc(virtualTxs[0].ID, []channel.Bal{big.NewInt(1)}, nil),
+		}
+	}
+	if bytes.Compare(parents[0].txs[0].ID[:], parents[1].txs[0].ID[:]) > 0 {
+		parents[0], parents[1] = parents[1], parents[0]
+	}
+	return parents, virtualParams, virtualTxs
+}
+
+// randomTxsForVirtualCh returns "n" transactions for a random virtual channel.
+func randomTxsForVirtualCh(rng *rand.Rand, n int) (*channel.Params, []channel.Transaction) {
+	return randomTxsForSingleCh(rng, n, channeltest.WithVirtualChannel(true))
+}
+
+// newIdleAdjSub returns an adjudicator subscription that never emits an event.
+func newIdleAdjSub() *mocks.AdjudicatorSubscription {
+	adjSub := &mocks.AdjudicatorSubscription{}
+	trigger := setExpectationNextCall(adjSub)
+	setExpectationCloseCallErrCall(adjSub, trigger, nil)
+	return adjSub
+}
@@ -47,7 +47,9 @@ type (
 	Watcher struct {
 		*registry
 
-		rs channel.RegisterSubscriber
+		rs     channel.RegisterSubscriber
+		store  Store // store is nil if the registry is not persisted.
+		alerts alerts
 	}
 
 	txRetriever struct {
@@ -60,9 +62,13 @@ type (
 		params      *channel.Params
 		isClosed    bool
 		done        chan struct{}
-		parent      *ch
 		multiLedger bool
 
+		// parents are the ledger channels funding a sub-channel or a virtual
+		// channel, ordered by their IDs. A sub-channel has one parent, a
+		// virtual channel one or two. It is empty for ledger channels.
+		parents []*ch
+
 		// For keeping track of the sub-channels of this ledger channel
 		// registered with the watcher.
 		// Sub-channels are added when they are registered with the watcher and
@@ -83,6 +89,11 @@ type (
 		registered        bool
 		registeredVersion uint64
 
+		// ownDispute is set if registeredVersion was registered by the
+		// watcher itself. It is only used to not alert about the watcher's
+		// own registrations.
+		ownDispute bool
+
 		// published stores whether any event has been published for this
 		// channel.
 		published bool
@@ -106,6 +117,24 @@ type (
 		// wg is used to synchronize the starting and stopping of handler go
 		// routines for this channel.
 		wg sync.WaitGroup
+
+		// resumed holds the pub-subs of a channel that was resumed from the
+		// store, until the client starts watching the channel again. It is
+		// guarded by the registry mutex.
+		resumed *clientPubSubs
+
+		// progression makes the moves of an optional progression strategy
+		// during disputes.
+		progression progression
+
+		// timeoutAlertCancel cancels the pending timeout alert. It is only
+		// accessed by the handler of adjudicator events.
+		timeoutAlertCancel context.CancelFunc
+
+		// withdrawal is set if the watcher withdraws the funds of the
+		// ledger channel after its conclusion. It is guarded by
+		// subChsAccess.
+		withdrawal *withdrawal
 	}
 
 	chInitializer func() (*ch, error)
@@ -133,7 +162,7 @@ func (ch *ch) Go(fn func()) {
 	}()
 }
 
-func (ch *ch) isSubChannel() bool { return ch.parent != nil }
+func (ch *ch) isSubChannel() bool { return len(ch.parents) > 0 }
 
 // NewWatcher initializes a local watcher.
 //
@@ -154,7 +183,7 @@ func (w *Watcher) StartWatchingLedgerChannel(
 	return w.startWatching(ctx, nil, signedState)
 }
 
-// StartWatchingSubChannel starts watching for a sub-channel or virtual channel.
+// StartWatchingSubChannel starts watching for a sub-channel.
 //
 // Parent must be a ledger channel. Because, currently only one level of
 // sub-channels is supported.
@@ -174,7 +203,7 @@ func (w *Watcher) StartWatchingSubChannel(
 
 	parentCh.subChsAccess.Lock()
 	defer parentCh.subChsAccess.Unlock()
-	statesPub, eventsSub, err := w.startWatching(ctx, parentCh, signedState)
+	statesPub, eventsSub, err := w.startWatching(ctx, []*ch{parentCh}, signedState)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -194,20 +223,16 @@ func (w *Watcher) StartWatchingSubChannel(
 // states for the ledger channel and all its sub-channels (even if the watcher
 // has stopped watching for some of the sub-channel).
 //
-// Context is not used, it is for implementing watcher.Watcher interface.
-func (w *Watcher) StopWatching(_ context.Context, id channel.ID) error {
+// The context is only used for updating the store.
+func (w *Watcher) StopWatching(ctx context.Context, id channel.ID) error {
 	ch, ok := w.retrieve(id)
 	if !ok {
 		return errors.New("channel not registered with the watcher")
 	}
 
-	parent := ch
-	if ch.isSubChannel() {
-		parent = ch.parent
-	}
-
-	parent.subChsAccess.Lock()
-	defer parent.subChsAccess.Unlock()
+	family := ch.family()
+	lockFamily(family)
+	defer unlockFamily(family)
 
 	if ch.isClosed {
 		// Channel could have been closed while were waiting for the mutex locked.
@@ -215,13 +240,17 @@ func (w *Watcher) StopWatching(_ context.Context, id channel.ID) error {
 	}
 	close(ch.done)
 
+	archived := false
 	if ch.isSubChannel() {
-		latestParentTx := ch.parent.txRetriever.retrieve()
-		if _, ok := latestParentTx.SubAlloc(id); ok {
-			parent.archivedSubChStates[id] = makeSignedState(ch.params, ch.txRetriever.retrieve())
-		}
+		for _, parent := range ch.parents {
+			latestParentTx := parent.txRetriever.retrieve()
+			if _, ok := latestParentTx.SubAlloc(id); ok {
+				parent.archivedSubChStates[id] = makeSignedState(ch.params, ch.txRetriever.retrieve())
+				archived = true
+			}
 
-		delete(parent.subChs, id)
+			delete(parent.subChs, id)
+		}
 	} else if len(ch.subChs) > 0 {
 		return errors.WithMessagef(ErrSubChannelsPresent, "cannot de-register: %d %v", len(ch.subChs), ch.id)
 	}
@@ -229,33 +258,72 @@ func (w *Watcher) StopWatching(_ context.Context, id channel.ID) error {
 	closePubSubs(ch)
 	w.remove(ch.id)
 	ch.isClosed = true
-	return nil
+
+	if w.store == nil {
+		return nil
+	}
+	var err error
+	if archived {
+		err = w.store.ChannelArchived(ctx, id)
+	} else {
+		err = w.store.ChannelRemoved(ctx, id)
+	}
+	return errors.WithMessage(err, "stopped watching, but updating store")
 }
 
 func (w *Watcher) startWatching(
 	ctx context.Context,
-	parent *ch,
+	parents []*ch,
 	signedState channel.SignedState,
 ) (watcher.StatesPub, watcher.AdjudicatorSub, error) {
+	if statesPub, eventsSub, ok, err := w.adopt(ctx, parents, signedState); ok || err != nil {
+		return statesPub, eventsSub, err
+	}
+	_, statesPub, eventsSub, err := w.watch(ctx, parents, signedState, nil, w.store != nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return statesPub, eventsSub, nil
+}
+
+// watch adds the channel to the registry and starts its handlers. If persist
+// is set, the channel is persisted in the store and the returned states
+// publisher persists each published transaction. The cursor is the persisted
+// position of the channel's adjudicator subscription, or nil.
+func (w *Watcher) watch(
+	ctx context.Context,
+	parents []*ch,
+	signedState channel.SignedState,
+	cursor channel.EventCursor,
+	persist bool,
+) (*ch, watcher.StatesPub, *adjudicatorPubSub, error) {
 	id := signedState.State.ID
 
 	var statesPubSub *statesPubSub
 
 	var eventsToClientPubSub *adjudicatorPubSub
 	chInitializer1 := func() (*ch, error) {
-		eventsFromChainSub, err := w.rs.Subscribe(ctx, id)
+		eventsFromChainSub, err := w.subscribe(ctx, id, cursor)
 		if err != nil {
 			return nil, errors.WithMessage(err, "subscribing to adjudicator events from blockchain")
 		}
+		if persist {
+			if err := w.store.ChannelWatched(ctx, makeWatchedChannel(parents, signedState)); err != nil {
+				if cerr := eventsFromChainSub.Close(); cerr != nil {
+					log.WithField("ID", id).Error("Closing events from chain sub: ", cerr)
+				}
+				return nil, errors.WithMessage(err, "persisting watched channel")
+			}
+		}
 		statesPubSub = newStatesPubSub()
 		eventsToClientPubSub = newAdjudicatorEventsPubSub()
 		multiLedger := multi.IsMultiLedgerAssets(signedState.State.Assets)
-		return newCh(id, parent, signedState.Params, eventsFromChainSub, eventsToClientPubSub, statesPubSub, multiLedger), nil
+		return newCh(id, parents, signedState.Params, eventsFromChainSub, eventsToClientPubSub, statesPubSub, multiLedger), nil
 	}
 
 	ch, err := w.addIfSucceeds(id, chInitializer1)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	initialTx := channel.Transaction{
 		State: signedState.State,
@@ -263,14 +331,26 @@ func (w *Watcher) startWatching(
 	}
 
 	ch.Go(func() { ch.handleStatesFromClient(initialTx) })
-	ch.Go(func() { ch.handleEventsFromChain(w.rs, w.registry) }) //nolint:contextcheck
+	ch.Go(func() { ch.handleEventsFromChain(w.rs, w.registry, &w.alerts, w.store) }) //nolint:contextcheck
+
+	var statesPub watcher.StatesPub = statesPubSub
+	if w.store != nil {
+		statesPub = &persistingStatesPub{statesPubSub: statesPubSub, store: w.store}
+	}
+	return ch, statesPub, eventsToClientPubSub, nil
+}
 
-	return statesPubSub, eventsToClientPubSub, nil
+func makeWatchedChannel(parents []*ch, signedState channel.SignedState) WatchedChannel {
+	return WatchedChannel{
+		Parents: chIDs(parents),
+		Params:  signedState.Params,
+		Tx:      channel.Transaction{State: signedState.State, Sigs: signedState.Sigs},
+	}
 }
 
 func newCh(
 	id channel.ID,
-	parent *ch,
+	parents []*ch,
 	params *channel.Params,
 	eventsFromChainSub channel.AdjudicatorSubscription,
 	eventsToClientPub adjudicatorPub,
@@ -280,7 +360,7 @@ func newCh(
 	return &ch{
 		id:          id,
 		params:      params,
-		parent:      parent,
+		parents:     parents,
 		multiLedger: multiLedger,
 
 		subChs:              make(map[channel.ID]struct{}),
@@ -368,9 +448,12 @@ func readPendingTxs(statesSub statesSub, timeout time.Duration) (channel.Transac
 // relays it to the client. If received state is not the latest, it disputes by
 // registering the latest state.
 //
+// If the store is not nil, the position of the subscription is persisted after
+// each processed event.
+//
 // It should be started as a go-routine and returns when the subscription for
 // adjudicator events from blockchain is closed.
-func (ch *ch) handleEventsFromChain(registerer channel.Registerer, chRegistry *registry) {
+func (ch *ch) handleEventsFromChain(registerer channel.Registerer, chRegistry *registry, alerts *alerts, store Store) {
 	// Create a context that is canceled when the watcher is stopped.
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -378,21 +461,33 @@ func (ch *ch) handleEventsFromChain(registerer channel.Registerer, chRegistry *r
 		<-ch.done
 		cancel()
 	}()
+	defer ch.cancelTimeoutAlert()
+
+	progresser, _ := registerer.(channel.Progresser)
 
 	for e := ch.eventsFromChainSub.Next(); e != nil; e = ch.eventsFromChainSub.Next() {
 		switch e := e.(type) {
 		case *channel.RegisteredEvent:
-			ch.handleRegisteredEvent(ctx, e, registerer, chRegistry)
+			refuted := ch.handleRegisteredEvent(ctx, e, registerer, chRegistry, alerts)
+			ch.progress(ctx, e, refuted, progresser)
+			ch.alertTimeout(ctx, e, alerts)
 		case *channel.ProgressedEvent:
 			log.Debugf("Received progressed event from chain: %v", e)
 			ch.eventsToClientPub.publish(e)
+			ch.progress(ctx, e, false, progresser)
+			ch.alertTimeout(ctx, e, alerts)
 		case *channel.ConcludedEvent:
 			log.Debugf("Received concluded event from chain: %v", e)
 			ch.eventsToClientPub.publish(e)
+			ch.progress(ctx, e, false, progresser)
+			ch.cancelTimeoutAlert()
+			alerts.notify(watcher.Alert{Kind: watcher.AlertConcluded, Channel: ch.id, Version: e.Version()})
+			ch.handleConcludedEvent(ctx, e, chRegistry)
 		default:
 			// This should never happen.
 			log.Error("Received adjudicator event of unknown type (%T) from chain: %v", e)
 		}
+		ch.persistCursor(ctx, store)
 	}
 	err := ch.eventsFromChainSub.Err()
 	if err != nil {
@@ -400,28 +495,29 @@ func (ch *ch) handleEventsFromChain(registerer channel.Registerer, chRegistry *r
 	}
 }
 
+// handleRegisteredEvent refutes the registered state if a newer state is
+// available and relays the event to the client. It returns whether a newer
+// state was registered.
 func (ch *ch) handleRegisteredEvent(
 	ctx context.Context,
 	e *channel.RegisteredEvent,
 	registerer channel.Registerer,
 	chRegistry *registry,
-) {
-	parent := ch
-	if ch.isSubChannel() {
-		parent = ch.parent
-	}
-
+	alerts *alerts,
+) (refuted bool) {
 	// The following lock ensures that when there are one or more sub-channels and
 	// an adjudicator event is received for each channel, the events are processed
-	// one after the other.
+	// one after the other. The events of a virtual channel are processed one
+	// after the other with the events of both of its parents.
 	//
 	// Even if older states have been registered for more than one channel in the
 	// same family, the channel tree will be registered only once.
-	if !parent.subChsAccess.TryLockCtx(ctx) {
+	family := ch.family()
+	if !tryLockFamily(ctx, family) {
 		// Watching has been stopped. We return.
-		return
+		return false
 	}
-	defer parent.subChsAccess.Unlock()
+	defer unlockFamily(family)
 
 	log := log.WithFields(log.Fields{"ID": e.ID(), "Version": e.Version()})
 	log.Debug("Received registered event from chain")
@@ -429,6 +525,16 @@ func (ch *ch) handleRegisteredEvent(
 	latestTx := ch.txRetriever.retrieve()
 	log.Debugf("Latest version is (%d)", latestTx.Version)
 
+	alert := watcher.Alert{Channel: ch.id, Version: e.Version(), LatestVersion: latestTx.Version}
+	if ownRegistration := ch.ownDispute && e.Version() == ch.registeredVersion; !ownRegistration {
+		alert.Kind = watcher.AlertRegistered
+		alerts.notify(alert)
+		if e.Version() < latestTx.Version {
+			alert.Kind = watcher.AlertOutdatedState
+			alerts.notify(alert)
+		}
+	}
+
 	// A higher version is available and has not been registered previously.
 	higherVersionAvailable := e.Version() < latestTx.Version && e.Version() >= ch.registeredVersion
 	// We have a multi-ledger channel and it has not been registered previously.
@@ -437,14 +543,21 @@ func (ch *ch) handleRegisteredEvent(
 	unregisteredMultiLedger := ch.multiLedger && (!ch.registered || ch.registeredVersion < e.Version())
 	if higherVersionAvailable || unregisteredMultiLedger {
 		log.Debugf("Registering latest version (%d)", latestTx.Version)
-		err := registerDispute(ctx, chRegistry, registerer, parent)
+		err := registerDispute(ctx, chRegistry, registerer, ch.root())
 		if err != nil {
 			log.Error("Error registering dispute: ", err)
-			return
+			alert.Kind, alert.Err = watcher.AlertRefutationFailed, err
+			alerts.notify(alert)
+			return false
 		}
 
 		log.Debug("Registered successfully")
 		ch.registered = true
+		refuted = true
+		if higherVersionAvailable {
+			alert.Kind = watcher.AlertRefuted
+			alerts.notify(alert)
+		}
 	}
 
 	if !ch.published || ch.publishedVersion < e.Version() {
@@ -452,6 +565,7 @@ func (ch *ch) handleRegisteredEvent(
 		ch.published = true
 		ch.publishedVersion = e.Version()
 	}
+	return refuted
 }
 
 // registerDispute collects the latest transaction for the parent channel and
@@ -467,10 +581,12 @@ func registerDispute(ctx context.Context, r *registry, registerer channel.Regist
 	}
 
 	parentCh.registeredVersion = parentTx.Version
+	parentCh.ownDispute = true
 	for i := range subStates {
 		subCh, ok := r.retrieve(parentTx.Allocation.Locked[i].ID)
 		if ok {
 			subCh.registeredVersion = subStates[i].State.Version
+			subCh.ownDispute = true
 		}
 	}
 	return nil
@@ -725,10 +725,14 @@ func makeSignedStateWDummySigs(params *channel.Params, state *channel.State) cha
 	return channel.SignedState{Params: params, State: state}
 }
 
-// randomTxsForSingleCh returns "n" transactions for a random channel.
-func randomTxsForSingleCh(rng *rand.Rand, n int) (*channel.Params, []channel.Transaction) {
-	params, initialState := channeltest.NewRandomParamsAndState(rng, channeltest.WithVersion(0),
-		channeltest.WithNumParts(2), channeltest.WithNumAssets(1), channeltest.WithIsFinal(false), channeltest.WithNumLocked(0))
+// randomTxsForSingleCh returns "n" transactions for a random channel. The
+// options are applied on top of the defaults.
+func randomTxsForSingleCh(rng *rand.Rand, n int, opts ...channeltest.RandomOpt) (*channel.Params, []channel.Transaction) {
+	opts = append([]channeltest.RandomOpt{
+		channeltest.WithVersion(0), channeltest.WithNumParts(2), channeltest.WithNumAssets(1),
+		channeltest.WithIsFinal(false), channeltest.WithNumLocked(0),
+	}, opts...)
+	params, initialState := channeltest.NewRandomParamsAndState(rng, opts...)
 
 	txs := make([]channel.Transaction, n)
 	for i := range txs {
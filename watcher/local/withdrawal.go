@@ -0,0 +1,104 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/watcher"
+)
+
+// withdrawal holds what the watcher needs to withdraw a participant's funds
+// from a concluded ledger channel.
+type withdrawal struct {
+	withdrawer channel.Withdrawer
+	acc        map[wallet.BackendID]wallet.Account
+	idx        channel.Index
+	withdrawn  bool
+}
+
+// EnableWithdrawal enables the automatic withdrawal of the funds of the
+// participant with the given account and index, once the ledger channel is
+// concluded on-chain. The latest states of its sub-channels and virtual
+// channels are included in the withdrawal.
+//
+// The result of the withdrawal is relayed to the client as a
+// watcher.WithdrawnEvent on the channel's AdjudicatorSub.
+func (w *Watcher) EnableWithdrawal(
+	id channel.ID,
+	withdrawer channel.Withdrawer,
+	acc map[wallet.BackendID]wallet.Account,
+	idx channel.Index,
+) error {
+	ch, ok := w.retrieve(id)
+	if !ok {
+		return errors.New("channel not registered with the watcher")
+	}
+	if ch.isSubChannel() {
+		return errors.New("sub-channels and virtual channels are withdrawn with their parent")
+	}
+	if int(idx) >= len(ch.params.Parts) {
+		return errors.Errorf("invalid participant index: %d", idx)
+	}
+
+	ch.subChsAccess.Lock()
+	defer ch.subChsAccess.Unlock()
+	ch.withdrawal = &withdrawal{withdrawer: withdrawer, acc: acc, idx: idx}
+	return nil
+}
+
+// handleConcludedEvent withdraws the funds of the concluded channel, if the
+// withdrawal is enabled, and relays the result to the client.
+func (ch *ch) handleConcludedEvent(ctx context.Context, e *channel.ConcludedEvent, chRegistry *registry) {
+	if !ch.subChsAccess.TryLockCtx(ctx) {
+		// Watching has been stopped. We return.
+		return
+	}
+	defer ch.subChsAccess.Unlock()
+	if ch.withdrawal == nil || ch.withdrawal.withdrawn {
+		return
+	}
+
+	log := log.WithFields(log.Fields{"ID": e.ID(), "Version": e.Version()})
+	tx, subStates := retrieveLatestSubStates(chRegistry, ch)
+	stateMap := channel.MakeStateMap()
+	for _, s := range subStates {
+		if s.State != nil {
+			stateMap.Add(s.State)
+		}
+	}
+	req := channel.AdjudicatorReq{
+		Params: ch.params,
+		Acc:    ch.withdrawal.acc,
+		Tx:     tx,
+		Idx:    ch.withdrawal.idx,
+	}
+
+	log.Debug("Withdrawing concluded channel")
+	err := ch.withdrawal.withdrawer.Withdraw(ctx, req, stateMap)
+	if err != nil {
+		log.Error("Error withdrawing: ", err)
+		err = errors.WithMessage(err, "withdrawing")
+	} else {
+		log.Debug("Withdrawn successfully")
+		ch.withdrawal.withdrawn = true
+	}
+	ch.eventsToClientPub.publish(watcher.NewWithdrawnEvent(ch.id, tx.Version, err))
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	channeltest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/watcher"
+	"perun.network/go-perun/watcher/internal/mocks"
+	"polycry.pt/poly-go/test"
+)
+
+func Test_Watcher_Withdrawal(t *testing.T) {
+	rng := test.Prng(t)
+
+	t.Run("error/enable", func(t *testing.T) {
+		rs := &mocks.RegisterSubscriber{}
+		setExpectationSubscribeCall(rs, newIdleAdjSub(), nil)
+		setExpectationSubscribeCall(rs, newIdleAdjSub(), nil)
+		w := newWatcher(t, rs)
+
+		parentParams, parentTxs := randomTxsForSingleCh(rng, 1)
+		startWatchingForLedgerChannel(t, w, makeSignedStateWDummySigs(parentParams, parentTxs[0].State))
+		subParams, subTxs := randomTxsForSingleCh(rng, 1)
+		startWatchingForSubChannel(t, w, makeSignedStateWDummySigs(subParams, subTxs[0].State), parentTxs[0].ID)
+
+		wd := &fakeWithdrawer{}
+		require.Error(t, w.EnableWithdrawal(channeltest.NewRandomChannelID(rng), wd, nil, 0), "unknown channel")
+		require.Error(t, w.EnableWithdrawal(subTxs[0].ID, wd, nil, 0), "sub-channel")
+		require.Error(t, w.EnableWithdrawal(parentTxs[0].ID, wd, nil, 2), "invalid index")
+		require.NoError(t, w.EnableWithdrawal(parentTxs[0].ID, wd, nil, 1))
+	})
+
+	// The ledger channel is withdrawn with the latest state of its sub-channel
+	// once, even if the concluded event is received again.
+	t.Run("happy/withdraw_with_sub_channel", func(t *testing.T) {
+		parentParams, parentTxs := randomTxsForSingleCh(rng, 2)
+		subParams, subTxs := randomTxsForSingleCh(rng, 2)
+		parentTxs[1].Locked = []channel.SubAlloc{
+			*channel.NewSubAlloc(subTxs[0].ID, []channel.Bal{big.NewInt(1)}, nil),
+		}
+
+		adjSub := &mocks.AdjudicatorSubscription{}
+		trigger := setExpectationNextCall(adjSub, append(makeConcludedEvents(parentTxs[1]),
+			makeConcludedEvents(parentTxs[1])...)...)
+		rs := &mocks.RegisterSubscriber{}
+		setExpectationSubscribeCall(rs, adjSub, nil)
+		setExpectationSubscribeCall(rs, newIdleAdjSub(), nil)
+		w := newWatcher(t, rs)
+
+		statesPub, eventsForClient := startWatchingForLedgerChannel(t, w,
+			makeSignedStateWDummySigs(parentParams, parentTxs[0].State))
+		require.NoError(t, statesPub.Publish(context.Background(), parentTxs[1]))
+		subStatesPub, _ := startWatchingForSubChannel(t, w,
+			makeSignedStateWDummySigs(subParams, subTxs[0].State), parentTxs[0].ID)
+		require.NoError(t, subStatesPub.Publish(context.Background(), subTxs[1]))
+		wd := &fakeWithdrawer{reqs: make(chan withdrawReq, 2)}
+		require.NoError(t, w.EnableWithdrawal(parentTxs[0].ID, wd, nil, 1))
+
+		triggerAdjEventAndExpectNotification(t, trigger, eventsForClient)
+		req := <-wd.reqs
+		assert.Equal(t, channel.Index(1), req.req.Idx)
+		assert.Equal(t, parentTxs[1].Version, req.req.Tx.Version)
+		require.Contains(t, req.subStates, subTxs[1].ID)
+		assert.Equal(t, subTxs[1].Version, req.subStates[subTxs[1].ID].Version)
+		requireWithdrawnEvent(t, eventsForClient, false)
+
+		triggerAdjEventAndExpectNotification(t, trigger, eventsForClient)
+		select {
+		case <-wd.reqs:
+			t.Fatal("unexpected second withdrawal")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("error/withdraw", func(t *testing.T) {
+		params, txs := randomTxsForSingleCh(rng, 1)
+		adjSub := &mocks.AdjudicatorSubscription{}
+		trigger := setExpectationNextCall(adjSub, makeConcludedEvents(txs[0])...)
+		rs := &mocks.RegisterSubscriber{}
+		setExpectationSubscribeCall(rs, adjSub, nil)
+		w := newWatcher(t, rs)
+
+		_, eventsForClient := startWatchingForLedgerChannel(t, w, makeSignedStateWDummySigs(params, txs[0].State))
+		wd := &fakeWithdrawer{reqs: make(chan withdrawReq, 1), err: errors.New("withdrawal failed")}
+		require.NoError(t, w.EnableWithdrawal(txs[0].ID, wd, nil, 0))
+
+		triggerAdjEventAndExpectNotification(t, trigger, eventsForClient)
+		requireWithdrawnEvent(t, eventsForClient, true)
+	})
+}
+
+type (
+	withdrawReq struct {
+		req       channel.AdjudicatorReq
+		subStates channel.StateMap
+	}
+
+	// fakeWithdrawer records the withdrawal requests and fails with err.
+	fakeWithdrawer struct {
+		reqs chan withdrawReq
+		err  error
+	}
+)
+
+func (w *fakeWithdrawer) Withdraw(_ context.Context, req channel.AdjudicatorReq, subStates channel.StateMap) error {
+	w.reqs <- withdrawReq{req: req, subStates: subStates}
+	return w.err
+}
+
+func requireWithdrawnEvent(t *testing.T, eventsSub watcher.AdjudicatorSub, wantErr bool) {
+	t.Helper()
+	select {
+	case e := <-eventsSub.EventStream():
+		withdrawn, ok := e.(*watcher.WithdrawnEvent)
+		require.True(t, ok, "expected withdrawn event, got %T", e)
+		if wantErr {
+			require.Error(t, withdrawn.Err)
+		} else {
+			require.NoError(t, withdrawn.Err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected withdrawn event")
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi
+
+import (
+	"context"
+	stderrors "errors"
+	stdsync "sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/watcher"
+)
+
+var _ watcher.Watcher = (*Watcher)(nil)
+
+// eventsBufferSize is the number of merged events buffered for the client.
+const eventsBufferSize = 10
+
+type (
+	// Watcher is a watcher.Watcher that registers each channel with all of
+	// its underlying watchers, publishes every state to all of them and
+	// merges their adjudicator events.
+	//
+	// An operation succeeds if it succeeds on at least one underlying
+	// watcher. Failures of the underlying watchers are reported by Health.
+	Watcher struct {
+		watchers []watcher.Watcher
+
+		mu     stdsync.Mutex
+		health []Health
+		chs    map[channel.ID]*ch
+	}
+
+	// Health is the health of an underlying watcher.
+	Health struct {
+		Failures    uint64    // Failures is the number of failed operations.
+		LastErr     error     // LastErr is the error of the last operation, if it failed.
+		LastFailure time.Time // LastFailure is the time of the last failure.
+	}
+
+	// ch is a channel that is watched by the underlying watchers. The
+	// pub-subs of watchers that failed to start watching are nil.
+	ch struct {
+		w    *Watcher
+		id   channel.ID
+		pubs []watcher.StatesPub
+		subs []watcher.AdjudicatorSub
+		sub  *adjudicatorSub
+	}
+
+	// adjudicatorSub merges and deduplicates the events of the underlying
+	// subscriptions.
+	adjudicatorSub struct {
+		events chan channel.AdjudicatorEvent
+		done   chan struct{}
+		once   stdsync.Once
+		wg     stdsync.WaitGroup
+
+		mu   stdsync.Mutex
+		seen map[eventKey]struct{}
+		errs []error
+	}
+
+	// eventKey identifies an event independently of the watcher that relayed
+	// it.
+	eventKey struct {
+		kind    string
+		version uint64
+		idx     channel.Index
+	}
+)
+
+// NewWatcher creates a watcher that watches with all the given watchers.
+func NewWatcher(watchers ...watcher.Watcher) (*Watcher, error) {
+	if len(watchers) == 0 {
+		return nil, errors.New("no watchers")
+	}
+	return &Watcher{
+		watchers: watchers,
+		health:   make([]Health, len(watchers)),
+		chs:      make(map[channel.ID]*ch),
+	}, nil
+}
+
+// Health returns the health of the underlying watchers, in the order in which
+// they were passed to NewWatcher.
+func (w *Watcher) Health() []Health {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]Health(nil), w.health...)
+}
+
+// Healthy returns whether the last operation on the watcher succeeded.
+func (h Health) Healthy() bool {
+	return h.LastErr == nil
+}
+
+// StartWatchingLedgerChannel starts watching the ledger channel with all
+// underlying watchers.
+func (w *Watcher) StartWatchingLedgerChannel(ctx context.Context, s channel.SignedState) (
+	watcher.StatesPub, watcher.AdjudicatorSub, error,
+) {
+	return w.startWatching(s.State.ID, func(u watcher.Watcher) (watcher.StatesPub, watcher.AdjudicatorSub, error) {
+		return u.StartWatchingLedgerChannel(ctx, s)
+	})
+}
+
+// StartWatchingSubChannel starts watching the sub-channel with all
+// underlying watchers.
+func (w *Watcher) StartWatchingSubChannel(ctx context.Context, parent channel.ID, s channel.SignedState) (
+	watcher.StatesPub, watcher.AdjudicatorSub, error,
+) {
+	return w.startWatching(s.State.ID, func(u watcher.Watcher) (watcher.StatesPub, watcher.AdjudicatorSub, error) {
+		return u.StartWatchingSubChannel(ctx, parent, s)
+	})
+}
+
+// StartWatchingVirtualChannel starts watching the virtual channel with all
+// underlying watchers.
+func (w *Watcher) StartWatchingVirtualChannel(ctx context.Context, parents []channel.ID, s channel.SignedState) (
+	watcher.StatesPub, watcher.AdjudicatorSub, error,
+) {
+	return w.startWatching(s.State.ID, func(u watcher.Watcher) (watcher.StatesPub, watcher.AdjudicatorSub, error) {
+		return u.StartWatchingVirtualChannel(ctx, parents, s)
+	})
+}
+
+func (w *Watcher) startWatching(
+	id channel.ID,
+	start func(watcher.Watcher) (watcher.StatesPub, watcher.AdjudicatorSub, error),
+) (watcher.StatesPub, watcher.AdjudicatorSub, error) {
+	w.mu.Lock()
+	if _, ok := w.chs[id]; ok {
+		w.mu.Unlock()
+		return nil, nil, errors.New("already watching channel")
+	}
+	c := &ch{
+		w:    w,
+		id:   id,
+		pubs: make([]watcher.StatesPub, len(w.watchers)),
+		subs: make([]watcher.AdjudicatorSub, len(w.watchers)),
+	}
+	w.chs[id] = c
+	w.mu.Unlock()
+
+	err := w.forAll(func(i int, u watcher.Watcher) (err error) {
+		c.pubs[i], c.subs[i], err = start(u)
+		return errors.WithMessagef(err, "starting watcher %d", i)
+	})
+	if err != nil {
+		w.mu.Lock()
+		delete(w.chs, id)
+		w.mu.Unlock()
+		return nil, nil, err
+	}
+	c.sub = newAdjudicatorSub(w, c.subs)
+	return c, c.sub, nil
+}
+
+// StopWatching stops watching the channel with all underlying watchers and
+// closes the merged subscription.
+func (w *Watcher) StopWatching(ctx context.Context, id channel.ID) error {
+	w.mu.Lock()
+	c, ok := w.chs[id]
+	delete(w.chs, id)
+	w.mu.Unlock()
+	if !ok {
+		return errors.New("channel not watched")
+	}
+
+	err := w.forAll(func(i int, u watcher.Watcher) error {
+		if c.subs[i] == nil {
+			return nil
+		}
+		return errors.WithMessagef(u.StopWatching(ctx, id), "stopping watcher %d", i)
+	})
+	c.sub.close()
+	return err
+}
+
+// Publish publishes the transaction to all underlying watchers that watch the
+// channel.
+func (c *ch) Publish(ctx context.Context, tx channel.Transaction) error {
+	return c.w.forAll(func(i int, _ watcher.Watcher) error {
+		if c.pubs[i] == nil {
+			return nil
+		}
+		return errors.WithMessagef(c.pubs[i].Publish(ctx, tx), "publishing to watcher %d", i)
+	})
+}
+
+// forAll calls fn concurrently for all underlying watchers and records their
+// health. It fails only if fn fails for all watchers.
+func (w *Watcher) forAll(fn func(int, watcher.Watcher) error) error {
+	errs := make([]error, len(w.watchers))
+	var wg stdsync.WaitGroup
+	for i, u := range w.watchers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = fn(i, u)
+			w.report(i, errs[i])
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+	return stderrors.Join(errs...)
+}
+
+// report records the result of an operation on the underlying watcher.
+func (w *Watcher) report(i int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	h := &w.health[i]
+	if err == nil {
+		h.LastErr = nil
+		return
+	}
+	h.Failures++
+	h.LastErr = err
+	h.LastFailure = time.Now()
+	log.WithField("watcher", i).Warn(err)
+}
+
+func newAdjudicatorSub(w *Watcher, subs []watcher.AdjudicatorSub) *adjudicatorSub {
+	s := &adjudicatorSub{
+		events: make(chan channel.AdjudicatorEvent, eventsBufferSize),
+		done:   make(chan struct{}),
+		seen:   make(map[eventKey]struct{}),
+	}
+	for i, sub := range subs {
+		if sub == nil {
+			continue
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.forward(sub)
+			if err := sub.Err(); err != nil {
+				w.report(i, errors.WithMessagef(err, "subscription of watcher %d", i))
+				s.mu.Lock()
+				s.errs = append(s.errs, err)
+				s.mu.Unlock()
+			}
+		}()
+	}
+	go func() {
+		s.wg.Wait()
+		close(s.events)
+	}()
+	return s
+}
+
+// forward forwards the events of the underlying subscription that were not
+// forwarded before, until it or the merged subscription is closed.
+func (s *adjudicatorSub) forward(sub watcher.AdjudicatorSub) {
+	for {
+		select {
+		case e, ok := <-sub.EventStream():
+			if !ok {
+				return
+			}
+			if !s.first(e) {
+				continue
+			}
+			select {
+			case s.events <- e:
+			case <-s.done:
+				return
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// first returns whether the event is seen for the first time.
+func (s *adjudicatorSub) first(e channel.AdjudicatorEvent) bool {
+	key := eventKey{version: e.Version()}
+	switch e := e.(type) {
+	case *channel.RegisteredEvent:
+		key.kind = "registered"
+	case *channel.ProgressedEvent:
+		key.kind = "progressed"
+		key.idx = e.Idx
+	case *channel.ConcludedEvent:
+		key.kind = "concluded"
+	default:
+		// Other events, e.g., the automatic withdrawals of the watchers, are
+		// specific to the watcher that relayed them.
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.seen[key]; ok {
+		return false
+	}
+	s.seen[key] = struct{}{}
+	return true
+}
+
+// EventStream returns the merged events. The channel is closed when the
+// channel is no longer watched or all underlying subscriptions are closed.
+func (s *adjudicatorSub) EventStream() <-chan channel.AdjudicatorEvent {
+	return s.events
+}
+
+// Err returns the errors of the underlying subscriptions.
+func (s *adjudicatorSub) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return stderrors.Join(s.errs...)
+}
+
+func (s *adjudicatorSub) close() {
+	s.once.Do(func() { close(s.done) })
+}
@@ -0,0 +1,207 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "perun.network/go-perun/backend/sim" // backend init
+	"perun.network/go-perun/channel"
+	ctest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/watcher"
+	"perun.network/go-perun/watcher/multi"
+	pkgtest "polycry.pt/poly-go/test"
+)
+
+const timeout = 5 * time.Second
+
+func TestWatcher(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	ctx := context.Background()
+	params, state := ctest.NewRandomParamsAndState(rng, ctest.WithNumLocked(0))
+	s := channel.SignedState{Params: params, State: state}
+
+	_, err := multi.NewWatcher()
+	require.Error(t, err)
+
+	t.Run("all_fail", func(t *testing.T) {
+		w, err := multi.NewWatcher(newFakeWatcher(errors.New("a")), newFakeWatcher(errors.New("b")))
+		require.NoError(t, err)
+		_, _, err = w.StartWatchingLedgerChannel(ctx, s)
+		require.Error(t, err)
+		for _, h := range w.Health() {
+			assert.False(t, h.Healthy())
+			assert.Equal(t, uint64(1), h.Failures)
+		}
+		// The channel can be watched again.
+		_, _, err = w.StartWatchingLedgerChannel(ctx, s)
+		require.Error(t, err)
+	})
+
+	t.Run("one_fails", func(t *testing.T) {
+		failing, healthy := newFakeWatcher(errors.New("failing")), newFakeWatcher(nil)
+		w, err := multi.NewWatcher(failing, healthy)
+		require.NoError(t, err)
+
+		pub, sub, err := w.StartWatchingLedgerChannel(ctx, s)
+		require.NoError(t, err)
+		health := w.Health()
+		assert.False(t, health[0].Healthy())
+		assert.True(t, health[1].Healthy())
+
+		tx := channel.Transaction{State: state.Clone()}
+		tx.Version++
+		require.NoError(t, pub.Publish(ctx, tx))
+		assert.Equal(t, []uint64{tx.Version}, healthy.published())
+
+		require.NoError(t, w.StopWatching(ctx, state.ID))
+		requireClosed(t, sub)
+		require.Error(t, w.StopWatching(ctx, state.ID))
+	})
+
+	t.Run("merge_events", func(t *testing.T) {
+		a, b := newFakeWatcher(nil), newFakeWatcher(nil)
+		w, err := multi.NewWatcher(a, b)
+		require.NoError(t, err)
+		_, sub, err := w.StartWatchingLedgerChannel(ctx, s)
+		require.NoError(t, err)
+
+		registered := &channel.RegisteredEvent{
+			AdjudicatorEventBase: channel.AdjudicatorEventBase{IDV: state.ID, VersionV: 1},
+		}
+		progressed := &channel.ProgressedEvent{
+			AdjudicatorEventBase: channel.AdjudicatorEventBase{IDV: state.ID, VersionV: 2}, Idx: 1,
+		}
+		// Duplicates are relayed by both watchers, but forwarded once.
+		a.sub.events <- registered
+		requireEvent(t, sub, registered)
+		b.sub.events <- registered
+		b.sub.events <- progressed
+		requireEvent(t, sub, progressed)
+		a.sub.events <- progressed
+
+		// A failing subscription does not interrupt the others.
+		a.sub.err = errors.New("subscription failed")
+		close(a.sub.events)
+		concluded := &channel.ConcludedEvent{
+			AdjudicatorEventBase: channel.AdjudicatorEventBase{IDV: state.ID, VersionV: 2},
+		}
+		b.sub.events <- concluded
+		requireEvent(t, sub, concluded)
+		require.Eventually(t, func() bool { return sub.Err() != nil }, timeout, 10*time.Millisecond)
+		assert.False(t, w.Health()[0].Healthy())
+
+		require.NoError(t, w.StopWatching(ctx, state.ID))
+		requireClosed(t, sub)
+	})
+}
+
+func requireEvent(t *testing.T, sub watcher.AdjudicatorSub, want channel.AdjudicatorEvent) {
+	t.Helper()
+	select {
+	case e := <-sub.EventStream():
+		assert.Equal(t, want, e)
+	case <-time.After(timeout):
+		t.Fatal("expected event")
+	}
+}
+
+func requireClosed(t *testing.T, sub watcher.AdjudicatorSub) {
+	t.Helper()
+	select {
+	case _, ok := <-sub.EventStream():
+		assert.False(t, ok)
+	case <-time.After(timeout):
+		t.Fatal("expected closed event stream")
+	}
+}
+
+type (
+	// fakeWatcher fails all operations with err, if set.
+	fakeWatcher struct {
+		err error
+		sub *fakeSub
+		pub *fakePub
+	}
+
+	fakePub struct {
+		versions chan uint64
+	}
+
+	fakeSub struct {
+		events chan channel.AdjudicatorEvent
+		err    error
+	}
+)
+
+func newFakeWatcher(err error) *fakeWatcher {
+	return &fakeWatcher{
+		err: err,
+		sub: &fakeSub{events: make(chan channel.AdjudicatorEvent)},
+		pub: &fakePub{versions: make(chan uint64, 10)},
+	}
+}
+
+func (w *fakeWatcher) StartWatchingLedgerChannel(context.Context, channel.SignedState) (
+	watcher.StatesPub, watcher.AdjudicatorSub, error,
+) {
+	if w.err != nil {
+		return nil, nil, w.err
+	}
+	return w.pub, w.sub, nil
+}
+
+func (w *fakeWatcher) StartWatchingSubChannel(ctx context.Context, _ channel.ID, s channel.SignedState) (
+	watcher.StatesPub, watcher.AdjudicatorSub, error,
+) {
+	return w.StartWatchingLedgerChannel(ctx, s)
+}
+
+func (w *fakeWatcher) StartWatchingVirtualChannel(ctx context.Context, _ []channel.ID, s channel.SignedState) (
+	watcher.StatesPub, watcher.AdjudicatorSub, error,
+) {
+	return w.StartWatchingLedgerChannel(ctx, s)
+}
+
+func (w *fakeWatcher) StopWatching(context.Context, channel.ID) error {
+	return w.err
+}
+
+func (w *fakeWatcher) published() []uint64 {
+	var versions []uint64
+	for {
+		select {
+		case v := <-w.pub.versions:
+			versions = append(versions, v)
+		default:
+			return versions
+		}
+	}
+}
+
+func (p *fakePub) Publish(_ context.Context, tx channel.Transaction) error {
+	p.versions <- tx.Version
+	return nil
+}
+
+func (s *fakeSub) EventStream() <-chan channel.AdjudicatorEvent { return s.events }
+
+func (s *fakeSub) Err() error { return s.err }
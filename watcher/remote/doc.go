@@ -0,0 +1,27 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package remote implements a watchtower that watches channels on behalf of
+// clients over the wire protocol, and a watcher.Watcher that forwards to such
+// a tower.
+//
+// The Tower accepts channel registrations and signed transactions from its
+// clients and watches the channels with a local watcher on its own
+// channel.RegisterSubscriber. Adjudicator events are relayed back to the
+// client that registered the channel. Because dispute timeouts cannot be
+// transferred over the wire in general, the tower also notifies the client
+// when the timeout of a relayed event elapsed. The deadline of a time-based
+// timeout is transferred, so that it elapses even if the tower is
+// unreachable.
+package remote // import "perun.network/go-perun/watcher/remote"
@@ -0,0 +1,299 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	stderrors "errors"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/watcher"
+	"perun.network/go-perun/wire"
+	"perun.network/go-perun/wire/perunio"
+)
+
+func init() {
+	wire.RegisterDecoder(wire.WatcherWatch,
+		func(r io.Reader) (wire.Msg, error) {
+			var m WatchMsg
+			return &m, m.Decode(r)
+		})
+	wire.RegisterDecoder(wire.WatcherStop,
+		func(r io.Reader) (wire.Msg, error) {
+			var m StopMsg
+			return &m, m.Decode(r)
+		})
+	wire.RegisterDecoder(wire.WatcherResponse,
+		func(r io.Reader) (wire.Msg, error) {
+			var m ResponseMsg
+			return &m, m.Decode(r)
+		})
+	wire.RegisterDecoder(wire.WatcherPublish,
+		func(r io.Reader) (wire.Msg, error) {
+			var m PublishMsg
+			return &m, m.Decode(r)
+		})
+	wire.RegisterDecoder(wire.WatcherEvent,
+		func(r io.Reader) (wire.Msg, error) {
+			var m EventMsg
+			return &m, m.Decode(r)
+		})
+	wire.RegisterDecoder(wire.WatcherTimeoutElapsed,
+		func(r io.Reader) (wire.Msg, error) {
+			var m TimeoutElapsedMsg
+			return &m, m.Decode(r)
+		})
+}
+
+type (
+	// WatchMsg requests the tower to start watching a channel. It is answered
+	// with a ResponseMsg.
+	WatchMsg struct {
+		Seq     uint64          // Seq identifies the request.
+		Virtual bool            // Virtual is set for virtual channels.
+		Parents []channel.ID    // Parents are the parents of a sub-channel or virtual channel.
+		Params  *channel.Params // Params are the channel parameters.
+		Tx      channel.Transaction
+	}
+
+	// StopMsg requests the tower to stop watching a channel. It is answered
+	// with a ResponseMsg.
+	StopMsg struct {
+		Seq uint64
+		ID  channel.ID
+	}
+
+	// ResponseMsg answers a WatchMsg, StopMsg or PublishMsg. An empty Error
+	// signals success.
+	ResponseMsg struct {
+		Seq   uint64
+		Error string
+	}
+
+	// PublishMsg publishes a newer transaction of a watched channel to the
+	// tower. It is answered with a ResponseMsg.
+	PublishMsg struct {
+		Seq uint64
+		Tx  channel.Transaction
+	}
+
+	// EventMsg relays an adjudicator event from the tower to the client. The
+	// event's timeout is not transferred. Instead, the tower sends a
+	// TimeoutElapsedMsg with the same TimeoutID when it elapsed. If the
+	// timeout is time-based, its deadline is transferred as well, so that the
+	// client does not depend on an unreachable tower.
+	EventMsg struct {
+		TimeoutID uint64
+		Deadline  int64 // Unix time in nanoseconds, 0 if unknown.
+		Event     channel.AdjudicatorEvent
+	}
+
+	// TimeoutElapsedMsg signals that the timeout of a relayed event elapsed.
+	TimeoutElapsedMsg struct {
+		ID        channel.ID
+		TimeoutID uint64
+	}
+
+	// channelIDs encodes a short list of channel IDs.
+	channelIDs struct {
+		IDs *[]channel.ID
+	}
+)
+
+// Event kinds of an EventMsg.
+const (
+	registeredEvent uint8 = iota
+	progressedEvent
+	concludedEvent
+	withdrawnEvent
+)
+
+// Type returns wire.WatcherWatch.
+func (*WatchMsg) Type() wire.Type { return wire.WatcherWatch }
+
+// Encode implements perunio.Encode.
+func (m *WatchMsg) Encode(w io.Writer) error {
+	return perunio.Encode(w, m.Seq, m.Virtual, channelIDs{&m.Parents}, m.Params, m.Tx)
+}
+
+// Decode implements perunio.Decode.
+func (m *WatchMsg) Decode(r io.Reader) error {
+	m.Params = new(channel.Params)
+	return perunio.Decode(r, &m.Seq, &m.Virtual, channelIDs{&m.Parents}, m.Params, &m.Tx)
+}
+
+// Type returns wire.WatcherStop.
+func (*StopMsg) Type() wire.Type { return wire.WatcherStop }
+
+// Encode implements perunio.Encode.
+func (m *StopMsg) Encode(w io.Writer) error {
+	return perunio.Encode(w, m.Seq, m.ID)
+}
+
+// Decode implements perunio.Decode.
+func (m *StopMsg) Decode(r io.Reader) error {
+	return perunio.Decode(r, &m.Seq, &m.ID)
+}
+
+// Type returns wire.WatcherResponse.
+func (*ResponseMsg) Type() wire.Type { return wire.WatcherResponse }
+
+// Encode implements perunio.Encode.
+func (m *ResponseMsg) Encode(w io.Writer) error {
+	return perunio.Encode(w, m.Seq, m.Error)
+}
+
+// Decode implements perunio.Decode.
+func (m *ResponseMsg) Decode(r io.Reader) error {
+	return perunio.Decode(r, &m.Seq, &m.Error)
+}
+
+// Err returns the error of the response, or nil on success.
+func (m *ResponseMsg) Err() error {
+	if m.Error == "" {
+		return nil
+	}
+	return errors.New(m.Error)
+}
+
+// Type returns wire.WatcherPublish.
+func (*PublishMsg) Type() wire.Type { return wire.WatcherPublish }
+
+// Encode implements perunio.Encode.
+func (m *PublishMsg) Encode(w io.Writer) error {
+	return perunio.Encode(w, m.Seq, m.Tx)
+}
+
+// Decode implements perunio.Decode.
+func (m *PublishMsg) Decode(r io.Reader) error {
+	return perunio.Decode(r, &m.Seq, &m.Tx)
+}
+
+// Type returns wire.WatcherEvent.
+func (*EventMsg) Type() wire.Type { return wire.WatcherEvent }
+
+// Encode implements perunio.Encode.
+func (m *EventMsg) Encode(w io.Writer) error {
+	if err := perunio.Encode(w, m.TimeoutID, m.Deadline, m.Event.ID(), m.Event.Version()); err != nil {
+		return err
+	}
+	switch e := m.Event.(type) {
+	case *channel.RegisteredEvent:
+		return perunio.Encode(w, registeredEvent, channel.Transaction{State: e.State, Sigs: e.Sigs})
+	case *channel.ProgressedEvent:
+		return perunio.Encode(w, progressedEvent, e.State, uint16(e.Idx))
+	case *channel.ConcludedEvent:
+		return perunio.Encode(w, concludedEvent)
+	case *watcher.WithdrawnEvent:
+		var errMsg string
+		if e.Err != nil {
+			errMsg = e.Err.Error()
+		}
+		return perunio.Encode(w, withdrawnEvent, errMsg)
+	default:
+		return errors.Errorf("unknown adjudicator event type %T", e)
+	}
+}
+
+// Decode implements perunio.Decode. The timeout of the decoded event is nil,
+// except for a WithdrawnEvent, whose timeout is always elapsed.
+func (m *EventMsg) Decode(r io.Reader) error {
+	var (
+		base channel.AdjudicatorEventBase
+		kind uint8
+	)
+	if err := perunio.Decode(r, &m.TimeoutID, &m.Deadline, &base.IDV, &base.VersionV, &kind); err != nil {
+		return err
+	}
+	switch kind {
+	case registeredEvent:
+		var tx channel.Transaction
+		if err := perunio.Decode(r, &tx); err != nil {
+			return err
+		}
+		m.Event = &channel.RegisteredEvent{AdjudicatorEventBase: base, State: tx.State, Sigs: tx.Sigs}
+	case progressedEvent:
+		var (
+			state = new(channel.State)
+			idx   uint16
+		)
+		if err := perunio.Decode(r, state, &idx); err != nil {
+			return err
+		}
+		m.Event = &channel.ProgressedEvent{AdjudicatorEventBase: base, State: state, Idx: channel.Index(idx)}
+	case concludedEvent:
+		m.Event = &channel.ConcludedEvent{AdjudicatorEventBase: base}
+	case withdrawnEvent:
+		var errMsg string
+		if err := perunio.Decode(r, &errMsg); err != nil {
+			return err
+		}
+		var err error
+		if errMsg != "" {
+			err = stderrors.New(errMsg)
+		}
+		m.Event = watcher.NewWithdrawnEvent(base.IDV, base.VersionV, err)
+	default:
+		return errors.Errorf("unknown adjudicator event kind %d", kind)
+	}
+	return nil
+}
+
+// Type returns wire.WatcherTimeoutElapsed.
+func (*TimeoutElapsedMsg) Type() wire.Type { return wire.WatcherTimeoutElapsed }
+
+// Encode implements perunio.Encode.
+func (m *TimeoutElapsedMsg) Encode(w io.Writer) error {
+	return perunio.Encode(w, m.ID, m.TimeoutID)
+}
+
+// Decode implements perunio.Decode.
+func (m *TimeoutElapsedMsg) Decode(r io.Reader) error {
+	return perunio.Decode(r, &m.ID, &m.TimeoutID)
+}
+
+func (ids channelIDs) Encode(w io.Writer) error {
+	if len(*ids.IDs) > math.MaxUint8 {
+		return errors.Errorf("too many channel IDs: %d", len(*ids.IDs))
+	}
+	if err := perunio.Encode(w, uint8(len(*ids.IDs))); err != nil {
+		return err
+	}
+	for _, id := range *ids.IDs {
+		if err := perunio.Encode(w, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ids channelIDs) Decode(r io.Reader) error {
+	var n uint8
+	if err := perunio.Decode(r, &n); err != nil {
+		return err
+	}
+	*ids.IDs = nil
+	for range n {
+		var id channel.ID
+		if err := perunio.Decode(r, &id); err != nil {
+			return err
+		}
+		*ids.IDs = append(*ids.IDs, id)
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	stdsync "sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/watcher"
+	"perun.network/go-perun/watcher/local"
+	"perun.network/go-perun/wire"
+	"polycry.pt/poly-go/sync"
+)
+
+// responseTimeout bounds sending a response to a client, so that an
+// unreachable client cannot stall the handling of other requests.
+const responseTimeout = 10 * time.Second
+
+type (
+	// Tower is a watchtower that watches channels on behalf of remote clients.
+	// Clients talk to it over the wire protocol, e.g., using a Watcher of this
+	// package.
+	Tower struct {
+		sync.Closer
+		log.Embedding
+
+		addr    map[wallet.BackendID]wire.Address
+		bus     wire.Bus
+		recv    *wire.Receiver
+		watcher watcher.Watcher
+
+		mu        stdsync.Mutex
+		chs       map[channel.ID]*towerCh
+		timeoutID atomic.Uint64
+	}
+
+	// towerCh is a channel that is watched on behalf of a client.
+	towerCh struct {
+		client  map[wallet.BackendID]wire.Address
+		parents []channel.ID
+		subs    int // number of watched sub-channels and virtual channels.
+		pub     watcher.StatesPub
+		sub     watcher.AdjudicatorSub
+	}
+)
+
+// NewTower creates a tower that receives the requests of its clients on the
+// bus at addr and watches the channels on rs. The tower handles requests
+// until it is closed.
+func NewTower(bus wire.Bus, addr map[wallet.BackendID]wire.Address, rs channel.RegisterSubscriber) (*Tower, error) {
+	w, err := local.NewWatcher(rs)
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating local watcher")
+	}
+	t := &Tower{
+		Embedding: log.MakeEmbedding(log.WithField("role", "tower")),
+		addr:      addr,
+		bus:       bus,
+		recv:      wire.NewReceiver(),
+		watcher:   w,
+		chs:       make(map[channel.ID]*towerCh),
+	}
+	if err := bus.SubscribeClient(t.recv, addr); err != nil {
+		return nil, errors.WithMessage(err, "subscribing tower to bus")
+	}
+	t.OnCloseAlways(func() {
+		t.stopAll()
+		if err := t.recv.Close(); err != nil {
+			t.Log().WithError(err).Warn("Closing receiver")
+		}
+	})
+	go t.handle()
+	return t, nil
+}
+
+// handle handles the requests of the clients until the tower is closed.
+func (t *Tower) handle() {
+	for {
+		env, err := t.recv.Next(t.Ctx())
+		if err != nil {
+			return
+		}
+		switch msg := env.Msg.(type) {
+		case *WatchMsg:
+			go t.respond(env.Sender, msg.Seq, t.startWatching(env.Sender, msg))
+		case *StopMsg:
+			go t.respond(env.Sender, msg.Seq, t.stopWatching(env.Sender, msg.ID))
+		case *PublishMsg:
+			err := t.publish(env.Sender, msg.Tx)
+			if err != nil {
+				t.Log().WithError(err).Warn("Discarding published transaction")
+			}
+			go t.respond(env.Sender, msg.Seq, err)
+		default:
+			t.Log().Warnf("Ignoring unexpected message of type %v", env.Msg.Type())
+		}
+	}
+}
+
+func (t *Tower) startWatching(client map[wallet.BackendID]wire.Address, msg *WatchMsg) error {
+	if msg.Tx.State == nil {
+		return errors.New("no state")
+	}
+	id := msg.Tx.ID
+	if id != msg.Params.ID() {
+		return errors.New("state does not belong to params")
+	}
+	if err := checkSigs(msg.Params, msg.Tx); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, p := range msg.Parents {
+		parent, ok := t.chs[p]
+		if !ok || !channel.EqualWireMaps(parent.client, client) {
+			return errors.New("parent channel not watched for this client")
+		}
+	}
+
+	signedState := channel.SignedState{Params: msg.Params, State: msg.Tx.State, Sigs: msg.Tx.Sigs}
+	var (
+		pub watcher.StatesPub
+		sub watcher.AdjudicatorSub
+		err error
+	)
+	switch {
+	case msg.Virtual:
+		pub, sub, err = t.watcher.StartWatchingVirtualChannel(t.Ctx(), msg.Parents, signedState)
+	case len(msg.Parents) == 0:
+		pub, sub, err = t.watcher.StartWatchingLedgerChannel(t.Ctx(), signedState)
+	case len(msg.Parents) == 1:
+		pub, sub, err = t.watcher.StartWatchingSubChannel(t.Ctx(), msg.Parents[0], signedState)
+	default:
+		err = errors.New("sub-channel with multiple parents")
+	}
+	if err != nil {
+		return err
+	}
+
+	ch := &towerCh{client: client, parents: msg.Parents, pub: pub, sub: sub}
+	t.chs[id] = ch
+	for _, p := range msg.Parents {
+		t.chs[p].subs++
+	}
+	go t.relayEvents(id, ch)
+	t.Log().WithField("channel", id).Debug("Started watching")
+	return nil
+}
+
+func (t *Tower) stopWatching(client map[wallet.BackendID]wire.Address, id channel.ID) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ch, ok := t.chs[id]
+	if !ok || !channel.EqualWireMaps(ch.client, client) {
+		return errors.New("channel not watched for this client")
+	}
+	if ch.subs > 0 {
+		return errors.Errorf("cannot stop watching: %d sub-channels watched", ch.subs)
+	}
+	return t.stop(id, ch)
+}
+
+// stop stops watching the channel. The tower must be locked.
+func (t *Tower) stop(id channel.ID, ch *towerCh) error {
+	if err := t.watcher.StopWatching(t.Ctx(), id); err != nil {
+		return err
+	}
+	t.remove(id, ch)
+	t.Log().WithField("channel", id).Debug("Stopped watching")
+	return nil
+}
+
+// remove removes the channel from the watched channels of the tower. The
+// tower must be locked.
+func (t *Tower) remove(id channel.ID, ch *towerCh) {
+	delete(t.chs, id)
+	for _, p := range ch.parents {
+		if parent, ok := t.chs[p]; ok {
+			parent.subs--
+		}
+	}
+}
+
+// stopAll stops watching all channels, sub-channels first. Channels that
+// cannot be stopped are removed anyway, so that their parents are stopped
+// afterwards.
+func (t *Tower) stopAll() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for len(t.chs) > 0 {
+		for id, ch := range t.chs {
+			if ch.subs > 0 {
+				continue
+			}
+			if err := t.stop(id, ch); err != nil {
+				t.Log().WithField("channel", id).WithError(err).Error("Stopping watching")
+				t.remove(id, ch)
+			}
+		}
+	}
+}
+
+func (t *Tower) publish(client map[wallet.BackendID]wire.Address, tx channel.Transaction) error {
+	if tx.State == nil {
+		return errors.New("no state")
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ch, ok := t.chs[tx.ID]
+	if !ok || !channel.EqualWireMaps(ch.client, client) {
+		return errors.New("channel not watched for this client")
+	}
+	return ch.pub.Publish(t.Ctx(), tx)
+}
+
+// relayEvents relays the adjudicator events of the channel to its client
+// until the local watcher closes the subscription. It must not lock the tower,
+// because the local watcher waits for the subscription to be drained when it
+// stops watching.
+func (t *Tower) relayEvents(id channel.ID, ch *towerCh) {
+	for e := range ch.sub.EventStream() {
+		timeoutID := t.timeoutID.Add(1)
+		t.send(ch.client, &EventMsg{TimeoutID: timeoutID, Deadline: timeoutDeadline(e.Timeout()), Event: e})
+		go func(timeout channel.Timeout) {
+			if err := timeout.Wait(t.Ctx()); err != nil {
+				return
+			}
+			t.send(ch.client, &TimeoutElapsedMsg{ID: id, TimeoutID: timeoutID})
+		}(e.Timeout())
+	}
+}
+
+// timeoutDeadline returns the Unix time in nanoseconds at which the timeout
+// elapses, or 0 if the timeout is not time-based.
+func timeoutDeadline(timeout channel.Timeout) int64 {
+	switch timeout := timeout.(type) {
+	case *channel.TimeTimeout:
+		return timeout.UnixNano()
+	case *channel.ElapsedTimeout:
+		return time.Now().UnixNano()
+	default:
+		return 0
+	}
+}
+
+// send sends the message to the client. Sending blocks until the message is
+// delivered or the tower is closed.
+func (t *Tower) send(client map[wallet.BackendID]wire.Address, msg wire.Msg) {
+	t.sendCtx(t.Ctx(), client, msg)
+}
+
+func (t *Tower) sendCtx(ctx context.Context, client map[wallet.BackendID]wire.Address, msg wire.Msg) {
+	env := &wire.Envelope{Sender: t.addr, Recipient: client, Msg: msg}
+	if err := t.bus.Publish(ctx, env); err != nil {
+		t.Log().WithError(err).Warnf("Sending %v to client", msg.Type())
+	}
+}
+
+// respond sends the response to a request to the client. It gives up after
+// responseTimeout.
+func (t *Tower) respond(client map[wallet.BackendID]wire.Address, seq uint64, err error) {
+	resp := &ResponseMsg{Seq: seq}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	ctx, cancel := context.WithTimeout(t.Ctx(), responseTimeout)
+	defer cancel()
+	t.sendCtx(ctx, client, resp)
+}
+
+// checkSigs checks that the transaction is signed by all participants.
+func checkSigs(params *channel.Params, tx channel.Transaction) error {
+	if len(tx.Sigs) != len(params.Parts) {
+		return errors.New("sigs length mismatch")
+	}
+	for i, sig := range tx.Sigs {
+		for _, p := range params.Parts[i] {
+			ok, err := channel.Verify(p, tx.State, sig)
+			if err != nil {
+				return errors.WithMessagef(err, "validating sig %d", i)
+			}
+			if !ok {
+				return errors.Errorf("invalid sig %d", i)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,420 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote
+
+import (
+	"context"
+	stdsync "sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/watcher"
+	"perun.network/go-perun/wire"
+	"polycry.pt/poly-go/sync"
+)
+
+var _ watcher.Watcher = (*Watcher)(nil)
+
+type (
+	// Watcher implements watcher.Watcher by delegating the watching to a
+	// remote Tower.
+	Watcher struct {
+		sync.Closer
+		log.Embedding
+
+		addr  map[wallet.BackendID]wire.Address
+		tower map[wallet.BackendID]wire.Address
+		bus   wire.Bus
+		recv  *wire.Receiver
+
+		mu      stdsync.Mutex
+		seq     uint64
+		pending map[uint64]chan error
+		subs    map[channel.ID]*adjudicatorSub
+	}
+
+	// statesPub publishes the states of a channel to the tower.
+	statesPub struct {
+		w  *Watcher
+		id channel.ID
+	}
+
+	// adjudicatorSub queues the events relayed by the tower, so that a slow
+	// consumer does not block the watcher.
+	adjudicatorSub struct {
+		mu       stdsync.Mutex
+		cond     *stdsync.Cond
+		queue    []channel.AdjudicatorEvent
+		timeouts map[uint64]*timeout
+		closed   bool
+		err      error
+		pipe     chan channel.AdjudicatorEvent
+		done     chan struct{}
+	}
+
+	// timeout is the timeout of a relayed event. It elapses when the tower
+	// sends the corresponding TimeoutElapsedMsg or, if the tower transferred
+	// the deadline of the event's timeout, when the deadline passed. The
+	// latter keeps settling possible while the tower is unreachable.
+	timeout struct {
+		once     stdsync.Once
+		elapsed  chan struct{}
+		deadline time.Time // zero if unknown
+	}
+)
+
+// NewWatcher creates a watcher that delegates to the tower at address tower.
+// It receives the tower's messages on the bus at addr, which must not be used
+// by any other subscriber, e.g., the client.
+func NewWatcher(bus wire.Bus, addr, tower map[wallet.BackendID]wire.Address) (*Watcher, error) {
+	w := &Watcher{
+		Embedding: log.MakeEmbedding(log.WithField("role", "remote watcher")),
+		addr:      addr,
+		tower:     tower,
+		bus:       bus,
+		recv:      wire.NewReceiver(),
+		pending:   make(map[uint64]chan error),
+		subs:      make(map[channel.ID]*adjudicatorSub),
+	}
+	if err := bus.SubscribeClient(w.recv, addr); err != nil {
+		return nil, errors.WithMessage(err, "subscribing watcher to bus")
+	}
+	w.OnCloseAlways(func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		for id, sub := range w.subs {
+			sub.close(errors.New("watcher closed"))
+			delete(w.subs, id)
+		}
+		if err := w.recv.Close(); err != nil {
+			w.Log().WithError(err).Warn("Closing receiver")
+		}
+	})
+	go w.handle()
+	return w, nil
+}
+
+// StartWatchingLedgerChannel requests the tower to start watching the ledger
+// channel.
+func (w *Watcher) StartWatchingLedgerChannel(ctx context.Context, s channel.SignedState) (
+	watcher.StatesPub, watcher.AdjudicatorSub, error,
+) {
+	return w.startWatching(ctx, nil, false, s)
+}
+
+// StartWatchingSubChannel requests the tower to start watching the
+// sub-channel. The parent must be watched by the same tower.
+func (w *Watcher) StartWatchingSubChannel(ctx context.Context, parent channel.ID, s channel.SignedState) (
+	watcher.StatesPub, watcher.AdjudicatorSub, error,
+) {
+	return w.startWatching(ctx, []channel.ID{parent}, false, s)
+}
+
+// StartWatchingVirtualChannel requests the tower to start watching the
+// virtual channel. The parents must be watched by the same tower.
+func (w *Watcher) StartWatchingVirtualChannel(ctx context.Context, parents []channel.ID, s channel.SignedState) (
+	watcher.StatesPub, watcher.AdjudicatorSub, error,
+) {
+	return w.startWatching(ctx, parents, true, s)
+}
+
+func (w *Watcher) startWatching(ctx context.Context, parents []channel.ID, virtual bool, s channel.SignedState) (
+	watcher.StatesPub, watcher.AdjudicatorSub, error,
+) {
+	id := s.State.ID
+	// The subscription is registered before the request is sent, because the
+	// tower may relay events before it responds.
+	sub := newAdjudicatorSub()
+	w.mu.Lock()
+	if w.IsClosed() {
+		w.mu.Unlock()
+		return nil, nil, errors.New("watcher closed")
+	}
+	if _, ok := w.subs[id]; ok {
+		w.mu.Unlock()
+		return nil, nil, errors.New("already watching channel")
+	}
+	w.subs[id] = sub
+	w.mu.Unlock()
+
+	err := w.request(ctx, func(seq uint64) wire.Msg {
+		return &WatchMsg{
+			Seq:     seq,
+			Virtual: virtual,
+			Parents: parents,
+			Params:  s.Params,
+			Tx:      channel.Transaction{State: s.State, Sigs: s.Sigs},
+		}
+	})
+	if err != nil {
+		w.removeSub(id, sub, err)
+		return nil, nil, errors.WithMessage(err, "requesting tower to watch")
+	}
+	return &statesPub{w: w, id: id}, sub, nil
+}
+
+// StopWatching requests the tower to stop watching the channel and closes its
+// subscription.
+func (w *Watcher) StopWatching(ctx context.Context, id channel.ID) error {
+	w.mu.Lock()
+	sub, ok := w.subs[id]
+	w.mu.Unlock()
+	if !ok {
+		return errors.New("channel not watched")
+	}
+
+	if err := w.request(ctx, func(seq uint64) wire.Msg {
+		return &StopMsg{Seq: seq, ID: id}
+	}); err != nil {
+		return errors.WithMessage(err, "requesting tower to stop watching")
+	}
+	w.removeSub(id, sub, nil)
+	return nil
+}
+
+// request sends the request created by newMsg and waits for the tower's
+// response.
+func (w *Watcher) request(ctx context.Context, newMsg func(seq uint64) wire.Msg) error {
+	resp := make(chan error, 1)
+	w.mu.Lock()
+	w.seq++
+	seq := w.seq
+	w.pending[seq] = resp
+	w.mu.Unlock()
+	defer func() {
+		w.mu.Lock()
+		delete(w.pending, seq)
+		w.mu.Unlock()
+	}()
+
+	if err := w.send(ctx, newMsg(seq)); err != nil {
+		return err
+	}
+	select {
+	case err := <-resp:
+		return err
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "waiting for response")
+	case <-w.Closed():
+		return errors.New("watcher closed")
+	}
+}
+
+// send sends the message to the tower if the tower supports the watcher
+// messages.
+func (w *Watcher) send(ctx context.Context, msg wire.Msg) error {
+	if err := wire.RequireFeatures(ctx, w.bus, w.tower, wire.FeatureWatcher); err != nil {
+		return err
+	}
+	env := &wire.Envelope{Sender: w.addr, Recipient: w.tower, Msg: msg}
+	return errors.WithMessage(w.bus.Publish(ctx, env), "sending to tower")
+}
+
+// removeSub removes and closes the subscription, if it is still registered.
+func (w *Watcher) removeSub(id channel.ID, sub *adjudicatorSub, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.subs[id] == sub {
+		delete(w.subs, id)
+	}
+	sub.close(err)
+}
+
+// handle handles the messages of the tower until the watcher is closed.
+func (w *Watcher) handle() {
+	for {
+		env, err := w.recv.Next(w.Ctx())
+		if err != nil {
+			return
+		}
+		if !channel.EqualWireMaps(env.Sender, w.tower) {
+			w.Log().Warn("Ignoring message from unknown sender")
+			continue
+		}
+
+		switch msg := env.Msg.(type) {
+		case *ResponseMsg:
+			// The response channel is buffered and only used once, so
+			// duplicate and late responses are dropped without blocking.
+			w.mu.Lock()
+			if resp, ok := w.pending[msg.Seq]; ok {
+				delete(w.pending, msg.Seq)
+				resp <- msg.Err()
+			}
+			w.mu.Unlock()
+		case *EventMsg:
+			w.mu.Lock()
+			if sub, ok := w.subs[msg.Event.ID()]; ok {
+				sub.publish(msg.Event, msg.TimeoutID, msg.Deadline)
+			}
+			w.mu.Unlock()
+		case *TimeoutElapsedMsg:
+			w.mu.Lock()
+			if sub, ok := w.subs[msg.ID]; ok {
+				sub.elapse(msg.TimeoutID)
+			}
+			w.mu.Unlock()
+		default:
+			w.Log().Warnf("Ignoring unexpected message of type %v", env.Msg.Type())
+		}
+	}
+}
+
+// Publish publishes the transaction to the tower and waits until the tower
+// accepted it. If the tower rejects the transaction, its error is returned.
+func (p *statesPub) Publish(ctx context.Context, tx channel.Transaction) error {
+	if tx.ID != p.id {
+		return errors.New("transaction of another channel")
+	}
+	return errors.WithMessage(p.w.request(ctx, func(seq uint64) wire.Msg {
+		return &PublishMsg{Seq: seq, Tx: tx}
+	}), "publishing to tower")
+}
+
+func newAdjudicatorSub() *adjudicatorSub {
+	s := &adjudicatorSub{
+		timeouts: make(map[uint64]*timeout),
+		pipe:     make(chan channel.AdjudicatorEvent),
+		done:     make(chan struct{}),
+	}
+	s.cond = stdsync.NewCond(&s.mu)
+	go s.run()
+	return s
+}
+
+// EventStream returns the channel of relayed events. It is closed when the
+// subscription is closed.
+func (s *adjudicatorSub) EventStream() <-chan channel.AdjudicatorEvent {
+	return s.pipe
+}
+
+// Err returns the error that caused the subscription to be closed, or nil.
+func (s *adjudicatorSub) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// run forwards the queued events to the event stream until the subscription
+// is closed.
+func (s *adjudicatorSub) run() {
+	defer close(s.pipe)
+	for {
+		s.mu.Lock()
+		for len(s.queue) == 0 && !s.closed {
+			s.cond.Wait()
+		}
+		if s.closed {
+			s.mu.Unlock()
+			return
+		}
+		e := s.queue[0]
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+
+		select {
+		case s.pipe <- e:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// publish queues the event. Its timeout elapses with the given timeout ID or
+// at the deadline, given in Unix nanoseconds, if it is not 0.
+func (s *adjudicatorSub) publish(e channel.AdjudicatorEvent, timeoutID uint64, deadline int64) {
+	t := &timeout{elapsed: make(chan struct{})}
+	if deadline != 0 {
+		t.deadline = time.Unix(0, deadline)
+	}
+	switch e := e.(type) {
+	case *channel.RegisteredEvent:
+		e.TimeoutV = t
+	case *channel.ProgressedEvent:
+		e.TimeoutV = t
+	case *channel.ConcludedEvent:
+		e.TimeoutV = t
+	case *watcher.WithdrawnEvent:
+		// The timeout of a withdrawn event is always elapsed.
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.timeouts[timeoutID] = t
+	s.queue = append(s.queue, e)
+	s.cond.Signal()
+}
+
+// elapse marks the timeout with the given ID as elapsed.
+func (s *adjudicatorSub) elapse(timeoutID uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.timeouts[timeoutID]; ok {
+		t.once.Do(func() { close(t.elapsed) })
+		delete(s.timeouts, timeoutID)
+	}
+}
+
+// close closes the subscription with the given error. Queued events are
+// discarded.
+func (s *adjudicatorSub) close(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.err = err
+	s.queue = nil
+	close(s.done)
+	s.cond.Signal()
+}
+
+// IsElapsed returns whether the tower reported the timeout as elapsed or its
+// deadline passed.
+func (t *timeout) IsElapsed(context.Context) bool {
+	select {
+	case <-t.elapsed:
+		return true
+	default:
+		return !t.deadline.IsZero() && !time.Now().Before(t.deadline)
+	}
+}
+
+// Wait waits until the tower reports the timeout as elapsed, its deadline
+// passed or the context is done.
+func (t *timeout) Wait(ctx context.Context) error {
+	var deadline <-chan time.Time
+	if !t.deadline.IsZero() {
+		timer := time.NewTimer(time.Until(t.deadline))
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	select {
+	case <-t.elapsed:
+		return nil
+	case <-deadline:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
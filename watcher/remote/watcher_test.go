@@ -0,0 +1,465 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remote_test
+
+import (
+	"context"
+	stderrors "errors"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "perun.network/go-perun/backend/sim" // backend init
+	"perun.network/go-perun/channel"
+	ctest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/wallet"
+	wtest "perun.network/go-perun/wallet/test"
+	"perun.network/go-perun/watcher"
+	"perun.network/go-perun/watcher/remote"
+	"perun.network/go-perun/wire"
+	perunio "perun.network/go-perun/wire/perunio/serializer"
+	peruniotest "perun.network/go-perun/wire/perunio/test"
+	"perun.network/go-perun/wire/protobuf"
+	protobuftest "perun.network/go-perun/wire/protobuf/test"
+	wiretest "perun.network/go-perun/wire/test"
+	pkgtest "polycry.pt/poly-go/test"
+)
+
+const timeout = 5 * time.Second
+
+// forEachSerializer runs test once for every envelope serializer the watcher
+// messages can be encoded with.
+func forEachSerializer(t *testing.T, test func(t *testing.T, ser wire.EnvelopeSerializer)) {
+	t.Helper()
+	t.Run("perunio", func(t *testing.T) { test(t, perunio.Serializer()) })
+	t.Run("protobuf", func(t *testing.T) { test(t, protobuf.Serializer()) })
+}
+
+func TestMsgs(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	params, txs := newSignedTxs(rng, 1)
+	parent := ctest.NewRandomChannelID(rng)
+	id := txs[0].ID
+
+	for _, serializerTest := range []func(*testing.T, wire.Msg){
+		peruniotest.MsgSerializerTest, protobuftest.MsgSerializerTest,
+	} {
+		serializerTest(t, &remote.WatchMsg{Seq: 1, Params: params, Tx: txs[0]})
+		serializerTest(t, &remote.WatchMsg{Seq: 2, Parents: []channel.ID{parent}, Params: params, Tx: txs[0]})
+		serializerTest(t, &remote.WatchMsg{
+			Seq: 3, Virtual: true, Parents: []channel.ID{parent, id}, Params: params, Tx: txs[0],
+		})
+		serializerTest(t, &remote.StopMsg{Seq: 3, ID: id})
+		serializerTest(t, &remote.ResponseMsg{Seq: 4})
+		serializerTest(t, &remote.ResponseMsg{Seq: 5, Error: "error"})
+		serializerTest(t, &remote.PublishMsg{Seq: 6, Tx: txs[0]})
+		serializerTest(t, &remote.TimeoutElapsedMsg{ID: id, TimeoutID: 6})
+
+		base := channel.AdjudicatorEventBase{IDV: id, VersionV: txs[0].Version}
+		for _, e := range []channel.AdjudicatorEvent{
+			&channel.RegisteredEvent{AdjudicatorEventBase: base, State: txs[0].State, Sigs: txs[0].Sigs},
+			&channel.ProgressedEvent{AdjudicatorEventBase: base, State: txs[0].State, Idx: 1},
+			&channel.ConcludedEvent{AdjudicatorEventBase: base},
+			watcher.NewWithdrawnEvent(id, txs[0].Version, nil),
+			watcher.NewWithdrawnEvent(id, txs[0].Version, stderrors.New("withdrawal failed")),
+		} {
+			serializerTest(t, &remote.EventMsg{TimeoutID: 7, Event: e})
+		}
+		serializerTest(t, &remote.EventMsg{
+			TimeoutID: 8, Deadline: time.Now().UnixNano(),
+			Event: &channel.ConcludedEvent{AdjudicatorEventBase: base},
+		})
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	forEachSerializer(t, func(t *testing.T, ser wire.EnvelopeSerializer) {
+		rng := pkgtest.Prng(t)
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		rs := newRegisterSubscriber()
+		w := newWatcher(t, rng, ser, rs)
+
+		params, txs := newSignedTxs(rng, 2)
+		signedState := channel.SignedState{Params: params, State: txs[0].State, Sigs: txs[0].Sigs}
+
+		t.Run("invalid_sigs", func(t *testing.T) {
+			invalid := signedState
+			invalid.Sigs = []wallet.Sig{txs[0].Sigs[1], txs[0].Sigs[0]}
+			_, _, err := w.StartWatchingLedgerChannel(ctx, invalid)
+			assert.Error(t, err)
+		})
+
+		pub, sub, err := w.StartWatchingLedgerChannel(ctx, signedState)
+		require.NoError(t, err)
+
+		t.Run("repeated", func(t *testing.T) {
+			_, _, err := w.StartWatchingLedgerChannel(ctx, signedState)
+			assert.Error(t, err)
+		})
+
+		t.Run("unknown_parent", func(t *testing.T) {
+			subParams, subTxs := newSignedTxs(rng, 1)
+			s := channel.SignedState{Params: subParams, State: subTxs[0].State, Sigs: subTxs[0].Sigs}
+			_, _, err := w.StartWatchingSubChannel(ctx, ctest.NewRandomChannelID(rng), s)
+			assert.Error(t, err)
+		})
+
+		t.Run("refute", func(t *testing.T) {
+			require.NoError(t, pub.Publish(ctx, txs[1]))
+
+			// The tower refutes an outdated registration with the latest published
+			// state and relays the event to the client.
+			elapsed := make(chan struct{})
+			rs.events <- &channel.RegisteredEvent{
+				AdjudicatorEventBase: channel.AdjudicatorEventBase{
+					IDV:      txs[0].ID,
+					TimeoutV: &testTimeout{elapsed},
+					VersionV: txs[0].Version,
+				},
+				State: txs[0].State,
+				Sigs:  txs[0].Sigs,
+			}
+
+			select {
+			case version := <-rs.registered:
+				assert.Equal(t, txs[1].Version, version)
+			case <-ctx.Done():
+				t.Fatal("tower did not refute")
+			}
+
+			var e channel.AdjudicatorEvent
+			select {
+			case e = <-sub.EventStream():
+			case <-ctx.Done():
+				t.Fatal("event not relayed")
+			}
+			require.IsType(t, &channel.RegisteredEvent{}, e)
+			assert.Equal(t, txs[0].ID, e.ID())
+			assert.Equal(t, txs[0].Version, e.Version())
+			assert.NoError(t, txs[0].State.Equal(e.(*channel.RegisteredEvent).State))
+
+			// The timeout of the relayed event elapses with the tower's timeout.
+			assert.False(t, e.Timeout().IsElapsed(ctx))
+			close(elapsed)
+			require.NoError(t, e.Timeout().Wait(ctx))
+		})
+
+		t.Run("stop", func(t *testing.T) {
+			require.NoError(t, w.StopWatching(ctx, txs[0].ID))
+			select {
+			case _, ok := <-sub.EventStream():
+				assert.False(t, ok)
+			case <-ctx.Done():
+				t.Fatal("subscription not closed")
+			}
+			assert.NoError(t, sub.Err())
+			assert.Error(t, w.StopWatching(ctx, txs[0].ID))
+		})
+
+		t.Run("publish_rejected", func(t *testing.T) {
+			// The tower rejects states of channels that it does not watch.
+			assert.Error(t, pub.Publish(ctx, txs[1]))
+		})
+	})
+}
+
+func TestTower_UnreachableClient(t *testing.T) {
+	forEachSerializer(t, func(t *testing.T, ser wire.EnvelopeSerializer) {
+		rng := pkgtest.Prng(t)
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		bus, towerAddr := newTower(t, rng, ser, newRegisterSubscriber())
+		w := newWatcherOn(t, rng, bus, towerAddr)
+
+		// The response to a client that never subscribed to the bus cannot be
+		// delivered. It must not stall the requests of other clients.
+		require.NoError(t, bus.Publish(ctx, &wire.Envelope{
+			Sender:    wiretest.NewRandomAddress(rng),
+			Recipient: towerAddr,
+			Msg:       &remote.StopMsg{Seq: 1, ID: ctest.NewRandomChannelID(rng)},
+		}))
+
+		params, txs := newSignedTxs(rng, 1)
+		_, _, err := w.StartWatchingLedgerChannel(ctx,
+			channel.SignedState{Params: params, State: txs[0].State, Sigs: txs[0].Sigs})
+		require.NoError(t, err)
+	})
+}
+
+func TestWatcher_UnreachableTower(t *testing.T) {
+	forEachSerializer(t, func(t *testing.T, ser wire.EnvelopeSerializer) {
+		rng := pkgtest.Prng(t)
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		rs := newRegisterSubscriber()
+		bus := wiretest.NewSerializingLocalBusWith(ser)
+		towerAddr := wiretest.NewRandomAddress(rng)
+		tower, err := remote.NewTower(bus, towerAddr, rs)
+		require.NoError(t, err)
+		w := newWatcherOn(t, rng, bus, towerAddr)
+
+		params, txs := newSignedTxs(rng, 1)
+		_, sub, err := w.StartWatchingLedgerChannel(ctx,
+			channel.SignedState{Params: params, State: txs[0].State, Sigs: txs[0].Sigs})
+		require.NoError(t, err)
+
+		deadline := time.Now().Add(500 * time.Millisecond)
+		rs.events <- &channel.RegisteredEvent{
+			AdjudicatorEventBase: channel.AdjudicatorEventBase{
+				IDV:      txs[0].ID,
+				TimeoutV: &channel.TimeTimeout{Time: deadline},
+				VersionV: txs[0].Version,
+			},
+			State: txs[0].State,
+			Sigs:  txs[0].Sigs,
+		}
+		var e channel.AdjudicatorEvent
+		select {
+		case e = <-sub.EventStream():
+		case <-ctx.Done():
+			t.Fatal("event not relayed")
+		}
+
+		// The timeout of the relayed event still elapses at its deadline
+		// when the tower goes offline before.
+		require.NoError(t, tower.Close())
+		require.NoError(t, e.Timeout().Wait(ctx))
+		assert.False(t, time.Now().Before(deadline))
+		assert.True(t, e.Timeout().IsElapsed(ctx))
+	})
+}
+
+func TestWatcher_DuplicateResponse(t *testing.T) {
+	forEachSerializer(t, func(t *testing.T, ser wire.EnvelopeSerializer) {
+		rng := pkgtest.Prng(t)
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		// The fake tower answers every request three times.
+		bus := wiretest.NewSerializingLocalBusWith(ser)
+		towerAddr := wiretest.NewRandomAddress(rng)
+		recv := wire.NewReceiver()
+		require.NoError(t, bus.SubscribeClient(recv, towerAddr))
+		t.Cleanup(func() { assert.NoError(t, recv.Close()) })
+		go func() {
+			for {
+				env, err := recv.Next(ctx)
+				if err != nil {
+					return
+				}
+				resp := &wire.Envelope{Sender: towerAddr, Recipient: env.Sender, Msg: &remote.ResponseMsg{Seq: env.Msg.(*remote.WatchMsg).Seq}}
+				for range 3 {
+					if err := bus.Publish(ctx, resp); err != nil {
+						return
+					}
+				}
+			}
+		}()
+		w := newWatcherOn(t, rng, bus, towerAddr)
+
+		for range 2 {
+			params, txs := newSignedTxs(rng, 1)
+			_, _, err := w.StartWatchingLedgerChannel(ctx,
+				channel.SignedState{Params: params, State: txs[0].State, Sigs: txs[0].Sigs})
+			require.NoError(t, err)
+		}
+	})
+}
+
+// featureBus is a bus whose peers support none of the optional features.
+type featureBus struct {
+	wire.Bus
+}
+
+func (featureBus) PeerFeatures(context.Context, map[wallet.BackendID]wire.Address) (wire.Features, error) {
+	return 0, nil
+}
+
+func TestWatcher_UnsupportedTower(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	bus := featureBus{wire.NewLocalBus()}
+	w := newWatcherOn(t, rng, bus, wiretest.NewRandomAddress(rng))
+
+	params, txs := newSignedTxs(rng, 1)
+	_, _, err := w.StartWatchingLedgerChannel(ctx,
+		channel.SignedState{Params: params, State: txs[0].State, Sigs: txs[0].Sigs})
+	assert.True(t, wire.IsFeatureNotSupportedError(err))
+}
+
+func TestWatcher_SubChannel(t *testing.T) {
+	forEachSerializer(t, func(t *testing.T, ser wire.EnvelopeSerializer) {
+		rng := pkgtest.Prng(t)
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		rs := newRegisterSubscriber()
+		w := newWatcher(t, rng, ser, rs)
+
+		params, txs := newSignedTxs(rng, 1)
+		_, parentSub, err := w.StartWatchingLedgerChannel(ctx,
+			channel.SignedState{Params: params, State: txs[0].State, Sigs: txs[0].Sigs})
+		require.NoError(t, err)
+
+		subParams, subTxs := newSignedTxs(rng, 1)
+		_, sub, err := w.StartWatchingSubChannel(ctx, txs[0].ID,
+			channel.SignedState{Params: subParams, State: subTxs[0].State, Sigs: subTxs[0].Sigs})
+		require.NoError(t, err)
+
+		// The parent cannot be stopped before its sub-channel.
+		require.Error(t, w.StopWatching(ctx, txs[0].ID))
+		require.NoError(t, w.StopWatching(ctx, subTxs[0].ID))
+		require.NoError(t, w.StopWatching(ctx, txs[0].ID))
+
+		for _, s := range []<-chan channel.AdjudicatorEvent{sub.EventStream(), parentSub.EventStream()} {
+			select {
+			case _, ok := <-s:
+				assert.False(t, ok)
+			case <-ctx.Done():
+				t.Fatal("subscription not closed")
+			}
+		}
+	})
+}
+
+func newWatcher(t *testing.T, rng *rand.Rand, ser wire.EnvelopeSerializer, rs channel.RegisterSubscriber) *remote.Watcher {
+	t.Helper()
+	bus, towerAddr := newTower(t, rng, ser, rs)
+	return newWatcherOn(t, rng, bus, towerAddr)
+}
+
+// newTower creates a tower on a new bus using ser and returns the bus and the
+// tower's address.
+func newTower(t *testing.T, rng *rand.Rand, ser wire.EnvelopeSerializer, rs channel.RegisterSubscriber) (wire.Bus, map[wallet.BackendID]wire.Address) {
+	t.Helper()
+	bus := wiretest.NewSerializingLocalBusWith(ser)
+	towerAddr := wiretest.NewRandomAddress(rng)
+	tower, err := remote.NewTower(bus, towerAddr, rs)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, tower.Close()) })
+	return bus, towerAddr
+}
+
+// newWatcherOn creates a watcher that uses the tower at towerAddr on bus.
+func newWatcherOn(t *testing.T, rng *rand.Rand, bus wire.Bus, towerAddr map[wallet.BackendID]wire.Address) *remote.Watcher {
+	t.Helper()
+	w, err := remote.NewWatcher(bus, wiretest.NewRandomAddress(rng), towerAddr)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, w.Close()) })
+	return w
+}
+
+// newSignedTxs returns n fully signed transactions with increasing versions
+// of a random two-party channel.
+func newSignedTxs(rng *rand.Rand, n int) (*channel.Params, []channel.Transaction) {
+	accs, parts := wtest.NewRandomAccounts(rng, 2, channel.TestBackendID)
+	params, state := ctest.NewRandomParamsAndState(rng, ctest.WithParts(parts), ctest.WithNumLocked(0),
+		ctest.WithVersion(0), ctest.WithIsFinal(false))
+
+	txs := make([]channel.Transaction, n)
+	for i := range txs {
+		s := state.Clone()
+		s.Version = uint64(i)
+		txs[i] = channel.Transaction{State: s, Sigs: make([]wallet.Sig, len(accs))}
+		for j, acc := range accs {
+			sig, err := channel.Sign(acc[channel.TestBackendID], s, channel.TestBackendID)
+			if err != nil {
+				panic(err)
+			}
+			txs[i].Sigs[j] = sig
+		}
+	}
+	return params, txs
+}
+
+type (
+	// registerSubscriber is a RegisterSubscriber whose subscriptions emit the
+	// events sent on events. It reports the versions of registered states on
+	// registered.
+	registerSubscriber struct {
+		events     chan channel.AdjudicatorEvent
+		registered chan uint64
+	}
+
+	subscription struct {
+		events <-chan channel.AdjudicatorEvent
+		once   sync.Once
+		closed chan struct{}
+	}
+
+	testTimeout struct {
+		elapsed chan struct{}
+	}
+)
+
+func newRegisterSubscriber() *registerSubscriber {
+	return &registerSubscriber{
+		events:     make(chan channel.AdjudicatorEvent),
+		registered: make(chan uint64, 1),
+	}
+}
+
+func (rs *registerSubscriber) Register(_ context.Context, req channel.AdjudicatorReq, _ []channel.SignedState) error {
+	rs.registered <- req.Tx.Version
+	return nil
+}
+
+func (rs *registerSubscriber) Subscribe(context.Context, channel.ID) (channel.AdjudicatorSubscription, error) {
+	return &subscription{events: rs.events, closed: make(chan struct{})}, nil
+}
+
+func (s *subscription) Next() channel.AdjudicatorEvent {
+	select {
+	case e := <-s.events:
+		return e
+	case <-s.closed:
+		return nil
+	}
+}
+
+func (s *subscription) Err() error { return nil }
+
+func (s *subscription) Close() error {
+	s.once.Do(func() { close(s.closed) })
+	return nil
+}
+
+func (t *testTimeout) IsElapsed(context.Context) bool {
+	select {
+	case <-t.elapsed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *testTimeout) Wait(ctx context.Context) error {
+	select {
+	case <-t.elapsed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
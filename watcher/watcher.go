@@ -38,10 +38,17 @@ type (
 	// watcher by calling the StopWatching function. This will close the
 	// AdjudicatorSub and StatesSub. The client must not publish states after
 	// this.
+	//
+	// A virtual channel is funded by two ledger channels, one between each
+	// participant and the hub. It is registered with the parents that are
+	// watched by this watcher: the participant's ledger channel with the hub
+	// or, for the hub, both ledger channels.
 	Watcher interface {
 		StartWatchingLedgerChannel(context.Context, channel.SignedState) (StatesPub, AdjudicatorSub, error)
 		StartWatchingSubChannel(_ context.Context, parent channel.ID, _ channel.SignedState) (
 			StatesPub, AdjudicatorSub, error)
+		StartWatchingVirtualChannel(_ context.Context, parents []channel.ID, _ channel.SignedState) (
+			StatesPub, AdjudicatorSub, error)
 		StopWatching(context.Context, channel.ID) error
 	}
 
@@ -76,4 +83,43 @@ type (
 		EventStream() <-chan channel.AdjudicatorEvent
 		Err() error
 	}
+
+	// ProgressionStrategy decides the moves of a participant during the
+	// progression phase of a dispute of an app channel.
+	//
+	// The watcher asks the strategy for a move when the refutation phase of a
+	// registered state has elapsed and after each on-chain progression. The
+	// strategy must return a nil request if it is not the participant's turn or
+	// if it does not want to move. Otherwise, it completes the given request,
+	// which refers to the state currently registered on-chain, with the new
+	// state, the participant's account, index and signature.
+	//
+	// Progressed is called with each ProgressedEvent of the channel before the
+	// strategy is asked for the next move, including the events caused by the
+	// strategy's own moves.
+	ProgressionStrategy interface {
+		Move(context.Context, channel.AdjudicatorReq) (*channel.ProgressReq, error)
+		Progressed(*channel.ProgressedEvent)
+	}
 )
+
+// WithdrawnEvent is relayed on the AdjudicatorSub of a ledger channel after the
+// watcher automatically withdrew the funds of a concluded channel. Err is the
+// error of the withdrawal, or nil if it succeeded. The timeout is always
+// elapsed.
+type WithdrawnEvent struct {
+	channel.AdjudicatorEventBase
+	Err error
+}
+
+// NewWithdrawnEvent creates a new WithdrawnEvent for the given version.
+func NewWithdrawnEvent(id channel.ID, version uint64, err error) *WithdrawnEvent {
+	return &WithdrawnEvent{
+		AdjudicatorEventBase: channel.AdjudicatorEventBase{
+			IDV:      id,
+			TimeoutV: &channel.ElapsedTimeout{},
+			VersionV: version,
+		},
+		Err: err,
+	}
+}
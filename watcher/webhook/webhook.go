@@ -0,0 +1,148 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/watcher"
+	"polycry.pt/poly-go/sync"
+)
+
+var _ watcher.Notifier = (*Webhook)(nil)
+
+const (
+	// QueueSize is the number of alerts that are queued for posting. Further
+	// alerts are dropped.
+	QueueSize = 64
+
+	// DefaultTimeout is the timeout of a post if no HTTP client is given.
+	DefaultTimeout = 10 * time.Second
+)
+
+type (
+	// Webhook posts each alert as a JSON encoded Payload to a URL. The alerts
+	// are posted one after the other in the background, so that Notify does
+	// not block the watcher.
+	Webhook struct {
+		sync.Closer
+		log.Embedding
+
+		url    string
+		client *http.Client
+		queue  chan watcher.Alert
+	}
+
+	// Payload is the JSON body of a posted alert.
+	Payload struct {
+		Kind          string     `json:"kind"`
+		Channel       string     `json:"channel"` // Channel is the hex encoded channel ID.
+		Version       uint64     `json:"version"`
+		LatestVersion uint64     `json:"latestVersion"`
+		Timeout       *time.Time `json:"timeout,omitempty"`
+		Error         string     `json:"error,omitempty"`
+		Time          time.Time  `json:"time"`
+	}
+)
+
+// NewWebhook creates a webhook that posts to url with client. If client is
+// nil, a client with DefaultTimeout is used. The webhook posts alerts until it
+// is closed.
+func NewWebhook(url string, client *http.Client) *Webhook {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	h := &Webhook{
+		Embedding: log.MakeEmbedding(log.WithField("role", "webhook")),
+		url:       url,
+		client:    client,
+		queue:     make(chan watcher.Alert, QueueSize),
+	}
+	go h.run()
+	return h
+}
+
+// Notify queues the alert for posting. The alert is dropped if the queue is
+// full or the webhook is closed.
+func (h *Webhook) Notify(a watcher.Alert) {
+	if h.IsClosed() {
+		return
+	}
+	select {
+	case h.queue <- a:
+	default:
+		h.Log().Warnf("Dropping %v alert: queue full", a.Kind)
+	}
+}
+
+func (h *Webhook) run() {
+	for {
+		select {
+		case a := <-h.queue:
+			if err := h.post(h.Ctx(), a); err != nil {
+				h.Log().WithError(err).Warnf("Posting %v alert", a.Kind)
+			}
+		case <-h.Closed():
+			return
+		}
+	}
+}
+
+func (h *Webhook) post(ctx context.Context, a watcher.Alert) error {
+	body, err := json.Marshal(NewPayload(a))
+	if err != nil {
+		return errors.Wrap(err, "encoding payload")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "posting")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("unexpected status: %s", resp.Status)
+	}
+	return nil
+}
+
+// NewPayload creates the payload of the alert.
+func NewPayload(a watcher.Alert) Payload {
+	p := Payload{
+		Kind:          a.Kind.String(),
+		Channel:       hex.EncodeToString(a.Channel[:]),
+		Version:       a.Version,
+		LatestVersion: a.LatestVersion,
+		Time:          a.Time,
+	}
+	if !a.Timeout.IsZero() {
+		p.Timeout = &a.Timeout
+	}
+	if a.Err != nil {
+		p.Error = a.Err.Error()
+	}
+	return p
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ctest "perun.network/go-perun/channel/test"
+	"perun.network/go-perun/watcher"
+	"perun.network/go-perun/watcher/webhook"
+	pkgtest "polycry.pt/poly-go/test"
+)
+
+func TestWebhook(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	payloads := make(chan webhook.Payload, 2)
+	status := http.StatusInternalServerError
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var p webhook.Payload
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&p))
+		payloads <- p
+		w.WriteHeader(status)
+		status = http.StatusOK
+	}))
+	defer srv.Close()
+
+	h := webhook.NewWebhook(srv.URL, nil)
+	id := ctest.NewRandomChannelID(rng)
+	now := time.Now().UTC().Truncate(time.Second)
+	alerts := []watcher.Alert{
+		// A failing post does not stop the webhook.
+		{Kind: watcher.AlertRefutationFailed, Channel: id, Version: 1, LatestVersion: 2, Err: errors.New("fail"), Time: now},
+		{Kind: watcher.AlertTimeoutNearing, Channel: id, Version: 2, Timeout: now.Add(time.Minute), Time: now},
+	}
+	for _, a := range alerts {
+		h.Notify(a)
+	}
+
+	for _, a := range alerts {
+		select {
+		case p := <-payloads:
+			assert.Equal(t, webhook.NewPayload(a).Kind, p.Kind)
+			assert.Equal(t, hex.EncodeToString(id[:]), p.Channel)
+			assert.Equal(t, a.Version, p.Version)
+			assert.Equal(t, a.LatestVersion, p.LatestVersion)
+			assert.True(t, a.Time.Equal(p.Time))
+			if a.Err != nil {
+				assert.Equal(t, a.Err.Error(), p.Error)
+			}
+			if a.Timeout.IsZero() {
+				assert.Nil(t, p.Timeout)
+			} else {
+				require.NotNil(t, p.Timeout)
+				assert.True(t, a.Timeout.Equal(*p.Timeout))
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected post")
+		}
+	}
+
+	require.NoError(t, h.Close())
+	h.Notify(alerts[0])
+	select {
+	case <-payloads:
+		t.Fatal("unexpected post after close")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"math"
 
 	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire/perunio"
 )
 
 func init() {
@@ -79,6 +80,9 @@ func (m *AuthResponseMsg) Decode(r io.Reader) (err error) {
 	if err != nil {
 		return fmt.Errorf("failed to read signature length: %w", err)
 	}
+	if err := perunio.CheckSize(r, "signature", int(signatureLen)); err != nil {
+		return err
+	}
 
 	// Read the signature bytes
 	m.Signature = make([]byte, signatureLen)
@@ -113,6 +113,12 @@ func (a *AddressDecMap) Decode(r stdio.Reader) (err error) {
 	if err := perunio.Decode(r, &mapLen); err != nil {
 		return errors.WithMessage(err, "decoding map length")
 	}
+	if mapLen < 0 {
+		return errors.Errorf("invalid map length %d", mapLen)
+	}
+	if err := perunio.CheckSize(r, "address map", int(mapLen)); err != nil {
+		return err
+	}
 	*a = make(map[wallet.BackendID]Address, mapLen)
 	for i := range mapLen {
 		var idx int32
@@ -135,6 +141,12 @@ func (a *AddressMapArray) Decode(r stdio.Reader) (err error) {
 	if err := perunio.Decode(r, &mapLen); err != nil {
 		return errors.WithMessage(err, "decoding array length")
 	}
+	if mapLen < 0 {
+		return errors.Errorf("invalid array length %d", mapLen)
+	}
+	if err := perunio.CheckSize(r, "address map array", int(mapLen)); err != nil {
+		return err
+	}
 	*a = make([]map[wallet.BackendID]Address, mapLen)
 	for i := range mapLen {
 		if err := perunio.Decode(r, (*AddressDecMap)(&(*a)[i])); err != nil {
@@ -14,7 +14,11 @@
 
 package wire
 
-import "perun.network/go-perun/wallet"
+import (
+	"context"
+
+	"perun.network/go-perun/wallet"
+)
 
 // A Bus is a central message bus over which all clients of a channel network
 // communicate. It is used as the transport layer abstraction for the
@@ -26,3 +30,25 @@ type Bus interface {
 	// the provided Consumer. Every address may only be subscribed to once.
 	SubscribeClient(c Consumer, clientAddr map[wallet.BackendID]Address) error
 }
+
+// A Drainer is a Bus that can be shut down gracefully and gives its users the
+// chance to respond to pending requests before a connection is closed.
+type Drainer interface {
+	// OnDrain adds a function that is called with the peer's address before
+	// the connection to the peer is shut down gracefully. It should return
+	// once the responses to all requests received from the peer are
+	// published, or when the context is done.
+	OnDrain(drain func(ctx context.Context, peer map[wallet.BackendID]Address))
+	// IsShuttingDown returns whether the connection to the peer is being shut
+	// down gracefully. New requests must not be sent to or accepted from such
+	// a peer, only responses.
+	IsShuttingDown(peer map[wallet.BackendID]Address) bool
+}
+
+// An EvictionNotifier is a Bus that closes the connections of unresponsive
+// peers.
+type EvictionNotifier interface {
+	// OnPeerEvicted adds a callback that is called with the address of each
+	// peer whose connection was closed because it was unresponsive.
+	OnPeerEvicted(handler func(map[wallet.BackendID]Address))
+}
@@ -25,6 +25,8 @@ func init() {
 	RegisterDecoder(Ping, func(r io.Reader) (Msg, error) { var m PingMsg; return &m, m.Decode(r) })
 	RegisterDecoder(Pong, func(r io.Reader) (Msg, error) { var m PongMsg; return &m, m.Decode(r) })
 	RegisterDecoder(Shutdown, func(r io.Reader) (Msg, error) { var m ShutdownMsg; return &m, m.Decode(r) })
+	RegisterDecoder(Reliable, func(r io.Reader) (Msg, error) { var m ReliableMsg; return &m, m.Decode(r) })
+	RegisterDecoder(Ack, func(r io.Reader) (Msg, error) { var m AckMsg; return &m, m.Decode(r) })
 }
 
 // Since ping and pong messages are essentially the same, this is a common
@@ -110,3 +112,53 @@ func (m *ShutdownMsg) Decode(r io.Reader) error {
 func (m *ShutdownMsg) Type() Type {
 	return Shutdown
 }
+
+// ReliableMsg wraps a message that is delivered at least once. The recipient
+// acknowledges it with an AckMsg carrying the same ID and uses the ID to
+// discard retransmitted duplicates.
+type ReliableMsg struct {
+	ID  uint64
+	Msg Msg
+}
+
+// Encode implements msg.Encode.
+func (m *ReliableMsg) Encode(w io.Writer) error {
+	if err := perunio.Encode(w, m.ID); err != nil {
+		return err
+	}
+	return EncodeMsg(m.Msg, w)
+}
+
+// Decode implements msg.Decode.
+func (m *ReliableMsg) Decode(r io.Reader) (err error) {
+	if err = perunio.Decode(r, &m.ID); err != nil {
+		return err
+	}
+	m.Msg, err = DecodeMsg(r)
+	return err
+}
+
+// Type implements msg.Type.
+func (m *ReliableMsg) Type() Type {
+	return Reliable
+}
+
+// AckMsg acknowledges the receipt of the ReliableMsg with the same ID.
+type AckMsg struct {
+	ID uint64
+}
+
+// Encode implements msg.Encode.
+func (m *AckMsg) Encode(w io.Writer) error {
+	return perunio.Encode(w, m.ID)
+}
+
+// Decode implements msg.Decode.
+func (m *AckMsg) Decode(r io.Reader) error {
+	return perunio.Decode(r, &m.ID)
+}
+
+// Type implements msg.Type.
+func (m *AckMsg) Type() Type {
+	return Ack
+}
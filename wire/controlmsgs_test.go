@@ -17,6 +17,7 @@ package wire_test
 import (
 	"testing"
 
+	"perun.network/go-perun/wire"
 	peruniotest "perun.network/go-perun/wire/perunio/test"
 	wiretest "perun.network/go-perun/wire/test"
 )
@@ -24,3 +25,8 @@ import (
 func TestControlMsgs(t *testing.T) {
 	wiretest.ControlMsgsSerializationTest(t, peruniotest.MsgSerializerTest)
 }
+
+func TestDeliveryMsgs(t *testing.T) {
+	peruniotest.MsgSerializerTest(t, &wire.ReliableMsg{ID: 42, Msg: wire.NewPingMsg()})
+	peruniotest.MsgSerializerTest(t, &wire.AckMsg{ID: 42})
+}
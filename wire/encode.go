@@ -119,6 +119,16 @@ const (
 	ChannelUpdateAcc
 	ChannelUpdateRej
 	ChannelSync
+	WatcherWatch
+	WatcherStop
+	WatcherResponse
+	WatcherPublish
+	WatcherEvent
+	WatcherTimeoutElapsed
+	WatcherBlindedUpload
+	Reliable
+	Ack
+	Hello
 	LastType // upper bound on the message types of the Perun wire protocol
 )
 
@@ -140,6 +150,16 @@ var typeNames = map[Type]string{
 	ChannelUpdateAcc:                 "ChannelUpdateAcc",
 	ChannelUpdateRej:                 "ChannelUpdateRej",
 	ChannelSync:                      "ChannelSync",
+	WatcherWatch:                     "WatcherWatch",
+	WatcherStop:                      "WatcherStop",
+	WatcherResponse:                  "WatcherResponse",
+	WatcherPublish:                   "WatcherPublish",
+	WatcherEvent:                     "WatcherEvent",
+	WatcherTimeoutElapsed:            "WatcherTimeoutElapsed",
+	WatcherBlindedUpload:             "WatcherBlindedUpload",
+	Reliable:                         "Reliable",
+	Ack:                              "Ack",
+	Hello:                            "Hello",
 }
 
 // String returns the name of a message type if it is valid and name known
@@ -152,6 +172,18 @@ func (t Type) String() string {
 	return name
 }
 
+// IsRequest returns whether the type is a request that opens a new exchange
+// with the peer, i.e., a channel proposal or a channel update.
+func (t Type) IsRequest() bool {
+	switch t {
+	case LedgerChannelProposal, SubChannelProposal, VirtualChannelProposal,
+		ChannelUpdate, VirtualChannelFundingProposal, VirtualChannelSettlementProposal:
+		return true
+	default:
+		return false
+	}
+}
+
 // Valid checks whether a decoder is known for the type.
 func (t Type) Valid() bool {
 	_, ok := decoders[t]
@@ -0,0 +1,165 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire/perunio"
+)
+
+func init() {
+	RegisterDecoder(Hello, func(r io.Reader) (Msg, error) { var m HelloMsg; return &m, m.Decode(r) })
+}
+
+const (
+	// ProtocolVersion is the version of the Perun wire protocol implemented
+	// by this package.
+	ProtocolVersion uint16 = 1
+	// MinProtocolVersion is the oldest protocol version that a peer may
+	// announce in its HelloMsg.
+	MinProtocolVersion uint16 = 1
+	// LegacyProtocolVersion is the protocol version of peers that predate the
+	// protocol negotiation and do not send a HelloMsg. They support none of
+	// the optional features.
+	LegacyProtocolVersion uint16 = 0
+)
+
+// Features is a set of optional protocol features that a node supports.
+type Features uint64
+
+// Enumeration of the optional protocol features.
+const (
+	// FeatureSubChannels is the support of sub-channel proposals.
+	FeatureSubChannels Features = 1 << iota
+	// FeatureVirtualChannels is the support of virtual channel proposals and
+	// their funding and settlement proposals.
+	FeatureVirtualChannels
+	// FeatureChannelSync is the support of channel synchronization messages.
+	FeatureChannelSync
+	// FeatureWatcher is the support of the remote watcher messages.
+	FeatureWatcher
+	// FeatureReliableDelivery is the support of acknowledged messages, see
+	// ReliableMsg.
+	FeatureReliableDelivery
+)
+
+// SupportedFeatures are all features that are supported by this package.
+const SupportedFeatures = FeatureSubChannels | FeatureVirtualChannels |
+	FeatureChannelSync | FeatureWatcher | FeatureReliableDelivery
+
+var featureNames = []string{
+	"SubChannels",
+	"VirtualChannels",
+	"ChannelSync",
+	"Watcher",
+	"ReliableDelivery",
+}
+
+// Has returns whether all of the given features are contained in f.
+func (f Features) Has(features Features) bool {
+	return f&features == features
+}
+
+// String returns the names of the features, separated by "|".
+func (f Features) String() string {
+	if f == 0 {
+		return "none"
+	}
+	var names []string
+	for f != 0 {
+		i := bits.TrailingZeros64(uint64(f))
+		if i < len(featureNames) {
+			names = append(names, featureNames[i])
+		} else {
+			names = append(names, fmt.Sprintf("Feature%d", i))
+		}
+		f &^= 1 << i
+	}
+	return strings.Join(names, "|")
+}
+
+// A FeatureProvider is a Bus that knows the features that its peers support.
+type FeatureProvider interface {
+	// PeerFeatures returns the features that were negotiated with the peer,
+	// connecting to the peer if necessary.
+	PeerFeatures(ctx context.Context, peer map[wallet.BackendID]Address) (Features, error)
+}
+
+// RequireFeatures returns a FeatureNotSupportedError if the peer does not
+// support all of the required features. The check is skipped if the bus is not
+// a FeatureProvider.
+func RequireFeatures(ctx context.Context, bus Bus, peer map[wallet.BackendID]Address, required Features) error {
+	fp, ok := bus.(FeatureProvider)
+	if !ok || required == 0 {
+		return nil
+	}
+	features, err := fp.PeerFeatures(ctx, peer)
+	if err != nil {
+		return errors.WithMessage(err, "querying peer features")
+	}
+	if !features.Has(required) {
+		return &FeatureNotSupportedError{Peer: peer, Missing: required &^ features}
+	}
+	return nil
+}
+
+// FeatureNotSupportedError describes an error which occurs when an operation
+// requires features that a peer does not support.
+type FeatureNotSupportedError struct {
+	Peer    map[wallet.BackendID]Address
+	Missing Features
+}
+
+func (e *FeatureNotSupportedError) Error() string {
+	return fmt.Sprintf("peer %v does not support feature %v", e.Peer, e.Missing)
+}
+
+// IsFeatureNotSupportedError returns true if the error was a
+// FeatureNotSupportedError.
+func IsFeatureNotSupportedError(err error) bool {
+	var ferr *FeatureNotSupportedError
+	return stderrors.As(err, &ferr)
+}
+
+// HelloMsg is exchanged after the address authentication of a new connection
+// to negotiate the protocol version and features.
+type HelloMsg struct {
+	Version  uint16
+	Features Features
+}
+
+// Encode implements msg.Encode.
+func (m *HelloMsg) Encode(w io.Writer) error {
+	return perunio.Encode(w, m.Version, uint64(m.Features))
+}
+
+// Decode implements msg.Decode.
+func (m *HelloMsg) Decode(r io.Reader) error {
+	return perunio.Decode(r, &m.Version, (*uint64)(&m.Features))
+}
+
+// Type implements msg.Type.
+func (m *HelloMsg) Type() Type {
+	return Hello
+}
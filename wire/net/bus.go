@@ -33,6 +33,12 @@ type Bus struct {
 	mainRecv *wire.Receiver
 	recvs    map[wire.AddrKey]wire.Consumer
 	mutex    sync.RWMutex // Protects reg, recv.
+
+	reconnect  ReconnectConfig                 // Settings of the reliable mode.
+	queues     map[wire.AddrKey]*outboundQueue // Outbound queues, nil if not in reliable mode.
+	queueMutex sync.Mutex                      // Protects reconnect, queues and their users.
+
+	delivery delivery // State of the at-least-once delivery.
 }
 
 const (
@@ -50,6 +56,7 @@ func NewBus(id map[wallet.BackendID]wire.Account, d Dialer, s wire.EnvelopeSeria
 	b := &Bus{
 		mainRecv: wire.NewReceiver(),
 		recvs:    make(map[wire.AddrKey]wire.Consumer),
+		delivery: newDelivery(),
 	}
 
 	onNewEndpoint := func(map[wallet.BackendID]wire.Address) wire.Consumer { return b.mainRecv }
@@ -65,6 +72,51 @@ func (b *Bus) Listen(l Listener) {
 	b.reg.Listen(l)
 }
 
+// SetKeepalive configures the keepalive pings of the bus' connections. This
+// method is expected to be called once during the setup of the bus.
+func (b *Bus) SetKeepalive(cfg KeepaliveConfig) {
+	b.reg.SetKeepalive(cfg)
+}
+
+// OnPeerEvicted adds a callback that is called with the address of each peer
+// whose connection is closed because it did not respond to keepalive pings.
+// The peer is dialed again by the next Publish call, so the callback may be
+// used, e.g., to synchronize channels with the peer after reconnecting. A
+// client.Client adds a callback that aborts the pending updates of the
+// channels with the peer.
+func (b *Bus) OnPeerEvicted(handler func(map[wallet.BackendID]wire.Address)) {
+	b.reg.OnEndpointEvicted(handler)
+}
+
+// OnDrain adds a function that is called before a connection is shut down
+// gracefully, see EndpointRegistry.OnDrain. A client.Client adds a function
+// that waits for its running request handlers of the peer.
+func (b *Bus) OnDrain(drain func(context.Context, map[wallet.BackendID]wire.Address)) {
+	b.reg.OnDrain(drain)
+}
+
+// IsShuttingDown returns whether the connection to the peer is being shut
+// down gracefully, see EndpointRegistry.IsShuttingDown.
+func (b *Bus) IsShuttingDown(peer map[wallet.BackendID]wire.Address) bool {
+	return b.reg.IsShuttingDown(peer)
+}
+
+// SetFeatures sets the protocol features that are offered to peers of new
+// connections, see EndpointRegistry.SetFeatures.
+func (b *Bus) SetFeatures(features wire.Features) {
+	b.reg.SetFeatures(features)
+}
+
+// PeerFeatures returns the protocol features that were negotiated with the
+// peer. Establishes a connection to the peer if there is none yet.
+func (b *Bus) PeerFeatures(ctx context.Context, peer map[wallet.BackendID]wire.Address) (wire.Features, error) {
+	ep, err := b.reg.Endpoint(ctx, peer)
+	if err != nil {
+		return 0, errors.WithMessage(err, "connecting to peer")
+	}
+	return ep.Features(), nil
+}
+
 // SubscribeClient subscribes a new client to the bus. Duplicate subscriptions
 // are forbidden and will cause a panic. The supplied consumer will receive all
 // messages that are sent to the requested address.
@@ -76,8 +128,27 @@ func (b *Bus) SubscribeClient(c wire.Consumer, addr map[wallet.BackendID]wire.Ad
 
 // Publish sends an envelope to its recipient. Automatically establishes a
 // communication channel to the recipient using the bus' dialer. Only returns
-// when the context is aborted or the envelope was sent successfully.
-func (b *Bus) Publish(ctx context.Context, e *wire.Envelope) (err error) {
+// when the context is aborted or the envelope was sent successfully. In
+// reliable mode, the envelope may be queued instead, see EnableReconnect. If
+// acknowledgements are enabled, it only returns once the envelope was
+// acknowledged, see EnableAcks. New requests to a peer whose connection is
+// being shut down are refused with ErrShuttingDown.
+func (b *Bus) Publish(ctx context.Context, e *wire.Envelope) error {
+	if e.Msg.Type().IsRequest() && b.reg.IsShuttingDown(e.Recipient) {
+		return errors.WithMessagef(ErrShuttingDown, "publishing %T", e.Msg)
+	}
+	if cfg, ok := b.delivery.config(); ok {
+		return b.publishAcked(ctx, e, cfg)
+	}
+	return b.publish(ctx, e)
+}
+
+// publish implements Publish without acknowledgements.
+func (b *Bus) publish(ctx context.Context, e *wire.Envelope) (err error) {
+	if cfg, ok := b.reliable(); ok {
+		return b.publishReliable(ctx, e, cfg)
+	}
+
 	for attempt := 1; attempt <= PublishAttempts; attempt++ {
 		log.Tracef("Bus.Publish attempt: %d/%d", attempt, PublishAttempts)
 
@@ -118,6 +189,22 @@ func (b *Bus) Close() error {
 	return b.reg.Close()
 }
 
+// Shutdown gracefully shuts down the bus. Other than Close, it waits until all
+// peers have acknowledged the shutdown or the context is done, so that no
+// in-flight envelopes are lost. See EndpointRegistry.Shutdown.
+func (b *Bus) Shutdown(ctx context.Context, reason string) error {
+	err := b.reg.Shutdown(ctx, reason)
+	if cerr := b.mainRecv.Close(); cerr != nil && err == nil {
+		err = cerr
+	}
+
+	b.mutex.Lock()
+	b.recvs = nil
+	b.mutex.Unlock()
+
+	return err
+}
+
 func (b *Bus) addSubscriber(c wire.Consumer, addr map[wallet.BackendID]wire.Address) {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
@@ -143,6 +230,16 @@ func (b *Bus) dispatchMsgs() {
 			return
 		}
 
+		switch msg := e.Msg.(type) {
+		case *wire.AckMsg:
+			b.delivery.ack(e.Sender, msg.ID)
+			continue
+		case *wire.ReliableMsg:
+			if e = b.receiveReliable(e, msg); e == nil {
+				continue
+			}
+		}
+
 		b.mutex.Lock()
 		r, ok := b.recvs[wire.Keys(e.Recipient)]
 		b.mutex.Unlock()
@@ -15,16 +15,21 @@
 package net_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"perun.network/go-perun/wallet"
 
+	"perun.network/go-perun/channel"
 	"perun.network/go-perun/wire"
 	"perun.network/go-perun/wire/net"
 	nettest "perun.network/go-perun/wire/net/test"
 	perunio "perun.network/go-perun/wire/perunio/serializer"
 	wiretest "perun.network/go-perun/wire/test"
+	"polycry.pt/poly-go/test"
 )
 
 func TestBus(t *testing.T) {
@@ -42,3 +47,45 @@ func TestBus(t *testing.T) {
 
 	require.NoError(t, hub.Close())
 }
+
+func TestBus_ShuttingDown(t *testing.T) {
+	rng := test.Prng(t)
+	var hub nettest.ConnHub
+	defer hub.Close()
+	aliceAcc := wiretest.NewRandomAccountMap(rng, channel.TestBackendID)
+	bobAcc := wiretest.NewRandomAccountMap(rng, channel.TestBackendID)
+	aliceAddr, bobAddr := wire.AddressMapfromAccountMap(aliceAcc), wire.AddressMapfromAccountMap(bobAcc)
+	alice := net.NewBus(aliceAcc, hub.NewNetDialer(), perunio.Serializer())
+	bob := net.NewBus(bobAcc, hub.NewNetDialer(), perunio.Serializer())
+	defer bob.Close()
+	go bob.Listen(hub.NewNetListener(bobAddr))
+	bobRecv := wire.NewReceiver()
+	require.NoError(t, bob.SubscribeClient(bobRecv, bobAddr))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, alice.Publish(ctx, &wire.Envelope{Sender: aliceAddr, Recipient: bobAddr, Msg: &wire.HelloMsg{Version: wire.ProtocolVersion}}))
+	_, err := bobRecv.Next(ctx)
+	require.NoError(t, err)
+
+	// While draining, Bob knows that Alice is shutting down and refuses to
+	// send her new requests.
+	drained := make(chan error, 1)
+	bob.OnDrain(func(_ context.Context, peer map[wallet.BackendID]wire.Address) {
+		assert.Equal(t, wire.Keys(aliceAddr), wire.Keys(peer))
+		assert.True(t, bob.IsShuttingDown(aliceAddr))
+		drained <- bob.Publish(ctx, &wire.Envelope{Sender: bobAddr, Recipient: aliceAddr, Msg: requestMsg{}})
+	})
+	require.NoError(t, alice.Shutdown(ctx, "test"))
+	assert.ErrorIs(t, <-drained, net.ErrShuttingDown)
+	assert.True(t, alice.IsShuttingDown(bobAddr))
+	assert.ErrorIs(t, alice.Publish(ctx, &wire.Envelope{Sender: aliceAddr, Recipient: bobAddr, Msg: requestMsg{}}), net.ErrShuttingDown)
+}
+
+// requestMsg is a message of a request type. It cannot be encoded, which is
+// not needed because requests are refused before encoding.
+type requestMsg struct {
+	wire.HelloMsg
+}
+
+func (requestMsg) Type() wire.Type { return wire.ChannelUpdate }
@@ -14,7 +14,10 @@
 
 package net
 
-import "perun.network/go-perun/wire"
+import (
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+)
 
 // Conn is a connection to a peer, and can send wire messages.
 // The Send and Recv methods do not have to be reentrant, but calls to Close
@@ -33,3 +36,13 @@ type Conn interface {
 	// Repeated calls to Close() result in an error.
 	Close() error
 }
+
+// AuthenticatedConn is a Conn whose transport already authenticated the Perun
+// addresses of the peer while establishing the connection, e.g., in a
+// cryptographic handshake. The EndpointRegistry skips the address exchange
+// protocol for such connections.
+type AuthenticatedConn interface {
+	Conn
+	// PeerAddress returns the authenticated Perun addresses of the peer.
+	PeerAddress() map[wallet.BackendID]wire.Address
+}
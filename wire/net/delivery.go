@@ -0,0 +1,249 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+)
+
+const (
+	// dedupWindow is the number of most recent message IDs per sender that
+	// are remembered for discarding duplicates.
+	dedupWindow = 4096
+	// ackTimeout is the time after which sending an acknowledgement is
+	// aborted.
+	ackTimeout = 10 * time.Second
+)
+
+// AckConfig configures the at-least-once delivery of a Bus.
+type AckConfig struct {
+	// RetransmitInterval is the time after which an unacknowledged envelope is
+	// sent again.
+	RetransmitInterval time.Duration
+}
+
+// DefaultAckConfig returns a configuration that retransmits unacknowledged
+// envelopes every second.
+func DefaultAckConfig() AckConfig {
+	return AckConfig{RetransmitInterval: time.Second}
+}
+
+// EnableAcks enables the at-least-once delivery of the bus. Afterwards,
+// published messages are wrapped in a wire.ReliableMsg with a unique ID and
+// retransmitted until the recipient acknowledges them, so that they survive
+// connection resets. Publish only returns once the envelope was acknowledged
+// or the context is done. Publishing to a peer that does not support
+// wire.FeatureReliableDelivery fails with a wire.FeatureNotSupportedError.
+//
+// Receiving buses always acknowledge and deduplicate such messages, so the
+// recipient does not need to enable acknowledgements itself. This method is
+// expected to be called once during the setup of the bus.
+func (b *Bus) EnableAcks(cfg AckConfig) {
+	b.delivery.mutex.Lock()
+	defer b.delivery.mutex.Unlock()
+	b.delivery.cfg = cfg
+	b.delivery.enabled = true
+}
+
+type (
+	// delivery tracks the state of the at-least-once delivery protocol.
+	delivery struct {
+		mutex   sync.Mutex // Protects all fields.
+		cfg     AckConfig
+		enabled bool
+
+		nextID  uint64                         // ID of the next sent message.
+		pending map[uint64]*pendingAck         // Unacknowledged sent messages.
+		seen    map[wire.AddrKey]*dedupHistory // Received message IDs per sender.
+	}
+
+	// pendingAck is an unacknowledged message.
+	pendingAck struct {
+		peer  wire.AddrKey
+		acked chan struct{}
+	}
+
+	// dedupHistory remembers the most recently received message IDs of a
+	// sender.
+	dedupHistory struct {
+		ids   map[uint64]struct{}
+		order []uint64 // Ring buffer of the IDs in the order they were received.
+		next  int      // Position of the oldest ID once the buffer is full.
+	}
+)
+
+func newDelivery() delivery {
+	return delivery{
+		// Start at a random ID so that the IDs of a restarted node do not
+		// collide with those remembered by its peers.
+		nextID:  rand.Uint64(), //nolint:gosec // IDs need not be unpredictable.
+		pending: make(map[uint64]*pendingAck),
+		seen:    make(map[wire.AddrKey]*dedupHistory),
+	}
+}
+
+// config returns the configuration and whether acknowledgements are enabled.
+func (d *delivery) config() (AckConfig, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.cfg, d.enabled
+}
+
+// register assigns an ID to a message for the peer and returns a channel that
+// is closed when the peer acknowledges it.
+func (d *delivery) register(peer map[wallet.BackendID]wire.Address) (uint64, <-chan struct{}) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	id := d.nextID
+	d.nextID++
+	p := &pendingAck{peer: wire.Keys(peer), acked: make(chan struct{})}
+	d.pending[id] = p
+	return id, p.acked
+}
+
+// unregister stops waiting for the acknowledgement of a message.
+func (d *delivery) unregister(id uint64) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	delete(d.pending, id)
+}
+
+// ack marks a message as acknowledged. Acknowledgements from other peers than
+// the recipient are ignored.
+func (d *delivery) ack(peer map[wallet.BackendID]wire.Address, id uint64) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	p, ok := d.pending[id]
+	if !ok || p.peer != wire.Keys(peer) {
+		return
+	}
+	close(p.acked)
+	delete(d.pending, id)
+}
+
+// firstSeen records a received message ID and returns whether it was not
+// received before.
+func (d *delivery) firstSeen(peer map[wallet.BackendID]wire.Address, id uint64) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	h, ok := d.seen[wire.Keys(peer)]
+	if !ok {
+		h = &dedupHistory{ids: make(map[uint64]struct{})}
+		d.seen[wire.Keys(peer)] = h
+	}
+	return h.add(id)
+}
+
+// add records an ID and returns whether it was not recorded before. If the
+// history is full, the oldest ID is forgotten.
+func (h *dedupHistory) add(id uint64) bool {
+	if _, ok := h.ids[id]; ok {
+		return false
+	}
+	if len(h.order) < dedupWindow {
+		h.order = append(h.order, id)
+	} else {
+		delete(h.ids, h.order[h.next])
+		h.order[h.next] = id
+		h.next = (h.next + 1) % dedupWindow
+	}
+	h.ids[id] = struct{}{}
+	return true
+}
+
+// publishAcked wraps the envelope's message in a wire.ReliableMsg, publishes
+// it and retransmits it until it is acknowledged. It fails if the peer does
+// not support acknowledgements. In reliable mode, a peer that cannot be
+// reached yet is assumed to support them, so that the envelope is queued.
+func (b *Bus) publishAcked(ctx context.Context, e *wire.Envelope, cfg AckConfig) error {
+	if err := b.requireAcks(ctx, e.Recipient); err != nil {
+		return errors.WithMessagef(err, "publishing %T envelope", e.Msg)
+	}
+
+	id, acked := b.delivery.register(e.Recipient)
+	defer b.delivery.unregister(id)
+
+	env := &wire.Envelope{Sender: e.Sender, Recipient: e.Recipient, Msg: &wire.ReliableMsg{ID: id, Msg: e.Msg}}
+	if err := b.publish(ctx, env); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(cfg.RetransmitInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-acked:
+			return nil
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "waiting for acknowledgement of %T envelope", e.Msg)
+		case <-b.ctx().Done():
+			return errors.Errorf("waiting for acknowledgement of %T envelope: Bus closed", e.Msg)
+		case <-ticker.C:
+		}
+
+		sendCtx, cancel := context.WithTimeout(ctx, cfg.RetransmitInterval)
+		if err := b.send(sendCtx, env); err != nil {
+			log.WithError(err).WithField("peer", e.Recipient).Debugf("Retransmitting %T envelope failed", e.Msg)
+		}
+		cancel()
+	}
+}
+
+// requireAcks connects to the peer and returns a wire.FeatureNotSupportedError
+// if the peer does not support acknowledgements. If the peer cannot be reached
+// in reliable mode, no error is returned, so that the envelope is queued.
+func (b *Bus) requireAcks(ctx context.Context, peer map[wallet.BackendID]wire.Address) error {
+	ep, err := b.reg.Endpoint(ctx, peer)
+	if err != nil {
+		if _, ok := b.reliable(); ok && !IsAuthenticationError(err) {
+			return nil
+		}
+		return errors.WithMessage(err, "connecting to peer")
+	}
+	if !ep.Features().Has(wire.FeatureReliableDelivery) {
+		return &wire.FeatureNotSupportedError{Peer: peer, Missing: wire.FeatureReliableDelivery}
+	}
+	return nil
+}
+
+// receiveReliable acknowledges a received wire.ReliableMsg and returns the
+// unwrapped envelope, or nil if the message is a duplicate.
+func (b *Bus) receiveReliable(e *wire.Envelope, msg *wire.ReliableMsg) *wire.Envelope {
+	go b.acknowledge(e, msg.ID)
+	if !b.delivery.firstSeen(e.Sender, msg.ID) {
+		log.WithField("sender", e.Sender).Tracef("Discarding duplicate %T message", msg.Msg)
+		return nil
+	}
+	return &wire.Envelope{Sender: e.Sender, Recipient: e.Recipient, Msg: msg.Msg}
+}
+
+// acknowledge sends an acknowledgement for the received envelope.
+func (b *Bus) acknowledge(e *wire.Envelope, id uint64) {
+	ctx, cancel := context.WithTimeout(b.ctx(), ackTimeout)
+	defer cancel()
+	ack := &wire.Envelope{Sender: e.Recipient, Recipient: e.Sender, Msg: &wire.AckMsg{ID: id}}
+	if err := b.send(ctx, ack); err != nil {
+		log.WithError(err).WithField("peer", e.Sender).Debug("Sending acknowledgement failed")
+	}
+}
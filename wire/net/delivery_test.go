@@ -0,0 +1,130 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	"perun.network/go-perun/wire/net"
+	nettest "perun.network/go-perun/wire/net/test"
+	perunio "perun.network/go-perun/wire/perunio/serializer"
+	"perun.network/go-perun/wire/protobuf"
+	wiretest "perun.network/go-perun/wire/test"
+	"polycry.pt/poly-go/test"
+)
+
+// forEachSerializer runs test with every envelope serializer, so that the
+// delivery messages are covered by both encodings.
+func forEachSerializer(t *testing.T, test func(t *testing.T, ser wire.EnvelopeSerializer)) {
+	t.Helper()
+	t.Run("perunio", func(t *testing.T) { test(t, perunio.Serializer()) })
+	t.Run("protobuf", func(t *testing.T) { test(t, protobuf.Serializer()) })
+}
+
+func TestBus_Acks(t *testing.T) {
+	t.Parallel()
+	forEachSerializer(t, func(t *testing.T, ser wire.EnvelopeSerializer) {
+		rng := test.Prng(t)
+
+		var hub nettest.ConnHub
+		defer hub.Close()
+		newBus := func() (*net.Bus, map[wallet.BackendID]wire.Address) {
+			acc := wiretest.NewRandomAccountMap(rng, channel.TestBackendID)
+			bus := net.NewBus(acc, hub.NewNetDialer(), ser)
+			t.Cleanup(func() { bus.Close() })
+			return bus, wire.AddressMapfromAccountMap(acc)
+		}
+		expectNone := func(t *testing.T, recv *wire.Receiver) {
+			t.Helper()
+			ctx, cancel := context.WithTimeout(context.Background(), 2*timeout)
+			defer cancel()
+			env, err := recv.Next(ctx)
+			assert.Error(t, err, "unexpected envelope %v", env)
+		}
+
+		t.Run("retransmission", func(t *testing.T) {
+			alice, aliceAddr := newBus()
+			bob, bobAddr := newBus()
+			alice.EnableReconnect(net.ReconnectConfig{QueueSize: 1, TTL: time.Second, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+			alice.EnableAcks(net.AckConfig{RetransmitInterval: time.Millisecond})
+			recv := wire.NewReceiver()
+			require.NoError(t, bob.SubscribeClient(recv, bobAddr))
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			msg := wire.NewPingMsg()
+			published := make(chan error, 1)
+			go func() {
+				published <- alice.Publish(ctx, &wire.Envelope{Sender: aliceAddr, Recipient: bobAddr, Msg: msg})
+			}()
+
+			// Bob becomes reachable after the envelope was sent several times.
+			time.Sleep(timeout)
+			select {
+			case err := <-published:
+				t.Fatalf("Publish returned before acknowledgement: %v", err)
+			default:
+			}
+			go bob.Listen(hub.NewNetListener(bobAddr))
+
+			require.NoError(t, <-published)
+			env, err := recv.Next(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, msg, env.Msg)
+			expectNone(t, recv)
+		})
+
+		t.Run("unsupported", func(t *testing.T) {
+			alice, aliceAddr := newBus()
+			bob, bobAddr := newBus()
+			bob.SetFeatures(wire.SupportedFeatures &^ wire.FeatureReliableDelivery)
+			go bob.Listen(hub.NewNetListener(bobAddr))
+			alice.EnableAcks(net.AckConfig{RetransmitInterval: time.Millisecond})
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			err := alice.Publish(ctx, &wire.Envelope{Sender: aliceAddr, Recipient: bobAddr, Msg: wire.NewPingMsg()})
+			assert.True(t, wire.IsFeatureNotSupportedError(err), "unexpected error: %v", err)
+		})
+
+		t.Run("deduplication", func(t *testing.T) {
+			alice, aliceAddr := newBus()
+			bob, bobAddr := newBus()
+			recv := wire.NewReceiver()
+			require.NoError(t, bob.SubscribeClient(recv, bobAddr))
+			go bob.Listen(hub.NewNetListener(bobAddr))
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			msg := &wire.ReliableMsg{ID: 1, Msg: wire.NewPingMsg()}
+			for range 3 {
+				require.NoError(t, alice.Publish(ctx, &wire.Envelope{Sender: aliceAddr, Recipient: bobAddr, Msg: msg}))
+			}
+
+			env, err := recv.Next(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, msg.Msg, env.Msg)
+			expectNone(t, recv)
+		})
+	})
+}
@@ -18,6 +18,9 @@ import (
 	"context"
 	"fmt"
 	"io"
+	stdsync "sync"
+	"sync/atomic"
+	"time"
 
 	"perun.network/go-perun/wallet"
 
@@ -40,6 +43,21 @@ type Endpoint struct {
 	conn    Conn                              // The Endpoint's connection.
 
 	sending sync.Mutex // Blocks multiple Send calls.
+
+	lastRecv atomic.Int64 // Unix nanoseconds of the last received envelope.
+	pingSent atomic.Int64 // Unix nanoseconds of the unanswered keepalive ping, or 0.
+	rtt      atomic.Int64 // Last measured keepalive round-trip time.
+
+	shutdownSent  atomic.Bool   // Whether a ShutdownMsg was sent to the peer.
+	shutdownRecvd atomic.Bool   // Whether a ShutdownMsg was received from the peer.
+	peerShutdown  chan struct{} // Closed when a ShutdownMsg is received.
+	stopped       chan struct{} // Closed when the receive loop returns.
+
+	drain     func(context.Context, map[wallet.BackendID]wire.Address) // Waits for pending responses before a shutdown, if set.
+	drainOnce stdsync.Once                                             // Drains only once, by whichever side shuts down first.
+
+	version  uint16        // Negotiated protocol version.
+	features wire.Features // Negotiated protocol features.
 }
 
 // Send sends a single message to an Endpoint.
@@ -78,10 +96,26 @@ func (p *Endpoint) Close() (err error) {
 
 // newEndpoint creates a new Endpoint from a wire Address and connection.
 func newEndpoint(addr map[wallet.BackendID]wire.Address, conn Conn) *Endpoint {
-	return &Endpoint{
-		Address: addr,
-		conn:    conn,
+	e := &Endpoint{
+		Address:      addr,
+		conn:         conn,
+		peerShutdown: make(chan struct{}),
+		stopped:      make(chan struct{}),
 	}
+	e.lastRecv.Store(time.Now().UnixNano())
+	return e
+}
+
+// ProtocolVersion returns the protocol version that was negotiated with the
+// peer.
+func (p *Endpoint) ProtocolVersion() uint16 {
+	return p.version
+}
+
+// Features returns the protocol features that both the node and the peer
+// support.
+func (p *Endpoint) Features() wire.Features {
+	return p.features
 }
 
 // String returns the Endpoint's address string.
@@ -93,19 +127,39 @@ func (p *Endpoint) String() string {
 // Received messages are relayed via the Endpoint's subscription system. This is
 // called by the registry when the Endpoint is registered.
 //
-// Does not return an error when the Endpoint closing fails or when
-// conn.Recv returns io.EOF, which indicates connection closing for TCP.
+// Pings are answered with a pong and pongs are only used for measuring the
+// round-trip time. Neither is relayed. Shutdown messages are
+// acknowledged and not relayed.
+//
+// Does not return an error when the Endpoint closing fails, when conn.Recv
+// returns io.EOF, which indicates connection closing for TCP, or when the
+// connection was shut down gracefully.
 func (p *Endpoint) recvLoop(c wire.Consumer) error {
+	defer close(p.stopped)
 	for {
 		e, err := p.conn.Recv()
 		if err != nil {
 			p.Close() // Ignore double close.
 			// Check for graceful TCP connection close.
-			if errors.Cause(err) == io.EOF {
+			if errors.Cause(err) == io.EOF || p.IsShutdown() {
 				return nil
 			}
 			return err
 		}
+		p.lastRecv.Store(time.Now().UnixNano())
+		switch msg := e.Msg.(type) {
+		case *wire.PingMsg:
+			go p.pong(e)
+			continue
+		case *wire.PongMsg:
+			if sent := p.pingSent.Swap(0); sent != 0 {
+				p.rtt.Store(time.Now().UnixNano() - sent)
+			}
+			continue
+		case *wire.ShutdownMsg:
+			p.recvShutdown(e, msg)
+			continue
+		}
 		// Emit the received envelope.
 		c.Put(e)
 	}
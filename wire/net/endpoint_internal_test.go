@@ -69,10 +69,10 @@ func (s *setup) Dial(ctx context.Context, addr map[wallet.BackendID]wire.Address
 	a, b := newPipeConnPair()
 
 	if channel.EqualWireMaps(addr, s.alice.endpoint.Address) { // Dialing Bob?
-		s.bob.Registry.addEndpoint(s.bob.endpoint.Address, b, true) // Bob accepts connection.
+		s.bob.Registry.addEndpoint(s.bob.endpoint.Address, b, true, nil) // Bob accepts connection.
 		return a, nil
 	} else if channel.EqualWireMaps(addr, s.bob.endpoint.Address) { // Dialing Alice?
-		s.alice.Registry.addEndpoint(s.alice.endpoint.Address, a, true) // Alice accepts connection.
+		s.alice.Registry.addEndpoint(s.alice.endpoint.Address, a, true, nil) // Alice accepts connection.
 		return b, nil
 	}
 	return nil, errors.New("unknown peer")
@@ -105,7 +105,7 @@ func makeClient(conn Conn, rng *rand.Rand, dialer Dialer) *client {
 	}, dialer, perunio.Serializer())
 
 	return &client{
-		endpoint: registry.addEndpoint(wiretest.NewRandomAddress(rng), conn, true),
+		endpoint: registry.addEndpoint(wiretest.NewRandomAddress(rng), conn, true, nil),
 		Registry: registry,
 		Receiver: receiver,
 	}
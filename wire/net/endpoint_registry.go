@@ -77,7 +77,15 @@ type EndpointRegistry struct {
 
 	endpoints map[wire.AddrKey]*fullEndpoint // The list of all of all established Endpoints.
 	dialing   map[wire.AddrKey]*dialingEndpoint
-	mutex     sync.RWMutex // protects peers and dialing.
+	mutex     sync.RWMutex // protects peers, dialing and the keepalive settings.
+
+	keepaliveCfg KeepaliveConfig                                            // Keepalive settings for new Endpoints.
+	onEvicted    []func(map[wallet.BackendID]wire.Address)                  // Called when an Endpoint is evicted.
+	onDrain      []func(context.Context, map[wallet.BackendID]wire.Address) // Called before a connection is shut down.
+
+	shuttingDown atomic.Bool // Set when a graceful shutdown was started.
+
+	features wire.Features // Protocol features offered to new Endpoints.
 }
 
 const exchangeAddrsTimeout = 10 * time.Second
@@ -99,11 +107,28 @@ func NewEndpointRegistry(
 
 		endpoints: make(map[wire.AddrKey]*fullEndpoint),
 		dialing:   make(map[wire.AddrKey]*dialingEndpoint),
+		features:  wire.SupportedFeatures,
 
 		Embedding: log.MakeEmbedding(log.WithField("id", id)),
 	}
 }
 
+// SetFeatures sets the protocol features that are offered to peers of new
+// Endpoints. By default, all features in wire.SupportedFeatures are offered.
+// This method is expected to be called once during the setup of the registry.
+func (r *EndpointRegistry) SetFeatures(features wire.Features) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.features = features
+}
+
+// hello returns the hello message that is sent during protocol negotiation.
+func (r *EndpointRegistry) hello() *wire.HelloMsg {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return &wire.HelloMsg{Version: wire.ProtocolVersion, Features: r.features}
+}
+
 // Close closes the registry's dialer and all its peers.
 func (r *EndpointRegistry) Close() (err error) {
 	if err = r.Closer.Close(); err != nil {
@@ -154,6 +179,12 @@ func (r *EndpointRegistry) Listen(listener Listener) {
 			return
 		}
 
+		if r.shuttingDown.Load() {
+			r.Log().Debug("EndpointRegistry.Listen: rejecting incoming connection during shutdown")
+			conn.Close()
+			continue
+		}
+
 		r.Log().Debug("EndpointRegistry.Listen: setting up incoming connection")
 		// setup connection in a separate routine so that new incoming
 		// connections can immediately be handled.
@@ -187,6 +218,10 @@ func (r *EndpointRegistry) Endpoint(ctx context.Context, addr map[wallet.Backend
 			return e, nil
 		}
 	}
+	if r.shuttingDown.Load() {
+		r.mutex.Unlock()
+		return nil, errors.New("failed to dial peer: registry shutting down")
+	}
 	de, created := r.dialingEndpoint(addr)
 	r.mutex.Unlock()
 
@@ -253,12 +288,23 @@ func (r *EndpointRegistry) authenticatedDial(
 		return nil, errors.WithMessage(err, "failed to dial")
 	}
 
-	if err := ExchangeAddrsActive(ctx, r.id, addr, conn); err != nil {
+	if ac, ok := conn.(AuthenticatedConn); ok {
+		if peer := ac.PeerAddress(); !channel.EqualWireMaps(peer, addr) {
+			conn.Close()
+			own := wire.AddressMapfromAccountMap(r.id)
+			return nil, NewAuthenticationError(peer, own, own, "dialed peer has a different address")
+		}
+	} else if err := ExchangeAddrsActive(ctx, r.id, addr, conn); err != nil {
 		conn.Close()
 		return nil, errors.WithMessage(err, "ExchangeAddrs failed")
 	}
 
-	return r.addEndpoint(addr, conn, true), nil
+	negotiated, conn, err := NegotiateActive(ctx, wire.AddressMapfromAccountMap(r.id), addr, r.hello(), conn)
+	if err != nil {
+		return nil, errors.WithMessage(err, "protocol negotiation failed")
+	}
+
+	return r.addEndpoint(addr, conn, true, negotiated), nil
 }
 
 // dialingEndpoint retrieves or creates a dialingEndpoint for the passed address.
@@ -273,11 +319,16 @@ func (r *EndpointRegistry) dialingEndpoint(a map[wallet.BackendID]wire.Address)
 	return entry, !ok
 }
 
-// addEndpoint adds a new peer to the registry.
-func (r *EndpointRegistry) addEndpoint(addr map[wallet.BackendID]wire.Address, conn Conn, dialer bool) *Endpoint {
+// addEndpoint adds a new peer to the registry. negotiated contains the
+// negotiated protocol version and features, if any.
+func (r *EndpointRegistry) addEndpoint(addr map[wallet.BackendID]wire.Address, conn Conn, dialer bool, negotiated *wire.HelloMsg) *Endpoint {
 	r.Log().WithField("peer", addr).Trace("EndpointRegistry.addEndpoint")
 
 	e := newEndpoint(addr, conn)
+	e.drain = r.drain
+	if negotiated != nil {
+		e.version, e.features = negotiated.Version, negotiated.Features
+	}
 	fe, created := r.fullEndpoint(addr, e)
 	if !created {
 		if e, closed := fe.replace(e, wire.AddressMapfromAccountMap(r.id), dialer); closed {
@@ -294,6 +345,13 @@ func (r *EndpointRegistry) addEndpoint(addr map[wallet.BackendID]wire.Address, c
 		fe.delete(e)
 	}()
 
+	r.mutex.RLock()
+	cfg := r.keepaliveCfg
+	r.mutex.RUnlock()
+	if cfg.Interval > 0 {
+		go r.keepalive(fe, e, cfg)
+	}
+
 	return e
 }
 
@@ -383,7 +441,9 @@ func (r *EndpointRegistry) setupConn(conn Conn) error {
 	var peerAddr map[wallet.BackendID]wire.Address
 
 	var err error
-	if peerAddr, err = ExchangeAddrsPassive(ctx, r.id, conn); err != nil {
+	if ac, ok := conn.(AuthenticatedConn); ok {
+		peerAddr = ac.PeerAddress()
+	} else if peerAddr, err = ExchangeAddrsPassive(ctx, r.id, conn); err != nil {
 		conn.Close()
 		r.Log().WithField("peer", peerAddr).Error("could not authenticate peer:", err)
 		return err
@@ -394,6 +454,12 @@ func (r *EndpointRegistry) setupConn(conn Conn) error {
 		return errors.New("dialed by self")
 	}
 
-	r.addEndpoint(peerAddr, conn, false)
+	negotiated, conn, err := NegotiatePassive(ctx, wire.AddressMapfromAccountMap(r.id), peerAddr, r.hello(), conn)
+	if err != nil {
+		r.Log().WithField("peer", peerAddr).Error("protocol negotiation failed:", err)
+		return err
+	}
+
+	r.addEndpoint(peerAddr, conn, false, negotiated)
 	return nil
 }
@@ -168,6 +168,8 @@ func TestRegistry_Get(t *testing.T) {
 			dialer.put(a)
 			_, err := ExchangeAddrsPassive(ctx, peerID, b)
 			assert.NoError(t, err)
+			_, _, err = NegotiatePassive(ctx, peerAddr, wire.AddressMapfromAccountMap(id), testHello(), b)
+			assert.NoError(t, err)
 			_, err = b.Recv()
 			assert.NoError(t, err)
 		})
@@ -254,6 +256,10 @@ func TestRegistry_authenticatedDial(t *testing.T) {
 			if err != nil {
 				panic(err)
 			}
+			_, _, err = NegotiatePassive(ctx, remoteAddr, wire.AddressMapfromAccountMap(id), testHello(), b)
+			if err != nil {
+				panic(err)
+			}
 		}()
 		ctx, cancel := context.WithTimeout(context.Background(), timeout)
 		defer cancel()
@@ -262,6 +268,38 @@ func TestRegistry_authenticatedDial(t *testing.T) {
 		require.NoError(t, err)
 		assert.NotNil(t, e)
 	})
+
+	t.Run("dial success, authenticated conn", func(t *testing.T) {
+		a, b := newPipeConnPair()
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			d.put(&authConn{Conn: a, peer: remoteAddr})
+			// The address exchange is skipped.
+			_, _, err := NegotiatePassive(ctx, remoteAddr, wire.AddressMapfromAccountMap(id), testHello(), b)
+			if err != nil {
+				panic(err)
+			}
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		de, created := r.dialingEndpoint(remoteAddr)
+		e, err := r.authenticatedDial(ctx, remoteAddr, de, created)
+		require.NoError(t, err)
+		assert.NotNil(t, e)
+	})
+
+	t.Run("dial success, authenticated conn imposter", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		a, _ := newPipeConnPair()
+		imposter := wiretest.NewRandomAddress(rng)
+		go d.put(&authConn{Conn: a, peer: imposter})
+		de, created := r.dialingEndpoint(remoteAddr)
+		e, err := r.authenticatedDial(ctx, remoteAddr, de, created)
+		assert.True(t, IsAuthenticationError(err))
+		assert.Nil(t, e)
+	})
 }
 
 func TestRegistry_setupConn(t *testing.T) {
@@ -298,9 +336,13 @@ func TestRegistry_setupConn(t *testing.T) {
 			if err != nil {
 				panic(err)
 			}
+			_, _, err = NegotiateActive(context.Background(), wire.AddressMapfromAccountMap(remoteID), wire.AddressMapfromAccountMap(id), testHello(), b)
+			if err != nil {
+				panic(err)
+			}
 		}()
 
-		r.addEndpoint(wire.AddressMapfromAccountMap(remoteID), newMockConn(), false)
+		r.addEndpoint(wire.AddressMapfromAccountMap(remoteID), newMockConn(), false, nil)
 		ctxtest.AssertTerminates(t, timeout, func() {
 			require.NoError(t, r.setupConn(a))
 		})
@@ -316,12 +358,46 @@ func TestRegistry_setupConn(t *testing.T) {
 			if err != nil {
 				panic(err)
 			}
+			_, _, err = NegotiateActive(context.Background(), wire.AddressMapfromAccountMap(remoteID), wire.AddressMapfromAccountMap(id), testHello(), b)
+			if err != nil {
+				panic(err)
+			}
 		}()
 
 		ctxtest.AssertTerminates(t, timeout, func() {
 			require.NoError(t, r.setupConn(a))
 		})
 	})
+
+	t.Run("authenticated conn", func(t *testing.T) {
+		d := &mockDialer{dial: make(chan Conn)}
+		r := NewEndpointRegistry(id, nilConsumer, d, perunio.Serializer())
+		a, b := newPipeConnPair()
+		remoteAddr := wire.AddressMapfromAccountMap(remoteID)
+
+		go func() {
+			// The address exchange is skipped.
+			_, _, err := NegotiateActive(context.Background(), remoteAddr, wire.AddressMapfromAccountMap(id), testHello(), b)
+			if err != nil {
+				panic(err)
+			}
+		}()
+
+		ctxtest.AssertTerminates(t, timeout, func() {
+			require.NoError(t, r.setupConn(&authConn{Conn: a, peer: remoteAddr}))
+		})
+		assert.True(t, r.Has(remoteAddr))
+	})
+}
+
+// authConn is a Conn whose peer was authenticated by the transport.
+type authConn struct {
+	Conn
+	peer map[wallet.BackendID]wire.Address
+}
+
+func (c *authConn) PeerAddress() map[wallet.BackendID]wire.Address {
+	return c.peer
 }
 
 func TestRegistry_Listen(t *testing.T) {
@@ -350,6 +426,8 @@ func TestRegistry_Listen(t *testing.T) {
 	defer cancel()
 	err := ExchangeAddrsActive(ctx, remoteID, addr, b)
 	require.NoError(t, err)
+	_, _, err = NegotiateActive(ctx, remoteAddr, addr, testHello(), b)
+	require.NoError(t, err)
 
 	<-time.After(timeout)
 	assert.True(r.Has(remoteAddr))
@@ -379,7 +457,7 @@ func TestRegistry_addEndpoint_Subscribe(t *testing.T) {
 	)
 
 	assert.False(t, called, "onNewEndpoint must not have been called yet")
-	r.addEndpoint(wiretest.NewRandomAddress(rng), newMockConn(), false)
+	r.addEndpoint(wiretest.NewRandomAddress(rng), newMockConn(), false, nil)
 	assert.True(t, called, "onNewEndpoint must have been called")
 }
 
@@ -414,6 +492,11 @@ func TestRegistry_Close(t *testing.T) {
 }
 
 // newPipeConnPair creates endpoints that are connected via pipes.
+// testHello returns the hello message sent by simulated peers.
+func testHello() *wire.HelloMsg {
+	return &wire.HelloMsg{Version: wire.ProtocolVersion, Features: wire.SupportedFeatures}
+}
+
 func newPipeConnPair() (a Conn, b Conn) {
 	c0, c1 := net.Pipe()
 	ser := perunio.Serializer()
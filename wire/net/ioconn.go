@@ -20,6 +20,7 @@ import (
 	"github.com/pkg/errors"
 
 	"perun.network/go-perun/wire"
+	"perun.network/go-perun/wire/perunio"
 	"polycry.pt/poly-go/sync/atomic"
 )
 
@@ -30,13 +31,24 @@ type ioConn struct {
 	closed     atomic.Bool
 	conn       io.ReadWriteCloser
 	serializer wire.EnvelopeSerializer
+	limits     perunio.Limits
 }
 
-// NewIoConn creates a peer message connection from an io stream.
+// NewIoConn creates a peer message connection from an io stream. Received
+// envelopes are subject to the default limits, see perunio.DefaultLimits.
 func NewIoConn(conn io.ReadWriteCloser, serializer wire.EnvelopeSerializer) Conn {
+	return NewIoConnWithLimits(conn, serializer, perunio.DefaultLimits())
+}
+
+// NewIoConnWithLimits creates a peer message connection from an io stream
+// that enforces the given limits on each received envelope. An envelope that
+// exceeds the limits fails with a perunio.LimitError and closes the
+// connection.
+func NewIoConnWithLimits(conn io.ReadWriteCloser, serializer wire.EnvelopeSerializer, limits perunio.Limits) Conn {
 	return &ioConn{
 		conn:       conn,
 		serializer: serializer,
+		limits:     limits,
 	}
 }
 
@@ -49,7 +61,7 @@ func (c *ioConn) Send(e *wire.Envelope) error {
 }
 
 func (c *ioConn) Recv() (*wire.Envelope, error) {
-	e, err := c.serializer.Decode(c.conn)
+	e, err := c.serializer.Decode(perunio.NewLimitedReader(c.conn, c.limits))
 	if err != nil {
 		c.conn.Close()
 		return nil, err
@@ -0,0 +1,61 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/wire"
+	"perun.network/go-perun/wire/perunio"
+	perunioserializer "perun.network/go-perun/wire/perunio/serializer"
+	"perun.network/go-perun/wire/protobuf"
+	wiretest "perun.network/go-perun/wire/test"
+	"polycry.pt/poly-go/test"
+)
+
+func TestIoConn_Limits(t *testing.T) {
+	rng := test.Prng(t)
+	limits := perunio.Limits{MaxEnvelopeSize: 1024, MaxFieldSize: 1024}
+
+	for name, ser := range map[string]wire.EnvelopeSerializer{
+		"perunio":  perunioserializer.Serializer(),
+		"protobuf": protobuf.Serializer(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			c0, c1 := net.Pipe()
+			sender, receiver := NewIoConn(c0, ser), NewIoConnWithLimits(c1, ser, limits)
+			defer sender.Close()
+
+			// An envelope within the limits is received.
+			env := wiretest.NewRandomEnvelope(rng, &wire.ShutdownMsg{Reason: "ok"})
+			go sender.Send(env) //nolint:errcheck
+			_, err := receiver.Recv()
+			require.NoError(t, err)
+
+			// An oversized envelope fails and closes the connection.
+			env = wiretest.NewRandomEnvelope(rng, &wire.ShutdownMsg{Reason: strings.Repeat("x", 2048)})
+			sent := make(chan error, 1)
+			go func() { sent <- sender.Send(env) }()
+			_, err = receiver.Recv()
+			assert.True(t, perunio.IsLimitError(err), "unexpected error: %v", err)
+			assert.Error(t, <-sent)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+)
+
+// KeepaliveConfig configures the keepalive pings that an EndpointRegistry
+// sends over its connections to detect dead peers.
+type KeepaliveConfig struct {
+	// Interval is the time between two pings. Keepalive is disabled if it is
+	// zero. A ping that cannot be sent within the interval closes the
+	// connection.
+	Interval time.Duration
+	// Timeout is the time after which an endpoint from which no envelope has
+	// been received is evicted. It should be a multiple of the interval.
+	Timeout time.Duration
+}
+
+// DefaultKeepaliveConfig returns a keepalive configuration that pings every 30
+// seconds and evicts endpoints that have been silent for 90 seconds.
+func DefaultKeepaliveConfig() KeepaliveConfig {
+	return KeepaliveConfig{Interval: 30 * time.Second, Timeout: 90 * time.Second}
+}
+
+// RTT returns the last measured round-trip time of a keepalive ping, or zero
+// if no ping has been answered yet.
+func (p *Endpoint) RTT() time.Duration {
+	return time.Duration(p.rtt.Load())
+}
+
+// ping sends a keepalive ping. The time of the ping is only recorded if no
+// other ping is unanswered, so that the round-trip time is not underestimated.
+func (p *Endpoint) ping(ctx context.Context, self map[wallet.BackendID]wire.Address) error {
+	p.pingSent.CompareAndSwap(0, time.Now().UnixNano())
+	return p.Send(ctx, &wire.Envelope{Sender: self, Recipient: p.Address, Msg: wire.NewPingMsg()})
+}
+
+// pong answers the ping envelope. It is called by the receive loop.
+func (p *Endpoint) pong(ping *wire.Envelope) {
+	ctx, cancel := context.WithTimeout(context.Background(), pongTimeout)
+	defer cancel()
+	if err := p.Send(ctx, &wire.Envelope{Sender: ping.Recipient, Recipient: ping.Sender, Msg: wire.NewPongMsg()}); err != nil {
+		log.WithField("peer", p.Address).Debugf("Sending pong: %v", err)
+	}
+}
+
+// silentFor returns the time since the last envelope was received.
+func (p *Endpoint) silentFor() time.Duration {
+	return time.Since(time.Unix(0, p.lastRecv.Load()))
+}
+
+// pongTimeout is the time after which sending a pong is aborted.
+const pongTimeout = 10 * time.Second
+
+// SetKeepalive configures the keepalive pings for all endpoints that are
+// added afterwards. This method is expected to be called once during the setup
+// of the registry.
+func (r *EndpointRegistry) SetKeepalive(cfg KeepaliveConfig) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.keepaliveCfg = cfg
+}
+
+// OnEndpointEvicted adds a callback that is called with the address of each
+// endpoint that is evicted because it did not respond to keepalive pings. The
+// endpoint is removed from the registry before the callbacks are called, so
+// that the peer is dialed again by the next lookup. The callbacks are called in
+// the order in which they were added. This function may be safely called at
+// any time.
+func (r *EndpointRegistry) OnEndpointEvicted(handler func(map[wallet.BackendID]wire.Address)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.onEvicted = append(r.onEvicted, handler)
+}
+
+// keepalive pings the endpoint until its receive loop returns and evicts it if
+// it stops responding.
+func (r *EndpointRegistry) keepalive(fe *fullEndpoint, e *Endpoint, cfg KeepaliveConfig) {
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+	self := wire.AddressMapfromAccountMap(r.id)
+
+	for {
+		select {
+		case <-e.stopped:
+			return
+		case <-r.Closed():
+			return
+		case <-ticker.C:
+		}
+
+		var err error
+		if silent := e.silentFor(); silent > cfg.Timeout {
+			err = errors.Errorf("silent for %v", silent)
+		} else {
+			ctx, cancel := context.WithTimeout(r.Ctx(), cfg.Interval)
+			err = errors.WithMessage(e.ping(ctx, self), "sending ping")
+			cancel()
+		}
+		if err != nil {
+			r.evict(fe, e, err)
+			return
+		}
+	}
+}
+
+// evict closes the unresponsive endpoint, removes it from the registry and
+// calls the eviction handlers.
+func (r *EndpointRegistry) evict(fe *fullEndpoint, e *Endpoint, reason error) {
+	if r.IsClosed() {
+		return
+	}
+	r.Log().WithField("peer", e.Address).Warnf("Evicting unresponsive endpoint: %v", reason)
+	e.Close() // Ignore double close.
+	fe.delete(e)
+
+	r.mutex.RLock()
+	handlers := r.onEvicted
+	r.mutex.RUnlock()
+	for _, handler := range handlers {
+		handler(e.Address)
+	}
+}
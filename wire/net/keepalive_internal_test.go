@@ -0,0 +1,90 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	perunio "perun.network/go-perun/wire/perunio/serializer"
+	wiretest "perun.network/go-perun/wire/test"
+	"polycry.pt/poly-go/test"
+)
+
+func TestRegistry_Keepalive(t *testing.T) {
+	t.Parallel()
+	rng := test.Prng(t)
+	cfg := KeepaliveConfig{Interval: 10 * time.Millisecond, Timeout: 50 * time.Millisecond}
+
+	newRegistry := func() (*EndpointRegistry, *wire.Receiver) {
+		recv := wire.NewReceiver()
+		id := wiretest.NewRandomAccountMap(rng, channel.TestBackendID)
+		r := NewEndpointRegistry(id, func(map[wallet.BackendID]wire.Address) wire.Consumer { return recv }, nil, perunio.Serializer())
+		r.SetKeepalive(cfg)
+		t.Cleanup(func() { r.Close() })
+		return r, recv
+	}
+
+	t.Run("responsive", func(t *testing.T) {
+		alice, aliceRecv := newRegistry()
+		bob, _ := newRegistry()
+		evicted := make(chan struct{}, 1)
+		alice.OnEndpointEvicted(func(map[wallet.BackendID]wire.Address) { evicted <- struct{}{} })
+
+		a, b := newPipeConnPair()
+		e := alice.addEndpoint(wire.AddressMapfromAccountMap(bob.id), a, true, nil)
+		bob.addEndpoint(wire.AddressMapfromAccountMap(alice.id), b, false, nil)
+
+		require.Eventually(t, func() bool { return e.RTT() > 0 }, time.Second, cfg.Interval)
+		select {
+		case <-evicted:
+			t.Fatal("responsive endpoint evicted")
+		case <-time.After(2 * cfg.Timeout):
+		}
+		assert.Same(t, e, alice.find(e.Address))
+
+		// Neither pings nor pongs are relayed.
+		ctx, cancel := context.WithTimeout(context.Background(), 2*cfg.Interval)
+		defer cancel()
+		_, err := aliceRecv.Next(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("unresponsive", func(t *testing.T) {
+		alice, _ := newRegistry()
+		evicted := make(chan map[wallet.BackendID]wire.Address, 1)
+		alice.OnEndpointEvicted(func(addr map[wallet.BackendID]wire.Address) { evicted <- addr })
+
+		// Nobody reads from the other end of the connection.
+		a, _ := newPipeConnPair()
+		addr := wiretest.NewRandomAddress(rng)
+		alice.addEndpoint(addr, a, true, nil)
+
+		select {
+		case got := <-evicted:
+			assert.True(t, channel.EqualWireMaps(addr, got))
+		case <-time.After(time.Second):
+			t.Fatal("unresponsive endpoint not evicted")
+		}
+		assert.Nil(t, alice.find(addr))
+	})
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	pkg "polycry.pt/poly-go/context"
+)
+
+// NegotiateActive executes the active role of the protocol negotiation, which
+// follows the address exchange. It sends the own protocol version and
+// features, receives those of the peer, and returns the negotiated version
+// and features. It is executed by the person that dials.
+//
+// If the peer predates the protocol negotiation and replies with another
+// message instead of its hello message, the negotiation falls back to the
+// wire.LegacyProtocolVersion without any features. The returned connection
+// must be used instead of conn, because it yields the already received
+// message first.
+func NegotiateActive(ctx context.Context, id, peer map[wallet.BackendID]wire.Address, own *wire.HelloMsg, conn Conn) (*wire.HelloMsg, Conn, error) {
+	var first *wire.Envelope
+	negotiated, err := negotiate(ctx, conn, func() (negotiated *wire.HelloMsg, err error) {
+		if err := conn.Send(&wire.Envelope{Sender: id, Recipient: peer, Msg: own}); err != nil {
+			return nil, errors.WithMessage(err, "sending hello")
+		}
+		negotiated, first, err = recvHello(own, conn)
+		return negotiated, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return negotiated, withFirst(conn, first), nil
+}
+
+// NegotiatePassive executes the passive role of the protocol negotiation. It
+// is executed by the person that listens for incoming connections.
+//
+// If the peer predates the protocol negotiation and sends another message
+// instead of its hello message, the negotiation falls back to the
+// wire.LegacyProtocolVersion without any features, and no hello message is
+// sent to the peer. The returned connection must be used instead of conn,
+// because it yields the already received message first.
+func NegotiatePassive(ctx context.Context, id, peer map[wallet.BackendID]wire.Address, own *wire.HelloMsg, conn Conn) (*wire.HelloMsg, Conn, error) {
+	var first *wire.Envelope
+	negotiated, err := negotiate(ctx, conn, func() (negotiated *wire.HelloMsg, err error) {
+		negotiated, first, err = recvHello(own, conn)
+		if err != nil || first != nil {
+			return negotiated, err
+		}
+		return negotiated, errors.WithMessage(
+			conn.Send(&wire.Envelope{Sender: id, Recipient: peer, Msg: own}),
+			"sending hello")
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return negotiated, withFirst(conn, first), nil
+}
+
+// withFirst returns a legacyConn that yields first before receiving from conn,
+// or conn if first is nil.
+func withFirst(conn Conn, first *wire.Envelope) Conn {
+	if first == nil {
+		return conn
+	}
+	return &legacyConn{Conn: conn, first: first}
+}
+
+// recvHello receives the peer's hello message and negotiates the protocol
+// version and features. If the peer sends another message instead, it is
+// returned as first together with the legacy protocol version.
+func recvHello(own *wire.HelloMsg, conn Conn) (_ *wire.HelloMsg, first *wire.Envelope, err error) {
+	e, err := conn.Recv()
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "receiving hello")
+	}
+	peer, ok := e.Msg.(*wire.HelloMsg)
+	if !ok {
+		return &wire.HelloMsg{Version: wire.LegacyProtocolVersion}, e, nil
+	}
+	if peer.Version < wire.MinProtocolVersion {
+		return nil, nil, errors.Errorf("peer protocol version %d too old, need at least %d", peer.Version, wire.MinProtocolVersion)
+	}
+	return &wire.HelloMsg{
+		Version:  min(own.Version, peer.Version),
+		Features: own.Features & peer.Features,
+	}, nil, nil
+}
+
+// legacyConn is the connection to a peer that does not support the protocol
+// negotiation. It returns the message that the peer sent in place of its
+// hello message before receiving from the underlying connection.
+type legacyConn struct {
+	Conn
+	first *wire.Envelope
+}
+
+// Recv receives an envelope from the peer.
+func (c *legacyConn) Recv() (*wire.Envelope, error) {
+	if e := c.first; e != nil {
+		c.first = nil
+		return e, nil
+	}
+	return c.Conn.Recv()
+}
+
+// negotiate runs the negotiation and closes the connection if it fails or
+// the context is done first. The results are only read if the negotiation
+// terminated, because it keeps running in the background otherwise.
+func negotiate(ctx context.Context, conn Conn, run func() (*wire.HelloMsg, error)) (*wire.HelloMsg, error) {
+	var negotiated *wire.HelloMsg
+	var err error
+	if !pkg.TerminatesCtx(ctx, func() { negotiated, err = run() }) {
+		conn.Close()
+		return nil, errors.WithMessage(ctx.Err(), "timeout")
+	} else if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return negotiated, nil
+}
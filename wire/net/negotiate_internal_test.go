@@ -0,0 +1,177 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	perunio "perun.network/go-perun/wire/perunio/serializer"
+	wiretest "perun.network/go-perun/wire/test"
+	ctxtest "polycry.pt/poly-go/context/test"
+	"polycry.pt/poly-go/test"
+)
+
+func TestNegotiate_Success(t *testing.T) {
+	rng := test.Prng(t)
+	conn0, conn1 := newPipeConnPair()
+	defer conn0.Close()
+	defer conn1.Close()
+	addr0, addr1 := wiretest.NewRandomAddress(rng), wiretest.NewRandomAddress(rng)
+	hello0 := &wire.HelloMsg{Version: wire.ProtocolVersion + 1, Features: wire.FeatureSubChannels | wire.FeatureWatcher}
+	hello1 := &wire.HelloMsg{Version: wire.ProtocolVersion, Features: wire.FeatureWatcher | wire.FeatureChannelSync}
+	expected := &wire.HelloMsg{Version: wire.ProtocolVersion, Features: wire.FeatureWatcher}
+
+	ct := test.NewConcurrent(t)
+	go ct.Stage("passive", func(t test.ConcT) {
+		negotiated, _, err := NegotiatePassive(context.Background(), addr1, addr0, hello1, conn1)
+		require.NoError(t, err)
+		assert.Equal(t, expected, negotiated)
+	})
+
+	negotiated, _, err := NegotiateActive(context.Background(), addr0, addr1, hello0, conn0)
+	require.NoError(t, err)
+	assert.Equal(t, expected, negotiated)
+	ct.Wait("passive")
+}
+
+func TestNegotiate_OldVersion(t *testing.T) {
+	rng := test.Prng(t)
+	conn := newMockConn()
+	conn.recvQueue <- wiretest.NewRandomEnvelope(rng, &wire.HelloMsg{Version: wire.MinProtocolVersion - 1})
+
+	negotiated, _, err := NegotiatePassive(context.Background(), wiretest.NewRandomAddress(rng), wiretest.NewRandomAddress(rng), testHello(), conn)
+	assert.Error(t, err)
+	assert.Nil(t, negotiated)
+	assert.True(t, conn.closed.IsSet(), "failed negotiation should close the connection")
+}
+
+func TestNegotiate_Legacy(t *testing.T) {
+	rng := test.Prng(t)
+	conn := newMockConn()
+	conn.sent = func(e *wire.Envelope) { t.Errorf("sent %v to a legacy peer", e.Msg) }
+	first := wiretest.NewRandomEnvelope(rng, wire.NewPingMsg())
+	conn.recvQueue <- first
+
+	negotiated, legacy, err := NegotiatePassive(context.Background(), wiretest.NewRandomAddress(rng), wiretest.NewRandomAddress(rng), testHello(), conn)
+	require.NoError(t, err)
+	assert.Equal(t, &wire.HelloMsg{Version: wire.LegacyProtocolVersion}, negotiated)
+	assert.False(t, conn.closed.IsSet())
+
+	// The message sent in place of the hello message is not lost.
+	e, err := legacy.Recv()
+	require.NoError(t, err)
+	assert.Same(t, first, e)
+}
+
+func TestNegotiate_ActiveLegacy(t *testing.T) {
+	rng := test.Prng(t)
+	conn := newMockConn()
+	var sent []wire.Msg
+	conn.sent = func(e *wire.Envelope) { sent = append(sent, e.Msg) }
+	first := wiretest.NewRandomEnvelope(rng, wire.NewPingMsg())
+	conn.recvQueue <- first
+
+	negotiated, legacy, err := NegotiateActive(context.Background(), wiretest.NewRandomAddress(rng), wiretest.NewRandomAddress(rng), testHello(), conn)
+	require.NoError(t, err)
+	assert.Equal(t, &wire.HelloMsg{Version: wire.LegacyProtocolVersion}, negotiated)
+	assert.Equal(t, []wire.Msg{testHello()}, sent)
+	assert.False(t, conn.closed.IsSet())
+
+	// The peer's first message is not lost.
+	e, err := legacy.Recv()
+	require.NoError(t, err)
+	assert.Same(t, first, e)
+}
+
+func TestRegistry_negotiatedFeatures(t *testing.T) {
+	rng := test.Prng(t)
+	id := wiretest.NewRandomAccountMap(rng, channel.TestBackendID)
+	remoteID := wiretest.NewRandomAccountMap(rng, channel.TestBackendID)
+	remoteAddr := wire.AddressMapfromAccountMap(remoteID)
+
+	r := NewEndpointRegistry(id, nilConsumer, newMockDialer(), perunio.Serializer())
+	r.SetFeatures(wire.FeatureSubChannels | wire.FeatureWatcher)
+	a, b := newPipeConnPair()
+
+	go func() {
+		err := ExchangeAddrsActive(context.Background(), remoteID, wire.AddressMapfromAccountMap(id), b)
+		if err != nil {
+			panic(err)
+		}
+		peerHello := &wire.HelloMsg{Version: wire.ProtocolVersion, Features: wire.SupportedFeatures &^ wire.FeatureWatcher}
+		_, _, err = NegotiateActive(context.Background(), remoteAddr, wire.AddressMapfromAccountMap(id), peerHello, b)
+		if err != nil {
+			panic(err)
+		}
+	}()
+
+	ctxtest.AssertTerminates(t, timeout, func() {
+		require.NoError(t, r.setupConn(a))
+	})
+	e := r.find(remoteAddr)
+	require.NotNil(t, e)
+	assert.Equal(t, wire.ProtocolVersion, e.ProtocolVersion())
+	assert.Equal(t, wire.FeatureSubChannels, e.Features())
+	assert.NoError(t, r.Close())
+}
+
+func TestRegistry_legacyPeer(t *testing.T) {
+	rng := test.Prng(t)
+	id := wiretest.NewRandomAccountMap(rng, channel.TestBackendID)
+	remoteID := wiretest.NewRandomAccountMap(rng, channel.TestBackendID)
+	remoteAddr := wire.AddressMapfromAccountMap(remoteID)
+
+	recv := wire.NewReceiver()
+	defer recv.Close()
+	r := NewEndpointRegistry(id, func(map[wallet.BackendID]wire.Address) wire.Consumer { return recv },
+		newMockDialer(), perunio.Serializer())
+	a, b := newPipeConnPair()
+
+	// The legacy peer sends its first message right after the address
+	// exchange.
+	msg, err := wire.NewAuthResponseMsg(remoteID, channel.TestBackendID)
+	require.NoError(t, err)
+	go func() {
+		err := ExchangeAddrsActive(context.Background(), remoteID, wire.AddressMapfromAccountMap(id), b)
+		if err != nil {
+			panic(err)
+		}
+		if err := b.Send(&wire.Envelope{Sender: remoteAddr, Recipient: wire.AddressMapfromAccountMap(id), Msg: msg}); err != nil {
+			panic(err)
+		}
+	}()
+
+	ctxtest.AssertTerminates(t, timeout, func() {
+		require.NoError(t, r.setupConn(a))
+	})
+	e := r.find(remoteAddr)
+	require.NotNil(t, e)
+	assert.Equal(t, wire.LegacyProtocolVersion, e.ProtocolVersion())
+	assert.Equal(t, wire.Features(0), e.Features())
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	env, err := recv.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, msg, env.Msg)
+	assert.NoError(t, r.Close())
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package noise
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	perunnet "perun.network/go-perun/wire/net"
+	perunio "perun.network/go-perun/wire/perunio/serializer"
+	wiretest "perun.network/go-perun/wire/test"
+)
+
+func TestBus(t *testing.T) {
+	const numClients = 4
+	const numMsgs = 5
+	const defaultTimeout = 1000 * time.Millisecond
+
+	// The dialers and listeners need the accounts of the buses, so they are
+	// created by the bus assigner.
+	var (
+		dialers []*Dialer
+		addrs   []map[wallet.BackendID]wire.Address
+		hosts   []string
+	)
+	wiretest.GenericBusTest(t, func(acc map[wallet.BackendID]wire.Account) (wire.Bus, wire.Bus) {
+		listener, err := NewListener(acc, "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { listener.Close() })
+		dialer, err := NewDialer(acc, defaultTimeout)
+		require.NoError(t, err)
+		t.Cleanup(func() { dialer.Close() })
+
+		addr, host := wire.AddressMapfromAccountMap(acc), listener.Addr().String()
+		for i := range dialers {
+			dialers[i].Register(addr, host)
+			dialer.Register(addrs[i], hosts[i])
+		}
+		dialer.Register(addr, host)
+		dialers, addrs, hosts = append(dialers, dialer), append(addrs, addr), append(hosts, host)
+
+		bus := perunnet.NewBus(acc, dialer, perunio.Serializer())
+		// Retransmit the envelopes that are lost on the duplicate connection
+		// that the registry closes if two clients dial each other at once.
+		bus.EnableAcks(perunnet.AckConfig{RetransmitInterval: 50 * time.Millisecond})
+		go bus.Listen(listener)
+		return bus, bus
+	}, numClients, numMsgs)
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package noise
+
+import (
+	"bytes"
+	"net"
+	"sync"
+
+	"github.com/flynn/noise"
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	wirenet "perun.network/go-perun/wire/net"
+	"perun.network/go-perun/wire/perunio"
+	"polycry.pt/poly-go/sync/atomic"
+)
+
+var _ wirenet.AuthenticatedConn = (*conn)(nil)
+
+// conn is a connection whose envelopes are encrypted with the cipher states
+// of a completed Noise handshake. Each envelope is sent in one or more
+// length-prefixed frames.
+type conn struct {
+	closed     atomic.Bool
+	raw        net.Conn
+	serializer wire.EnvelopeSerializer
+	limits     perunio.Limits
+	peer       map[wallet.BackendID]wire.Address
+
+	sendMutex sync.Mutex         // Serializes Send.
+	send      *noise.CipherState // Encrypts sent frames.
+	recv      *noise.CipherState // Decrypts received frames.
+	frame     []byte             // Buffer for received frames.
+	plain     []byte             // Decrypted data that was not read yet.
+}
+
+func newConn(raw net.Conn, s *session, serializer wire.EnvelopeSerializer, limits perunio.Limits) *conn {
+	return &conn{
+		raw:        raw,
+		serializer: serializer,
+		limits:     limits,
+		peer:       s.peer,
+		send:       s.send,
+		recv:       s.recv,
+		frame:      make([]byte, noise.MaxMsgLen),
+	}
+}
+
+// PeerAddress returns the Perun addresses that the peer authenticated in the
+// handshake.
+func (c *conn) PeerAddress() map[wallet.BackendID]wire.Address {
+	return c.peer
+}
+
+func (c *conn) Send(e *wire.Envelope) error {
+	var buf bytes.Buffer
+	if err := c.serializer.Encode(&buf, e); err != nil {
+		c.raw.Close()
+		return err
+	}
+
+	c.sendMutex.Lock()
+	defer c.sendMutex.Unlock()
+
+	var out []byte
+	for data := buf.Bytes(); len(data) > 0; {
+		n := min(len(data), maxPlaintextLen)
+		ciphertext, err := c.send.Encrypt(nil, nil, data[:n])
+		if err != nil {
+			c.raw.Close()
+			return errors.Wrap(err, "encrypting envelope")
+		}
+		out = appendFrame(out, ciphertext)
+		data = data[n:]
+	}
+	if _, err := c.raw.Write(out); err != nil {
+		c.raw.Close()
+		return errors.Wrap(err, "writing envelope")
+	}
+	return nil
+}
+
+func (c *conn) Recv() (*wire.Envelope, error) {
+	e, err := c.serializer.Decode(perunio.NewLimitedReader(c, c.limits))
+	if err != nil {
+		c.raw.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+// Read reads decrypted data. It is only used by Recv.
+func (c *conn) Read(p []byte) (int, error) {
+	for len(c.plain) == 0 {
+		frame, err := readFrame(c.raw, c.frame)
+		if err != nil {
+			return 0, err
+		}
+		if c.plain, err = c.recv.Decrypt(c.plain[:0], nil, frame); err != nil {
+			return 0, errors.Wrap(err, "decrypting frame")
+		}
+	}
+	n := copy(p, c.plain)
+	c.plain = c.plain[n:]
+	return n, nil
+}
+
+func (c *conn) Close() error {
+	if !c.closed.TrySet() {
+		return errors.New("already closed")
+	}
+	return c.raw.Close()
+}
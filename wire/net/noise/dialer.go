@@ -0,0 +1,137 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package noise
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	wirenet "perun.network/go-perun/wire/net"
+	"perun.network/go-perun/wire/perunio"
+	pkgsync "polycry.pt/poly-go/sync"
+)
+
+// Dialer is a lookup-table based dialer that can dial known peers over TCP
+// and authenticates them in a Noise handshake. New peer addresses can be
+// added via Register().
+type Dialer struct {
+	pkgsync.Closer
+
+	identity *identity               // Static key and handshake payload.
+	mutex    sync.RWMutex            // Protects peers and limits.
+	peers    map[wire.AddrKey]string // Known peer addresses.
+	dialer   net.Dialer              // Used to dial connections.
+	timeout  time.Duration           // Timeout of the handshake.
+	limits   perunio.Limits          // Limits of received envelopes.
+}
+
+var _ wirenet.Dialer = (*Dialer)(nil)
+
+// NewDialer creates a new dialer with a preset default timeout for dialing
+// and the handshake. Leaving the timeout as 0 will result in no timeouts. The
+// dialer authenticates itself with the passed accounts, which must be the
+// accounts of the wire.Bus that uses the dialer.
+func NewDialer(id map[wallet.BackendID]wire.Account, defaultTimeout time.Duration) (*Dialer, error) {
+	ident, err := newIdentity(id)
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating handshake identity")
+	}
+
+	return &Dialer{
+		identity: ident,
+		peers:    make(map[wire.AddrKey]string),
+		dialer:   net.Dialer{Timeout: defaultTimeout},
+		timeout:  defaultTimeout,
+		limits:   perunio.DefaultLimits(),
+	}, nil
+}
+
+// Dial implements Dialer.Dial(). It fails with an AuthenticationError if the
+// peer does not authenticate itself with the requested addresses.
+func (d *Dialer) Dial(ctx context.Context, addr map[wallet.BackendID]wire.Address, ser wire.EnvelopeSerializer) (wirenet.Conn, error) {
+	host, ok := d.host(wire.Keys(addr))
+	if !ok {
+		return nil, errors.New("peer not found")
+	}
+
+	// To combine the provided context with the Dialer's Closer as specified by
+	// the Dialer interface, we have to use some goroutine trickery.
+	wrappedCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-d.Closed():
+			cancel()
+		case <-wrappedCtx.Done():
+		}
+	}()
+
+	raw, err := d.dialer.DialContext(wrappedCtx, "tcp", host)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dial peer")
+	}
+
+	if d.timeout > 0 {
+		raw.SetDeadline(time.Now().Add(d.timeout)) //nolint:errcheck
+	}
+	// Abort the handshake by letting it time out if the context is done.
+	stop := context.AfterFunc(wrappedCtx, func() {
+		raw.SetDeadline(time.Unix(1, 0)) //nolint:errcheck
+	})
+	s, err := handshake(raw, d.identity, true, addr)
+	if !stop() {
+		raw.Close()
+		return nil, errors.Wrap(wrappedCtx.Err(), "handshake aborted")
+	} else if err != nil {
+		raw.Close()
+		return nil, errors.WithMessage(err, "handshake failed")
+	}
+	raw.SetDeadline(time.Time{}) //nolint:errcheck
+
+	d.mutex.RLock()
+	limits := d.limits
+	d.mutex.RUnlock()
+	return newConn(raw, s, ser, limits), nil
+}
+
+// SetLimits sets the limits that are enforced on the envelopes received over
+// dialed connections. The default limits are perunio.DefaultLimits.
+func (d *Dialer) SetLimits(limits perunio.Limits) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.limits = limits
+}
+
+// Register registers a network address for a peer address.
+func (d *Dialer) Register(addr map[wallet.BackendID]wire.Address, address string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.peers[wire.Keys(addr)] = address
+}
+
+func (d *Dialer) host(key wire.AddrKey) (string, bool) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	host, ok := d.peers[key]
+	return host, ok
+}
@@ -0,0 +1,167 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package noise
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wire"
+	wirenet "perun.network/go-perun/wire/net"
+	perunio "perun.network/go-perun/wire/perunio/serializer"
+	wiretest "perun.network/go-perun/wire/test"
+	ctxtest "polycry.pt/poly-go/context/test"
+	"polycry.pt/poly-go/test"
+)
+
+func TestNewDialer(t *testing.T) {
+	d, err := NewDialer(nil, 0)
+	assert.Error(t, err)
+	assert.Nil(t, d)
+}
+
+func TestDialer_Register(t *testing.T) {
+	rng := test.Prng(t)
+	addr := wiretest.NewRandomAddress(rng)
+	key := wire.Keys(addr)
+	d, err := NewDialer(wiretest.NewRandomAccountMap(rng, channel.TestBackendID), 0)
+	require.NoError(t, err)
+
+	_, ok := d.host(key)
+	require.False(t, ok)
+
+	d.Register(addr, "host")
+
+	host, ok := d.host(key)
+	assert.True(t, ok)
+	assert.Equal(t, "host", host)
+}
+
+func TestDialer_Dial(t *testing.T) {
+	timeout := 500 * time.Millisecond
+	rng := test.Prng(t)
+	lacc := wiretest.NewRandomAccountMap(rng, channel.TestBackendID)
+	laddr := wire.AddressMapfromAccountMap(lacc)
+
+	l, err := NewListener(lacc, "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	lhost := l.Addr().String()
+
+	ser := perunio.Serializer()
+	dacc := wiretest.NewRandomAccountMap(rng, channel.TestBackendID)
+	daddr := wire.AddressMapfromAccountMap(dacc)
+	d, err := NewDialer(dacc, timeout)
+	require.NoError(t, err)
+	d.Register(laddr, lhost)
+	defer d.Close()
+
+	// echo dials the listener, sends the envelope and waits for it to be
+	// echoed back.
+	echo := func(t *testing.T, e *wire.Envelope) {
+		t.Helper()
+		ct := test.NewConcurrent(t)
+		go ct.Stage("accept", func(rt test.ConcT) {
+			conn, err := l.Accept(ser)
+			require.NoError(rt, err)
+			assert.True(rt, channel.EqualWireMaps(daddr, conn.(wirenet.AuthenticatedConn).PeerAddress()))
+
+			re, err := conn.Recv()
+			require.NoError(rt, err)
+			assert.Equal(rt, e, re)
+			require.NoError(rt, conn.Send(e))
+		})
+
+		ct.Stage("dial", func(rt test.ConcT) {
+			ctxtest.AssertTerminates(t, timeout, func() {
+				conn, err := d.Dial(context.Background(), laddr, ser)
+				require.NoError(rt, err)
+				assert.True(rt, channel.EqualWireMaps(laddr, conn.(wirenet.AuthenticatedConn).PeerAddress()))
+
+				require.NoError(rt, conn.Send(e))
+				re, err := conn.Recv()
+				require.NoError(rt, err)
+				assert.Equal(rt, e, re)
+				require.NoError(rt, conn.Close())
+			})
+		})
+
+		ct.Wait("dial", "accept")
+	}
+
+	t.Run("happy", func(t *testing.T) {
+		echo(t, &wire.Envelope{
+			Sender:    daddr,
+			Recipient: laddr,
+			Msg:       wire.NewPingMsg(),
+		})
+	})
+
+	t.Run("multiple frames", func(t *testing.T) {
+		reason := strings.Repeat("x", maxPlaintextLen)
+		echo(t, &wire.Envelope{
+			Sender:    daddr,
+			Recipient: laddr,
+			Msg:       &wire.ShutdownMsg{Reason: reason},
+		})
+	})
+
+	t.Run("wrong identity", func(t *testing.T) {
+		otherAddr := wiretest.NewRandomAddress(rng)
+		d.Register(otherAddr, lhost)
+
+		ctxtest.AssertTerminates(t, timeout, func() {
+			conn, err := d.Dial(context.Background(), otherAddr, ser)
+			assert.Nil(t, conn)
+			assert.True(t, wirenet.IsAuthenticationError(err))
+		})
+	})
+
+	t.Run("aborted context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		ctxtest.AssertTerminates(t, timeout, func() {
+			conn, err := d.Dial(ctx, laddr, ser)
+			assert.Nil(t, conn)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("unknown host", func(t *testing.T) {
+		noHostAddr := wiretest.NewRandomAddress(rng)
+		d.Register(noHostAddr, "no such host")
+
+		ctxtest.AssertTerminates(t, timeout, func() {
+			conn, err := d.Dial(context.Background(), noHostAddr, ser)
+			assert.Nil(t, conn)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("unknown address", func(t *testing.T) {
+		ctxtest.AssertTerminates(t, timeout, func() {
+			unkownAddr := wiretest.NewRandomAddress(rng)
+			conn, err := d.Dial(context.Background(), unkownAddr, ser)
+			assert.Error(t, err)
+			assert.Nil(t, conn)
+		})
+	})
+}
@@ -0,0 +1,20 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package noise contains an implementation of the wire.Dialer and
+// wire.Listener interfaces that encrypts the envelopes with the Noise
+// protocol framework over TCP. Instead of TLS certificates, the peers
+// authenticate each other in the Noise XX handshake with their wire.Account
+// keys, so the connections do not need the address exchange protocol.
+package noise // import "perun.network/go-perun/wire/net/noise"
@@ -0,0 +1,261 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package noise
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+
+	"github.com/flynn/noise"
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	wirenet "perun.network/go-perun/wire/net"
+	"perun.network/go-perun/wire/perunio"
+)
+
+const (
+	// prologue is mixed into the handshake, so that it fails against peers
+	// that run a different protocol.
+	prologue = "perun-noise-1"
+	// staticKeyPrefix is prepended to the static Noise key that the wire
+	// accounts sign, so that the signatures are not valid in another context.
+	staticKeyPrefix = "perun-noise-static-key:"
+	// maxPlaintextLen is the maximum length of the plaintext of a frame, which
+	// leaves room for the authentication tag of the cipher.
+	maxPlaintextLen = noise.MaxMsgLen - 16
+)
+
+var cipherSuite = noise.NewCipherSuite(noise.DH25519, noise.CipherChaChaPoly, noise.HashSHA256)
+
+type (
+	// identity is the local side of the handshake. It consists of a static
+	// Noise key and the payload that binds the key to the Perun addresses.
+	identity struct {
+		static  noise.DHKey
+		addr    map[wallet.BackendID]wire.Address
+		payload []byte
+	}
+
+	// session is the outcome of a completed handshake.
+	session struct {
+		send, recv *noise.CipherState
+		peer       map[wallet.BackendID]wire.Address
+	}
+)
+
+// newIdentity generates a static Noise key and signs it with every account.
+func newIdentity(id map[wallet.BackendID]wire.Account) (*identity, error) {
+	if len(id) == 0 {
+		return nil, errors.New("no accounts")
+	}
+	static, err := cipherSuite.GenerateKeypair(rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "generating static key")
+	}
+
+	msg := staticKeyMsg(static.Public)
+	sigs := make(map[wallet.BackendID][]byte, len(id))
+	for bid, acc := range id {
+		if sigs[bid], err = acc.Sign(msg); err != nil {
+			return nil, errors.WithMessagef(err, "signing static key for backend %d", bid)
+		}
+	}
+
+	addr := wire.AddressMapfromAccountMap(id)
+	var buf bytes.Buffer
+	if err := encodePayload(&buf, addr, sigs); err != nil {
+		return nil, errors.WithMessage(err, "encoding handshake payload")
+	}
+	return &identity{static: static, addr: addr, payload: buf.Bytes()}, nil
+}
+
+// handshake runs the Noise XX handshake over rw. The initiator expects the
+// responder to have the passed addresses and aborts the handshake before
+// revealing its own addresses otherwise.
+func handshake(rw io.ReadWriter, self *identity, initiator bool, expected map[wallet.BackendID]wire.Address) (*session, error) {
+	hs, err := noise.NewHandshakeState(noise.Config{
+		CipherSuite:   cipherSuite,
+		Pattern:       noise.HandshakeXX,
+		Initiator:     initiator,
+		Prologue:      []byte(prologue),
+		StaticKeypair: self.static,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "creating handshake state")
+	}
+
+	if initiator {
+		// -> e
+		if _, _, err := writeHandshakeMsg(rw, hs, nil); err != nil {
+			return nil, err
+		}
+		// <- e, ee, s, es
+		payload, _, _, err := readHandshakeMsg(rw, hs)
+		if err != nil {
+			return nil, err
+		}
+		peer, err := verifyPayload(payload, hs.PeerStatic())
+		if err != nil {
+			return nil, err
+		}
+		if !channel.EqualWireMaps(peer, expected) {
+			return nil, wirenet.NewAuthenticationError(peer, self.addr, self.addr, "responder has a different address")
+		}
+		// -> s, se
+		send, recv, err := writeHandshakeMsg(rw, hs, self.payload)
+		if err != nil {
+			return nil, err
+		}
+		return &session{send: send, recv: recv, peer: peer}, nil
+	}
+
+	// <- e
+	if _, _, _, err := readHandshakeMsg(rw, hs); err != nil {
+		return nil, err
+	}
+	// -> e, ee, s, es
+	if _, _, err := writeHandshakeMsg(rw, hs, self.payload); err != nil {
+		return nil, err
+	}
+	// <- s, se
+	payload, recv, send, err := readHandshakeMsg(rw, hs)
+	if err != nil {
+		return nil, err
+	}
+	peer, err := verifyPayload(payload, hs.PeerStatic())
+	if err != nil {
+		return nil, err
+	}
+	return &session{send: send, recv: recv, peer: peer}, nil
+}
+
+// writeHandshakeMsg writes the next handshake message. After the last
+// message, it returns the cipher states of the initiator and the responder.
+func writeHandshakeMsg(w io.Writer, hs *noise.HandshakeState, payload []byte) (*noise.CipherState, *noise.CipherState, error) {
+	msg, cs1, cs2, err := hs.WriteMessage(nil, payload)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "creating handshake message")
+	}
+	if _, err := w.Write(appendFrame(nil, msg)); err != nil {
+		return nil, nil, errors.Wrap(err, "writing handshake message")
+	}
+	return cs1, cs2, nil
+}
+
+// readHandshakeMsg reads the next handshake message and returns its payload.
+// After the last message, it also returns the cipher states of the initiator
+// and the responder.
+func readHandshakeMsg(r io.Reader, hs *noise.HandshakeState) ([]byte, *noise.CipherState, *noise.CipherState, error) {
+	msg, err := readFrame(r, make([]byte, noise.MaxMsgLen))
+	if err != nil {
+		return nil, nil, nil, errors.WithMessage(err, "reading handshake message")
+	}
+	payload, cs1, cs2, err := hs.ReadMessage(nil, msg)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "processing handshake message")
+	}
+	return payload, cs1, cs2, nil
+}
+
+// appendFrame appends the length-prefixed frame of msg to b.
+func appendFrame(b, msg []byte) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(msg))) //nolint:gosec // Noise messages are at most noise.MaxMsgLen bytes long.
+	return append(b, msg...)
+}
+
+// readFrame reads a length-prefixed frame into buf, which must be able to
+// hold noise.MaxMsgLen bytes.
+func readFrame(r io.Reader, buf []byte) ([]byte, error) {
+	var length uint16
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, errors.Wrap(err, "reading frame length")
+	}
+	if _, err := io.ReadFull(r, buf[:length]); err != nil {
+		return nil, errors.Wrap(err, "reading frame")
+	}
+	return buf[:length], nil
+}
+
+// staticKeyMsg returns the message that is signed to bind a static Noise key
+// to a Perun address.
+func staticKeyMsg(static []byte) []byte {
+	return append([]byte(staticKeyPrefix), static...)
+}
+
+// encodePayload encodes the Perun addresses and the signatures of the static
+// key into a handshake payload.
+func encodePayload(w io.Writer, addr map[wallet.BackendID]wire.Address, sigs map[wallet.BackendID][]byte) error {
+	if err := perunio.Encode(w, wire.AddressDecMap(addr), uint16(len(sigs))); err != nil { //nolint:gosec // There is one signature per backend.
+		return err
+	}
+	for bid, sig := range sigs {
+		if len(sig) > noise.MaxMsgLen {
+			return errors.Errorf("signature for backend %d too long", bid)
+		}
+		if err := perunio.Encode(w, int32(bid), uint16(len(sig)), sig); err != nil { //nolint:gosec // Lengths are checked above.
+			return errors.WithMessagef(err, "encoding signature for backend %d", bid)
+		}
+	}
+	return nil
+}
+
+// verifyPayload decodes a handshake payload and verifies that the signatures
+// bind the static key of the peer to all of its Perun addresses.
+func verifyPayload(payload, static []byte) (map[wallet.BackendID]wire.Address, error) {
+	r := bytes.NewReader(payload)
+	var (
+		addr    wire.AddressDecMap
+		numSigs uint16
+	)
+	if err := perunio.Decode(r, &addr, &numSigs); err != nil {
+		return nil, errors.WithMessage(err, "decoding handshake payload")
+	}
+	if len(addr) == 0 {
+		return nil, errors.New("peer sent no addresses")
+	}
+
+	sigs := make(map[wallet.BackendID][]byte)
+	for range numSigs {
+		var (
+			bid    int32
+			sigLen uint16
+		)
+		if err := perunio.Decode(r, &bid, &sigLen); err != nil {
+			return nil, errors.WithMessage(err, "decoding signature")
+		}
+		sig := make([]byte, sigLen)
+		if err := perunio.Decode(r, &sig); err != nil {
+			return nil, errors.WithMessage(err, "decoding signature")
+		}
+		sigs[wallet.BackendID(bid)] = sig
+	}
+
+	msg := staticKeyMsg(static)
+	for bid, a := range addr {
+		sig, ok := sigs[bid]
+		if !ok {
+			return nil, errors.Errorf("missing signature for backend %d", bid)
+		}
+		if err := a.Verify(msg, sig); err != nil {
+			return nil, errors.WithMessagef(err, "verifying signature for backend %d", bid)
+		}
+	}
+	return addr, nil
+}
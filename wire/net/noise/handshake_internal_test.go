@@ -0,0 +1,105 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package noise
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	wirenet "perun.network/go-perun/wire/net"
+	wiretest "perun.network/go-perun/wire/test"
+	"polycry.pt/poly-go/test"
+)
+
+func TestHandshake(t *testing.T) {
+	rng := test.Prng(t)
+	newIdent := func() *identity {
+		ident, err := newIdentity(wiretest.NewRandomAccountMap(rng, channel.TestBackendID))
+		require.NoError(t, err)
+		return ident
+	}
+	// run runs the handshake between the initiator and the responder over a
+	// pipe.
+	run := func(initiator, responder *identity, expected map[wallet.BackendID]wire.Address) (is, rs *session, ierr, rerr error) {
+		a, b := net.Pipe()
+		defer a.Close()
+		defer b.Close()
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			rs, rerr = handshake(b, responder, false, nil)
+			if rerr != nil {
+				b.Close()
+			}
+		}()
+		is, ierr = handshake(a, initiator, true, expected)
+		if ierr != nil {
+			a.Close()
+		}
+		<-done
+		return is, rs, ierr, rerr
+	}
+
+	t.Run("happy", func(t *testing.T) {
+		alice, bob := newIdent(), newIdent()
+		is, rs, ierr, rerr := run(alice, bob, bob.addr)
+		require.NoError(t, ierr)
+		require.NoError(t, rerr)
+		assert.True(t, channel.EqualWireMaps(bob.addr, is.peer))
+		assert.True(t, channel.EqualWireMaps(alice.addr, rs.peer))
+
+		// The cipher states of both directions match.
+		msg := []byte("hello")
+		ciphertext, err := is.send.Encrypt(nil, nil, msg)
+		require.NoError(t, err)
+		plaintext, err := rs.recv.Decrypt(nil, nil, ciphertext)
+		require.NoError(t, err)
+		assert.Equal(t, msg, plaintext)
+		ciphertext, err = rs.send.Encrypt(nil, nil, msg)
+		require.NoError(t, err)
+		plaintext, err = is.recv.Decrypt(nil, nil, ciphertext)
+		require.NoError(t, err)
+		assert.Equal(t, msg, plaintext)
+	})
+
+	t.Run("unexpected responder", func(t *testing.T) {
+		alice, bob, carol := newIdent(), newIdent(), newIdent()
+		_, _, ierr, rerr := run(alice, bob, carol.addr)
+		assert.True(t, wirenet.IsAuthenticationError(ierr))
+		// The initiator does not reveal its addresses to the wrong peer.
+		assert.Error(t, rerr)
+	})
+
+	t.Run("stolen payload", func(t *testing.T) {
+		alice, bob, carol := newIdent(), newIdent(), newIdent()
+		// Carol claims to be Bob, but cannot sign her static key as Bob.
+		carol.payload = bob.payload
+		_, _, ierr, rerr := run(alice, carol, bob.addr)
+		assert.ErrorContains(t, ierr, "verifying signature")
+		assert.Error(t, rerr)
+	})
+}
+
+func TestNewIdentity(t *testing.T) {
+	_, err := newIdentity(nil)
+	assert.Error(t, err)
+}
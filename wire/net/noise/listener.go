@@ -0,0 +1,142 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package noise
+
+import (
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	wirenet "perun.network/go-perun/wire/net"
+	"perun.network/go-perun/wire/perunio"
+	pkgsync "polycry.pt/poly-go/sync"
+)
+
+// handshakeTimeout limits the time that an accepted connection has for
+// completing the handshake.
+const handshakeTimeout = 10 * time.Second
+
+// Listener is a TCP listener that authenticates the peers in a Noise
+// handshake.
+type Listener struct {
+	pkgsync.Closer
+
+	identity *identity      // Static key and handshake payload.
+	listener net.Listener   // Accepts the TCP connections.
+	conns    chan accepted  // Connections that wait for Accept.
+	limits   perunio.Limits // Limits of received envelopes.
+}
+
+// accepted is a TCP connection that completed the handshake.
+type accepted struct {
+	raw     net.Conn
+	session *session
+}
+
+var _ wirenet.Listener = (*Listener)(nil)
+
+// NewListener creates a listener reachable under the requested TCP address.
+// The peers are authenticated with the passed accounts, which must be the
+// accounts of the wire.Bus that uses the listener.
+func NewListener(id map[wallet.BackendID]wire.Account, address string) (*Listener, error) {
+	ident, err := newIdentity(id)
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating handshake identity")
+	}
+
+	ln, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, errors.Wrapf(err,
+			"failed to create listener for '%s'", address)
+	}
+
+	l := &Listener{
+		identity: ident,
+		listener: ln,
+		conns:    make(chan accepted),
+		limits:   perunio.DefaultLimits(),
+	}
+	go l.acceptLoop()
+	return l, nil
+}
+
+// Addr returns the network address of the listener.
+func (l *Listener) Addr() net.Addr {
+	return l.listener.Addr()
+}
+
+// Accept implements peer.Listener.Accept().
+func (l *Listener) Accept(ser wire.EnvelopeSerializer) (wirenet.Conn, error) {
+	select {
+	case a := <-l.conns:
+		return newConn(a.raw, a.session, ser, l.limits), nil
+	case <-l.Closed():
+		return nil, errors.New("accept failed: listener closed")
+	}
+}
+
+// Close closes the listener. Repeated calls result in an error.
+func (l *Listener) Close() error {
+	if err := l.Closer.Close(); err != nil {
+		return err
+	}
+	return errors.Wrap(l.listener.Close(), "closing listener")
+}
+
+// SetLimits sets the limits that are enforced on the envelopes received over
+// accepted connections. The default limits are perunio.DefaultLimits. It must
+// not be called concurrently with Accept.
+func (l *Listener) SetLimits(limits perunio.Limits) {
+	l.limits = limits
+}
+
+// acceptLoop accepts TCP connections until the listener is closed. The
+// handshakes run concurrently so that a slow peer does not block the others.
+func (l *Listener) acceptLoop() {
+	for {
+		raw, err := l.listener.Accept()
+		if err != nil {
+			if !l.IsClosed() {
+				log.WithError(err).Error("Accepting TCP connections failed")
+				l.Close()
+			}
+			return
+		}
+		go l.setup(raw)
+	}
+}
+
+// setup runs the handshake as the responder and passes the connection on to
+// Accept.
+func (l *Listener) setup(raw net.Conn) {
+	raw.SetDeadline(time.Now().Add(handshakeTimeout)) //nolint:errcheck
+	s, err := handshake(raw, l.identity, false, nil)
+	if err != nil {
+		log.WithError(err).Debug("Noise handshake failed")
+		raw.Close()
+		return
+	}
+	raw.SetDeadline(time.Time{}) //nolint:errcheck
+
+	select {
+	case l.conns <- accepted{raw: raw, session: s}:
+	case <-l.Closed():
+		raw.Close()
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	perunnet "perun.network/go-perun/wire/net"
+	perunio "perun.network/go-perun/wire/perunio/serializer"
+	wiretest "perun.network/go-perun/wire/test"
+)
+
+func TestBus(t *testing.T) {
+	const numClients = 4
+	const numMsgs = 5
+	const defaultTimeout = 1000 * time.Millisecond
+
+	commonName := "127.0.0.1"
+	sans := []string{"127.0.0.1", "localhost"}
+	tlsConfigs, err := generateSelfSignedCertConfigs(commonName, sans, numClients)
+	require.NoError(t, err)
+
+	dialers := make([]*Dialer, numClients)
+	for j := range numClients {
+		dialers[j] = NewDialer(defaultTimeout, tlsConfigs[j])
+		defer dialers[j].Close()
+	}
+
+	listeners := make([]*Listener, numClients)
+	hosts := make([]string, numClients)
+	for j := range numClients {
+		listener, err := NewListener("127.0.0.1:0", tlsConfigs[j])
+		require.NoError(t, err)
+		listeners[j] = listener
+		hosts[j] = listener.Addr().String()
+		defer listeners[j].Close()
+	}
+
+	i := 0
+	wiretest.GenericBusTest(t, func(acc map[wallet.BackendID]wire.Account) (wire.Bus, wire.Bus) {
+		for j := range numClients {
+			dialers[j].Register(
+				wire.AddressMapfromAccountMap(acc),
+				hosts[i],
+			)
+		}
+
+		bus := perunnet.NewBus(acc, dialers[i], perunio.Serializer())
+		// When two clients dial each other concurrently, the registry closes
+		// one of the connections. Envelopes that were sent over it but not yet
+		// received are lost, which is more likely with the slower QUIC
+		// handshakes than with TCP, so they are retransmitted.
+		bus.EnableAcks(perunnet.AckConfig{RetransmitInterval: 50 * time.Millisecond})
+		go bus.Listen(listeners[i])
+		i++
+		return bus, bus
+	}, numClients, numMsgs)
+}
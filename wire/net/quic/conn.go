@@ -0,0 +1,213 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quic
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	quicgo "github.com/quic-go/quic-go"
+
+	"perun.network/go-perun/wire"
+	wirenet "perun.network/go-perun/wire/net"
+	"perun.network/go-perun/wire/perunio"
+	"polycry.pt/poly-go/sync/atomic"
+)
+
+// lingerTimeout limits how long a closed connection waits for the peer to
+// receive the remaining data.
+const lingerTimeout = 5 * time.Second
+
+var _ wirenet.Conn = (*conn)(nil)
+
+// conn is a connection that transports envelopes over a single bidirectional
+// QUIC stream.
+type conn struct {
+	closed     atomic.Bool
+	serializer wire.EnvelopeSerializer
+	limits     perunio.Limits
+
+	sendMutex sync.Mutex             // Serializes writes to the stream.
+	mutex     sync.Mutex             // Protects the following fields.
+	qconn     quicgo.Connection      // Underlying QUIC connection.
+	stream    quicgo.Stream          // Stream that carries the envelopes.
+	early     quicgo.EarlyConnection // Set while data may be sent as 0-RTT data.
+	earlyData [][]byte               // Envelopes sent as 0-RTT data.
+}
+
+func newConn(qconn quicgo.Connection, stream quicgo.Stream, serializer wire.EnvelopeSerializer, limits perunio.Limits) *conn {
+	return &conn{
+		serializer: serializer,
+		limits:     limits,
+		qconn:      qconn,
+		stream:     stream,
+	}
+}
+
+// newEarlyConn creates a connection on a dialed QUIC connection whose
+// handshake may not be complete yet. Envelopes that are sent before the
+// handshake completes are kept, so that they can be sent again if the server
+// rejects the 0-RTT data.
+func newEarlyConn(early quicgo.EarlyConnection, stream quicgo.Stream, serializer wire.EnvelopeSerializer, limits perunio.Limits) *conn {
+	c := newConn(early, stream, serializer, limits)
+	c.early = early
+	return c
+}
+
+func (c *conn) Send(e *wire.Envelope) error {
+	c.sendMutex.Lock()
+	defer c.sendMutex.Unlock()
+
+	var buf bytes.Buffer
+	if err := c.serializer.Encode(&buf, e); err != nil {
+		c.abort()
+		return err
+	}
+
+	_, err := c.currentForSend(buf.Bytes()).Write(buf.Bytes())
+	if errors.Is(err, quicgo.Err0RTTRejected) {
+		// The envelope is sent again by recoverRejection.
+		err = c.recoverRejection()
+	}
+	if err != nil {
+		c.abort()
+		return errors.Wrap(err, "writing envelope")
+	}
+	return nil
+}
+
+func (c *conn) Recv() (*wire.Envelope, error) {
+	e, err := c.serializer.Decode(perunio.NewLimitedReader(c.current(), c.limits))
+	if errors.Is(err, quicgo.Err0RTTRejected) {
+		// The server discarded all 0-RTT data, so it did not reply yet.
+		c.sendMutex.Lock()
+		err = c.recoverRejection()
+		c.sendMutex.Unlock()
+		if err == nil {
+			e, err = c.serializer.Decode(perunio.NewLimitedReader(c.current(), c.limits))
+		}
+	}
+	if err != nil {
+		c.abort()
+		return nil, err
+	}
+	return e, nil
+}
+
+// Close closes the stream and interrupts ongoing Send and Recv calls. Unlike
+// abort, it lets the peer receive the data that was already sent before the
+// QUIC connection is closed.
+func (c *conn) Close() error {
+	if !c.closed.TrySet() {
+		return errors.New("already closed")
+	}
+
+	c.mutex.Lock()
+	qconn, stream := c.qconn, c.stream
+	c.mutex.Unlock()
+
+	stream.CancelRead(0)
+	stream.SetWriteDeadline(time.Now()) //nolint:errcheck
+	c.sendMutex.Lock()
+	err := stream.Close()
+	c.sendMutex.Unlock()
+
+	go func() {
+		// The peer closes the connection once it received the end of stream.
+		select {
+		case <-qconn.Context().Done():
+		case <-time.After(lingerTimeout):
+		}
+		qconn.CloseWithError(0, "connection closed") //nolint:errcheck
+	}()
+	return errors.Wrap(err, "closing stream")
+}
+
+// current returns the stream that carries the envelopes.
+func (c *conn) current() quicgo.Stream {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.stream
+}
+
+// currentForSend returns the stream that carries the envelopes and keeps the
+// data if it may be sent as 0-RTT data.
+func (c *conn) currentForSend(data []byte) quicgo.Stream {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.early != nil {
+		select {
+		case <-c.early.HandshakeComplete():
+			if c.early.ConnectionState().Used0RTT {
+				// The server accepted the 0-RTT data.
+				c.early, c.earlyData = nil, nil
+				return c.stream
+			}
+		default:
+		}
+		c.earlyData = append(c.earlyData, data)
+	}
+	return c.stream
+}
+
+// recoverRejection switches to the connection that is established after the
+// server rejected the 0-RTT data and sends the envelopes again that were sent
+// as 0-RTT data. It is called by Send and Recv, so only the first call
+// switches the connection. The caller must hold sendMutex.
+func (c *conn) recoverRejection() error {
+	c.mutex.Lock()
+	early, earlyData := c.early, c.earlyData
+	c.mutex.Unlock()
+	if early == nil {
+		return nil
+	}
+
+	// NextConnection returns once the handshake completed or failed, or the
+	// connection was closed by abort.
+	next, err := early.NextConnection(context.Background())
+	if err != nil {
+		return errors.Wrap(err, "establishing connection after 0-RTT rejection")
+	}
+	stream, err := next.OpenStream()
+	if err != nil {
+		return errors.Wrap(err, "opening stream after 0-RTT rejection")
+	}
+	for _, data := range earlyData {
+		if _, err := stream.Write(data); err != nil {
+			return errors.Wrap(err, "resending 0-RTT data")
+		}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.qconn, c.stream, c.early, c.earlyData = next, stream, nil, nil
+	return nil
+}
+
+// abort closes the QUIC connection, which interrupts ongoing Send and Recv
+// calls. It does nothing if the connection was closed by Close, so that the
+// errors caused by Close do not discard the data that was already sent.
+func (c *conn) abort() {
+	if c.closed.IsSet() {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.qconn.CloseWithError(0, "connection closed") //nolint:errcheck
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quic
+
+import (
+	"context"
+	"crypto/tls"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	quicgo "github.com/quic-go/quic-go"
+
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	wirenet "perun.network/go-perun/wire/net"
+	"perun.network/go-perun/wire/perunio"
+	pkgsync "polycry.pt/poly-go/sync"
+)
+
+// alpn is the application protocol that is negotiated in the TLS handshake.
+const alpn = "perun"
+
+// Dialer is a lookup-table based dialer that can dial known peers over QUIC.
+// New peer addresses can be added via Register().
+type Dialer struct {
+	pkgsync.Closer
+
+	mutex     sync.RWMutex            // Protects peers and limits.
+	peers     map[wire.AddrKey]string // Known peer addresses.
+	tlsConfig *tls.Config             // Used to authenticate the peers.
+	config    *quicgo.Config          // Used to dial connections.
+	limits    perunio.Limits          // Limits of received envelopes.
+}
+
+var _ wirenet.Dialer = (*Dialer)(nil)
+
+// NewDialer creates a new QUIC dialer. The timeout limits the duration of the
+// handshake, leaving it as 0 results in the QUIC default timeout. Like in
+// package simple, the peers are authenticated via the TLS config, whose
+// server name defaults to the host of the dialed address.
+//
+// If the TLS config has no session cache, a new one is created, so that
+// connections to known peers are resumed and send their first envelopes as
+// 0-RTT data.
+func NewDialer(defaultTimeout time.Duration, tlsConfig *tls.Config) *Dialer {
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS13}
+	}
+	tlsConfig = tlsConfig.Clone()
+	tlsConfig.NextProtos = []string{alpn}
+	if tlsConfig.ClientSessionCache == nil {
+		tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(0)
+	}
+
+	return &Dialer{
+		peers:     make(map[wire.AddrKey]string),
+		tlsConfig: tlsConfig,
+		config:    &quicgo.Config{HandshakeIdleTimeout: defaultTimeout},
+		limits:    perunio.DefaultLimits(),
+	}
+}
+
+// Dial implements Dialer.Dial().
+func (d *Dialer) Dial(ctx context.Context, addr map[wallet.BackendID]wire.Address, ser wire.EnvelopeSerializer) (wirenet.Conn, error) {
+	done := make(chan struct{})
+	defer close(done)
+
+	host, ok := d.host(wire.Keys(addr))
+	if !ok {
+		return nil, errors.New("peer not found")
+	}
+
+	// To combine the provided context with the Dialer's Closer as specified by
+	// the Dialer interface, we have to use some goroutine trickery.
+	wrappedCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		defer cancel()
+
+		select {
+		case <-d.Closed():
+		case <-done:
+		}
+	}()
+
+	tlsConfig := d.tlsConfig.Clone()
+	tlsConfig.ClientSessionCache = &sessionCache{cache: d.tlsConfig.ClientSessionCache, host: host}
+	qconn, err := quicgo.DialAddrEarly(wrappedCtx, host, tlsConfig, d.config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dial peer")
+	}
+
+	d.mutex.RLock()
+	limits := d.limits
+	d.mutex.RUnlock()
+
+	select {
+	case <-qconn.HandshakeComplete():
+	default:
+		stream, err := qconn.OpenStream()
+		if err == nil {
+			return newEarlyConn(qconn, stream, ser, limits), nil
+		} else if !errors.Is(err, quicgo.Err0RTTRejected) {
+			qconn.CloseWithError(0, "opening stream failed") //nolint:errcheck
+			return nil, errors.Wrap(err, "failed to open stream")
+		}
+	}
+
+	// The handshake completed before any data was sent, so no data is sent
+	// as 0-RTT data.
+	next, err := qconn.NextConnection(wrappedCtx)
+	if err != nil {
+		qconn.CloseWithError(0, "handshake failed") //nolint:errcheck
+		return nil, errors.Wrap(err, "failed to establish connection")
+	}
+	stream, err := next.OpenStream()
+	if err != nil {
+		next.CloseWithError(0, "opening stream failed") //nolint:errcheck
+		return nil, errors.Wrap(err, "failed to open stream")
+	}
+	return newConn(next, stream, ser, limits), nil
+}
+
+// SetLimits sets the limits that are enforced on the envelopes received over
+// dialed connections. The default limits are perunio.DefaultLimits.
+func (d *Dialer) SetLimits(limits perunio.Limits) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.limits = limits
+}
+
+// Register registers a network address, e.g., "example.com:4242", for a peer
+// address.
+func (d *Dialer) Register(addr map[wallet.BackendID]wire.Address, address string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.peers[wire.Keys(addr)] = address
+}
+
+func (d *Dialer) host(key wire.AddrKey) (string, bool) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	host, ok := d.peers[key]
+	return host, ok
+}
+
+// sessionCache stores the TLS sessions of a peer under its network address.
+// By default, sessions are stored under the server name, which may be shared
+// by different peers.
+type sessionCache struct {
+	cache tls.ClientSessionCache
+	host  string
+}
+
+func (c *sessionCache) Get(sessionKey string) (*tls.ClientSessionState, bool) {
+	return c.cache.Get(c.host + "/" + sessionKey)
+}
+
+func (c *sessionCache) Put(sessionKey string, cs *tls.ClientSessionState) {
+	c.cache.Put(c.host+"/"+sessionKey, cs)
+}
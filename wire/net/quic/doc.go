@@ -0,0 +1,19 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package quic contains an implementation of the wire.Dialer and
+// wire.Listener interfaces that transports envelopes over QUIC streams. Each
+// connection uses a single bidirectional stream. Peers are authenticated via
+// TLS, like in package simple, and resumed connections use 0-RTT data.
+package quic // import "perun.network/go-perun/wire/net/quic"
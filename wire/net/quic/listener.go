@@ -0,0 +1,157 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quic
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	quicgo "github.com/quic-go/quic-go"
+
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/wire"
+	wirenet "perun.network/go-perun/wire/net"
+	"perun.network/go-perun/wire/perunio"
+	pkgsync "polycry.pt/poly-go/sync"
+)
+
+// acceptStreamTimeout limits the time between accepting a QUIC connection
+// and the peer opening its stream.
+const acceptStreamTimeout = 10 * time.Second
+
+// Listener is a QUIC listener.
+type Listener struct {
+	pkgsync.Closer
+
+	listener *quicgo.EarlyListener
+	conns    chan accepted  // Connections that wait for Accept.
+	limits   perunio.Limits // Limits of received envelopes.
+}
+
+// accepted is a QUIC connection whose stream was opened by the peer.
+type accepted struct {
+	qconn  quicgo.Connection
+	stream quicgo.Stream
+}
+
+var _ wirenet.Listener = (*Listener)(nil)
+
+// NewListener creates a listener reachable under the requested UDP address.
+// The TLS config must contain the certificate of the listener and may
+// require client certificates.
+//
+// Resumed connections may send 0-RTT data. Since 0-RTT data can be replayed
+// by an attacker, envelopes are only passed on after the handshake is
+// complete, which also verifies client certificates.
+func NewListener(address string, tlsConfig *tls.Config) (*Listener, error) {
+	if tlsConfig == nil {
+		return nil, errors.New("QUIC requires a TLS config")
+	}
+	tlsConfig = tlsConfig.Clone()
+	tlsConfig.NextProtos = []string{alpn}
+
+	ql, err := quicgo.ListenAddrEarly(address, tlsConfig, &quicgo.Config{Allow0RTT: true})
+	if err != nil {
+		return nil, errors.Wrapf(err,
+			"failed to create listener for '%s'", address)
+	}
+
+	l := &Listener{
+		listener: ql,
+		conns:    make(chan accepted),
+		limits:   perunio.DefaultLimits(),
+	}
+	go l.acceptLoop()
+	return l, nil
+}
+
+// Addr returns the network address of the listener.
+func (l *Listener) Addr() net.Addr {
+	return l.listener.Addr()
+}
+
+// Accept implements peer.Listener.Accept().
+func (l *Listener) Accept(ser wire.EnvelopeSerializer) (wirenet.Conn, error) {
+	select {
+	case a := <-l.conns:
+		return newConn(a.qconn, a.stream, ser, l.limits), nil
+	case <-l.Closed():
+		return nil, errors.New("accept failed: listener closed")
+	}
+}
+
+// Close closes the listener. Repeated calls result in an error.
+func (l *Listener) Close() error {
+	if err := l.Closer.Close(); err != nil {
+		return err
+	}
+	return errors.Wrap(l.listener.Close(), "closing listener")
+}
+
+// SetLimits sets the limits that are enforced on the envelopes received over
+// accepted connections. The default limits are perunio.DefaultLimits. It must
+// not be called concurrently with Accept.
+func (l *Listener) SetLimits(limits perunio.Limits) {
+	l.limits = limits
+}
+
+// acceptLoop accepts QUIC connections until the listener is closed. The
+// connections are set up concurrently so that a slow peer does not block the
+// others.
+func (l *Listener) acceptLoop() {
+	for {
+		qconn, err := l.listener.Accept(l.Ctx())
+		if err != nil {
+			if !l.IsClosed() {
+				log.WithError(err).Error("Accepting QUIC connections failed")
+				l.Close()
+			}
+			return
+		}
+		go l.setup(qconn)
+	}
+}
+
+// setup waits for the handshake to complete and the peer to open its stream,
+// and then passes the connection on to Accept.
+func (l *Listener) setup(qconn quicgo.EarlyConnection) {
+	ctx, cancel := context.WithTimeout(l.Ctx(), acceptStreamTimeout)
+	defer cancel()
+
+	select {
+	case <-qconn.HandshakeComplete():
+	case <-ctx.Done():
+	}
+	if ctx.Err() != nil || qconn.Context().Err() != nil {
+		qconn.CloseWithError(0, "handshake failed") //nolint:errcheck
+		return
+	}
+
+	stream, err := qconn.AcceptStream(ctx)
+	if err != nil {
+		log.WithError(err).Debug("Accepting QUIC stream failed")
+		qconn.CloseWithError(0, "no stream opened") //nolint:errcheck
+		return
+	}
+
+	select {
+	case l.conns <- accepted{qconn: qconn, stream: stream}:
+	case <-l.Closed():
+		qconn.CloseWithError(0, "listener closed") //nolint:errcheck
+	}
+}
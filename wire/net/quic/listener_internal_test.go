@@ -0,0 +1,103 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	perunio "perun.network/go-perun/wire/perunio/serializer"
+	ctxtest "polycry.pt/poly-go/context/test"
+)
+
+const addr = "127.0.0.1:1339"
+
+func TestNewListener(t *testing.T) {
+	tlsConfigs, err := generateSelfSignedCertConfigs("127.0.0.1", []string{"127.0.0.1"}, 1)
+	require.NoError(t, err)
+	tlsConfig := tlsConfigs[0]
+
+	t.Run("happy", func(t *testing.T) {
+		l, err := NewListener(addr, tlsConfig)
+		require.NoError(t, err)
+		require.NotNil(t, l)
+		assert.Equal(t, addr, l.Addr().String())
+		l.Close()
+	})
+
+	t.Run("sad", func(t *testing.T) {
+		l, err := NewListener("not an address", tlsConfig)
+		assert.Error(t, err)
+		assert.Nil(t, l)
+	})
+
+	t.Run("no TLS config", func(t *testing.T) {
+		l, err := NewListener(addr, nil)
+		assert.Error(t, err)
+		assert.Nil(t, l)
+	})
+
+	t.Run("address in use", func(t *testing.T) {
+		l, err := NewListener(addr, tlsConfig)
+		require.NoError(t, err)
+		_, err = NewListener(addr, tlsConfig)
+		require.Error(t, err)
+		l.Close()
+	})
+}
+
+func TestListener_Close(t *testing.T) {
+	tlsConfigs, err := generateSelfSignedCertConfigs("127.0.0.1", []string{"127.0.0.1"}, 1)
+	require.NoError(t, err)
+
+	t.Run("double close", func(t *testing.T) {
+		l, err := NewListener(addr, tlsConfigs[0])
+		require.NoError(t, err)
+		require.NoError(t, l.Close(), "first close must not return error")
+		assert.Error(t, l.Close(), "second close must result in error")
+	})
+}
+
+func TestListener_Accept(t *testing.T) {
+	tlsConfigs, err := generateSelfSignedCertConfigs("127.0.0.1", []string{"127.0.0.1"}, 1)
+	require.NoError(t, err)
+	// Happy case already tested in TestDialer_Dial.
+	ser := perunio.Serializer()
+	timeout := 100 * time.Millisecond
+	t.Run("timeout", func(t *testing.T) {
+		l, err := NewListener(addr, tlsConfigs[0])
+		require.NoError(t, err)
+		defer l.Close()
+
+		ctxtest.AssertNotTerminates(t, timeout, func() {
+			l.Accept(ser) //nolint:errcheck
+		})
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		l, err := NewListener(addr, tlsConfigs[0])
+		require.NoError(t, err)
+		l.Close()
+
+		ctxtest.AssertTerminates(t, timeout, func() {
+			conn, err := l.Accept(ser)
+			assert.Nil(t, conn)
+			assert.Error(t, err)
+		})
+	})
+}
@@ -0,0 +1,297 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+)
+
+// ReconnectConfig configures the reliable mode of a Bus, in which envelopes
+// for temporarily unreachable peers are queued until the peer is reachable
+// again. Fields that are not positive are set to the value of
+// DefaultReconnectConfig.
+type ReconnectConfig struct {
+	// QueueSize is the maximum number of envelopes that are queued per peer.
+	QueueSize int
+	// TTL is the time after which a queued envelope is dropped.
+	TTL time.Duration
+	// MinBackoff is the time that is waited after the first failed attempt to
+	// reach a peer. It is doubled after each further failed attempt.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum time that is waited between two attempts. It
+	// is raised to MinBackoff if it is smaller.
+	MaxBackoff time.Duration
+}
+
+// withDefaults returns the configuration with all fields that are not positive
+// set to their default values.
+func (c ReconnectConfig) withDefaults() ReconnectConfig {
+	def := DefaultReconnectConfig()
+	if c.QueueSize <= 0 {
+		c.QueueSize = def.QueueSize
+	}
+	if c.TTL <= 0 {
+		c.TTL = def.TTL
+	}
+	if c.MinBackoff <= 0 {
+		c.MinBackoff = def.MinBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = def.MaxBackoff
+	}
+	c.MaxBackoff = max(c.MaxBackoff, c.MinBackoff)
+	return c
+}
+
+// DefaultReconnectConfig returns a configuration that queues up to 256
+// envelopes per peer for 5 minutes and retries every 100 milliseconds up to
+// every 30 seconds.
+func DefaultReconnectConfig() ReconnectConfig {
+	return ReconnectConfig{
+		QueueSize:  256,
+		TTL:        5 * time.Minute,
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: 30 * time.Second,
+	}
+}
+
+// QueueFullError describes an error which occurs when an envelope cannot be
+// queued because the outbound queue for its recipient is full.
+type QueueFullError struct {
+	Peer map[wallet.BackendID]wire.Address
+	Size int
+}
+
+func (e *QueueFullError) Error() string {
+	return fmt.Sprintf("outbound queue for peer %v full (size %d)", e.Peer, e.Size)
+}
+
+// IsQueueFullError returns true if the error was a QueueFullError.
+func IsQueueFullError(err error) bool {
+	cause := errors.Cause(err)
+	_, ok := cause.(*QueueFullError)
+	return ok
+}
+
+// EnableReconnect enables the reliable mode of the bus. Afterwards, Publish
+// does not fail if the recipient cannot be reached, but queues the envelope
+// and returns. The bus then redials the peer with exponential backoff and
+// jitter and sends the queued envelopes in order once the peer is reachable
+// again. Envelopes that are queued for longer than the TTL are dropped.
+// Publish only fails if the queue is full or the peer cannot be
+// authenticated. This method is expected to be called once during the setup
+// of the bus.
+func (b *Bus) EnableReconnect(cfg ReconnectConfig) {
+	b.queueMutex.Lock()
+	defer b.queueMutex.Unlock()
+	b.reconnect = cfg.withDefaults()
+	if b.queues == nil {
+		b.queues = make(map[wire.AddrKey]*outboundQueue)
+	}
+}
+
+type (
+	// outboundQueue holds the envelopes for a peer that could not be sent yet.
+	// It is removed from the bus once it is idle.
+	outboundQueue struct {
+		key   wire.AddrKey // The peer's key in the bus' queues.
+		users int          // Number of publishers using the queue, protected by the bus' queueMutex.
+
+		mutex    sync.Mutex // Protects envs and flushing.
+		envs     []queuedEnvelope
+		flushing bool // Whether a flush routine is running.
+
+		// sending is held during direct sends, so that an envelope is not
+		// sent directly while an earlier one is still being sent or queued.
+		sending chan struct{}
+	}
+
+	queuedEnvelope struct {
+		env     *wire.Envelope
+		expires time.Time
+	}
+)
+
+// publishReliable sends the envelope directly if no envelopes are queued for
+// the recipient, and queues it otherwise or if sending fails. Concurrent calls
+// for the same recipient are serialized, so that the envelopes are sent in
+// the order in which they are published.
+func (b *Bus) publishReliable(ctx context.Context, e *wire.Envelope, cfg ReconnectConfig) error {
+	q := b.queue(e.Recipient)
+	defer b.releaseQueue(q)
+
+	select {
+	case q.sending <- struct{}{}:
+		defer func() { <-q.sending }()
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "waiting for earlier envelope")
+	}
+
+	q.mutex.Lock()
+	if len(q.envs) == 0 && !q.flushing {
+		q.mutex.Unlock()
+		err := b.send(ctx, e)
+		if err == nil || IsAuthenticationError(err) {
+			return err
+		}
+		log.WithError(err).WithField("peer", e.Recipient).Debug("Publishing failed, queueing envelope.")
+		q.mutex.Lock()
+	}
+	defer q.mutex.Unlock()
+
+	if len(q.envs) >= cfg.QueueSize {
+		return errors.WithStack(&QueueFullError{Peer: e.Recipient, Size: cfg.QueueSize})
+	}
+	q.envs = append(q.envs, queuedEnvelope{env: e, expires: time.Now().Add(cfg.TTL)})
+	if !q.flushing {
+		q.flushing = true
+		go b.flush(q, cfg)
+	}
+	return nil
+}
+
+// queue returns the outbound queue for a peer, creating it if necessary. The
+// queue must be released with releaseQueue when it is not used anymore.
+func (b *Bus) queue(addr map[wallet.BackendID]wire.Address) *outboundQueue {
+	b.queueMutex.Lock()
+	defer b.queueMutex.Unlock()
+	key := wire.Keys(addr)
+	q, ok := b.queues[key]
+	if !ok {
+		q = &outboundQueue{key: key, sending: make(chan struct{}, 1)}
+		b.queues[key] = q
+	}
+	q.users++
+	return q
+}
+
+// releaseQueue releases a queue that was returned by queue and removes it if
+// it is idle.
+func (b *Bus) releaseQueue(q *outboundQueue) {
+	b.queueMutex.Lock()
+	q.users--
+	b.queueMutex.Unlock()
+	b.removeIfIdle(q)
+}
+
+// removeIfIdle removes the queue from the bus if it is empty, not flushed and
+// not used by a publisher, so that the queues of peers that are not published
+// to anymore do not accumulate.
+func (b *Bus) removeIfIdle(q *outboundQueue) {
+	b.queueMutex.Lock()
+	defer b.queueMutex.Unlock()
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.users == 0 && len(q.envs) == 0 && !q.flushing {
+		delete(b.queues, q.key)
+	}
+}
+
+// reliable returns the reconnect configuration and whether the reliable mode
+// is enabled.
+func (b *Bus) reliable() (ReconnectConfig, bool) {
+	b.queueMutex.Lock()
+	defer b.queueMutex.Unlock()
+	return b.reconnect, b.queues != nil
+}
+
+// send makes a single attempt to send the envelope, dialing the recipient if
+// necessary.
+func (b *Bus) send(ctx context.Context, e *wire.Envelope) error {
+	ep, err := b.reg.Endpoint(ctx, e.Recipient)
+	if err != nil {
+		return err
+	}
+	return ep.Send(ctx, e)
+}
+
+// flush sends the queued envelopes in order until the queue is empty or the
+// bus is closed. Failed attempts are retried with exponential backoff. The
+// emptied queue is removed if it is idle.
+func (b *Bus) flush(q *outboundQueue, cfg ReconnectConfig) {
+	backoff := cfg.MinBackoff
+	for {
+		q.mutex.Lock()
+		q.dropExpired()
+		if len(q.envs) == 0 {
+			q.flushing = false
+			q.mutex.Unlock()
+			b.removeIfIdle(q)
+			return
+		}
+		next := q.envs[0]
+		q.mutex.Unlock()
+
+		ctx, cancel := context.WithDeadline(b.ctx(), next.expires)
+		err := b.send(ctx, next.env)
+		cancel()
+
+		// Only the flush routine removes envelopes, so next is still the
+		// first queued envelope.
+		if err == nil || IsAuthenticationError(err) {
+			if err != nil {
+				log.WithError(err).WithField("peer", next.env.Recipient).
+					Warnf("Dropping queued %T envelope", next.env.Msg)
+			}
+			q.mutex.Lock()
+			q.envs = q.envs[1:]
+			q.mutex.Unlock()
+			backoff = cfg.MinBackoff
+			continue
+		}
+
+		log.WithError(err).WithField("peer", next.env.Recipient).
+			Debugf("Sending queued envelope failed, retrying in %v", backoff)
+		select {
+		case <-b.ctx().Done():
+			return
+		case <-time.After(jitter(backoff)):
+		}
+		backoff = min(2*backoff, cfg.MaxBackoff)
+	}
+}
+
+// dropExpired removes all envelopes whose TTL has passed. The queue's mutex
+// must be held.
+func (q *outboundQueue) dropExpired() {
+	now := time.Now()
+	kept := q.envs[:0]
+	for _, qe := range q.envs {
+		if now.Before(qe.expires) {
+			kept = append(kept, qe)
+			continue
+		}
+		log.WithField("peer", qe.env.Recipient).Warnf("Dropping expired %T envelope", qe.env.Msg)
+	}
+	q.envs = kept
+}
+
+// jitter returns a random duration between half and the full duration.
+func jitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1)) //nolint:gosec // Jitter does not need secure randomness.
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	perunio "perun.network/go-perun/wire/perunio/serializer"
+	wiretest "perun.network/go-perun/wire/test"
+	"polycry.pt/poly-go/test"
+)
+
+func TestBus_RemoveIdleQueues(t *testing.T) {
+	t.Parallel()
+	rng := test.Prng(t)
+	aliceAcc := wiretest.NewRandomAccountMap(rng, channel.TestBackendID)
+	bobAcc := wiretest.NewRandomAccountMap(rng, channel.TestBackendID)
+	aliceAddr, bobAddr := wire.AddressMapfromAccountMap(aliceAcc), wire.AddressMapfromAccountMap(bobAcc)
+
+	alice := NewBus(aliceAcc, newMockDialer(), perunio.Serializer())
+	defer alice.Close()
+	alice.EnableReconnect(ReconnectConfig{TTL: time.Second, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+	recv := wire.NewReceiver()
+	bob := NewEndpointRegistry(bobAcc, func(map[wallet.BackendID]wire.Address) wire.Consumer { return recv }, nil, perunio.Serializer())
+	defer bob.Close()
+	numQueues := func() int {
+		alice.queueMutex.Lock()
+		defer alice.queueMutex.Unlock()
+		return len(alice.queues)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	env := &wire.Envelope{Sender: aliceAddr, Recipient: bobAddr, Msg: &wire.HelloMsg{Version: wire.ProtocolVersion}}
+
+	// Bob cannot be dialed, so the envelope is queued.
+	pubCtx, pubCancel := context.WithTimeout(ctx, timeout)
+	require.NoError(t, alice.Publish(pubCtx, env))
+	pubCancel()
+	assert.Equal(t, 1, numQueues())
+
+	// Once Bob is connected, the queued envelope is delivered and the queue
+	// removed.
+	a, b := newPipeConnPair()
+	alice.reg.addEndpoint(bobAddr, a, true, nil)
+	bob.addEndpoint(aliceAddr, b, false, nil)
+	_, err := recv.Next(ctx)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return numQueues() == 0 }, time.Second, timeout/10)
+
+	// Envelopes that are sent directly do not leave a queue behind.
+	require.NoError(t, alice.Publish(ctx, env))
+	_, err = recv.Next(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, numQueues())
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	"perun.network/go-perun/wire/net"
+	nettest "perun.network/go-perun/wire/net/test"
+	perunio "perun.network/go-perun/wire/perunio/serializer"
+	wiretest "perun.network/go-perun/wire/test"
+	"polycry.pt/poly-go/test"
+)
+
+func TestBus_Reconnect(t *testing.T) {
+	t.Parallel()
+	rng := test.Prng(t)
+	cfg := net.ReconnectConfig{QueueSize: 3, TTL: time.Second, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+
+	var hub nettest.ConnHub
+	defer hub.Close()
+	newBus := func(acc map[wallet.BackendID]wire.Account) *net.Bus {
+		bus := net.NewBus(acc, hub.NewNetDialer(), perunio.Serializer())
+		t.Cleanup(func() { bus.Close() })
+		return bus
+	}
+
+	aliceAcc := wiretest.NewRandomAccountMap(rng, channel.TestBackendID)
+	bobAcc := wiretest.NewRandomAccountMap(rng, channel.TestBackendID)
+	alice, bob := newBus(aliceAcc), newBus(bobAcc)
+	alice.EnableReconnect(cfg)
+	aliceAddr, bobAddr := wire.AddressMapfromAccountMap(aliceAcc), wire.AddressMapfromAccountMap(bobAcc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// Bob is not listening yet, so the envelopes are queued.
+	msgs := make([]wire.Msg, cfg.QueueSize)
+	for i := range msgs {
+		msgs[i] = &wire.HelloMsg{Version: wire.ProtocolVersion}
+		require.NoError(t, alice.Publish(ctx, &wire.Envelope{Sender: aliceAddr, Recipient: bobAddr, Msg: msgs[i]}))
+	}
+	err := alice.Publish(ctx, &wire.Envelope{Sender: aliceAddr, Recipient: bobAddr, Msg: &wire.HelloMsg{Version: wire.ProtocolVersion}})
+	assert.True(t, net.IsQueueFullError(err))
+
+	// Once Bob is reachable, the envelopes are delivered in order.
+	recv := wire.NewReceiver()
+	require.NoError(t, bob.SubscribeClient(recv, bobAddr))
+	go bob.Listen(hub.NewNetListener(bobAddr))
+	for _, msg := range msgs {
+		env, err := recv.Next(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, msg, env.Msg)
+	}
+}
+
+func TestBus_Reconnect_ZeroConfig(t *testing.T) {
+	t.Parallel()
+	rng := test.Prng(t)
+
+	var hub nettest.ConnHub
+	defer hub.Close()
+	aliceAcc := wiretest.NewRandomAccountMap(rng, channel.TestBackendID)
+	alice := net.NewBus(aliceAcc, hub.NewNetDialer(), perunio.Serializer())
+	defer alice.Close()
+	alice.EnableReconnect(net.ReconnectConfig{})
+
+	// The default queue size is used, so envelopes for unreachable peers are
+	// queued.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	aliceAddr, bobAddr := wire.AddressMapfromAccountMap(aliceAcc), wiretest.NewRandomAddress(rng)
+	for range 3 {
+		require.NoError(t, alice.Publish(ctx, &wire.Envelope{Sender: aliceAddr, Recipient: bobAddr, Msg: &wire.HelloMsg{Version: wire.ProtocolVersion}}))
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+)
+
+// ErrShuttingDown is returned when a new request is published to a peer whose
+// connection is being shut down gracefully.
+var ErrShuttingDown = errors.New("connection shutting down")
+
+// shutdownAckTimeout is the time after which acknowledging the shutdown of a
+// peer is aborted.
+const shutdownAckTimeout = 10 * time.Second
+
+// IsShutdown returns whether the Endpoint's connection is being or was shut
+// down gracefully by either side. The closing of such a connection is not a
+// failure.
+func (p *Endpoint) IsShutdown() bool {
+	return p.shutdownSent.Load() || p.shutdownRecvd.Load()
+}
+
+// shutdown announces the shutdown to the peer and waits until the peer has
+// acknowledged it or the context is done. Afterwards, the pending responses to
+// requests of the peer are drained and the Endpoint is closed.
+//
+// Since envelopes are sent in order, the acknowledgement is only received
+// after all envelopes that the peer sent before, so that pending responses
+// are not lost.
+func (p *Endpoint) shutdown(ctx context.Context, self map[wallet.BackendID]wire.Address, reason string) error {
+	defer p.Close() // Ignore double close.
+
+	if err := p.announceShutdown(ctx, self, reason); err != nil {
+		return errors.WithMessage(err, "announcing shutdown")
+	}
+
+	select {
+	case <-p.peerShutdown:
+		p.drainPending(ctx)
+		return nil
+	case <-p.stopped:
+		select {
+		case <-p.peerShutdown:
+			return nil
+		default:
+			return errors.New("connection closed before shutdown was acknowledged")
+		}
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "waiting for shutdown acknowledgement")
+	}
+}
+
+// announceShutdown sends a ShutdownMsg to the peer, unless one was already
+// sent. It only returns after all envelopes that were sent before.
+func (p *Endpoint) announceShutdown(ctx context.Context, self map[wallet.BackendID]wire.Address, reason string) error {
+	if !p.shutdownSent.CompareAndSwap(false, true) {
+		return nil
+	}
+	return p.Send(ctx, &wire.Envelope{Sender: self, Recipient: p.Address, Msg: &wire.ShutdownMsg{Reason: reason}})
+}
+
+// drainPending waits until the drain functions of the registry returned, so
+// that the responses to requests that were received before are sent. The
+// drain functions are only called once, also if both the shutdown and the
+// acknowledgement of the peer's shutdown drain. Concurrent callers wait until
+// the first one is done.
+func (p *Endpoint) drainPending(ctx context.Context) {
+	p.drainOnce.Do(func() {
+		if p.drain != nil {
+			p.drain(ctx, p.Address)
+		}
+	})
+}
+
+// recvShutdown handles a ShutdownMsg envelope. It is called by the receive
+// loop. The shutdown is acknowledged with a ShutdownMsg after the pending
+// responses have been drained and all pending envelopes have been sent, after
+// which the peer closes the connection.
+func (p *Endpoint) recvShutdown(env *wire.Envelope, msg *wire.ShutdownMsg) {
+	if !p.shutdownRecvd.CompareAndSwap(false, true) {
+		return
+	}
+	close(p.peerShutdown)
+	log.WithField("peer", p.Address).Debugf("Peer shutting down: %s", msg.Reason)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownAckTimeout)
+		defer cancel()
+		p.drainPending(ctx)
+		if err := p.announceShutdown(ctx, env.Recipient, "acknowledged"); err != nil {
+			log.WithField("peer", p.Address).Debugf("Acknowledging shutdown: %v", err)
+		}
+	}()
+}
+
+// Shutdown gracefully shuts down the registry. It stops accepting new
+// connections and dialing peers, announces the shutdown to all peers and
+// waits until every peer has acknowledged it. Envelopes that are received in
+// the meantime, such as responses to pending requests, are still relayed.
+// Before a connection is closed, the drain functions are called, see OnDrain.
+// When the context is done, the remaining connections are torn down.
+// Finally, the registry is closed.
+func (r *EndpointRegistry) Shutdown(ctx context.Context, reason string) error {
+	if !r.shuttingDown.CompareAndSwap(false, true) {
+		return errors.New("registry already shutting down")
+	}
+
+	r.mutex.RLock()
+	endpoints := make(map[*fullEndpoint]*Endpoint, len(r.endpoints))
+	for _, fe := range r.endpoints {
+		if e := fe.Endpoint(); e != nil {
+			endpoints[fe] = e
+		}
+	}
+	r.mutex.RUnlock()
+
+	self := wire.AddressMapfromAccountMap(r.id)
+	errs := make(chan error, len(endpoints))
+	for fe, e := range endpoints {
+		go func() {
+			err := e.shutdown(ctx, self, reason)
+			// Remove the closed endpoint so that Close does not close it again.
+			fe.delete(e)
+			errs <- errors.WithMessagef(err, "shutting down %v", e)
+		}()
+	}
+
+	var err error
+	for range endpoints {
+		if serr := <-errs; serr != nil && err == nil {
+			err = serr
+		}
+	}
+	if cerr := r.Close(); cerr != nil && err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// OnDrain adds a function that is called with the peer's address before the
+// connection to the peer is shut down gracefully, by either side. It should
+// return once the responses to all requests that were received from the peer
+// are published, or when the context is done. This function may be safely
+// called at any time.
+func (r *EndpointRegistry) OnDrain(drain func(context.Context, map[wallet.BackendID]wire.Address)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.onDrain = append(r.onDrain, drain)
+}
+
+// drain calls the drain functions in the order in which they were added.
+func (r *EndpointRegistry) drain(ctx context.Context, peer map[wallet.BackendID]wire.Address) {
+	r.mutex.RLock()
+	drains := r.onDrain
+	r.mutex.RUnlock()
+	for _, drain := range drains {
+		drain(ctx, peer)
+	}
+}
+
+// IsShuttingDown returns whether the connection to the peer is being shut
+// down gracefully, either because the registry is shutting down or because a
+// shutdown was announced to or by the peer.
+func (r *EndpointRegistry) IsShuttingDown(peer map[wallet.BackendID]wire.Address) bool {
+	if r.shuttingDown.Load() {
+		return true
+	}
+	e := r.find(peer)
+	return e != nil && e.IsShutdown()
+}
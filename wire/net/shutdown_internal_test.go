@@ -0,0 +1,135 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	perunio "perun.network/go-perun/wire/perunio/serializer"
+	wiretest "perun.network/go-perun/wire/test"
+	"polycry.pt/poly-go/test"
+)
+
+func TestRegistry_Shutdown(t *testing.T) {
+	t.Parallel()
+	rng := test.Prng(t)
+
+	newRegistry := func() (*EndpointRegistry, *wire.Receiver) {
+		recv := wire.NewReceiver()
+		id := wiretest.NewRandomAccountMap(rng, channel.TestBackendID)
+		r := NewEndpointRegistry(id, func(map[wallet.BackendID]wire.Address) wire.Consumer { return recv }, nil, perunio.Serializer())
+		t.Cleanup(func() { r.Close() })
+		return r, recv
+	}
+
+	t.Run("acknowledged", func(t *testing.T) {
+		alice, aliceRecv := newRegistry()
+		bob, bobRecv := newRegistry()
+		aliceAddr := wire.AddressMapfromAccountMap(alice.id)
+		bobAddr := wire.AddressMapfromAccountMap(bob.id)
+
+		a, b := newPipeConnPair()
+		ea := alice.addEndpoint(bobAddr, a, true, nil)
+		eb := bob.addEndpoint(aliceAddr, b, false, nil)
+
+		// A response that is in flight when Alice shuts down.
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		response := &wire.Envelope{Sender: bobAddr, Recipient: aliceAddr, Msg: &wire.HelloMsg{Version: wire.ProtocolVersion}}
+		require.NoError(t, eb.Send(ctx, response))
+
+		require.NoError(t, alice.Shutdown(ctx, "test"))
+		assert.True(t, ea.IsShutdown())
+		assert.True(t, alice.IsClosed())
+
+		env, err := aliceRecv.Next(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, response.Msg, env.Msg)
+
+		// Bob sees a clean close and does not relay the shutdown message.
+		assert.True(t, eb.IsShutdown())
+		require.Eventually(t, func() bool { return bob.find(aliceAddr) == nil }, timeout, timeout/10)
+		recvCtx, recvCancel := context.WithTimeout(context.Background(), timeout)
+		defer recvCancel()
+		_, err = bobRecv.Next(recvCtx)
+		assert.Error(t, err)
+	})
+
+	t.Run("drained", func(t *testing.T) {
+		alice, aliceRecv := newRegistry()
+		bob, bobRecv := newRegistry()
+		aliceAddr := wire.AddressMapfromAccountMap(alice.id)
+		bobAddr := wire.AddressMapfromAccountMap(bob.id)
+
+		a, b := newPipeConnPair()
+		ea := alice.addEndpoint(bobAddr, a, true, nil)
+		eb := bob.addEndpoint(aliceAddr, b, false, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		// Both sides respond to pending requests while draining, which must
+		// happen before the shutdown is acknowledged or the connection closed.
+		bobResponse := &wire.Envelope{Sender: bobAddr, Recipient: aliceAddr, Msg: &wire.HelloMsg{Version: wire.ProtocolVersion}}
+		bob.OnDrain(func(ctx context.Context, _ map[wallet.BackendID]wire.Address) {
+			assert.NoError(t, eb.Send(ctx, bobResponse))
+		})
+		aliceResponse := &wire.Envelope{Sender: aliceAddr, Recipient: bobAddr, Msg: &wire.HelloMsg{Version: wire.ProtocolVersion}}
+		alice.OnDrain(func(ctx context.Context, _ map[wallet.BackendID]wire.Address) {
+			assert.NoError(t, ea.Send(ctx, aliceResponse))
+		})
+
+		require.NoError(t, alice.Shutdown(ctx, "test"))
+
+		env, err := aliceRecv.Next(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, bobResponse.Msg, env.Msg)
+		env, err = bobRecv.Next(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, aliceResponse.Msg, env.Msg)
+	})
+
+	t.Run("unacknowledged", func(t *testing.T) {
+		alice, _ := newRegistry()
+		addr := wiretest.NewRandomAddress(rng)
+
+		// Nobody reads from the other end of the connection.
+		a, _ := newPipeConnPair()
+		e := alice.addEndpoint(addr, a, true, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		assert.Error(t, alice.Shutdown(ctx, "test"))
+		assert.True(t, e.IsShutdown())
+		assert.True(t, alice.IsClosed())
+	})
+
+	t.Run("no new connections", func(t *testing.T) {
+		alice, _ := newRegistry()
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		require.NoError(t, alice.Shutdown(ctx, "test"))
+
+		_, err := alice.Endpoint(ctx, wiretest.NewRandomAddress(rng))
+		assert.Error(t, err)
+	})
+}
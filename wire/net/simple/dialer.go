@@ -26,6 +26,7 @@ import (
 	"github.com/pkg/errors"
 	"perun.network/go-perun/wire"
 	wirenet "perun.network/go-perun/wire/net"
+	"perun.network/go-perun/wire/perunio"
 	pkgsync "polycry.pt/poly-go/sync"
 )
 
@@ -34,10 +35,11 @@ import (
 type Dialer struct {
 	pkgsync.Closer
 
-	mutex   sync.RWMutex            // Protects peers.
+	mutex   sync.RWMutex            // Protects peers and limits.
 	peers   map[wire.AddrKey]string // Known peer addresses.
 	dialer  tls.Dialer              // Used to dial connections.
 	network string                  // The socket type.
+	limits  perunio.Limits          // Limits of received envelopes.
 }
 
 var _ wirenet.Dialer = (*Dialer)(nil)
@@ -57,6 +59,7 @@ func NewNetDialer(network string, defaultTimeout time.Duration, tlsConfig *tls.C
 			Config:    tlsConfig,
 		},
 		network: network,
+		limits:  perunio.DefaultLimits(),
 	}
 }
 
@@ -97,7 +100,18 @@ func (d *Dialer) Dial(ctx context.Context, addr map[wallet.BackendID]wire.Addres
 		return nil, errors.Wrap(err, "failed to dial peer")
 	}
 
-	return wirenet.NewIoConn(conn, ser), nil
+	d.mutex.RLock()
+	limits := d.limits
+	d.mutex.RUnlock()
+	return wirenet.NewIoConnWithLimits(conn, ser, limits), nil
+}
+
+// SetLimits sets the limits that are enforced on the envelopes received over
+// dialed connections. The default limits are perunio.DefaultLimits.
+func (d *Dialer) SetLimits(limits perunio.Limits) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.limits = limits
 }
 
 // Register registers a network address for a peer address.
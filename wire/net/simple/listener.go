@@ -21,11 +21,13 @@ import (
 	"github.com/pkg/errors"
 	"perun.network/go-perun/wire"
 	wirenet "perun.network/go-perun/wire/net"
+	"perun.network/go-perun/wire/perunio"
 )
 
 // Listener is a TCP Listener.
 type Listener struct {
 	net.Listener
+	limits perunio.Limits // Limits of received envelopes.
 }
 
 var _ wirenet.Listener = (*Listener)(nil)
@@ -38,7 +40,7 @@ func NewNetListener(network string, address string, config *tls.Config) (*Listen
 			"failed to create listener for '%s'", address)
 	}
 
-	return &Listener{Listener: l}, nil
+	return &Listener{Listener: l, limits: perunio.DefaultLimits()}, nil
 }
 
 // NewTCPListener is a short-hand version of NewNetListener for TCP listeners.
@@ -58,5 +60,12 @@ func (l *Listener) Accept(ser wire.EnvelopeSerializer) (wirenet.Conn, error) {
 		return nil, errors.Wrap(err, "accept failed")
 	}
 
-	return wirenet.NewIoConn(conn, ser), nil
+	return wirenet.NewIoConnWithLimits(conn, ser, l.limits), nil
+}
+
+// SetLimits sets the limits that are enforced on the envelopes received over
+// accepted connections. The default limits are perunio.DefaultLimits. It must
+// not be called concurrently with Accept.
+func (l *Listener) SetLimits(limits perunio.Limits) {
+	l.limits = limits
 }
@@ -0,0 +1,86 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websocket
+
+import (
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/wire"
+	wirenet "perun.network/go-perun/wire/net"
+	"perun.network/go-perun/wire/perunio"
+	"polycry.pt/poly-go/sync/atomic"
+)
+
+var _ wirenet.Conn = (*conn)(nil)
+
+// conn is a connection that transports each envelope in a binary WebSocket
+// message.
+type conn struct {
+	closed     atomic.Bool
+	ws         *websocket.Conn
+	serializer wire.EnvelopeSerializer
+	limits     perunio.Limits
+}
+
+func newConn(ws *websocket.Conn, serializer wire.EnvelopeSerializer, limits perunio.Limits) *conn {
+	return &conn{
+		ws:         ws,
+		serializer: serializer,
+		limits:     limits,
+	}
+}
+
+func (c *conn) Send(e *wire.Envelope) error {
+	w, err := c.ws.NextWriter(websocket.BinaryMessage)
+	if err != nil {
+		c.ws.Close()
+		return errors.Wrap(err, "starting message")
+	}
+	if err := c.serializer.Encode(w, e); err != nil {
+		c.ws.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		c.ws.Close()
+		return errors.Wrap(err, "flushing message")
+	}
+	return nil
+}
+
+func (c *conn) Recv() (*wire.Envelope, error) {
+	typ, r, err := c.ws.NextReader()
+	if err != nil {
+		c.ws.Close()
+		return nil, errors.Wrap(err, "receiving message")
+	}
+	if typ != websocket.BinaryMessage {
+		c.ws.Close()
+		return nil, errors.Errorf("unexpected WebSocket message type %d", typ)
+	}
+	e, err := c.serializer.Decode(perunio.NewLimitedReader(r, c.limits))
+	if err != nil {
+		c.ws.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+func (c *conn) Close() error {
+	if !c.closed.TrySet() {
+		return errors.New("already closed")
+	}
+	return c.ws.Close()
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websocket
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/wire"
+	wirenet "perun.network/go-perun/wire/net"
+	"perun.network/go-perun/wire/perunio"
+	pkgsync "polycry.pt/poly-go/sync"
+)
+
+// Dialer is a lookup-table based dialer that can dial known peers over
+// WebSocket. New peer URLs can be added via Register().
+type Dialer struct {
+	pkgsync.Closer
+
+	mutex  sync.RWMutex            // Protects peers and limits.
+	peers  map[wire.AddrKey]string // Known peer URLs.
+	dialer websocket.Dialer        // Used to dial connections.
+	limits perunio.Limits          // Limits of received envelopes.
+}
+
+var _ wirenet.Dialer = (*Dialer)(nil)
+
+// NewDialer creates a new WebSocket dialer. The timeout limits the duration
+// of the opening handshake, leaving it as 0 will result in no timeouts. The
+// TLS config is used to dial "wss" URLs, a nil config uses the default
+// configuration. Proxies are configured via the environment, see
+// http.ProxyFromEnvironment.
+func NewDialer(defaultTimeout time.Duration, tlsConfig *tls.Config) *Dialer {
+	return &Dialer{
+		peers: make(map[wire.AddrKey]string),
+		dialer: websocket.Dialer{
+			Proxy:            http.ProxyFromEnvironment,
+			HandshakeTimeout: defaultTimeout,
+			TLSClientConfig:  tlsConfig,
+		},
+		limits: perunio.DefaultLimits(),
+	}
+}
+
+// Dial implements Dialer.Dial().
+func (d *Dialer) Dial(ctx context.Context, addr map[wallet.BackendID]wire.Address, ser wire.EnvelopeSerializer) (wirenet.Conn, error) {
+	done := make(chan struct{})
+	defer close(done)
+
+	url, ok := d.url(wire.Keys(addr))
+	if !ok {
+		return nil, errors.New("peer not found")
+	}
+
+	// To combine the provided context with the Dialer's Closer as specified by
+	// the Dialer interface, we have to use some goroutine trickery.
+	wrappedCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		defer cancel()
+
+		select {
+		case <-d.Closed():
+		case <-done:
+		}
+	}()
+
+	// The response body does not need to be closed, see websocket.Dialer.
+	ws, _, err := d.dialer.DialContext(wrappedCtx, url, nil) //nolint:bodyclose
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dial peer")
+	}
+
+	d.mutex.RLock()
+	limits := d.limits
+	d.mutex.RUnlock()
+	return newConn(ws, ser, limits), nil
+}
+
+// SetLimits sets the limits that are enforced on the envelopes received over
+// dialed connections. The default limits are perunio.DefaultLimits.
+func (d *Dialer) SetLimits(limits perunio.Limits) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.limits = limits
+}
+
+// Register registers the WebSocket URL of a peer, e.g.,
+// "wss://example.com:443/perun".
+func (d *Dialer) Register(addr map[wallet.BackendID]wire.Address, url string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.peers[wire.Keys(addr)] = url
+}
+
+func (d *Dialer) url(key wire.AddrKey) (string, bool) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	url, ok := d.peers[key]
+	return url, ok
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websocket
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/log"
+	"perun.network/go-perun/wire"
+	wirenet "perun.network/go-perun/wire/net"
+	"perun.network/go-perun/wire/perunio"
+	pkgsync "polycry.pt/poly-go/sync"
+)
+
+// readHeaderTimeout limits the time for reading the opening handshake.
+const readHeaderTimeout = 10 * time.Second
+
+// Listener accepts WebSocket connections. It is an http.Handler that upgrades
+// incoming requests, so it can either serve its own HTTP server, see
+// NewListener, or be mounted on an existing one, see NewHandler.
+type Listener struct {
+	pkgsync.Closer
+
+	upgrader websocket.Upgrader
+	conns    chan *websocket.Conn // Upgraded connections that wait for Accept.
+	server   *http.Server         // Own HTTP server, nil for NewHandler.
+	listener net.Listener         // Listener of the own HTTP server.
+	limits   perunio.Limits       // Limits of received envelopes.
+}
+
+var (
+	_ wirenet.Listener = (*Listener)(nil)
+	_ http.Handler     = (*Listener)(nil)
+)
+
+// NewHandler creates a listener that is not bound to a network address. It
+// has to be mounted on an HTTP server, which passes the requests of the
+// peers to ServeHTTP.
+func NewHandler() *Listener {
+	return &Listener{
+		conns:  make(chan *websocket.Conn),
+		limits: perunio.DefaultLimits(),
+	}
+}
+
+// NewListener creates a listener that serves WebSocket connections under the
+// requested TCP address and URL path, e.g., "/perun". If tlsConfig is not
+// nil, connections are served over TLS and peers have to dial a "wss" URL.
+func NewListener(address, path string, tlsConfig *tls.Config) (*Listener, error) {
+	ln, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, errors.Wrapf(err,
+			"failed to create listener for '%s'", address)
+	}
+	if tlsConfig != nil {
+		ln = tls.NewListener(ln, tlsConfig)
+	}
+
+	l := NewHandler()
+	mux := http.NewServeMux()
+	mux.Handle(path, l)
+	l.listener = ln
+	l.server = &http.Server{Handler: mux, ReadHeaderTimeout: readHeaderTimeout}
+
+	go func() {
+		if err := l.server.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+			log.WithError(err).Error("Serving WebSocket connections failed")
+			l.Close()
+		}
+	}()
+	return l, nil
+}
+
+// Addr returns the network address of the listener, or nil if it was
+// created by NewHandler.
+func (l *Listener) Addr() net.Addr {
+	if l.listener == nil {
+		return nil
+	}
+	return l.listener.Addr()
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection, which is then
+// returned by Accept.
+func (l *Listener) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if l.IsClosed() {
+		http.Error(w, "listener closed", http.StatusServiceUnavailable)
+		return
+	}
+	ws, err := l.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader already replied with an HTTP error.
+		log.WithError(err).Debug("Upgrading WebSocket connection failed")
+		return
+	}
+
+	select {
+	case l.conns <- ws:
+	case <-l.Closed():
+		ws.Close()
+	}
+}
+
+// Accept implements peer.Listener.Accept().
+func (l *Listener) Accept(ser wire.EnvelopeSerializer) (wirenet.Conn, error) {
+	select {
+	case ws := <-l.conns:
+		return newConn(ws, ser, l.limits), nil
+	case <-l.Closed():
+		return nil, errors.New("accept failed: listener closed")
+	}
+}
+
+// Close closes the listener and its HTTP server, if it has one. Repeated
+// calls result in an error.
+func (l *Listener) Close() error {
+	if err := l.Closer.Close(); err != nil {
+		return err
+	}
+	if l.server != nil {
+		err := l.server.Close()
+		// The server only closes the listener once it is serving.
+		l.listener.Close() //nolint:errcheck
+		return errors.Wrap(err, "closing server")
+	}
+	return nil
+}
+
+// SetLimits sets the limits that are enforced on the envelopes received over
+// accepted connections. The default limits are perunio.DefaultLimits. It must
+// not be called concurrently with Accept.
+func (l *Listener) SetLimits(limits perunio.Limits) {
+	l.limits = limits
+}
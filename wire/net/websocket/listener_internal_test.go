@@ -0,0 +1,166 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websocket
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/wire"
+	"perun.network/go-perun/wire/perunio"
+	perunioserializer "perun.network/go-perun/wire/perunio/serializer"
+	wiretest "perun.network/go-perun/wire/test"
+	ctxtest "polycry.pt/poly-go/context/test"
+	"polycry.pt/poly-go/test"
+)
+
+const addr = "127.0.0.1:1338"
+
+func TestNewListener(t *testing.T) {
+	t.Run("happy", func(t *testing.T) {
+		l, err := NewListener(addr, "/", nil)
+		require.NoError(t, err)
+		require.NotNil(t, l)
+		assert.Equal(t, addr, l.Addr().String())
+		l.Close()
+	})
+
+	t.Run("sad", func(t *testing.T) {
+		l, err := NewListener("not an address", "/", nil)
+		assert.Error(t, err)
+		assert.Nil(t, l)
+	})
+
+	t.Run("address in use", func(t *testing.T) {
+		l, err := NewListener(addr, "/", nil)
+		require.NoError(t, err)
+		_, err = NewListener(addr, "/", nil)
+		require.Error(t, err)
+		l.Close()
+	})
+}
+
+func TestListener_Close(t *testing.T) {
+	t.Run("double close", func(t *testing.T) {
+		l, err := NewListener(addr, "/", nil)
+		require.NoError(t, err)
+		require.NoError(t, l.Close(), "first close must not return error")
+		assert.Error(t, l.Close(), "second close must result in error")
+	})
+}
+
+func TestListener_Accept(t *testing.T) {
+	// Happy case already tested in TestDialer_Dial.
+	ser := perunioserializer.Serializer()
+	timeout := 100 * time.Millisecond
+	t.Run("timeout", func(t *testing.T) {
+		l, err := NewListener(addr, "/", nil)
+		require.NoError(t, err)
+		defer l.Close()
+
+		ctxtest.AssertNotTerminates(t, timeout, func() {
+			l.Accept(ser) //nolint:errcheck
+		})
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		l, err := NewListener(addr, "/", nil)
+		require.NoError(t, err)
+		l.Close()
+
+		ctxtest.AssertTerminates(t, timeout, func() {
+			conn, err := l.Accept(ser)
+			assert.Nil(t, conn)
+			assert.Error(t, err)
+		})
+	})
+}
+
+func TestNewHandler(t *testing.T) {
+	rng := test.Prng(t)
+	ser := perunioserializer.Serializer()
+	l := NewHandler()
+	defer l.Close()
+	assert.Nil(t, l.Addr())
+	server := httptest.NewServer(l)
+	defer server.Close()
+
+	peer := wiretest.NewRandomAddress(rng)
+	d := NewDialer(time.Second, nil)
+	defer d.Close()
+	d.Register(peer, "ws"+strings.TrimPrefix(server.URL, "http"))
+
+	ct := test.NewConcurrent(t)
+	e := wiretest.NewRandomEnvelope(rng, wire.NewPingMsg())
+	go ct.Stage("accept", func(t test.ConcT) {
+		conn, err := l.Accept(ser)
+		require.NoError(t, err)
+		re, err := conn.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, e, re)
+	})
+
+	conn, err := d.Dial(context.Background(), peer, ser)
+	require.NoError(t, err)
+	require.NoError(t, conn.Send(e))
+	ct.Wait("accept")
+}
+
+func TestConn_Recv(t *testing.T) {
+	rng := test.Prng(t)
+	ser := perunioserializer.Serializer()
+	l := NewHandler()
+	defer l.Close()
+	server := httptest.NewServer(l)
+	defer server.Close()
+	l.SetLimits(perunio.Limits{MaxEnvelopeSize: 64})
+
+	dial := func() *websocket.Conn {
+		ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil) //nolint:bodyclose
+		require.NoError(t, err)
+		return ws
+	}
+
+	t.Run("text message", func(t *testing.T) {
+		ws := dial()
+		defer ws.Close()
+		conn, err := l.Accept(ser)
+		require.NoError(t, err)
+		require.NoError(t, ws.WriteMessage(websocket.TextMessage, []byte("hello")))
+		_, err = conn.Recv()
+		assert.Error(t, err)
+	})
+
+	t.Run("envelope too large", func(t *testing.T) {
+		ws := dial()
+		defer ws.Close()
+		conn, err := l.Accept(ser)
+		require.NoError(t, err)
+		msg := &wire.ShutdownMsg{Reason: strings.Repeat("x", 128)}
+		w, err := ws.NextWriter(websocket.BinaryMessage)
+		require.NoError(t, err)
+		require.NoError(t, ser.Encode(w, wiretest.NewRandomEnvelope(rng, msg)))
+		require.NoError(t, w.Close())
+		_, err = conn.Recv()
+		assert.True(t, perunio.IsLimitError(err))
+	})
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package perunio
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+)
+
+// Limits configures the resource limits that are enforced while decoding
+// data from an untrusted source. A zero value disables the respective limit.
+type Limits struct {
+	// MaxEnvelopeSize is the maximum number of bytes that may be read for a
+	// single envelope.
+	MaxEnvelopeSize int
+	// MaxFieldSize is the maximum length of a single length-prefixed field,
+	// e.g., the number of bytes of a string or the number of entries of a
+	// list. Serializers that decode the envelope as a whole, like the
+	// protobuf serializer, are only bound by MaxEnvelopeSize.
+	MaxFieldSize int
+}
+
+// DefaultLimits returns limits that allow envelopes of up to 1 MiB and fields
+// of up to 64 KiB.
+func DefaultLimits() Limits {
+	return Limits{MaxEnvelopeSize: 1 << 20, MaxFieldSize: 1 << 16} //nolint:mnd
+}
+
+// LimitError describes an error which occurs when decoded data exceeds its
+// limit.
+type LimitError struct {
+	Field string // The field or "envelope" if the envelope size was exceeded.
+	Size  int
+	Limit int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s size %d exceeds limit %d", e.Field, e.Size, e.Limit)
+}
+
+// IsLimitError returns true if the error was a LimitError.
+func IsLimitError(err error) bool {
+	var lerr *LimitError
+	return stderrors.As(err, &lerr)
+}
+
+// LimitedReader is a reader that enforces Limits on the data that is decoded
+// from it. Reading more than the maximum envelope size fails with a
+// LimitError. The field size limit is enforced by CheckSize.
+type LimitedReader struct {
+	r      io.Reader
+	limits Limits
+	read   int
+}
+
+// NewLimitedReader creates a reader that enforces the limits on the data read
+// from r.
+func NewLimitedReader(r io.Reader, limits Limits) *LimitedReader {
+	return &LimitedReader{r: r, limits: limits}
+}
+
+// Read implements io.Reader.
+func (l *LimitedReader) Read(p []byte) (int, error) {
+	if limit := l.limits.MaxEnvelopeSize; limit > 0 && len(p) > limit-l.read {
+		if l.read >= limit {
+			return 0, &LimitError{Field: "envelope", Size: l.read + len(p), Limit: limit}
+		}
+		p = p[:limit-l.read]
+	}
+	n, err := l.r.Read(p)
+	l.read += n
+	return n, err
+}
+
+// CheckSize returns a LimitError if a field of the given size may not be
+// decoded from the reader. It should be called by decoders before allocating
+// memory for a length-prefixed field. Only readers created by
+// NewLimitedReader impose limits.
+func CheckSize(r io.Reader, field string, size int) error {
+	l, ok := r.(*LimitedReader)
+	if !ok {
+		return nil
+	}
+	if limit := l.limits.MaxFieldSize; limit > 0 && size > limit {
+		return &LimitError{Field: field, Size: size, Limit: limit}
+	}
+	return l.checkEnvelopeSize(size)
+}
+
+// CheckEnvelopeSize returns a LimitError if size more bytes may not be read
+// from the reader without exceeding the maximum envelope size. Unlike
+// CheckSize, it does not enforce the field size limit. Only readers created
+// by NewLimitedReader impose limits.
+func CheckEnvelopeSize(r io.Reader, size int) error {
+	l, ok := r.(*LimitedReader)
+	if !ok {
+		return nil
+	}
+	return l.checkEnvelopeSize(size)
+}
+
+func (l *LimitedReader) checkEnvelopeSize(size int) error {
+	if limit := l.limits.MaxEnvelopeSize; limit > 0 && size > limit-l.read {
+		return &LimitError{Field: "envelope", Size: l.read + size, Limit: limit}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package perunio_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"perun.network/go-perun/wire/perunio"
+)
+
+func TestLimitedReader(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, perunio.Encode(&buf, "0123456789", "0123456789"))
+	data := buf.Bytes()
+
+	t.Run("unlimited", func(t *testing.T) {
+		var s1, s2 string
+		r := perunio.NewLimitedReader(bytes.NewReader(data), perunio.Limits{})
+		require.NoError(t, perunio.Decode(r, &s1, &s2))
+		assert.Equal(t, "0123456789", s2)
+	})
+
+	t.Run("field size", func(t *testing.T) {
+		var s string
+		r := perunio.NewLimitedReader(bytes.NewReader(data), perunio.Limits{MaxFieldSize: 9})
+		err := perunio.Decode(r, &s)
+		assert.True(t, perunio.IsLimitError(err))
+	})
+
+	t.Run("envelope size", func(t *testing.T) {
+		var s1, s2 string
+		r := perunio.NewLimitedReader(bytes.NewReader(data), perunio.Limits{MaxEnvelopeSize: len(data) - 1})
+		require.NoError(t, perunio.Decode(r, &s1))
+		err := perunio.Decode(r, &s2)
+		assert.True(t, perunio.IsLimitError(err))
+	})
+
+	t.Run("envelope check", func(t *testing.T) {
+		r := perunio.NewLimitedReader(bytes.NewReader(data), perunio.Limits{MaxEnvelopeSize: len(data), MaxFieldSize: 1})
+		require.NoError(t, perunio.CheckEnvelopeSize(r, len(data)))
+		assert.True(t, perunio.IsLimitError(perunio.CheckEnvelopeSize(r, len(data)+1)))
+	})
+
+	t.Run("plain reader", func(t *testing.T) {
+		assert.NoError(t, perunio.CheckSize(bytes.NewReader(data), "field", 1<<30))
+		assert.NoError(t, perunio.CheckEnvelopeSize(bytes.NewReader(data), 1<<30))
+	})
+}
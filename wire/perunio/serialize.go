@@ -126,6 +126,9 @@ func Decode(reader io.Reader, values ...interface{}) (err error) {
 			if length == 0 {
 				break
 			}
+			if err = CheckSize(reader, "binary data", int(length)); err != nil {
+				break
+			}
 
 			var data ByteSlice = make([]byte, length)
 			err = data.Decode(reader)
@@ -57,6 +57,9 @@ func decodeString(r io.Reader, s *string) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to read string length")
 	}
+	if err := CheckSize(r, "string", int(l)); err != nil {
+		return err
+	}
 
 	buf := make([]byte, l)
 	_, err = io.ReadFull(r, buf)
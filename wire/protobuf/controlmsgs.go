@@ -15,8 +15,11 @@
 package protobuf
 
 import (
+	"math"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"perun.network/go-perun/wire"
 )
 
@@ -45,6 +48,33 @@ func fromAuthResponseMsg(msg *wire.AuthResponseMsg) *Envelope_AuthResponseMsg {
 	return &Envelope_AuthResponseMsg{protoMsg}
 }
 
+func fromHelloMsg(msg *wire.HelloMsg) *Envelope_HelloMsg {
+	protoMsg := &HelloMsg{}
+	protoMsg.Version = uint32(msg.Version)
+	protoMsg.Features = uint64(msg.Features)
+	return &Envelope_HelloMsg{protoMsg}
+}
+
+func fromReliableMsg(msg *wire.ReliableMsg) (*Envelope_ReliableMsg, error) {
+	protoMsg := &ReliableMsg{}
+	protoMsg.Id = msg.ID
+	if _, ok := msg.Msg.(*wire.ReliableMsg); ok {
+		return nil, errors.New("nested reliable message")
+	}
+	wrapped, err := fromMsg(msg.Msg)
+	if err != nil {
+		return nil, errors.WithMessage(err, "wrapped message")
+	}
+	protoMsg.Envelope = &Envelope{Msg: wrapped}
+	return &Envelope_ReliableMsg{protoMsg}, nil
+}
+
+func fromAckMsg(msg *wire.AckMsg) *Envelope_AckMsg {
+	protoMsg := &AckMsg{}
+	protoMsg.Id = msg.ID
+	return &Envelope_AckMsg{protoMsg}
+}
+
 func toPingMsg(protoMsg *Envelope_PingMsg) (msg *wire.PingMsg) {
 	msg = &wire.PingMsg{}
 	msg.Created = time.Unix(0, protoMsg.PingMsg.GetCreated())
@@ -70,3 +100,32 @@ func toAuthResponseMsg(protoEnvMsg *Envelope_AuthResponseMsg) (msg *wire.AuthRes
 	msg.Signature = protoEnvMsg.AuthResponseMsg.GetSignature()
 	return msg
 }
+
+func toHelloMsg(protoEnvMsg *Envelope_HelloMsg) (*wire.HelloMsg, error) {
+	version := protoEnvMsg.HelloMsg.GetVersion()
+	if version > math.MaxUint16 {
+		return nil, errors.Errorf("protocol version %d out of range", version)
+	}
+	return &wire.HelloMsg{
+		Version:  uint16(version),
+		Features: wire.Features(protoEnvMsg.HelloMsg.GetFeatures()),
+	}, nil
+}
+
+func toReliableMsg(protoEnvMsg *Envelope_ReliableMsg) (*wire.ReliableMsg, error) {
+	protoEnv := protoEnvMsg.ReliableMsg.GetEnvelope()
+	if _, ok := protoEnv.GetMsg().(*Envelope_ReliableMsg); ok {
+		return nil, errors.New("nested reliable message")
+	}
+	msg, err := toMsg(protoEnv)
+	if err != nil {
+		return nil, errors.WithMessage(err, "wrapped message")
+	}
+	return &wire.ReliableMsg{ID: protoEnvMsg.ReliableMsg.GetId(), Msg: msg}, nil
+}
+
+func toAckMsg(protoEnvMsg *Envelope_AckMsg) (msg *wire.AckMsg) {
+	msg = &wire.AckMsg{}
+	msg.ID = protoEnvMsg.AckMsg.GetId()
+	return msg
+}
@@ -26,6 +26,7 @@ import (
 	"perun.network/go-perun/client"
 	"perun.network/go-perun/wallet"
 	"perun.network/go-perun/wire"
+	"perun.network/go-perun/wire/perunio"
 )
 
 // ToLedgerChannelProposalMsg converts a protobuf Envelope_LedgerChannelProposalMsg to a client
@@ -296,6 +297,11 @@ func ToAllocation(protoAlloc *Allocation) (alloc *channel.Allocation, err error)
 			return nil, errors.WithMessagef(err, "%d'th sub alloc", i)
 		}
 	}
+	for _, protoBalance := range protoAlloc.GetBalances().GetBalances() {
+		if err := checkBalance(protoBalance); err != nil {
+			return nil, err
+		}
+	}
 	alloc.Balances = ToBalances(protoAlloc.GetBalances())
 	return alloc, nil
 }
@@ -318,10 +324,24 @@ func ToBalance(protoBalance *Balance) (balance []channel.Bal) {
 	return balance
 }
 
+// checkBalance returns a perunio.LimitError if a balance of the protobuf
+// Balance is larger than the perunio encoding allows.
+func checkBalance(protoBalance *Balance) error {
+	for _, bal := range protoBalance.GetBalance() {
+		if len(bal) > perunio.MaxBigIntLength {
+			return &perunio.LimitError{Field: "balance", Size: len(bal), Limit: perunio.MaxBigIntLength}
+		}
+	}
+	return nil
+}
+
 // ToSubAlloc converts a protobuf SubAlloc to a channel.SubAlloc.
 func ToSubAlloc(protoSubAlloc *SubAlloc) (subAlloc channel.SubAlloc, err error) {
 	subAlloc = channel.SubAlloc{}
 
+	if err := checkBalance(protoSubAlloc.GetBals()); err != nil {
+		return subAlloc, err
+	}
 	subAlloc.Bals = ToBalance(protoSubAlloc.GetBals())
 	if len(protoSubAlloc.GetId()) != len(subAlloc.ID) {
 		return subAlloc, errors.New("sub alloc id has incorrect length")
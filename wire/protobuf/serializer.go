@@ -25,7 +25,10 @@ import (
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/proto"
 	"perun.network/go-perun/client"
+	"perun.network/go-perun/watcher/blinded"
+	"perun.network/go-perun/watcher/remote"
 	"perun.network/go-perun/wire"
+	"perun.network/go-perun/wire/perunio"
 )
 
 type serializer struct{}
@@ -37,59 +40,83 @@ func Serializer() wire.EnvelopeSerializer {
 
 // Encode encodes an envelope from the reader using protocol buffers
 // serialization format.
-func (serializer) Encode(w io.Writer, env *wire.Envelope) (err error) { //nolint: funlen, cyclop
+func (serializer) Encode(w io.Writer, env *wire.Envelope) (err error) {
 	protoEnv := &Envelope{}
+	if protoEnv.Msg, err = fromMsg(env.Msg); err != nil {
+		return err
+	}
+	sender, recipient, err := marshalSenderRecipient(env)
+	protoEnv.Sender, protoEnv.Recipient = sender, recipient
+
+	if err != nil {
+		return err
+	}
 
-	switch msg := env.Msg.(type) {
+	return writeEnvelope(w, protoEnv)
+}
+
+// fromMsg converts a wire message to the message of a protobuf envelope.
+func fromMsg(msg wire.Msg) (protoMsg isEnvelope_Msg, err error) { //nolint: funlen, cyclop
+	switch msg := msg.(type) {
 	case *wire.PingMsg:
-		protoEnv.Msg = fromPingMsg(msg)
+		protoMsg = fromPingMsg(msg)
 	case *wire.PongMsg:
-		protoEnv.Msg = fromPongMsg(msg)
+		protoMsg = fromPongMsg(msg)
 	case *wire.ShutdownMsg:
-		protoEnv.Msg = fromShutdownMsg(msg)
+		protoMsg = fromShutdownMsg(msg)
 	case *wire.AuthResponseMsg:
-		protoEnv.Msg = fromAuthResponseMsg(msg)
+		protoMsg = fromAuthResponseMsg(msg)
+	case *wire.HelloMsg:
+		protoMsg = fromHelloMsg(msg)
+	case *wire.ReliableMsg:
+		protoMsg, err = fromReliableMsg(msg)
+	case *wire.AckMsg:
+		protoMsg = fromAckMsg(msg)
 	case *client.LedgerChannelProposalMsg:
-		protoEnv.Msg, err = FromLedgerChannelProposalMsg(msg)
+		protoMsg, err = FromLedgerChannelProposalMsg(msg)
 	case *client.SubChannelProposalMsg:
-		protoEnv.Msg, err = FromSubChannelProposalMsg(msg)
+		protoMsg, err = FromSubChannelProposalMsg(msg)
 	case *client.VirtualChannelProposalMsg:
-		protoEnv.Msg, err = FromVirtualChannelProposalMsg(msg)
+		protoMsg, err = FromVirtualChannelProposalMsg(msg)
 	case *client.LedgerChannelProposalAccMsg:
-		protoEnv.Msg, err = FromLedgerChannelProposalAccMsg(msg)
+		protoMsg, err = FromLedgerChannelProposalAccMsg(msg)
 	case *client.SubChannelProposalAccMsg:
-		protoEnv.Msg = FromSubChannelProposalAccMsg(msg)
+		protoMsg = FromSubChannelProposalAccMsg(msg)
 	case *client.VirtualChannelProposalAccMsg:
-		protoEnv.Msg, err = FromVirtualChannelProposalAccMsg(msg)
+		protoMsg, err = FromVirtualChannelProposalAccMsg(msg)
 	case *client.ChannelProposalRejMsg:
-		protoEnv.Msg = FromChannelProposalRejMsg(msg)
+		protoMsg = FromChannelProposalRejMsg(msg)
 	case *client.ChannelUpdateMsg:
-		protoEnv.Msg, err = FromChannelUpdateMsg(msg)
+		protoMsg, err = FromChannelUpdateMsg(msg)
 	case *client.VirtualChannelFundingProposalMsg:
-		protoEnv.Msg, err = FromVirtualChannelFundingProposalMsg(msg)
+		protoMsg, err = FromVirtualChannelFundingProposalMsg(msg)
 	case *client.VirtualChannelSettlementProposalMsg:
-		protoEnv.Msg, err = FromVirtualChannelSettlementProposalMsg(msg)
+		protoMsg, err = FromVirtualChannelSettlementProposalMsg(msg)
 	case *client.ChannelUpdateAccMsg:
-		protoEnv.Msg = FromChannelUpdateAccMsg(msg)
+		protoMsg = FromChannelUpdateAccMsg(msg)
 	case *client.ChannelUpdateRejMsg:
-		protoEnv.Msg = FromChannelUpdateRejMsg(msg)
+		protoMsg = FromChannelUpdateRejMsg(msg)
 	case *client.ChannelSyncMsg:
-		protoEnv.Msg, err = fromChannelSyncMsg(msg)
+		protoMsg, err = fromChannelSyncMsg(msg)
+	case *remote.WatchMsg:
+		protoMsg, err = fromWatcherWatchMsg(msg)
+	case *remote.StopMsg:
+		protoMsg = fromWatcherStopMsg(msg)
+	case *remote.ResponseMsg:
+		protoMsg = fromWatcherResponseMsg(msg)
+	case *remote.PublishMsg:
+		protoMsg, err = fromWatcherPublishMsg(msg)
+	case *remote.EventMsg:
+		protoMsg, err = fromWatcherEventMsg(msg)
+	case *remote.TimeoutElapsedMsg:
+		protoMsg = fromWatcherTimeoutElapsedMsg(msg)
+	case *blinded.UploadMsg:
+		protoMsg = fromWatcherBlindedUploadMsg(msg)
 	default:
 		err = fmt.Errorf("unknown message type: %T", msg)
 	}
 
-	if err != nil {
-		return err
-	}
-	sender, recipient, err := marshalSenderRecipient(env)
-	protoEnv.Sender, protoEnv.Recipient = sender, recipient
-
-	if err != nil {
-		return err
-	}
-
-	return writeEnvelope(w, protoEnv)
+	return protoMsg, err
 }
 
 func marshalSenderRecipient(env *wire.Envelope) (*Address, *Address, error) {
@@ -119,7 +146,13 @@ func writeEnvelope(w io.Writer, env *Envelope) error {
 
 // Decode decodes an envelope from the reader, that was encoded using protocol
 // buffers serialization format.
-func (serializer) Decode(r io.Reader) (env *wire.Envelope, err error) { //nolint: funlen, cyclop
+//
+// If r is a perunio.LimitedReader, the size of the envelope is checked against
+// the maximum envelope size before it is read. The envelope size is the only
+// bound on the decoded data: the field size limit is not enforced on the
+// repeated and bytes fields of the envelope, which cannot be larger than the
+// envelope itself.
+func (serializer) Decode(r io.Reader) (env *wire.Envelope, err error) {
 	env = &wire.Envelope{}
 
 	protoEnv, err := readEnvelope(r)
@@ -134,46 +167,72 @@ func (serializer) Decode(r io.Reader) (env *wire.Envelope, err error) { //nolint
 		return nil, err
 	}
 
+	env.Msg, err = toMsg(protoEnv)
+	return env, err
+}
+
+// toMsg converts the message of a protobuf envelope to a wire message.
+func toMsg(protoEnv *Envelope) (msg wire.Msg, err error) { //nolint: funlen, cyclop
 	switch protoMsg := protoEnv.GetMsg().(type) {
 	case *Envelope_PingMsg:
-		env.Msg = toPingMsg(protoMsg)
+		msg = toPingMsg(protoMsg)
 	case *Envelope_PongMsg:
-		env.Msg = toPongMsg(protoMsg)
+		msg = toPongMsg(protoMsg)
 	case *Envelope_ShutdownMsg:
-		env.Msg = toShutdownMsg(protoMsg)
+		msg = toShutdownMsg(protoMsg)
 	case *Envelope_AuthResponseMsg:
-		env.Msg = toAuthResponseMsg(protoMsg)
+		msg = toAuthResponseMsg(protoMsg)
+	case *Envelope_HelloMsg:
+		msg, err = toHelloMsg(protoMsg)
+	case *Envelope_ReliableMsg:
+		msg, err = toReliableMsg(protoMsg)
+	case *Envelope_AckMsg:
+		msg = toAckMsg(protoMsg)
 	case *Envelope_LedgerChannelProposalMsg:
-		env.Msg, err = ToLedgerChannelProposalMsg(protoMsg)
+		msg, err = ToLedgerChannelProposalMsg(protoMsg)
 	case *Envelope_SubChannelProposalMsg:
-		env.Msg, err = ToSubChannelProposalMsg(protoMsg)
+		msg, err = ToSubChannelProposalMsg(protoMsg)
 	case *Envelope_VirtualChannelProposalMsg:
-		env.Msg, err = ToVirtualChannelProposalMsg(protoMsg)
+		msg, err = ToVirtualChannelProposalMsg(protoMsg)
 	case *Envelope_LedgerChannelProposalAccMsg:
-		env.Msg, err = ToLedgerChannelProposalAccMsg(protoMsg)
+		msg, err = ToLedgerChannelProposalAccMsg(protoMsg)
 	case *Envelope_SubChannelProposalAccMsg:
-		env.Msg = ToSubChannelProposalAccMsg(protoMsg)
+		msg = ToSubChannelProposalAccMsg(protoMsg)
 	case *Envelope_VirtualChannelProposalAccMsg:
-		env.Msg, err = ToVirtualChannelProposalAccMsg(protoMsg)
+		msg, err = ToVirtualChannelProposalAccMsg(protoMsg)
 	case *Envelope_ChannelProposalRejMsg:
-		env.Msg = ToChannelProposalRejMsg(protoMsg)
+		msg = ToChannelProposalRejMsg(protoMsg)
 	case *Envelope_ChannelUpdateMsg:
-		env.Msg, err = ToChannelUpdateMsg(protoMsg)
+		msg, err = ToChannelUpdateMsg(protoMsg)
 	case *Envelope_VirtualChannelFundingProposalMsg:
-		env.Msg, err = ToVirtualChannelFundingProposalMsg(protoMsg)
+		msg, err = ToVirtualChannelFundingProposalMsg(protoMsg)
 	case *Envelope_VirtualChannelSettlementProposalMsg:
-		env.Msg, err = ToVirtualChannelSettlementProposalMsg(protoMsg)
+		msg, err = ToVirtualChannelSettlementProposalMsg(protoMsg)
 	case *Envelope_ChannelUpdateAccMsg:
-		env.Msg = ToChannelUpdateAccMsg(protoMsg)
+		msg = ToChannelUpdateAccMsg(protoMsg)
 	case *Envelope_ChannelUpdateRejMsg:
-		env.Msg = ToChannelUpdateRejMsg(protoMsg)
+		msg = ToChannelUpdateRejMsg(protoMsg)
 	case *Envelope_ChannelSyncMsg:
-		env.Msg, err = toChannelSyncMsg(protoMsg)
+		msg, err = toChannelSyncMsg(protoMsg)
+	case *Envelope_WatcherWatchMsg:
+		msg, err = toWatcherWatchMsg(protoMsg)
+	case *Envelope_WatcherStopMsg:
+		msg = toWatcherStopMsg(protoMsg)
+	case *Envelope_WatcherResponseMsg:
+		msg = toWatcherResponseMsg(protoMsg)
+	case *Envelope_WatcherPublishMsg:
+		msg, err = toWatcherPublishMsg(protoMsg)
+	case *Envelope_WatcherEventMsg:
+		msg, err = toWatcherEventMsg(protoMsg)
+	case *Envelope_WatcherTimeoutElapsedMsg:
+		msg = toWatcherTimeoutElapsedMsg(protoMsg)
+	case *Envelope_WatcherBlindedUploadMsg:
+		msg = toWatcherBlindedUploadMsg(protoMsg)
 	default:
 		err = fmt.Errorf("unknown message type: %T", protoMsg)
 	}
 
-	return env, err
+	return msg, err
 }
 
 func readEnvelope(r io.Reader) (*Envelope, error) {
@@ -181,8 +240,11 @@ func readEnvelope(r io.Reader) (*Envelope, error) {
 	if err := binary.Read(r, binary.BigEndian, &size); err != nil {
 		return nil, errors.Wrap(err, "reading size of data from wire")
 	}
+	if err := perunio.CheckEnvelopeSize(r, int(size)); err != nil {
+		return nil, err
+	}
 	data := make([]byte, size)
-	if _, err := r.Read(data); err != nil {
+	if _, err := io.ReadFull(r, data); err != nil {
 		return nil, errors.Wrap(err, "reading data from wire")
 	}
 
@@ -15,11 +15,19 @@
 package protobuf_test
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	clienttest "perun.network/go-perun/client/test"
+	"perun.network/go-perun/wire"
+	"perun.network/go-perun/wire/perunio"
+	"perun.network/go-perun/wire/protobuf"
 	protobuftest "perun.network/go-perun/wire/protobuf/test"
 	wiretest "perun.network/go-perun/wire/test"
+	pkgtest "polycry.pt/poly-go/test"
 )
 
 func TestControlMsgsSerialization(t *testing.T) {
@@ -41,3 +49,25 @@ func TestUpdateMsgsSerialization(t *testing.T) {
 func TestChannelSyncMsgSerialization(t *testing.T) {
 	clienttest.ChannelSyncMsgSerializationTest(t, protobuftest.MsgSerializerTest)
 }
+
+func TestDecodeLimits(t *testing.T) {
+	rng := pkgtest.Prng(t)
+	env := wiretest.NewRandomEnvelope(rng, &wire.ShutdownMsg{Reason: "shutting down"})
+	var buf bytes.Buffer
+	require.NoError(t, protobuf.Serializer().Encode(&buf, env))
+	data := buf.Bytes()
+
+	t.Run("field size", func(t *testing.T) {
+		// The field size limit does not apply to the envelope data.
+		r := perunio.NewLimitedReader(bytes.NewReader(data), perunio.Limits{MaxFieldSize: 1})
+		decoded, err := protobuf.Serializer().Decode(r)
+		require.NoError(t, err)
+		assert.Equal(t, env.Msg, decoded.Msg)
+	})
+
+	t.Run("envelope size", func(t *testing.T) {
+		r := perunio.NewLimitedReader(bytes.NewReader(data), perunio.Limits{MaxEnvelopeSize: len(data) - 1})
+		_, err := protobuf.Serializer().Decode(r)
+		assert.True(t, perunio.IsLimitError(err))
+	})
+}
@@ -0,0 +1,265 @@
+// Copyright 2025 - See NOTICE file for copyright holders.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protobuf
+
+import (
+	stderrors "errors"
+	"fmt"
+	"math"
+
+	"github.com/pkg/errors"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/watcher"
+	"perun.network/go-perun/watcher/blinded"
+	"perun.network/go-perun/watcher/remote"
+)
+
+// maxWatcherParents is the maximum number of parents of a watched channel, as
+// in the perunio encoding of remote.WatchMsg.
+const maxWatcherParents = math.MaxUint8
+
+func fromWatcherWatchMsg(msg *remote.WatchMsg) (_ *Envelope_WatcherWatchMsg, err error) {
+	protoMsg := &WatcherWatchMsg{}
+	protoMsg.Seq = msg.Seq
+	protoMsg.Virtual = msg.Virtual
+	if len(msg.Parents) > maxWatcherParents {
+		return nil, errors.Errorf("too many channel IDs: %d", len(msg.Parents))
+	}
+	protoMsg.Parents = make([][]byte, len(msg.Parents))
+	for i := range msg.Parents {
+		protoMsg.Parents[i] = fromChannelID(msg.Parents[i])
+	}
+	protoMsg.Params, err = FromParams(msg.Params)
+	if err != nil {
+		return nil, errors.WithMessage(err, "params")
+	}
+	protoMsg.Tx, err = fromTransaction(msg.Tx)
+	return &Envelope_WatcherWatchMsg{protoMsg}, err
+}
+
+func fromWatcherStopMsg(msg *remote.StopMsg) *Envelope_WatcherStopMsg {
+	protoMsg := &WatcherStopMsg{}
+	protoMsg.Seq = msg.Seq
+	protoMsg.Id = fromChannelID(msg.ID)
+	return &Envelope_WatcherStopMsg{protoMsg}
+}
+
+func fromWatcherResponseMsg(msg *remote.ResponseMsg) *Envelope_WatcherResponseMsg {
+	protoMsg := &WatcherResponseMsg{}
+	protoMsg.Seq = msg.Seq
+	protoMsg.Error = msg.Error
+	return &Envelope_WatcherResponseMsg{protoMsg}
+}
+
+func fromWatcherPublishMsg(msg *remote.PublishMsg) (_ *Envelope_WatcherPublishMsg, err error) {
+	protoMsg := &WatcherPublishMsg{}
+	protoMsg.Seq = msg.Seq
+	protoMsg.Tx, err = fromTransaction(msg.Tx)
+	return &Envelope_WatcherPublishMsg{protoMsg}, err
+}
+
+func fromWatcherEventMsg(msg *remote.EventMsg) (_ *Envelope_WatcherEventMsg, err error) {
+	protoMsg := &WatcherEventMsg{}
+	protoMsg.TimeoutId = msg.TimeoutID
+	protoMsg.Deadline = msg.Deadline
+	protoMsg.Id = fromChannelID(msg.Event.ID())
+	protoMsg.Version = msg.Event.Version()
+
+	switch e := msg.Event.(type) {
+	case *channel.RegisteredEvent:
+		registered := &RegisteredEvent{}
+		registered.Tx, err = fromTransaction(channel.Transaction{State: e.State, Sigs: e.Sigs})
+		protoMsg.Event = &WatcherEventMsg_Registered{registered}
+	case *channel.ProgressedEvent:
+		progressed := &ProgressedEvent{}
+		progressed.Idx = uint32(e.Idx)
+		progressed.State, err = FromState(e.State)
+		protoMsg.Event = &WatcherEventMsg_Progressed{progressed}
+	case *channel.ConcludedEvent:
+		protoMsg.Event = &WatcherEventMsg_Concluded{&ConcludedEvent{}}
+	case *watcher.WithdrawnEvent:
+		withdrawn := &WithdrawnEvent{}
+		if e.Err != nil {
+			withdrawn.Error = e.Err.Error()
+		}
+		protoMsg.Event = &WatcherEventMsg_Withdrawn{withdrawn}
+	default:
+		return nil, errors.Errorf("unknown adjudicator event type %T", e)
+	}
+	return &Envelope_WatcherEventMsg{protoMsg}, err
+}
+
+func fromWatcherTimeoutElapsedMsg(msg *remote.TimeoutElapsedMsg) *Envelope_WatcherTimeoutElapsedMsg {
+	protoMsg := &WatcherTimeoutElapsedMsg{}
+	protoMsg.Id = fromChannelID(msg.ID)
+	protoMsg.TimeoutId = msg.TimeoutID
+	return &Envelope_WatcherTimeoutElapsedMsg{protoMsg}
+}
+
+func fromWatcherBlindedUploadMsg(msg *blinded.UploadMsg) *Envelope_WatcherBlindedUploadMsg {
+	protoMsg := &WatcherBlindedUploadMsg{}
+	protoMsg.Hint = make([]byte, len(msg.Hint))
+	copy(protoMsg.GetHint(), msg.Hint[:])
+	protoMsg.Blob = make([]byte, len(msg.Blob))
+	copy(protoMsg.GetBlob(), msg.Blob)
+	return &Envelope_WatcherBlindedUploadMsg{protoMsg}
+}
+
+func fromChannelID(id channel.ID) []byte {
+	protoID := make([]byte, len(id))
+	copy(protoID, id[:])
+	return protoID
+}
+
+func fromTransaction(tx channel.Transaction) (protoTx *Transaction, err error) {
+	protoTx = &Transaction{}
+	protoTx.Sigs = make([][]byte, len(tx.Sigs))
+	for i := range tx.Sigs {
+		protoTx.Sigs[i] = make([]byte, len(tx.Sigs[i]))
+		copy(protoTx.GetSigs()[i], tx.Sigs[i])
+	}
+	protoTx.State, err = FromState(tx.State)
+	return protoTx, err
+}
+
+func toWatcherWatchMsg(protoEnvMsg *Envelope_WatcherWatchMsg) (msg *remote.WatchMsg, err error) {
+	protoMsg := protoEnvMsg.WatcherWatchMsg
+
+	msg = &remote.WatchMsg{}
+	msg.Seq = protoMsg.GetSeq()
+	msg.Virtual = protoMsg.GetVirtual()
+	if len(protoMsg.GetParents()) > maxWatcherParents {
+		return nil, errors.Errorf("too many channel IDs: %d", len(protoMsg.GetParents()))
+	}
+	for _, parent := range protoMsg.GetParents() {
+		msg.Parents = append(msg.Parents, toChannelID(parent))
+	}
+	msg.Params, err = ToParams(protoMsg.GetParams())
+	if err != nil {
+		return nil, errors.WithMessage(err, "params")
+	}
+	msg.Tx, err = toTransaction(protoMsg.GetTx())
+	return msg, err
+}
+
+func toWatcherStopMsg(protoEnvMsg *Envelope_WatcherStopMsg) (msg *remote.StopMsg) {
+	protoMsg := protoEnvMsg.WatcherStopMsg
+
+	msg = &remote.StopMsg{}
+	msg.Seq = protoMsg.GetSeq()
+	msg.ID = toChannelID(protoMsg.GetId())
+	return msg
+}
+
+func toWatcherResponseMsg(protoEnvMsg *Envelope_WatcherResponseMsg) (msg *remote.ResponseMsg) {
+	protoMsg := protoEnvMsg.WatcherResponseMsg
+
+	msg = &remote.ResponseMsg{}
+	msg.Seq = protoMsg.GetSeq()
+	msg.Error = protoMsg.GetError()
+	return msg
+}
+
+func toWatcherPublishMsg(protoEnvMsg *Envelope_WatcherPublishMsg) (msg *remote.PublishMsg, err error) {
+	protoMsg := protoEnvMsg.WatcherPublishMsg
+
+	msg = &remote.PublishMsg{}
+	msg.Seq = protoMsg.GetSeq()
+	msg.Tx, err = toTransaction(protoMsg.GetTx())
+	return msg, err
+}
+
+// toWatcherEventMsg converts a protobuf Envelope_WatcherEventMsg to a
+// remote.EventMsg. The timeout of the decoded event is nil, except for a
+// WithdrawnEvent, whose timeout is always elapsed.
+func toWatcherEventMsg(protoEnvMsg *Envelope_WatcherEventMsg) (*remote.EventMsg, error) {
+	protoMsg := protoEnvMsg.WatcherEventMsg
+
+	msg := &remote.EventMsg{}
+	msg.TimeoutID = protoMsg.GetTimeoutId()
+	msg.Deadline = protoMsg.GetDeadline()
+	base := channel.AdjudicatorEventBase{IDV: toChannelID(protoMsg.GetId()), VersionV: protoMsg.GetVersion()}
+
+	switch protoEvent := protoMsg.GetEvent().(type) {
+	case *WatcherEventMsg_Registered:
+		tx, err := toTransaction(protoEvent.Registered.GetTx())
+		if err != nil {
+			return nil, err
+		}
+		msg.Event = &channel.RegisteredEvent{AdjudicatorEventBase: base, State: tx.State, Sigs: tx.Sigs}
+	case *WatcherEventMsg_Progressed:
+		idx := protoEvent.Progressed.GetIdx()
+		if idx > math.MaxUint16 {
+			return nil, fmt.Errorf("invalid actor index: %d", idx)
+		}
+		state, err := ToState(protoEvent.Progressed.GetState())
+		if err != nil {
+			return nil, err
+		}
+		msg.Event = &channel.ProgressedEvent{AdjudicatorEventBase: base, State: state, Idx: channel.Index(idx)}
+	case *WatcherEventMsg_Concluded:
+		msg.Event = &channel.ConcludedEvent{AdjudicatorEventBase: base}
+	case *WatcherEventMsg_Withdrawn:
+		var err error
+		if errMsg := protoEvent.Withdrawn.GetError(); errMsg != "" {
+			err = stderrors.New(errMsg)
+		}
+		msg.Event = watcher.NewWithdrawnEvent(base.IDV, base.VersionV, err)
+	default:
+		return nil, fmt.Errorf("unknown adjudicator event type: %T", protoEvent)
+	}
+	return msg, nil
+}
+
+func toWatcherTimeoutElapsedMsg(protoEnvMsg *Envelope_WatcherTimeoutElapsedMsg) (msg *remote.TimeoutElapsedMsg) {
+	protoMsg := protoEnvMsg.WatcherTimeoutElapsedMsg
+
+	msg = &remote.TimeoutElapsedMsg{}
+	msg.ID = toChannelID(protoMsg.GetId())
+	msg.TimeoutID = protoMsg.GetTimeoutId()
+	return msg
+}
+
+func toWatcherBlindedUploadMsg(protoEnvMsg *Envelope_WatcherBlindedUploadMsg) (msg *blinded.UploadMsg) {
+	protoMsg := protoEnvMsg.WatcherBlindedUploadMsg
+
+	msg = &blinded.UploadMsg{}
+	copy(msg.Hint[:], protoMsg.GetHint())
+	msg.Blob = make([]byte, len(protoMsg.GetBlob()))
+	copy(msg.Blob, protoMsg.GetBlob())
+	return msg
+}
+
+func toChannelID(protoID []byte) (id channel.ID) {
+	copy(id[:], protoID)
+	return id
+}
+
+// toTransaction converts a protobuf Transaction to a channel.Transaction.
+// Missing signatures are encoded as empty byte slices and decoded as nil.
+func toTransaction(protoTx *Transaction) (tx channel.Transaction, err error) {
+	tx.Sigs = make([]wallet.Sig, len(protoTx.GetSigs()))
+	for i, sig := range protoTx.GetSigs() {
+		if len(sig) == 0 {
+			continue
+		}
+		tx.Sigs[i] = make([]byte, len(sig))
+		copy(tx.Sigs[i], sig)
+	}
+	tx.State, err = ToState(protoTx.GetState())
+	return tx, err
+}
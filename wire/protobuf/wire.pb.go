@@ -66,6 +66,16 @@ type Envelope struct {
 	//	*Envelope_ChannelUpdateAccMsg
 	//	*Envelope_ChannelUpdateRejMsg
 	//	*Envelope_ChannelSyncMsg
+	//	*Envelope_HelloMsg
+	//	*Envelope_ReliableMsg
+	//	*Envelope_AckMsg
+	//	*Envelope_WatcherWatchMsg
+	//	*Envelope_WatcherStopMsg
+	//	*Envelope_WatcherResponseMsg
+	//	*Envelope_WatcherPublishMsg
+	//	*Envelope_WatcherEventMsg
+	//	*Envelope_WatcherTimeoutElapsedMsg
+	//	*Envelope_WatcherBlindedUploadMsg
 	Msg           isEnvelope_Msg `protobuf_oneof:"msg"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -275,6 +285,96 @@ func (x *Envelope) GetChannelSyncMsg() *ChannelSyncMsg {
 	return nil
 }
 
+func (x *Envelope) GetHelloMsg() *HelloMsg {
+	if x != nil {
+		if x, ok := x.Msg.(*Envelope_HelloMsg); ok {
+			return x.HelloMsg
+		}
+	}
+	return nil
+}
+
+func (x *Envelope) GetReliableMsg() *ReliableMsg {
+	if x != nil {
+		if x, ok := x.Msg.(*Envelope_ReliableMsg); ok {
+			return x.ReliableMsg
+		}
+	}
+	return nil
+}
+
+func (x *Envelope) GetAckMsg() *AckMsg {
+	if x != nil {
+		if x, ok := x.Msg.(*Envelope_AckMsg); ok {
+			return x.AckMsg
+		}
+	}
+	return nil
+}
+
+func (x *Envelope) GetWatcherWatchMsg() *WatcherWatchMsg {
+	if x != nil {
+		if x, ok := x.Msg.(*Envelope_WatcherWatchMsg); ok {
+			return x.WatcherWatchMsg
+		}
+	}
+	return nil
+}
+
+func (x *Envelope) GetWatcherStopMsg() *WatcherStopMsg {
+	if x != nil {
+		if x, ok := x.Msg.(*Envelope_WatcherStopMsg); ok {
+			return x.WatcherStopMsg
+		}
+	}
+	return nil
+}
+
+func (x *Envelope) GetWatcherResponseMsg() *WatcherResponseMsg {
+	if x != nil {
+		if x, ok := x.Msg.(*Envelope_WatcherResponseMsg); ok {
+			return x.WatcherResponseMsg
+		}
+	}
+	return nil
+}
+
+func (x *Envelope) GetWatcherPublishMsg() *WatcherPublishMsg {
+	if x != nil {
+		if x, ok := x.Msg.(*Envelope_WatcherPublishMsg); ok {
+			return x.WatcherPublishMsg
+		}
+	}
+	return nil
+}
+
+func (x *Envelope) GetWatcherEventMsg() *WatcherEventMsg {
+	if x != nil {
+		if x, ok := x.Msg.(*Envelope_WatcherEventMsg); ok {
+			return x.WatcherEventMsg
+		}
+	}
+	return nil
+}
+
+func (x *Envelope) GetWatcherTimeoutElapsedMsg() *WatcherTimeoutElapsedMsg {
+	if x != nil {
+		if x, ok := x.Msg.(*Envelope_WatcherTimeoutElapsedMsg); ok {
+			return x.WatcherTimeoutElapsedMsg
+		}
+	}
+	return nil
+}
+
+func (x *Envelope) GetWatcherBlindedUploadMsg() *WatcherBlindedUploadMsg {
+	if x != nil {
+		if x, ok := x.Msg.(*Envelope_WatcherBlindedUploadMsg); ok {
+			return x.WatcherBlindedUploadMsg
+		}
+	}
+	return nil
+}
+
 type isEnvelope_Msg interface {
 	isEnvelope_Msg()
 }
@@ -347,6 +447,46 @@ type Envelope_ChannelSyncMsg struct {
 	ChannelSyncMsg *ChannelSyncMsg `protobuf:"bytes,19,opt,name=channel_sync_msg,json=channelSyncMsg,proto3,oneof"`
 }
 
+type Envelope_HelloMsg struct {
+	HelloMsg *HelloMsg `protobuf:"bytes,20,opt,name=hello_msg,json=helloMsg,proto3,oneof"`
+}
+
+type Envelope_ReliableMsg struct {
+	ReliableMsg *ReliableMsg `protobuf:"bytes,21,opt,name=reliable_msg,json=reliableMsg,proto3,oneof"`
+}
+
+type Envelope_AckMsg struct {
+	AckMsg *AckMsg `protobuf:"bytes,22,opt,name=ack_msg,json=ackMsg,proto3,oneof"`
+}
+
+type Envelope_WatcherWatchMsg struct {
+	WatcherWatchMsg *WatcherWatchMsg `protobuf:"bytes,23,opt,name=watcher_watch_msg,json=watcherWatchMsg,proto3,oneof"`
+}
+
+type Envelope_WatcherStopMsg struct {
+	WatcherStopMsg *WatcherStopMsg `protobuf:"bytes,24,opt,name=watcher_stop_msg,json=watcherStopMsg,proto3,oneof"`
+}
+
+type Envelope_WatcherResponseMsg struct {
+	WatcherResponseMsg *WatcherResponseMsg `protobuf:"bytes,25,opt,name=watcher_response_msg,json=watcherResponseMsg,proto3,oneof"`
+}
+
+type Envelope_WatcherPublishMsg struct {
+	WatcherPublishMsg *WatcherPublishMsg `protobuf:"bytes,26,opt,name=watcher_publish_msg,json=watcherPublishMsg,proto3,oneof"`
+}
+
+type Envelope_WatcherEventMsg struct {
+	WatcherEventMsg *WatcherEventMsg `protobuf:"bytes,27,opt,name=watcher_event_msg,json=watcherEventMsg,proto3,oneof"`
+}
+
+type Envelope_WatcherTimeoutElapsedMsg struct {
+	WatcherTimeoutElapsedMsg *WatcherTimeoutElapsedMsg `protobuf:"bytes,28,opt,name=watcher_timeout_elapsed_msg,json=watcherTimeoutElapsedMsg,proto3,oneof"`
+}
+
+type Envelope_WatcherBlindedUploadMsg struct {
+	WatcherBlindedUploadMsg *WatcherBlindedUploadMsg `protobuf:"bytes,29,opt,name=watcher_blinded_upload_msg,json=watcherBlindedUploadMsg,proto3,oneof"`
+}
+
 func (*Envelope_PingMsg) isEnvelope_Msg() {}
 
 func (*Envelope_PongMsg) isEnvelope_Msg() {}
@@ -381,6 +521,26 @@ func (*Envelope_ChannelUpdateRejMsg) isEnvelope_Msg() {}
 
 func (*Envelope_ChannelSyncMsg) isEnvelope_Msg() {}
 
+func (*Envelope_HelloMsg) isEnvelope_Msg() {}
+
+func (*Envelope_ReliableMsg) isEnvelope_Msg() {}
+
+func (*Envelope_AckMsg) isEnvelope_Msg() {}
+
+func (*Envelope_WatcherWatchMsg) isEnvelope_Msg() {}
+
+func (*Envelope_WatcherStopMsg) isEnvelope_Msg() {}
+
+func (*Envelope_WatcherResponseMsg) isEnvelope_Msg() {}
+
+func (*Envelope_WatcherPublishMsg) isEnvelope_Msg() {}
+
+func (*Envelope_WatcherEventMsg) isEnvelope_Msg() {}
+
+func (*Envelope_WatcherTimeoutElapsedMsg) isEnvelope_Msg() {}
+
+func (*Envelope_WatcherBlindedUploadMsg) isEnvelope_Msg() {}
+
 // Balance represents the balance of a single asset, for all the channel
 // participants.
 type Balance struct {
@@ -2174,156 +2334,1045 @@ func (x *ChannelSyncMsg) GetCurrentTx() *Transaction {
 	return nil
 }
 
-var File_wire_protobuf_wire_proto protoreflect.FileDescriptor
+// HelloMsg represents wire.HelloMsg.
+type HelloMsg struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       uint32                 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Features      uint64                 `protobuf:"varint,2,opt,name=features,proto3" json:"features,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_wire_protobuf_wire_proto_rawDesc = "" +
-	"\n" +
-	"\x18wire/protobuf/wire.proto\x12\tperunwire\"\xf5\f\n" +
-	"\bEnvelope\x12*\n" +
-	"\x06sender\x18\x01 \x01(\v2\x12.perunwire.AddressR\x06sender\x120\n" +
-	"\trecipient\x18\x02 \x01(\v2\x12.perunwire.AddressR\trecipient\x12/\n" +
-	"\bping_msg\x18\x03 \x01(\v2\x12.perunwire.PingMsgH\x00R\apingMsg\x12/\n" +
-	"\bpong_msg\x18\x04 \x01(\v2\x12.perunwire.PongMsgH\x00R\apongMsg\x12;\n" +
-	"\fshutdown_msg\x18\x05 \x01(\v2\x16.perunwire.ShutdownMsgH\x00R\vshutdownMsg\x12H\n" +
-	"\x11auth_response_msg\x18\x06 \x01(\v2\x1a.perunwire.AuthResponseMsgH\x00R\x0fauthResponseMsg\x12d\n" +
-	"\x1bledger_channel_proposal_msg\x18\a \x01(\v2#.perunwire.LedgerChannelProposalMsgH\x00R\x18ledgerChannelProposalMsg\x12n\n" +
-	"\x1fledger_channel_proposal_acc_msg\x18\b \x01(\v2&.perunwire.LedgerChannelProposalAccMsgH\x00R\x1bledgerChannelProposalAccMsg\x12[\n" +
-	"\x18sub_channel_proposal_msg\x18\t \x01(\v2 .perunwire.SubChannelProposalMsgH\x00R\x15subChannelProposalMsg\x12e\n" +
-	"\x1csub_channel_proposal_acc_msg\x18\n" +
-	" \x01(\v2#.perunwire.SubChannelProposalAccMsgH\x00R\x18subChannelProposalAccMsg\x12g\n" +
-	"\x1cvirtual_channel_proposal_msg\x18\v \x01(\v2$.perunwire.VirtualChannelProposalMsgH\x00R\x19virtualChannelProposalMsg\x12q\n" +
-	" virtual_channel_proposal_acc_msg\x18\f \x01(\v2'.perunwire.VirtualChannelProposalAccMsgH\x00R\x1cvirtualChannelProposalAccMsg\x12[\n" +
-	"\x18channel_proposal_rej_msg\x18\r \x01(\v2 .perunwire.ChannelProposalRejMsgH\x00R\x15channelProposalRejMsg\x12K\n" +
-	"\x12channel_update_msg\x18\x0e \x01(\v2\x1b.perunwire.ChannelUpdateMsgH\x00R\x10channelUpdateMsg\x12}\n" +
-	"$virtual_channel_funding_proposal_msg\x18\x0f \x01(\v2+.perunwire.VirtualChannelFundingProposalMsgH\x00R virtualChannelFundingProposalMsg\x12\x86\x01\n" +
-	"'virtual_channel_settlement_proposal_msg\x18\x10 \x01(\v2..perunwire.VirtualChannelSettlementProposalMsgH\x00R#virtualChannelSettlementProposalMsg\x12U\n" +
-	"\x16channel_update_acc_msg\x18\x11 \x01(\v2\x1e.perunwire.ChannelUpdateAccMsgH\x00R\x13channelUpdateAccMsg\x12U\n" +
-	"\x16channel_update_rej_msg\x18\x12 \x01(\v2\x1e.perunwire.ChannelUpdateRejMsgH\x00R\x13channelUpdateRejMsg\x12E\n" +
-	"\x10channel_sync_msg\x18\x13 \x01(\v2\x19.perunwire.ChannelSyncMsgH\x00R\x0echannelSyncMsgB\x05\n" +
-	"\x03msg\"#\n" +
-	"\aBalance\x12\x18\n" +
-	"\abalance\x18\x01 \x03(\fR\abalance\":\n" +
-	"\bBalances\x12.\n" +
-	"\bbalances\x18\x01 \x03(\v2\x12.perunwire.BalanceR\bbalances\"<\n" +
-	"\x0eAddressMapping\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\fR\x03key\x12\x18\n" +
-	"\aaddress\x18\x02 \x01(\fR\aaddress\"M\n" +
-	"\aAddress\x12B\n" +
-	"\x0faddress_mapping\x18\x01 \x03(\v2\x19.perunwire.AddressMappingR\x0eaddressMapping\"'\n" +
-	"\bIndexMap\x12\x1b\n" +
-	"\tindex_map\x18\x01 \x03(\rR\bindexMap\"t\n" +
-	"\bSubAlloc\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\fR\x02id\x12&\n" +
-	"\x04bals\x18\x02 \x01(\v2\x12.perunwire.BalanceR\x04bals\x120\n" +
-	"\tindex_map\x18\x03 \x01(\v2\x13.perunwire.IndexMapR\bindexMap\"\x9e\x01\n" +
-	"\n" +
-	"Allocation\x12\x1a\n" +
-	"\bbackends\x18\x01 \x03(\fR\bbackends\x12\x16\n" +
-	"\x06assets\x18\x02 \x03(\fR\x06assets\x12/\n" +
-	"\bbalances\x18\x03 \x01(\v2\x13.perunwire.BalancesR\bbalances\x12+\n" +
-	"\x06locked\x18\x04 \x03(\v2\x13.perunwire.SubAllocR\x06locked\"\xbd\x02\n" +
-	"\x13BaseChannelProposal\x12\x1f\n" +
-	"\vproposal_id\x18\x01 \x01(\fR\n" +
-	"proposalId\x12-\n" +
-	"\x12challenge_duration\x18\x02 \x01(\x04R\x11challengeDuration\x12\x1f\n" +
-	"\vnonce_share\x18\x03 \x01(\fR\n" +
-	"nonceShare\x12\x10\n" +
-	"\x03app\x18\x04 \x01(\fR\x03app\x12\x1b\n" +
-	"\tinit_data\x18\x05 \x01(\fR\binitData\x122\n" +
-	"\tinit_bals\x18\x06 \x01(\v2\x15.perunwire.AllocationR\binitBals\x12@\n" +
-	"\x11funding_agreement\x18\a \x01(\v2\x13.perunwire.BalancesR\x10fundingAgreement\x12\x10\n" +
-	"\x03aux\x18\b \x01(\fR\x03aux\"Z\n" +
-	"\x16BaseChannelProposalAcc\x12\x1f\n" +
-	"\vproposal_id\x18\x01 \x01(\fR\n" +
-	"proposalId\x12\x1f\n" +
-	"\vnonce_share\x18\x02 \x01(\fR\n" +
-	"nonceShare\"\xfb\x01\n" +
-	"\x06Params\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\fR\x02id\x12-\n" +
-	"\x12challenge_duration\x18\x02 \x01(\x04R\x11challengeDuration\x12(\n" +
-	"\x05parts\x18\x03 \x03(\v2\x12.perunwire.AddressR\x05parts\x12\x10\n" +
-	"\x03app\x18\x04 \x01(\fR\x03app\x12\x14\n" +
-	"\x05nonce\x18\x05 \x01(\fR\x05nonce\x12%\n" +
-	"\x0eledger_channel\x18\x06 \x01(\bR\rledgerChannel\x12'\n" +
-	"\x0fvirtual_channel\x18\a \x01(\bR\x0evirtualChannel\x12\x10\n" +
-	"\x03aux\x18\b \x01(\fR\x03aux\"\xa9\x01\n" +
-	"\x05State\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\fR\x02id\x12\x18\n" +
-	"\aversion\x18\x02 \x01(\x04R\aversion\x12\x10\n" +
-	"\x03app\x18\x03 \x01(\fR\x03app\x125\n" +
-	"\n" +
-	"allocation\x18\x04 \x01(\v2\x15.perunwire.AllocationR\n" +
-	"allocation\x12\x12\n" +
-	"\x04data\x18\x05 \x01(\fR\x04data\x12\x19\n" +
-	"\bis_final\x18\x06 \x01(\bR\aisFinal\"I\n" +
-	"\vTransaction\x12&\n" +
-	"\x05state\x18\x01 \x01(\v2\x10.perunwire.StateR\x05state\x12\x12\n" +
-	"\x04sigs\x18\x02 \x03(\fR\x04sigs\"t\n" +
-	"\vSignedState\x12)\n" +
-	"\x06params\x18\x01 \x01(\v2\x11.perunwire.ParamsR\x06params\x12&\n" +
-	"\x05state\x18\x02 \x01(\v2\x10.perunwire.StateR\x05state\x12\x12\n" +
-	"\x04sigs\x18\x03 \x03(\fR\x04sigs\"T\n" +
-	"\rChannelUpdate\x12&\n" +
-	"\x05state\x18\x01 \x01(\v2\x10.perunwire.StateR\x05state\x12\x1b\n" +
-	"\tactor_idx\x18\x02 \x01(\rR\bactorIdx\"#\n" +
-	"\aPingMsg\x12\x18\n" +
-	"\acreated\x18\x01 \x01(\x03R\acreated\"#\n" +
-	"\aPongMsg\x12\x18\n" +
-	"\acreated\x18\x01 \x01(\x03R\acreated\"%\n" +
-	"\vShutdownMsg\x12\x16\n" +
-	"\x06reason\x18\x01 \x01(\tR\x06reason\"/\n" +
-	"\x0fAuthResponseMsg\x12\x1c\n" +
-	"\tsignature\x18\x01 \x01(\fR\tsignature\"\xce\x01\n" +
-	"\x18LedgerChannelProposalMsg\x12R\n" +
-	"\x15base_channel_proposal\x18\x01 \x01(\v2\x1e.perunwire.BaseChannelProposalR\x13baseChannelProposal\x124\n" +
-	"\vparticipant\x18\x02 \x01(\v2\x12.perunwire.AddressR\vparticipant\x12(\n" +
-	"\x05peers\x18\x03 \x03(\v2\x12.perunwire.AddressR\x05peers\"\xb1\x01\n" +
-	"\x1bLedgerChannelProposalAccMsg\x12\\\n" +
-	"\x19base_channel_proposal_acc\x18\x01 \x01(\v2!.perunwire.BaseChannelProposalAccR\x16baseChannelProposalAcc\x124\n" +
-	"\vparticipant\x18\x02 \x01(\v2\x12.perunwire.AddressR\vparticipant\"\x83\x01\n" +
-	"\x15SubChannelProposalMsg\x12R\n" +
-	"\x15base_channel_proposal\x18\x01 \x01(\v2\x1e.perunwire.BaseChannelProposalR\x13baseChannelProposal\x12\x16\n" +
-	"\x06parent\x18\x02 \x01(\fR\x06parent\"x\n" +
-	"\x18SubChannelProposalAccMsg\x12\\\n" +
-	"\x19base_channel_proposal_acc\x18\x01 \x01(\v2!.perunwire.BaseChannelProposalAccR\x16baseChannelProposalAcc\"\x97\x02\n" +
-	"\x19VirtualChannelProposalMsg\x12R\n" +
-	"\x15base_channel_proposal\x18\x01 \x01(\v2\x1e.perunwire.BaseChannelProposalR\x13baseChannelProposal\x12.\n" +
-	"\bproposer\x18\x02 \x01(\v2\x12.perunwire.AddressR\bproposer\x12(\n" +
-	"\x05peers\x18\x03 \x03(\v2\x12.perunwire.AddressR\x05peers\x12\x18\n" +
-	"\aparents\x18\x04 \x03(\fR\aparents\x122\n" +
-	"\n" +
-	"index_maps\x18\x05 \x03(\v2\x13.perunwire.IndexMapR\tindexMaps\"\xae\x01\n" +
-	"\x1cVirtualChannelProposalAccMsg\x12\\\n" +
-	"\x19base_channel_proposal_acc\x18\x01 \x01(\v2!.perunwire.BaseChannelProposalAccR\x16baseChannelProposalAcc\x120\n" +
-	"\tresponder\x18\x02 \x01(\v2\x12.perunwire.AddressR\tresponder\"P\n" +
-	"\x15ChannelProposalRejMsg\x12\x1f\n" +
-	"\vproposal_id\x18\x01 \x01(\fR\n" +
-	"proposalId\x12\x16\n" +
-	"\x06reason\x18\x02 \x01(\tR\x06reason\"e\n" +
-	"\x10ChannelUpdateMsg\x12?\n" +
-	"\x0echannel_update\x18\x01 \x01(\v2\x18.perunwire.ChannelUpdateR\rchannelUpdate\x12\x10\n" +
-	"\x03sig\x18\x02 \x01(\fR\x03sig\"\xd1\x01\n" +
-	" VirtualChannelFundingProposalMsg\x12I\n" +
-	"\x12channel_update_msg\x18\x01 \x01(\v2\x1b.perunwire.ChannelUpdateMsgR\x10channelUpdateMsg\x120\n" +
-	"\ainitial\x18\x02 \x01(\v2\x16.perunwire.SignedStateR\ainitial\x120\n" +
-	"\tindex_map\x18\x03 \x01(\v2\x13.perunwire.IndexMapR\bindexMap\"\x9e\x01\n" +
-	"#VirtualChannelSettlementProposalMsg\x12I\n" +
-	"\x12channel_update_msg\x18\x01 \x01(\v2\x1b.perunwire.ChannelUpdateMsgR\x10channelUpdateMsg\x12,\n" +
-	"\x05final\x18\x02 \x01(\v2\x16.perunwire.SignedStateR\x05final\"`\n" +
-	"\x13ChannelUpdateAccMsg\x12\x1d\n" +
-	"\n" +
-	"channel_id\x18\x01 \x01(\fR\tchannelId\x12\x18\n" +
-	"\aversion\x18\x02 \x01(\x04R\aversion\x12\x10\n" +
-	"\x03sig\x18\x03 \x01(\fR\x03sig\"f\n" +
-	"\x13ChannelUpdateRejMsg\x12\x1d\n" +
-	"\n" +
-	"channel_id\x18\x01 \x01(\fR\tchannelId\x12\x18\n" +
-	"\aversion\x18\x02 \x01(\x04R\aversion\x12\x16\n" +
-	"\x06reason\x18\x03 \x01(\tR\x06reason\"]\n" +
-	"\x0eChannelSyncMsg\x12\x14\n" +
-	"\x05phase\x18\x01 \x01(\rR\x05phase\x125\n" +
+func (x *HelloMsg) Reset() {
+	*x = HelloMsg{}
+	mi := &file_wire_protobuf_wire_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HelloMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HelloMsg) ProtoMessage() {}
+
+func (x *HelloMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_protobuf_wire_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HelloMsg.ProtoReflect.Descriptor instead.
+func (*HelloMsg) Descriptor() ([]byte, []int) {
+	return file_wire_protobuf_wire_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *HelloMsg) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *HelloMsg) GetFeatures() uint64 {
+	if x != nil {
+		return x.Features
+	}
+	return 0
+}
+
+// ReliableMsg represents wire.ReliableMsg. The wrapped message is encoded as
+// an envelope without sender and recipient.
+type ReliableMsg struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Envelope      *Envelope              `protobuf:"bytes,2,opt,name=envelope,proto3" json:"envelope,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReliableMsg) Reset() {
+	*x = ReliableMsg{}
+	mi := &file_wire_protobuf_wire_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReliableMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReliableMsg) ProtoMessage() {}
+
+func (x *ReliableMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_protobuf_wire_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReliableMsg.ProtoReflect.Descriptor instead.
+func (*ReliableMsg) Descriptor() ([]byte, []int) {
+	return file_wire_protobuf_wire_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ReliableMsg) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ReliableMsg) GetEnvelope() *Envelope {
+	if x != nil {
+		return x.Envelope
+	}
+	return nil
+}
+
+// AckMsg represents wire.AckMsg.
+type AckMsg struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AckMsg) Reset() {
+	*x = AckMsg{}
+	mi := &file_wire_protobuf_wire_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AckMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AckMsg) ProtoMessage() {}
+
+func (x *AckMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_protobuf_wire_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AckMsg.ProtoReflect.Descriptor instead.
+func (*AckMsg) Descriptor() ([]byte, []int) {
+	return file_wire_protobuf_wire_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *AckMsg) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// WatcherWatchMsg represents remote.WatchMsg.
+type WatcherWatchMsg struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seq           uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Virtual       bool                   `protobuf:"varint,2,opt,name=virtual,proto3" json:"virtual,omitempty"`
+	Parents       [][]byte               `protobuf:"bytes,3,rep,name=parents,proto3" json:"parents,omitempty"`
+	Params        *Params                `protobuf:"bytes,4,opt,name=params,proto3" json:"params,omitempty"`
+	Tx            *Transaction           `protobuf:"bytes,5,opt,name=tx,proto3" json:"tx,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatcherWatchMsg) Reset() {
+	*x = WatcherWatchMsg{}
+	mi := &file_wire_protobuf_wire_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatcherWatchMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatcherWatchMsg) ProtoMessage() {}
+
+func (x *WatcherWatchMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_protobuf_wire_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatcherWatchMsg.ProtoReflect.Descriptor instead.
+func (*WatcherWatchMsg) Descriptor() ([]byte, []int) {
+	return file_wire_protobuf_wire_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *WatcherWatchMsg) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *WatcherWatchMsg) GetVirtual() bool {
+	if x != nil {
+		return x.Virtual
+	}
+	return false
+}
+
+func (x *WatcherWatchMsg) GetParents() [][]byte {
+	if x != nil {
+		return x.Parents
+	}
+	return nil
+}
+
+func (x *WatcherWatchMsg) GetParams() *Params {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *WatcherWatchMsg) GetTx() *Transaction {
+	if x != nil {
+		return x.Tx
+	}
+	return nil
+}
+
+// WatcherStopMsg represents remote.StopMsg.
+type WatcherStopMsg struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seq           uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Id            []byte                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatcherStopMsg) Reset() {
+	*x = WatcherStopMsg{}
+	mi := &file_wire_protobuf_wire_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatcherStopMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatcherStopMsg) ProtoMessage() {}
+
+func (x *WatcherStopMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_protobuf_wire_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatcherStopMsg.ProtoReflect.Descriptor instead.
+func (*WatcherStopMsg) Descriptor() ([]byte, []int) {
+	return file_wire_protobuf_wire_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *WatcherStopMsg) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *WatcherStopMsg) GetId() []byte {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+// WatcherResponseMsg represents remote.ResponseMsg.
+type WatcherResponseMsg struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seq           uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatcherResponseMsg) Reset() {
+	*x = WatcherResponseMsg{}
+	mi := &file_wire_protobuf_wire_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatcherResponseMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatcherResponseMsg) ProtoMessage() {}
+
+func (x *WatcherResponseMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_protobuf_wire_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatcherResponseMsg.ProtoReflect.Descriptor instead.
+func (*WatcherResponseMsg) Descriptor() ([]byte, []int) {
+	return file_wire_protobuf_wire_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *WatcherResponseMsg) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *WatcherResponseMsg) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// WatcherPublishMsg represents remote.PublishMsg.
+type WatcherPublishMsg struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seq           uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Tx            *Transaction           `protobuf:"bytes,2,opt,name=tx,proto3" json:"tx,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatcherPublishMsg) Reset() {
+	*x = WatcherPublishMsg{}
+	mi := &file_wire_protobuf_wire_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatcherPublishMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatcherPublishMsg) ProtoMessage() {}
+
+func (x *WatcherPublishMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_protobuf_wire_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatcherPublishMsg.ProtoReflect.Descriptor instead.
+func (*WatcherPublishMsg) Descriptor() ([]byte, []int) {
+	return file_wire_protobuf_wire_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *WatcherPublishMsg) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *WatcherPublishMsg) GetTx() *Transaction {
+	if x != nil {
+		return x.Tx
+	}
+	return nil
+}
+
+// WatcherEventMsg represents remote.EventMsg. As with the perunio encoding,
+// the timeout of the event is not transferred.
+type WatcherEventMsg struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	TimeoutId uint64                 `protobuf:"varint,1,opt,name=timeout_id,json=timeoutId,proto3" json:"timeout_id,omitempty"`
+	Id        []byte                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Version   uint64                 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	// event contains the kind specific fields of the event.
+	//
+	// Types that are valid to be assigned to Event:
+	//
+	//	*WatcherEventMsg_Registered
+	//	*WatcherEventMsg_Progressed
+	//	*WatcherEventMsg_Concluded
+	//	*WatcherEventMsg_Withdrawn
+	Event isWatcherEventMsg_Event `protobuf_oneof:"event"`
+	// deadline is the Unix time in nanoseconds at which the event's timeout
+	// elapses, or 0 if unknown.
+	Deadline      int64 `protobuf:"varint,8,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatcherEventMsg) Reset() {
+	*x = WatcherEventMsg{}
+	mi := &file_wire_protobuf_wire_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatcherEventMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatcherEventMsg) ProtoMessage() {}
+
+func (x *WatcherEventMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_protobuf_wire_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatcherEventMsg.ProtoReflect.Descriptor instead.
+func (*WatcherEventMsg) Descriptor() ([]byte, []int) {
+	return file_wire_protobuf_wire_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *WatcherEventMsg) GetTimeoutId() uint64 {
+	if x != nil {
+		return x.TimeoutId
+	}
+	return 0
+}
+
+func (x *WatcherEventMsg) GetId() []byte {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *WatcherEventMsg) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *WatcherEventMsg) GetEvent() isWatcherEventMsg_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *WatcherEventMsg) GetRegistered() *RegisteredEvent {
+	if x != nil {
+		if x, ok := x.Event.(*WatcherEventMsg_Registered); ok {
+			return x.Registered
+		}
+	}
+	return nil
+}
+
+func (x *WatcherEventMsg) GetProgressed() *ProgressedEvent {
+	if x != nil {
+		if x, ok := x.Event.(*WatcherEventMsg_Progressed); ok {
+			return x.Progressed
+		}
+	}
+	return nil
+}
+
+func (x *WatcherEventMsg) GetConcluded() *ConcludedEvent {
+	if x != nil {
+		if x, ok := x.Event.(*WatcherEventMsg_Concluded); ok {
+			return x.Concluded
+		}
+	}
+	return nil
+}
+
+func (x *WatcherEventMsg) GetWithdrawn() *WithdrawnEvent {
+	if x != nil {
+		if x, ok := x.Event.(*WatcherEventMsg_Withdrawn); ok {
+			return x.Withdrawn
+		}
+	}
+	return nil
+}
+
+func (x *WatcherEventMsg) GetDeadline() int64 {
+	if x != nil {
+		return x.Deadline
+	}
+	return 0
+}
+
+type isWatcherEventMsg_Event interface {
+	isWatcherEventMsg_Event()
+}
+
+type WatcherEventMsg_Registered struct {
+	Registered *RegisteredEvent `protobuf:"bytes,4,opt,name=registered,proto3,oneof"`
+}
+
+type WatcherEventMsg_Progressed struct {
+	Progressed *ProgressedEvent `protobuf:"bytes,5,opt,name=progressed,proto3,oneof"`
+}
+
+type WatcherEventMsg_Concluded struct {
+	Concluded *ConcludedEvent `protobuf:"bytes,6,opt,name=concluded,proto3,oneof"`
+}
+
+type WatcherEventMsg_Withdrawn struct {
+	Withdrawn *WithdrawnEvent `protobuf:"bytes,7,opt,name=withdrawn,proto3,oneof"`
+}
+
+func (*WatcherEventMsg_Registered) isWatcherEventMsg_Event() {}
+
+func (*WatcherEventMsg_Progressed) isWatcherEventMsg_Event() {}
+
+func (*WatcherEventMsg_Concluded) isWatcherEventMsg_Event() {}
+
+func (*WatcherEventMsg_Withdrawn) isWatcherEventMsg_Event() {}
+
+// RegisteredEvent represents the fields of a channel.RegisteredEvent.
+type RegisteredEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tx            *Transaction           `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisteredEvent) Reset() {
+	*x = RegisteredEvent{}
+	mi := &file_wire_protobuf_wire_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisteredEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisteredEvent) ProtoMessage() {}
+
+func (x *RegisteredEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_protobuf_wire_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisteredEvent.ProtoReflect.Descriptor instead.
+func (*RegisteredEvent) Descriptor() ([]byte, []int) {
+	return file_wire_protobuf_wire_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *RegisteredEvent) GetTx() *Transaction {
+	if x != nil {
+		return x.Tx
+	}
+	return nil
+}
+
+// ProgressedEvent represents the fields of a channel.ProgressedEvent.
+type ProgressedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         *State                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Idx           uint32                 `protobuf:"varint,2,opt,name=idx,proto3" json:"idx,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProgressedEvent) Reset() {
+	*x = ProgressedEvent{}
+	mi := &file_wire_protobuf_wire_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProgressedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProgressedEvent) ProtoMessage() {}
+
+func (x *ProgressedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_protobuf_wire_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProgressedEvent.ProtoReflect.Descriptor instead.
+func (*ProgressedEvent) Descriptor() ([]byte, []int) {
+	return file_wire_protobuf_wire_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ProgressedEvent) GetState() *State {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+func (x *ProgressedEvent) GetIdx() uint32 {
+	if x != nil {
+		return x.Idx
+	}
+	return 0
+}
+
+// ConcludedEvent represents a channel.ConcludedEvent.
+type ConcludedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConcludedEvent) Reset() {
+	*x = ConcludedEvent{}
+	mi := &file_wire_protobuf_wire_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConcludedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConcludedEvent) ProtoMessage() {}
+
+func (x *ConcludedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_protobuf_wire_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConcludedEvent.ProtoReflect.Descriptor instead.
+func (*ConcludedEvent) Descriptor() ([]byte, []int) {
+	return file_wire_protobuf_wire_proto_rawDescGZIP(), []int{42}
+}
+
+// WithdrawnEvent represents the fields of a watcher.WithdrawnEvent.
+type WithdrawnEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WithdrawnEvent) Reset() {
+	*x = WithdrawnEvent{}
+	mi := &file_wire_protobuf_wire_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WithdrawnEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WithdrawnEvent) ProtoMessage() {}
+
+func (x *WithdrawnEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_protobuf_wire_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WithdrawnEvent.ProtoReflect.Descriptor instead.
+func (*WithdrawnEvent) Descriptor() ([]byte, []int) {
+	return file_wire_protobuf_wire_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *WithdrawnEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// WatcherTimeoutElapsedMsg represents remote.TimeoutElapsedMsg.
+type WatcherTimeoutElapsedMsg struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            []byte                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TimeoutId     uint64                 `protobuf:"varint,2,opt,name=timeout_id,json=timeoutId,proto3" json:"timeout_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatcherTimeoutElapsedMsg) Reset() {
+	*x = WatcherTimeoutElapsedMsg{}
+	mi := &file_wire_protobuf_wire_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatcherTimeoutElapsedMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatcherTimeoutElapsedMsg) ProtoMessage() {}
+
+func (x *WatcherTimeoutElapsedMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_protobuf_wire_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatcherTimeoutElapsedMsg.ProtoReflect.Descriptor instead.
+func (*WatcherTimeoutElapsedMsg) Descriptor() ([]byte, []int) {
+	return file_wire_protobuf_wire_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *WatcherTimeoutElapsedMsg) GetId() []byte {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *WatcherTimeoutElapsedMsg) GetTimeoutId() uint64 {
+	if x != nil {
+		return x.TimeoutId
+	}
+	return 0
+}
+
+// WatcherBlindedUploadMsg represents blinded.UploadMsg.
+type WatcherBlindedUploadMsg struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hint          []byte                 `protobuf:"bytes,1,opt,name=hint,proto3" json:"hint,omitempty"`
+	Blob          []byte                 `protobuf:"bytes,2,opt,name=blob,proto3" json:"blob,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatcherBlindedUploadMsg) Reset() {
+	*x = WatcherBlindedUploadMsg{}
+	mi := &file_wire_protobuf_wire_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatcherBlindedUploadMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatcherBlindedUploadMsg) ProtoMessage() {}
+
+func (x *WatcherBlindedUploadMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_wire_protobuf_wire_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatcherBlindedUploadMsg.ProtoReflect.Descriptor instead.
+func (*WatcherBlindedUploadMsg) Descriptor() ([]byte, []int) {
+	return file_wire_protobuf_wire_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *WatcherBlindedUploadMsg) GetHint() []byte {
+	if x != nil {
+		return x.Hint
+	}
+	return nil
+}
+
+func (x *WatcherBlindedUploadMsg) GetBlob() []byte {
+	if x != nil {
+		return x.Blob
+	}
+	return nil
+}
+
+var File_wire_protobuf_wire_proto protoreflect.FileDescriptor
+
+const file_wire_protobuf_wire_proto_rawDesc = "" +
+	"\n" +
+	"\x18wire/protobuf/wire.proto\x12\tperunwire\"\xdb\x12\n" +
+	"\bEnvelope\x12*\n" +
+	"\x06sender\x18\x01 \x01(\v2\x12.perunwire.AddressR\x06sender\x120\n" +
+	"\trecipient\x18\x02 \x01(\v2\x12.perunwire.AddressR\trecipient\x12/\n" +
+	"\bping_msg\x18\x03 \x01(\v2\x12.perunwire.PingMsgH\x00R\apingMsg\x12/\n" +
+	"\bpong_msg\x18\x04 \x01(\v2\x12.perunwire.PongMsgH\x00R\apongMsg\x12;\n" +
+	"\fshutdown_msg\x18\x05 \x01(\v2\x16.perunwire.ShutdownMsgH\x00R\vshutdownMsg\x12H\n" +
+	"\x11auth_response_msg\x18\x06 \x01(\v2\x1a.perunwire.AuthResponseMsgH\x00R\x0fauthResponseMsg\x12d\n" +
+	"\x1bledger_channel_proposal_msg\x18\a \x01(\v2#.perunwire.LedgerChannelProposalMsgH\x00R\x18ledgerChannelProposalMsg\x12n\n" +
+	"\x1fledger_channel_proposal_acc_msg\x18\b \x01(\v2&.perunwire.LedgerChannelProposalAccMsgH\x00R\x1bledgerChannelProposalAccMsg\x12[\n" +
+	"\x18sub_channel_proposal_msg\x18\t \x01(\v2 .perunwire.SubChannelProposalMsgH\x00R\x15subChannelProposalMsg\x12e\n" +
+	"\x1csub_channel_proposal_acc_msg\x18\n" +
+	" \x01(\v2#.perunwire.SubChannelProposalAccMsgH\x00R\x18subChannelProposalAccMsg\x12g\n" +
+	"\x1cvirtual_channel_proposal_msg\x18\v \x01(\v2$.perunwire.VirtualChannelProposalMsgH\x00R\x19virtualChannelProposalMsg\x12q\n" +
+	" virtual_channel_proposal_acc_msg\x18\f \x01(\v2'.perunwire.VirtualChannelProposalAccMsgH\x00R\x1cvirtualChannelProposalAccMsg\x12[\n" +
+	"\x18channel_proposal_rej_msg\x18\r \x01(\v2 .perunwire.ChannelProposalRejMsgH\x00R\x15channelProposalRejMsg\x12K\n" +
+	"\x12channel_update_msg\x18\x0e \x01(\v2\x1b.perunwire.ChannelUpdateMsgH\x00R\x10channelUpdateMsg\x12}\n" +
+	"$virtual_channel_funding_proposal_msg\x18\x0f \x01(\v2+.perunwire.VirtualChannelFundingProposalMsgH\x00R virtualChannelFundingProposalMsg\x12\x86\x01\n" +
+	"'virtual_channel_settlement_proposal_msg\x18\x10 \x01(\v2..perunwire.VirtualChannelSettlementProposalMsgH\x00R#virtualChannelSettlementProposalMsg\x12U\n" +
+	"\x16channel_update_acc_msg\x18\x11 \x01(\v2\x1e.perunwire.ChannelUpdateAccMsgH\x00R\x13channelUpdateAccMsg\x12U\n" +
+	"\x16channel_update_rej_msg\x18\x12 \x01(\v2\x1e.perunwire.ChannelUpdateRejMsgH\x00R\x13channelUpdateRejMsg\x12E\n" +
+	"\x10channel_sync_msg\x18\x13 \x01(\v2\x19.perunwire.ChannelSyncMsgH\x00R\x0echannelSyncMsg\x122\n" +
+	"\thello_msg\x18\x14 \x01(\v2\x13.perunwire.HelloMsgH\x00R\bhelloMsg\x12;\n" +
+	"\freliable_msg\x18\x15 \x01(\v2\x16.perunwire.ReliableMsgH\x00R\vreliableMsg\x12,\n" +
+	"\aack_msg\x18\x16 \x01(\v2\x11.perunwire.AckMsgH\x00R\x06ackMsg\x12H\n" +
+	"\x11watcher_watch_msg\x18\x17 \x01(\v2\x1a.perunwire.WatcherWatchMsgH\x00R\x0fwatcherWatchMsg\x12E\n" +
+	"\x10watcher_stop_msg\x18\x18 \x01(\v2\x19.perunwire.WatcherStopMsgH\x00R\x0ewatcherStopMsg\x12Q\n" +
+	"\x14watcher_response_msg\x18\x19 \x01(\v2\x1d.perunwire.WatcherResponseMsgH\x00R\x12watcherResponseMsg\x12N\n" +
+	"\x13watcher_publish_msg\x18\x1a \x01(\v2\x1c.perunwire.WatcherPublishMsgH\x00R\x11watcherPublishMsg\x12H\n" +
+	"\x11watcher_event_msg\x18\x1b \x01(\v2\x1a.perunwire.WatcherEventMsgH\x00R\x0fwatcherEventMsg\x12d\n" +
+	"\x1bwatcher_timeout_elapsed_msg\x18\x1c \x01(\v2#.perunwire.WatcherTimeoutElapsedMsgH\x00R\x18watcherTimeoutElapsedMsg\x12a\n" +
+	"\x1awatcher_blinded_upload_msg\x18\x1d \x01(\v2\".perunwire.WatcherBlindedUploadMsgH\x00R\x17watcherBlindedUploadMsgB\x05\n" +
+	"\x03msg\"#\n" +
+	"\aBalance\x12\x18\n" +
+	"\abalance\x18\x01 \x03(\fR\abalance\":\n" +
+	"\bBalances\x12.\n" +
+	"\bbalances\x18\x01 \x03(\v2\x12.perunwire.BalanceR\bbalances\"<\n" +
+	"\x0eAddressMapping\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\fR\x03key\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\fR\aaddress\"M\n" +
+	"\aAddress\x12B\n" +
+	"\x0faddress_mapping\x18\x01 \x03(\v2\x19.perunwire.AddressMappingR\x0eaddressMapping\"'\n" +
+	"\bIndexMap\x12\x1b\n" +
+	"\tindex_map\x18\x01 \x03(\rR\bindexMap\"t\n" +
+	"\bSubAlloc\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\fR\x02id\x12&\n" +
+	"\x04bals\x18\x02 \x01(\v2\x12.perunwire.BalanceR\x04bals\x120\n" +
+	"\tindex_map\x18\x03 \x01(\v2\x13.perunwire.IndexMapR\bindexMap\"\x9e\x01\n" +
+	"\n" +
+	"Allocation\x12\x1a\n" +
+	"\bbackends\x18\x01 \x03(\fR\bbackends\x12\x16\n" +
+	"\x06assets\x18\x02 \x03(\fR\x06assets\x12/\n" +
+	"\bbalances\x18\x03 \x01(\v2\x13.perunwire.BalancesR\bbalances\x12+\n" +
+	"\x06locked\x18\x04 \x03(\v2\x13.perunwire.SubAllocR\x06locked\"\xbd\x02\n" +
+	"\x13BaseChannelProposal\x12\x1f\n" +
+	"\vproposal_id\x18\x01 \x01(\fR\n" +
+	"proposalId\x12-\n" +
+	"\x12challenge_duration\x18\x02 \x01(\x04R\x11challengeDuration\x12\x1f\n" +
+	"\vnonce_share\x18\x03 \x01(\fR\n" +
+	"nonceShare\x12\x10\n" +
+	"\x03app\x18\x04 \x01(\fR\x03app\x12\x1b\n" +
+	"\tinit_data\x18\x05 \x01(\fR\binitData\x122\n" +
+	"\tinit_bals\x18\x06 \x01(\v2\x15.perunwire.AllocationR\binitBals\x12@\n" +
+	"\x11funding_agreement\x18\a \x01(\v2\x13.perunwire.BalancesR\x10fundingAgreement\x12\x10\n" +
+	"\x03aux\x18\b \x01(\fR\x03aux\"Z\n" +
+	"\x16BaseChannelProposalAcc\x12\x1f\n" +
+	"\vproposal_id\x18\x01 \x01(\fR\n" +
+	"proposalId\x12\x1f\n" +
+	"\vnonce_share\x18\x02 \x01(\fR\n" +
+	"nonceShare\"\xfb\x01\n" +
+	"\x06Params\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\fR\x02id\x12-\n" +
+	"\x12challenge_duration\x18\x02 \x01(\x04R\x11challengeDuration\x12(\n" +
+	"\x05parts\x18\x03 \x03(\v2\x12.perunwire.AddressR\x05parts\x12\x10\n" +
+	"\x03app\x18\x04 \x01(\fR\x03app\x12\x14\n" +
+	"\x05nonce\x18\x05 \x01(\fR\x05nonce\x12%\n" +
+	"\x0eledger_channel\x18\x06 \x01(\bR\rledgerChannel\x12'\n" +
+	"\x0fvirtual_channel\x18\a \x01(\bR\x0evirtualChannel\x12\x10\n" +
+	"\x03aux\x18\b \x01(\fR\x03aux\"\xa9\x01\n" +
+	"\x05State\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\fR\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x04R\aversion\x12\x10\n" +
+	"\x03app\x18\x03 \x01(\fR\x03app\x125\n" +
+	"\n" +
+	"allocation\x18\x04 \x01(\v2\x15.perunwire.AllocationR\n" +
+	"allocation\x12\x12\n" +
+	"\x04data\x18\x05 \x01(\fR\x04data\x12\x19\n" +
+	"\bis_final\x18\x06 \x01(\bR\aisFinal\"I\n" +
+	"\vTransaction\x12&\n" +
+	"\x05state\x18\x01 \x01(\v2\x10.perunwire.StateR\x05state\x12\x12\n" +
+	"\x04sigs\x18\x02 \x03(\fR\x04sigs\"t\n" +
+	"\vSignedState\x12)\n" +
+	"\x06params\x18\x01 \x01(\v2\x11.perunwire.ParamsR\x06params\x12&\n" +
+	"\x05state\x18\x02 \x01(\v2\x10.perunwire.StateR\x05state\x12\x12\n" +
+	"\x04sigs\x18\x03 \x03(\fR\x04sigs\"T\n" +
+	"\rChannelUpdate\x12&\n" +
+	"\x05state\x18\x01 \x01(\v2\x10.perunwire.StateR\x05state\x12\x1b\n" +
+	"\tactor_idx\x18\x02 \x01(\rR\bactorIdx\"#\n" +
+	"\aPingMsg\x12\x18\n" +
+	"\acreated\x18\x01 \x01(\x03R\acreated\"#\n" +
+	"\aPongMsg\x12\x18\n" +
+	"\acreated\x18\x01 \x01(\x03R\acreated\"%\n" +
+	"\vShutdownMsg\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\"/\n" +
+	"\x0fAuthResponseMsg\x12\x1c\n" +
+	"\tsignature\x18\x01 \x01(\fR\tsignature\"\xce\x01\n" +
+	"\x18LedgerChannelProposalMsg\x12R\n" +
+	"\x15base_channel_proposal\x18\x01 \x01(\v2\x1e.perunwire.BaseChannelProposalR\x13baseChannelProposal\x124\n" +
+	"\vparticipant\x18\x02 \x01(\v2\x12.perunwire.AddressR\vparticipant\x12(\n" +
+	"\x05peers\x18\x03 \x03(\v2\x12.perunwire.AddressR\x05peers\"\xb1\x01\n" +
+	"\x1bLedgerChannelProposalAccMsg\x12\\\n" +
+	"\x19base_channel_proposal_acc\x18\x01 \x01(\v2!.perunwire.BaseChannelProposalAccR\x16baseChannelProposalAcc\x124\n" +
+	"\vparticipant\x18\x02 \x01(\v2\x12.perunwire.AddressR\vparticipant\"\x83\x01\n" +
+	"\x15SubChannelProposalMsg\x12R\n" +
+	"\x15base_channel_proposal\x18\x01 \x01(\v2\x1e.perunwire.BaseChannelProposalR\x13baseChannelProposal\x12\x16\n" +
+	"\x06parent\x18\x02 \x01(\fR\x06parent\"x\n" +
+	"\x18SubChannelProposalAccMsg\x12\\\n" +
+	"\x19base_channel_proposal_acc\x18\x01 \x01(\v2!.perunwire.BaseChannelProposalAccR\x16baseChannelProposalAcc\"\x97\x02\n" +
+	"\x19VirtualChannelProposalMsg\x12R\n" +
+	"\x15base_channel_proposal\x18\x01 \x01(\v2\x1e.perunwire.BaseChannelProposalR\x13baseChannelProposal\x12.\n" +
+	"\bproposer\x18\x02 \x01(\v2\x12.perunwire.AddressR\bproposer\x12(\n" +
+	"\x05peers\x18\x03 \x03(\v2\x12.perunwire.AddressR\x05peers\x12\x18\n" +
+	"\aparents\x18\x04 \x03(\fR\aparents\x122\n" +
+	"\n" +
+	"index_maps\x18\x05 \x03(\v2\x13.perunwire.IndexMapR\tindexMaps\"\xae\x01\n" +
+	"\x1cVirtualChannelProposalAccMsg\x12\\\n" +
+	"\x19base_channel_proposal_acc\x18\x01 \x01(\v2!.perunwire.BaseChannelProposalAccR\x16baseChannelProposalAcc\x120\n" +
+	"\tresponder\x18\x02 \x01(\v2\x12.perunwire.AddressR\tresponder\"P\n" +
+	"\x15ChannelProposalRejMsg\x12\x1f\n" +
+	"\vproposal_id\x18\x01 \x01(\fR\n" +
+	"proposalId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"e\n" +
+	"\x10ChannelUpdateMsg\x12?\n" +
+	"\x0echannel_update\x18\x01 \x01(\v2\x18.perunwire.ChannelUpdateR\rchannelUpdate\x12\x10\n" +
+	"\x03sig\x18\x02 \x01(\fR\x03sig\"\xd1\x01\n" +
+	" VirtualChannelFundingProposalMsg\x12I\n" +
+	"\x12channel_update_msg\x18\x01 \x01(\v2\x1b.perunwire.ChannelUpdateMsgR\x10channelUpdateMsg\x120\n" +
+	"\ainitial\x18\x02 \x01(\v2\x16.perunwire.SignedStateR\ainitial\x120\n" +
+	"\tindex_map\x18\x03 \x01(\v2\x13.perunwire.IndexMapR\bindexMap\"\x9e\x01\n" +
+	"#VirtualChannelSettlementProposalMsg\x12I\n" +
+	"\x12channel_update_msg\x18\x01 \x01(\v2\x1b.perunwire.ChannelUpdateMsgR\x10channelUpdateMsg\x12,\n" +
+	"\x05final\x18\x02 \x01(\v2\x16.perunwire.SignedStateR\x05final\"`\n" +
+	"\x13ChannelUpdateAccMsg\x12\x1d\n" +
+	"\n" +
+	"channel_id\x18\x01 \x01(\fR\tchannelId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x04R\aversion\x12\x10\n" +
+	"\x03sig\x18\x03 \x01(\fR\x03sig\"f\n" +
+	"\x13ChannelUpdateRejMsg\x12\x1d\n" +
+	"\n" +
+	"channel_id\x18\x01 \x01(\fR\tchannelId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x04R\aversion\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"]\n" +
+	"\x0eChannelSyncMsg\x12\x14\n" +
+	"\x05phase\x18\x01 \x01(\rR\x05phase\x125\n" +
+	"\n" +
+	"current_tx\x18\x02 \x01(\v2\x16.perunwire.TransactionR\tcurrentTx\"@\n" +
+	"\bHelloMsg\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\rR\aversion\x12\x1a\n" +
+	"\bfeatures\x18\x02 \x01(\x04R\bfeatures\"N\n" +
+	"\vReliableMsg\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12/\n" +
+	"\benvelope\x18\x02 \x01(\v2\x13.perunwire.EnvelopeR\benvelope\"\x18\n" +
+	"\x06AckMsg\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\xaa\x01\n" +
+	"\x0fWatcherWatchMsg\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x12\x18\n" +
+	"\avirtual\x18\x02 \x01(\bR\avirtual\x12\x18\n" +
+	"\aparents\x18\x03 \x03(\fR\aparents\x12)\n" +
+	"\x06params\x18\x04 \x01(\v2\x11.perunwire.ParamsR\x06params\x12&\n" +
+	"\x02tx\x18\x05 \x01(\v2\x16.perunwire.TransactionR\x02tx\"2\n" +
+	"\x0eWatcherStopMsg\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\fR\x02id\"<\n" +
+	"\x12WatcherResponseMsg\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"M\n" +
+	"\x11WatcherPublishMsg\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x12&\n" +
+	"\x02tx\x18\x02 \x01(\v2\x16.perunwire.TransactionR\x02tx\"\xf1\x02\n" +
+	"\x0fWatcherEventMsg\x12\x1d\n" +
+	"\n" +
+	"timeout_id\x18\x01 \x01(\x04R\ttimeoutId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\fR\x02id\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x04R\aversion\x12<\n" +
+	"\n" +
+	"registered\x18\x04 \x01(\v2\x1a.perunwire.RegisteredEventH\x00R\n" +
+	"registered\x12<\n" +
+	"\n" +
+	"progressed\x18\x05 \x01(\v2\x1a.perunwire.ProgressedEventH\x00R\n" +
+	"progressed\x129\n" +
+	"\tconcluded\x18\x06 \x01(\v2\x19.perunwire.ConcludedEventH\x00R\tconcluded\x129\n" +
+	"\twithdrawn\x18\a \x01(\v2\x19.perunwire.WithdrawnEventH\x00R\twithdrawn\x12\x1a\n" +
+	"\bdeadline\x18\b \x01(\x03R\bdeadlineB\a\n" +
+	"\x05event\"9\n" +
+	"\x0fRegisteredEvent\x12&\n" +
+	"\x02tx\x18\x01 \x01(\v2\x16.perunwire.TransactionR\x02tx\"K\n" +
+	"\x0fProgressedEvent\x12&\n" +
+	"\x05state\x18\x01 \x01(\v2\x10.perunwire.StateR\x05state\x12\x10\n" +
+	"\x03idx\x18\x02 \x01(\rR\x03idx\"\x10\n" +
+	"\x0eConcludedEvent\"&\n" +
+	"\x0eWithdrawnEvent\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\"I\n" +
+	"\x18WatcherTimeoutElapsedMsg\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\fR\x02id\x12\x1d\n" +
 	"\n" +
-	"current_tx\x18\x02 \x01(\v2\x16.perunwire.TransactionR\tcurrentTxB&Z$perun.network/go-perun/wire/protobufb\x06proto3"
+	"timeout_id\x18\x02 \x01(\x04R\ttimeoutId\"A\n" +
+	"\x17WatcherBlindedUploadMsg\x12\x12\n" +
+	"\x04hint\x18\x01 \x01(\fR\x04hint\x12\x12\n" +
+	"\x04blob\x18\x02 \x01(\fR\x04blobB&Z$perun.network/go-perun/wire/protobufb\x06proto3"
 
 var (
 	file_wire_protobuf_wire_proto_rawDescOnce sync.Once
@@ -2337,7 +3386,7 @@ func file_wire_protobuf_wire_proto_rawDescGZIP() []byte {
 	return file_wire_protobuf_wire_proto_rawDescData
 }
 
-var file_wire_protobuf_wire_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
+var file_wire_protobuf_wire_proto_msgTypes = make([]protoimpl.MessageInfo, 46)
 var file_wire_protobuf_wire_proto_goTypes = []any{
 	(*Envelope)(nil),                            // 0: perunwire.Envelope
 	(*Balance)(nil),                             // 1: perunwire.Balance
@@ -2371,6 +3420,20 @@ var file_wire_protobuf_wire_proto_goTypes = []any{
 	(*ChannelUpdateAccMsg)(nil),                 // 29: perunwire.ChannelUpdateAccMsg
 	(*ChannelUpdateRejMsg)(nil),                 // 30: perunwire.ChannelUpdateRejMsg
 	(*ChannelSyncMsg)(nil),                      // 31: perunwire.ChannelSyncMsg
+	(*HelloMsg)(nil),                            // 32: perunwire.HelloMsg
+	(*ReliableMsg)(nil),                         // 33: perunwire.ReliableMsg
+	(*AckMsg)(nil),                              // 34: perunwire.AckMsg
+	(*WatcherWatchMsg)(nil),                     // 35: perunwire.WatcherWatchMsg
+	(*WatcherStopMsg)(nil),                      // 36: perunwire.WatcherStopMsg
+	(*WatcherResponseMsg)(nil),                  // 37: perunwire.WatcherResponseMsg
+	(*WatcherPublishMsg)(nil),                   // 38: perunwire.WatcherPublishMsg
+	(*WatcherEventMsg)(nil),                     // 39: perunwire.WatcherEventMsg
+	(*RegisteredEvent)(nil),                     // 40: perunwire.RegisteredEvent
+	(*ProgressedEvent)(nil),                     // 41: perunwire.ProgressedEvent
+	(*ConcludedEvent)(nil),                      // 42: perunwire.ConcludedEvent
+	(*WithdrawnEvent)(nil),                      // 43: perunwire.WithdrawnEvent
+	(*WatcherTimeoutElapsedMsg)(nil),            // 44: perunwire.WatcherTimeoutElapsedMsg
+	(*WatcherBlindedUploadMsg)(nil),             // 45: perunwire.WatcherBlindedUploadMsg
 }
 var file_wire_protobuf_wire_proto_depIdxs = []int32{
 	4,  // 0: perunwire.Envelope.sender:type_name -> perunwire.Address
@@ -2392,45 +3455,65 @@ var file_wire_protobuf_wire_proto_depIdxs = []int32{
 	29, // 16: perunwire.Envelope.channel_update_acc_msg:type_name -> perunwire.ChannelUpdateAccMsg
 	30, // 17: perunwire.Envelope.channel_update_rej_msg:type_name -> perunwire.ChannelUpdateRejMsg
 	31, // 18: perunwire.Envelope.channel_sync_msg:type_name -> perunwire.ChannelSyncMsg
-	1,  // 19: perunwire.Balances.balances:type_name -> perunwire.Balance
-	3,  // 20: perunwire.Address.address_mapping:type_name -> perunwire.AddressMapping
-	1,  // 21: perunwire.SubAlloc.bals:type_name -> perunwire.Balance
-	5,  // 22: perunwire.SubAlloc.index_map:type_name -> perunwire.IndexMap
-	2,  // 23: perunwire.Allocation.balances:type_name -> perunwire.Balances
-	6,  // 24: perunwire.Allocation.locked:type_name -> perunwire.SubAlloc
-	7,  // 25: perunwire.BaseChannelProposal.init_bals:type_name -> perunwire.Allocation
-	2,  // 26: perunwire.BaseChannelProposal.funding_agreement:type_name -> perunwire.Balances
-	4,  // 27: perunwire.Params.parts:type_name -> perunwire.Address
-	7,  // 28: perunwire.State.allocation:type_name -> perunwire.Allocation
-	11, // 29: perunwire.Transaction.state:type_name -> perunwire.State
-	10, // 30: perunwire.SignedState.params:type_name -> perunwire.Params
-	11, // 31: perunwire.SignedState.state:type_name -> perunwire.State
-	11, // 32: perunwire.ChannelUpdate.state:type_name -> perunwire.State
-	8,  // 33: perunwire.LedgerChannelProposalMsg.base_channel_proposal:type_name -> perunwire.BaseChannelProposal
-	4,  // 34: perunwire.LedgerChannelProposalMsg.participant:type_name -> perunwire.Address
-	4,  // 35: perunwire.LedgerChannelProposalMsg.peers:type_name -> perunwire.Address
-	9,  // 36: perunwire.LedgerChannelProposalAccMsg.base_channel_proposal_acc:type_name -> perunwire.BaseChannelProposalAcc
-	4,  // 37: perunwire.LedgerChannelProposalAccMsg.participant:type_name -> perunwire.Address
-	8,  // 38: perunwire.SubChannelProposalMsg.base_channel_proposal:type_name -> perunwire.BaseChannelProposal
-	9,  // 39: perunwire.SubChannelProposalAccMsg.base_channel_proposal_acc:type_name -> perunwire.BaseChannelProposalAcc
-	8,  // 40: perunwire.VirtualChannelProposalMsg.base_channel_proposal:type_name -> perunwire.BaseChannelProposal
-	4,  // 41: perunwire.VirtualChannelProposalMsg.proposer:type_name -> perunwire.Address
-	4,  // 42: perunwire.VirtualChannelProposalMsg.peers:type_name -> perunwire.Address
-	5,  // 43: perunwire.VirtualChannelProposalMsg.index_maps:type_name -> perunwire.IndexMap
-	9,  // 44: perunwire.VirtualChannelProposalAccMsg.base_channel_proposal_acc:type_name -> perunwire.BaseChannelProposalAcc
-	4,  // 45: perunwire.VirtualChannelProposalAccMsg.responder:type_name -> perunwire.Address
-	14, // 46: perunwire.ChannelUpdateMsg.channel_update:type_name -> perunwire.ChannelUpdate
-	26, // 47: perunwire.VirtualChannelFundingProposalMsg.channel_update_msg:type_name -> perunwire.ChannelUpdateMsg
-	13, // 48: perunwire.VirtualChannelFundingProposalMsg.initial:type_name -> perunwire.SignedState
-	5,  // 49: perunwire.VirtualChannelFundingProposalMsg.index_map:type_name -> perunwire.IndexMap
-	26, // 50: perunwire.VirtualChannelSettlementProposalMsg.channel_update_msg:type_name -> perunwire.ChannelUpdateMsg
-	13, // 51: perunwire.VirtualChannelSettlementProposalMsg.final:type_name -> perunwire.SignedState
-	12, // 52: perunwire.ChannelSyncMsg.current_tx:type_name -> perunwire.Transaction
-	53, // [53:53] is the sub-list for method output_type
-	53, // [53:53] is the sub-list for method input_type
-	53, // [53:53] is the sub-list for extension type_name
-	53, // [53:53] is the sub-list for extension extendee
-	0,  // [0:53] is the sub-list for field type_name
+	32, // 19: perunwire.Envelope.hello_msg:type_name -> perunwire.HelloMsg
+	33, // 20: perunwire.Envelope.reliable_msg:type_name -> perunwire.ReliableMsg
+	34, // 21: perunwire.Envelope.ack_msg:type_name -> perunwire.AckMsg
+	35, // 22: perunwire.Envelope.watcher_watch_msg:type_name -> perunwire.WatcherWatchMsg
+	36, // 23: perunwire.Envelope.watcher_stop_msg:type_name -> perunwire.WatcherStopMsg
+	37, // 24: perunwire.Envelope.watcher_response_msg:type_name -> perunwire.WatcherResponseMsg
+	38, // 25: perunwire.Envelope.watcher_publish_msg:type_name -> perunwire.WatcherPublishMsg
+	39, // 26: perunwire.Envelope.watcher_event_msg:type_name -> perunwire.WatcherEventMsg
+	44, // 27: perunwire.Envelope.watcher_timeout_elapsed_msg:type_name -> perunwire.WatcherTimeoutElapsedMsg
+	45, // 28: perunwire.Envelope.watcher_blinded_upload_msg:type_name -> perunwire.WatcherBlindedUploadMsg
+	1,  // 29: perunwire.Balances.balances:type_name -> perunwire.Balance
+	3,  // 30: perunwire.Address.address_mapping:type_name -> perunwire.AddressMapping
+	1,  // 31: perunwire.SubAlloc.bals:type_name -> perunwire.Balance
+	5,  // 32: perunwire.SubAlloc.index_map:type_name -> perunwire.IndexMap
+	2,  // 33: perunwire.Allocation.balances:type_name -> perunwire.Balances
+	6,  // 34: perunwire.Allocation.locked:type_name -> perunwire.SubAlloc
+	7,  // 35: perunwire.BaseChannelProposal.init_bals:type_name -> perunwire.Allocation
+	2,  // 36: perunwire.BaseChannelProposal.funding_agreement:type_name -> perunwire.Balances
+	4,  // 37: perunwire.Params.parts:type_name -> perunwire.Address
+	7,  // 38: perunwire.State.allocation:type_name -> perunwire.Allocation
+	11, // 39: perunwire.Transaction.state:type_name -> perunwire.State
+	10, // 40: perunwire.SignedState.params:type_name -> perunwire.Params
+	11, // 41: perunwire.SignedState.state:type_name -> perunwire.State
+	11, // 42: perunwire.ChannelUpdate.state:type_name -> perunwire.State
+	8,  // 43: perunwire.LedgerChannelProposalMsg.base_channel_proposal:type_name -> perunwire.BaseChannelProposal
+	4,  // 44: perunwire.LedgerChannelProposalMsg.participant:type_name -> perunwire.Address
+	4,  // 45: perunwire.LedgerChannelProposalMsg.peers:type_name -> perunwire.Address
+	9,  // 46: perunwire.LedgerChannelProposalAccMsg.base_channel_proposal_acc:type_name -> perunwire.BaseChannelProposalAcc
+	4,  // 47: perunwire.LedgerChannelProposalAccMsg.participant:type_name -> perunwire.Address
+	8,  // 48: perunwire.SubChannelProposalMsg.base_channel_proposal:type_name -> perunwire.BaseChannelProposal
+	9,  // 49: perunwire.SubChannelProposalAccMsg.base_channel_proposal_acc:type_name -> perunwire.BaseChannelProposalAcc
+	8,  // 50: perunwire.VirtualChannelProposalMsg.base_channel_proposal:type_name -> perunwire.BaseChannelProposal
+	4,  // 51: perunwire.VirtualChannelProposalMsg.proposer:type_name -> perunwire.Address
+	4,  // 52: perunwire.VirtualChannelProposalMsg.peers:type_name -> perunwire.Address
+	5,  // 53: perunwire.VirtualChannelProposalMsg.index_maps:type_name -> perunwire.IndexMap
+	9,  // 54: perunwire.VirtualChannelProposalAccMsg.base_channel_proposal_acc:type_name -> perunwire.BaseChannelProposalAcc
+	4,  // 55: perunwire.VirtualChannelProposalAccMsg.responder:type_name -> perunwire.Address
+	14, // 56: perunwire.ChannelUpdateMsg.channel_update:type_name -> perunwire.ChannelUpdate
+	26, // 57: perunwire.VirtualChannelFundingProposalMsg.channel_update_msg:type_name -> perunwire.ChannelUpdateMsg
+	13, // 58: perunwire.VirtualChannelFundingProposalMsg.initial:type_name -> perunwire.SignedState
+	5,  // 59: perunwire.VirtualChannelFundingProposalMsg.index_map:type_name -> perunwire.IndexMap
+	26, // 60: perunwire.VirtualChannelSettlementProposalMsg.channel_update_msg:type_name -> perunwire.ChannelUpdateMsg
+	13, // 61: perunwire.VirtualChannelSettlementProposalMsg.final:type_name -> perunwire.SignedState
+	12, // 62: perunwire.ChannelSyncMsg.current_tx:type_name -> perunwire.Transaction
+	0,  // 63: perunwire.ReliableMsg.envelope:type_name -> perunwire.Envelope
+	10, // 64: perunwire.WatcherWatchMsg.params:type_name -> perunwire.Params
+	12, // 65: perunwire.WatcherWatchMsg.tx:type_name -> perunwire.Transaction
+	12, // 66: perunwire.WatcherPublishMsg.tx:type_name -> perunwire.Transaction
+	40, // 67: perunwire.WatcherEventMsg.registered:type_name -> perunwire.RegisteredEvent
+	41, // 68: perunwire.WatcherEventMsg.progressed:type_name -> perunwire.ProgressedEvent
+	42, // 69: perunwire.WatcherEventMsg.concluded:type_name -> perunwire.ConcludedEvent
+	43, // 70: perunwire.WatcherEventMsg.withdrawn:type_name -> perunwire.WithdrawnEvent
+	12, // 71: perunwire.RegisteredEvent.tx:type_name -> perunwire.Transaction
+	11, // 72: perunwire.ProgressedEvent.state:type_name -> perunwire.State
+	73, // [73:73] is the sub-list for method output_type
+	73, // [73:73] is the sub-list for method input_type
+	73, // [73:73] is the sub-list for extension type_name
+	73, // [73:73] is the sub-list for extension extendee
+	0,  // [0:73] is the sub-list for field type_name
 }
 
 func init() { file_wire_protobuf_wire_proto_init() }
@@ -2456,6 +3539,22 @@ func file_wire_protobuf_wire_proto_init() {
 		(*Envelope_ChannelUpdateAccMsg)(nil),
 		(*Envelope_ChannelUpdateRejMsg)(nil),
 		(*Envelope_ChannelSyncMsg)(nil),
+		(*Envelope_HelloMsg)(nil),
+		(*Envelope_ReliableMsg)(nil),
+		(*Envelope_AckMsg)(nil),
+		(*Envelope_WatcherWatchMsg)(nil),
+		(*Envelope_WatcherStopMsg)(nil),
+		(*Envelope_WatcherResponseMsg)(nil),
+		(*Envelope_WatcherPublishMsg)(nil),
+		(*Envelope_WatcherEventMsg)(nil),
+		(*Envelope_WatcherTimeoutElapsedMsg)(nil),
+		(*Envelope_WatcherBlindedUploadMsg)(nil),
+	}
+	file_wire_protobuf_wire_proto_msgTypes[39].OneofWrappers = []any{
+		(*WatcherEventMsg_Registered)(nil),
+		(*WatcherEventMsg_Progressed)(nil),
+		(*WatcherEventMsg_Concluded)(nil),
+		(*WatcherEventMsg_Withdrawn)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -2463,7 +3562,7 @@ func file_wire_protobuf_wire_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_wire_protobuf_wire_proto_rawDesc), len(file_wire_protobuf_wire_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   32,
+			NumMessages:   46,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
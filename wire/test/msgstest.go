@@ -33,6 +33,9 @@ func ControlMsgsSerializationTest(t *testing.T, serializerTest func(t *testing.T
 	maxLenDiff := 16
 	rng := pkgtest.Prng(t)
 	serializerTest(t, &wire.ShutdownMsg{Reason: newRandomASCIIString(rng, minLen, maxLenDiff)})
+	serializerTest(t, &wire.HelloMsg{Version: wire.ProtocolVersion, Features: wire.Features(rng.Uint64())})
+	serializerTest(t, &wire.ReliableMsg{ID: rng.Uint64(), Msg: wire.NewPingMsg()})
+	serializerTest(t, &wire.AckMsg{ID: rng.Uint64()})
 }
 
 // AuthMsgsSerializationTest runs serialization tests on auth message.
@@ -31,9 +31,15 @@ type SerializingLocalBus struct {
 
 // NewSerializingLocalBus creates a new serializing local bus.
 func NewSerializingLocalBus() *SerializingLocalBus {
+	return NewSerializingLocalBusWith(perunio.Serializer())
+}
+
+// NewSerializingLocalBusWith creates a new local bus that serializes messages
+// using ser.
+func NewSerializingLocalBusWith(ser wire.EnvelopeSerializer) *SerializingLocalBus {
 	return &SerializingLocalBus{
 		LocalBus: wire.NewLocalBus(),
-		ser:      perunio.Serializer(),
+		ser:      ser,
 	}
 }
 